@@ -0,0 +1,166 @@
+// Package a11y provides page scaffolding for keyboard and screen-reader
+// users: skip links, a single set of landmark regions, and focus restoration
+// after HTMX swaps.
+//
+//	h.Body(
+//	    a11y.Layout(a11y.Page{
+//	        Header: siteHeader(),
+//	        Nav:    siteNav(),
+//	        Main:   content,
+//	        Footer: siteFooter(),
+//	    }),
+//	)
+//
+// Check reports pages whose landmarks are missing or duplicated.
+package a11y
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/hx"
+	"github.com/jeffh/htmlgen/internal/htmlscan"
+	"github.com/jeffh/htmlgen/js"
+)
+
+var (
+	// ErrMissingLandmark is returned by Check when a required landmark is absent.
+	ErrMissingLandmark = errors.New("a11y: missing landmark")
+	// ErrDuplicateLandmark is returned by Check when a landmark appears more than once.
+	ErrDuplicateLandmark = errors.New("a11y: duplicate landmark")
+)
+
+// DefaultMainID is the id given to the <main> element when Page.MainID is empty.
+const DefaultMainID = "main"
+
+// Skip is an additional skip link rendered after "Skip to content".
+type Skip struct {
+	Target string // Element id, without "#"
+	Text   string
+}
+
+// Page holds the regions rendered by Layout. Only Main is required; the other
+// regions are omitted when nil.
+type Page struct {
+	Header   h.Builder
+	Nav      h.Builder
+	NavLabel string // Accessible name of the <nav> (default "Main")
+	Main     h.Builder
+	Footer   h.Builder
+	MainID   string // Id of the <main> element (default DefaultMainID)
+	Skips    []Skip
+}
+
+// Layout renders skip links followed by the page's <header>, <nav>, <main>,
+// and <footer> landmarks, each at most once. The <main> element is
+// programmatically focusable so the skip link moves focus into it, and
+// carries FocusRestore so swaps inside it keep keyboard focus on the page.
+func Layout(p Page) h.Builder {
+	mainID := p.MainID
+	if mainID == "" {
+		mainID = DefaultMainID
+	}
+	navLabel := p.NavLabel
+	if navLabel == "" {
+		navLabel = "Main"
+	}
+
+	links := []h.Builder{SkipLink(mainID, "Skip to content")}
+	for _, s := range p.Skips {
+		links = append(links, SkipLink(s.Target, s.Text))
+	}
+
+	return h.Fragment(
+		h.Div(h.Class("skip-links"), h.Fragment(links...)),
+		h.When(p.Header != nil, h.Header(p.Header)),
+		h.When(p.Nav != nil, h.Nav(h.Attrs("aria-label", navLabel), p.Nav)),
+		h.Main(h.Attrs("id", mainID, "tabindex", "-1"), FocusRestore(), p.Main),
+		h.When(p.Footer != nil, h.Footer(p.Footer)),
+	)
+}
+
+// SkipLink renders a link that moves focus to the element with id target.
+// Style .skip-link to stay visually hidden until focused.
+func SkipLink(target, text string) h.Builder {
+	return h.A(h.Attrs("class", "skip-link", "href", "#"+target), h.Text(text))
+}
+
+// FocusRestore returns an hx-on::after-swap attribute that moves focus into
+// swapped content when the swap removed the focused element, which would
+// otherwise leave keyboard focus at the top of the document. Focus goes to
+// the first [autofocus] element or h1-h3 heading in the swap target, or the
+// target itself. Place it on <body> to cover every swap on the page.
+func FocusRestore() h.Attribute {
+	target := js.Ident("t")
+	focus := js.Ident("f")
+	active := js.Prop(js.Ident("document"), "activeElement")
+	return hx.OnHTMX("after-swap", js.Handler(
+		js.Const("t", js.Prop(js.Prop(js.Ident("event"), "detail"), "target")),
+		js.If(
+			js.Or(
+				js.Eq(active, js.Prop(js.Ident("document"), "body")),
+				js.Not(js.Method(js.Ident("document"), "contains", active)),
+			),
+			js.Const("f", js.Or(js.Method(target, "querySelector", js.String("[autofocus], h1, h2, h3")), target)),
+			js.If(js.Lt(js.Prop(focus, "tabIndex"), js.Int(0)),
+				js.ExprStmt(js.Method(focus, "setAttribute", js.String("tabindex"), js.String("-1"))),
+			),
+			js.ExprStmt(js.Method(focus, "focus")),
+		),
+	))
+}
+
+// Check reports landmark problems in a rendered document: the main, nav,
+// and footer (contentinfo) landmarks must each appear exactly once. Explicit
+// roles count as landmarks, and a <footer> inside sectioning content
+// (article, aside, main, nav, or section) is not a contentinfo landmark.
+// The returned error wraps ErrMissingLandmark or ErrDuplicateLandmark.
+func Check(doc []byte) error {
+	counts := map[string]int{}
+	var sectioning []string
+	htmlscan.Scan(doc, func(t htmlscan.Tag) {
+		switch t.Name {
+		case "article", "aside", "main", "nav", "section":
+			if t.End {
+				if n := len(sectioning); n > 0 {
+					sectioning = sectioning[:n-1]
+				}
+				return
+			}
+			sectioning = append(sectioning, t.Name)
+		}
+		if t.End {
+			return
+		}
+		switch role := t.Value("role"); {
+		case role == "main" || (t.Name == "main" && role == ""):
+			counts["main"]++
+		case role == "navigation" || (t.Name == "nav" && role == ""):
+			counts["navigation"]++
+		case role == "contentinfo" || (t.Name == "footer" && role == "" && len(sectioning) == 0):
+			counts["contentinfo"]++
+		}
+	})
+
+	var errs []error
+	for _, landmark := range []string{"main", "navigation", "contentinfo"} {
+		switch n := counts[landmark]; {
+		case n == 0:
+			errs = append(errs, fmt.Errorf("%w: %s", ErrMissingLandmark, landmark))
+		case n > 1:
+			errs = append(errs, fmt.Errorf("%w: %s (%d found)", ErrDuplicateLandmark, landmark, n))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckBuilder renders b and runs Check on the output.
+func CheckBuilder(b h.Builder) error {
+	var buf bytes.Buffer
+	if err := h.Render(&buf, b); err != nil {
+		return err
+	}
+	return Check(buf.Bytes())
+}
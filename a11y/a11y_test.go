@@ -0,0 +1,94 @@
+package a11y
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestLayout(t *testing.T) {
+	got := h.RenderString(Layout(Page{
+		Header: h.H1(h.Text("Site")),
+		Nav:    h.A(h.Attrs("href", "/"), h.Text("Home")),
+		Main:   h.P(h.Text("Hello")),
+		Footer: h.Text("© 2024"),
+		Skips:  []Skip{{Target: "search", Text: "Skip to search"}},
+	}))
+	for _, want := range []string{
+		`<div class="skip-links"><a class="skip-link" href="#main">Skip to content</a><a class="skip-link" href="#search">Skip to search</a></div>`,
+		`<header><h1>Site</h1></header>`,
+		`<nav aria-label="Main"><a href="/">Home</a></nav>`,
+		`<main id="main" tabindex="-1" hx-on::after-swap="`,
+		`<p>Hello</p></main><footer>© 2024</footer>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+}
+
+func TestLayoutOmitsMissingRegions(t *testing.T) {
+	got := h.RenderString(Layout(Page{Main: h.Text("x"), MainID: "content"}))
+	if strings.Contains(got, "<header") || strings.Contains(got, "<nav") || strings.Contains(got, "<footer") {
+		t.Errorf("expected only main, got %s", got)
+	}
+	if !strings.Contains(got, `href="#content"`) || !strings.Contains(got, `<main id="content"`) {
+		t.Errorf("expected custom main id, got %s", got)
+	}
+}
+
+func TestFocusRestore(t *testing.T) {
+	attr := FocusRestore()
+	if attr.Name != "hx-on::after-swap" {
+		t.Errorf("unexpected name %q", attr.Name)
+	}
+	for _, want := range []string{
+		"const t = event.detail.target",
+		"document.activeElement === document.body",
+		`t.querySelector("[autofocus], h1, h2, h3")`,
+		"f.focus()",
+	} {
+		if !strings.Contains(attr.Value, want) {
+			t.Errorf("expected %q in %s", want, attr.Value)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		expected []error
+	}{
+		{"complete", `<nav></nav><main><article><footer>by</footer></article></main><footer></footer>`, nil},
+		{"roles", `<div role="navigation"></div><div role="main"></div><div role="contentinfo"></div>`, nil},
+		{"missing", `<main></main>`, []error{ErrMissingLandmark}},
+		{"duplicate main", `<nav></nav><main></main><main></main><footer></footer>`, []error{ErrDuplicateLandmark}},
+		{"nested footer only", `<nav></nav><main><section><footer></footer></section></main>`, []error{ErrMissingLandmark}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check([]byte(tt.doc))
+			if tt.expected == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, want := range tt.expected {
+				if !errors.Is(err, want) {
+					t.Errorf("expected %v, got %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckBuilderLayout(t *testing.T) {
+	page := Layout(Page{Nav: h.Text("nav"), Main: h.Text("main"), Footer: h.Text("footer")})
+	if err := CheckBuilder(page); err != nil {
+		t.Errorf("expected a complete layout, got %v", err)
+	}
+	if err := CheckBuilder(Layout(Page{Main: h.Main()})); !errors.Is(err, ErrDuplicateLandmark) {
+		t.Errorf("expected duplicate main, got %v", err)
+	}
+}
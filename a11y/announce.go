@@ -0,0 +1,78 @@
+package a11y
+
+import (
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/hx"
+	"github.com/jeffh/htmlgen/js"
+)
+
+// Ids of the live regions rendered by Announcer.
+const (
+	StatusID = "a11y-status" // Polite announcements
+	AlertID  = "a11y-alert"  // Assertive announcements
+)
+
+// visuallyHidden keeps live regions available to screen readers while
+// taking no space on screen.
+const visuallyHidden = "position:absolute;width:1px;height:1px;margin:-1px;padding:0;overflow:hidden;clip:rect(0 0 0 0);white-space:nowrap;border:0"
+
+// Announcer renders the visually hidden polite and assertive live regions
+// that Announce, AnnounceAlert, and AnnounceOOB write to. Render it once per
+// page, outside any region that gets swapped, so the regions exist before
+// their content changes.
+func Announcer() h.Builder {
+	return h.Fragment(
+		region(StatusID, "status", "polite"),
+		region(AlertID, "alert", "assertive"),
+	)
+}
+
+func region(id, role, live string, args ...h.TagArg) h.Builder {
+	attrs := h.Attrs("id", id, "role", role, "aria-live", live, "aria-atomic", "true", "style", visuallyHidden)
+	return h.Div(append([]h.TagArg{attrs}, args...)...)
+}
+
+// Announce returns a statement that reads message to screen-reader users
+// without interrupting them, for use in client-side handlers:
+//
+//	h.Button(js.OnClick(copyToClipboard, a11y.Announce(js.String("Copied"))), ...)
+func Announce(message js.Expr) js.Stmt {
+	return announce(StatusID, message)
+}
+
+// AnnounceAlert is like Announce but interrupts the user; reserve it for
+// errors and other time-sensitive messages.
+func AnnounceAlert(message js.Expr) js.Stmt {
+	return announce(AlertID, message)
+}
+
+// announce clears the region and sets the message on the next tick, so a
+// repeated message is still announced. It guards with if rather than an
+// early return, so statements after it in a handler still run.
+func announce(id string, message js.Expr) js.Stmt {
+	r := js.Ident("r")
+	return js.Block(
+		js.Const("r", js.GetElementById(js.String(id))),
+		js.If(r,
+			js.Assign(js.Prop(r, "textContent"), js.String("")),
+			js.ExprStmt(js.SetTimeout(
+				js.ArrowFuncStmts(nil, js.Assign(js.Prop(r, "textContent"), message)),
+				js.Int(50),
+			)),
+		),
+	)
+}
+
+// AnnounceOOB renders an out-of-band swap that announces message politely
+// when included in an HTMX response, or when patched into the page with a
+// Datastar server-sent event:
+//
+//	h.Fragment(updatedRow, a11y.AnnounceOOB("Saved"))
+func AnnounceOOB(message string) h.Builder {
+	return region(StatusID, "status", "polite", hx.SwapOOB("innerHTML"), h.Text(message))
+}
+
+// AnnounceAlertOOB is the assertive form of AnnounceOOB.
+func AnnounceAlertOOB(message string) h.Builder {
+	return region(AlertID, "alert", "assertive", hx.SwapOOB("innerHTML"), h.Text(message))
+}
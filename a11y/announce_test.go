@@ -0,0 +1,48 @@
+package a11y
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+func TestAnnouncer(t *testing.T) {
+	got := h.RenderString(Announcer())
+	for _, want := range []string{
+		`<div id="a11y-status" role="status" aria-live="polite" aria-atomic="true" style="position:absolute;`,
+		`<div id="a11y-alert" role="alert" aria-live="assertive" aria-atomic="true"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+}
+
+func TestAnnounce(t *testing.T) {
+	got := js.Handler(Announce(js.String("Saved")))
+	expected := `{ const r = document.getElementById("a11y-status"); if (r) { r.textContent = ""; setTimeout(() => { r.textContent = "Saved" }, 50) } }`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	// Statements after the announcement run even without the region.
+	got = js.Handler(Announce(js.String("Saved")), js.ExprStmt(js.Call(js.Ident("next"))))
+	if strings.Contains(got, "return") || !strings.HasSuffix(got, "; next()") {
+		t.Errorf("announcement must not end the handler: %q", got)
+	}
+	if got := js.Handler(AnnounceAlert(js.Ident("msg"))); !strings.Contains(got, `getElementById("a11y-alert")`) || !strings.Contains(got, "r.textContent = msg") {
+		t.Errorf("unexpected alert handler %q", got)
+	}
+}
+
+func TestAnnounceOOB(t *testing.T) {
+	got := h.RenderString(AnnounceOOB("Saved <draft>"))
+	if !strings.HasPrefix(got, `<div id="a11y-status" role="status"`) ||
+		!strings.HasSuffix(got, `hx-swap-oob="innerHTML">Saved &lt;draft&gt;</div>`) {
+		t.Errorf("unexpected OOB announcement %s", got)
+	}
+	if got := h.RenderString(AnnounceAlertOOB("Failed")); !strings.Contains(got, `id="a11y-alert"`) {
+		t.Errorf("unexpected alert OOB %s", got)
+	}
+}
@@ -0,0 +1,269 @@
+// Package antispam provides honeypot and timestamp-token form fields with a
+// matching server-side verifier, giving public forms basic bot protection
+// without CAPTCHAs or client-side JavaScript. Optionally, a Guard rejects
+// tokens that were already submitted and rate-limits clients.
+//
+// Render the fields inside a form and verify the submission on the server:
+//
+//	guard := &antispam.Guard{Secret: secret}
+//
+//	h.Form(h.Attrs("method", "post", "action", "/contact"),
+//	    guard.Fields(),
+//	    h.Input(h.Attrs("name", "email")),
+//	)
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    if err := guard.Verify(r); err != nil {
+//	        http.Error(w, "rejected", http.StatusBadRequest)
+//	        return
+//	    }
+//	    // ...
+//	}
+//
+// A signed token on its own can be resubmitted until it expires. Set Tokens
+// to accept each token once, and Allow to limit submissions per client:
+//
+//	guard := &antispam.Guard{
+//	    Secret: secret,
+//	    Tokens: antispam.NewMemoryTokenStore(),
+//	    Allow:  func(r *http.Request) bool { return limiter.Allow(clientIP(r)) },
+//	}
+package antispam
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Default field names and timing windows used when Guard fields are zero.
+const (
+	DefaultHoneypotField = "website"
+	DefaultTokenField    = "_ts"
+	DefaultMinAge        = 2 * time.Second
+	DefaultMaxAge        = 24 * time.Hour
+)
+
+// Sentinel errors returned by Guard.Verify.
+var (
+	// ErrHoneypotFilled is returned when the hidden honeypot field has a value.
+	ErrHoneypotFilled = errors.New("antispam: honeypot field filled")
+	// ErrTokenMissing is returned when the timestamp token is absent.
+	ErrTokenMissing = errors.New("antispam: timestamp token missing")
+	// ErrTokenInvalid is returned when the timestamp token is malformed or its signature does not match.
+	ErrTokenInvalid = errors.New("antispam: timestamp token invalid")
+	// ErrTooFast is returned when the form was submitted sooner than MinAge after rendering.
+	ErrTooFast = errors.New("antispam: form submitted too quickly")
+	// ErrExpired is returned when the form was submitted later than MaxAge after rendering.
+	ErrExpired = errors.New("antispam: form token expired")
+	// ErrTokenReused is returned when Tokens has already seen the token.
+	ErrTokenReused = errors.New("antispam: form token already used")
+	// ErrRateLimited is returned when Allow rejects the request.
+	ErrRateLimited = errors.New("antispam: too many submissions")
+)
+
+// Guard renders anti-spam form fields and verifies submissions.
+// Secret is required; all other fields have defaults.
+type Guard struct {
+	// Secret signs timestamp tokens. Use at least 32 random bytes.
+	Secret []byte
+	// HoneypotField is the name of the hidden input bots tend to fill in.
+	HoneypotField string
+	// TokenField is the name of the hidden input holding the signed timestamp.
+	TokenField string
+	// MinAge rejects submissions faster than a human could fill in the form.
+	MinAge time.Duration
+	// MaxAge rejects replays of stale tokens.
+	MaxAge time.Duration
+	// Tokens, if set, records accepted tokens so each verifies only once.
+	// Without it a token can be resubmitted until MaxAge has passed.
+	Tokens TokenStore
+	// Allow, if set, is called by Verify before anything else and rejects
+	// the request with ErrRateLimited when it returns false. Key it by
+	// client address or session to rate-limit submissions.
+	Allow func(r *http.Request) bool
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// TokenStore records spent tokens for Guard.Tokens. Implementations must be
+// safe for concurrent use; back it with a shared store such as Redis when
+// several servers verify the same forms.
+type TokenStore interface {
+	// Spend marks token as used for at least ttl and reports whether it
+	// was unused before.
+	Spend(token string, ttl time.Duration) bool
+}
+
+// MemoryTokenStore is a TokenStore for a single process. Spent tokens are
+// dropped once their ttl has passed.
+type MemoryTokenStore struct {
+	mu      sync.Mutex
+	spent   map[string]time.Time // Token to expiry
+	sweepAt time.Time
+}
+
+// NewMemoryTokenStore creates an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{spent: map[string]time.Time{}}
+}
+
+// Spend implements TokenStore.
+func (s *MemoryTokenStore) Spend(token string, ttl time.Duration) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.After(s.sweepAt) {
+		for t, exp := range s.spent {
+			if now.After(exp) {
+				delete(s.spent, t)
+			}
+		}
+		s.sweepAt = now.Add(time.Minute)
+	}
+	if exp, ok := s.spent[token]; ok && !now.After(exp) {
+		return false
+	}
+	s.spent[token] = now.Add(ttl)
+	return true
+}
+
+// Fields returns the honeypot and timestamp token fields as a single Builder.
+func (g *Guard) Fields() h.Builder {
+	return h.Fragment(g.Honeypot(), g.Token())
+}
+
+// Honeypot returns a visually hidden text input that humans never see or fill in.
+// The input is removed from the tab order and accessibility tree, and its label
+// instructs any assistive technology that reaches it to leave it blank.
+func (g *Guard) Honeypot() h.Builder {
+	name := g.honeypotField()
+	return h.Div(
+		h.Attrs(
+			"aria-hidden", "true",
+			"style", "position:absolute;left:-10000px;top:auto;width:1px;height:1px;overflow:hidden",
+		),
+		h.Label(h.Attrs("for", name), h.Text("Leave this field empty")),
+		h.Input(h.Attrs(
+			"type", "text",
+			"id", name,
+			"name", name,
+			"value", "",
+			"tabindex", "-1",
+			"autocomplete", "off",
+		)),
+	)
+}
+
+// Token returns a hidden input holding a signed timestamp of when the form was rendered.
+func (g *Guard) Token() h.Builder {
+	return h.Input(h.Attrs(
+		"type", "hidden",
+		"name", g.tokenField(),
+		"value", g.Sign(g.now()),
+	))
+}
+
+// Sign returns a token encoding t and a random nonce, signed with the
+// guard's secret. Every call returns a different token.
+func (g *Guard) Sign(t time.Time) string {
+	payload := strconv.FormatInt(t.Unix(), 10) + "." + rand.Text()
+	return payload + "." + g.signature(payload)
+}
+
+// Verify checks Allow, then the honeypot and timestamp token in a submitted
+// form. It parses the request form if needed. Returns nil for a plausible
+// human submission.
+func (g *Guard) Verify(r *http.Request) error {
+	if g.Allow != nil && !g.Allow(r) {
+		return ErrRateLimited
+	}
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	return g.VerifyValues(r.PostForm.Get(g.honeypotField()), r.PostForm.Get(g.tokenField()))
+}
+
+// VerifyValues checks raw honeypot and token field values.
+// Use this when form values are decoded by other means than http.Request.ParseForm.
+func (g *Guard) VerifyValues(honeypot, token string) error {
+	if strings.TrimSpace(honeypot) != "" {
+		return ErrHoneypotFilled
+	}
+	if token == "" {
+		return ErrTokenMissing
+	}
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 || !hmac.Equal([]byte(token[i+1:]), []byte(g.signature(token[:i]))) {
+		return ErrTokenInvalid
+	}
+	ts, _, _ := strings.Cut(token[:i], ".")
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrTokenInvalid
+	}
+	age := g.now().Sub(time.Unix(unix, 0))
+	if age < g.minAge() {
+		return ErrTooFast
+	}
+	if age > g.maxAge() {
+		return ErrExpired
+	}
+	if g.Tokens != nil && !g.Tokens.Spend(token, g.maxAge()-age) {
+		return ErrTokenReused
+	}
+	return nil
+}
+
+func (g *Guard) signature(ts string) string {
+	if len(g.Secret) == 0 {
+		panic("antispam: Guard.Secret must be set")
+	}
+	mac := hmac.New(sha256.New, g.Secret)
+	mac.Write([]byte(ts))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (g *Guard) honeypotField() string {
+	if g.HoneypotField == "" {
+		return DefaultHoneypotField
+	}
+	return g.HoneypotField
+}
+
+func (g *Guard) tokenField() string {
+	if g.TokenField == "" {
+		return DefaultTokenField
+	}
+	return g.TokenField
+}
+
+func (g *Guard) minAge() time.Duration {
+	if g.MinAge == 0 {
+		return DefaultMinAge
+	}
+	return g.MinAge
+}
+
+func (g *Guard) maxAge() time.Duration {
+	if g.MaxAge == 0 {
+		return DefaultMaxAge
+	}
+	return g.MaxAge
+}
+
+func (g *Guard) now() time.Time {
+	if g.Now == nil {
+		return time.Now()
+	}
+	return g.Now()
+}
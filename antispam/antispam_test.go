@@ -0,0 +1,122 @@
+package antispam
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func newGuard(now *time.Time) *Guard {
+	return &Guard{
+		Secret: []byte("test-secret"),
+		Now:    func() time.Time { return *now },
+	}
+}
+
+func TestFieldsRender(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := newGuard(&now)
+	out := h.RenderString(g.Fields())
+
+	for _, want := range []string{
+		`name="website"`,
+		`tabindex="-1"`,
+		`aria-hidden="true"`,
+		`type="hidden" name="_ts" value="1700000000.`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Fields() missing %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestVerifyValues(t *testing.T) {
+	rendered := time.Unix(1700000000, 0)
+	now := rendered
+	g := newGuard(&now)
+	token := g.Sign(rendered)
+
+	tests := []struct {
+		name     string
+		honeypot string
+		token    string
+		elapsed  time.Duration
+		expected error
+	}{
+		{"valid", "", token, 10 * time.Second, nil},
+		{"honeypot filled", "http://spam", token, 10 * time.Second, ErrHoneypotFilled},
+		{"missing token", "", "", 10 * time.Second, ErrTokenMissing},
+		{"no separator", "", "1700000000", 10 * time.Second, ErrTokenInvalid},
+		{"tampered timestamp", "", "1699999000" + token[len("1700000000"):], 10 * time.Second, ErrTokenInvalid},
+		{"too fast", "", token, time.Second, ErrTooFast},
+		{"expired", "", token, 25 * time.Hour, ErrExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = rendered.Add(tt.elapsed)
+			err := g.VerifyValues(tt.honeypot, tt.token)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("VerifyValues() = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	rendered := time.Unix(1700000000, 0)
+	now := rendered.Add(5 * time.Second)
+	g := newGuard(&now)
+	g.HoneypotField = "url"
+	g.TokenField = "t"
+
+	form := url.Values{"url": {""}, "t": {g.Sign(rendered)}}
+	req := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if err := g.Verify(req); err != nil {
+		t.Errorf("Verify() = %v, want nil", err)
+	}
+}
+
+func TestSignPanicsWithoutSecret(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for missing secret")
+		}
+	}()
+	(&Guard{}).Sign(time.Now())
+}
+
+func TestVerifyValuesRejectsReusedToken(t *testing.T) {
+	rendered := time.Unix(1700000000, 0)
+	now := rendered.Add(10 * time.Second)
+	g := newGuard(&now)
+	g.Tokens = NewMemoryTokenStore()
+	token := g.Sign(rendered)
+
+	if err := g.VerifyValues("", token); err != nil {
+		t.Fatalf("first VerifyValues() = %v, want nil", err)
+	}
+	if err := g.VerifyValues("", token); !errors.Is(err, ErrTokenReused) {
+		t.Errorf("second VerifyValues() = %v, want %v", err, ErrTokenReused)
+	}
+	if err := g.VerifyValues("", g.Sign(rendered)); err != nil {
+		t.Errorf("VerifyValues() of a fresh token = %v, want nil", err)
+	}
+}
+
+func TestVerifyRateLimited(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := newGuard(&now)
+	g.Allow = func(r *http.Request) bool { return r.RemoteAddr != "192.0.2.1:1234" }
+
+	req := httptest.NewRequest("POST", "/", nil)
+	if err := g.Verify(req); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Verify() = %v, want %v", err, ErrRateLimited)
+	}
+}
@@ -0,0 +1,194 @@
+// Package appmap exports an architecture map of a hypermedia page: the
+// components named with h.Named, the Datastar signals they read and write,
+// and the endpoints their HTMX and Datastar attributes call.
+//
+//	g, err := appmap.Build(page)
+//	if err != nil {
+//	    return err
+//	}
+//	os.WriteFile("app.dot", []byte(g.DOT()), 0o644)
+//
+// Render the DOT output with Graphviz (dot -Tsvg app.dot) or feed the JSON
+// form to other tools.
+package appmap
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeffh/htmlgen/ds"
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Root is the component that elements outside any h.Named builder belong
+// to.
+const Root = "page"
+
+// NodeKind is the kind of a Node.
+type NodeKind string
+
+// Node kinds.
+const (
+	ComponentNode NodeKind = "component"
+	SignalNode    NodeKind = "signal"
+	EndpointNode  NodeKind = "endpoint"
+)
+
+// EdgeKind is the relationship an Edge records.
+type EdgeKind string
+
+// Edge kinds. Contains goes from a component to one rendered inside it; the
+// others go from a component to a signal or endpoint.
+const (
+	Contains EdgeKind = "contains"
+	Reads    EdgeKind = "reads"
+	Writes   EdgeKind = "writes"
+	Calls    EdgeKind = "calls"
+)
+
+// Node is a component, signal, or endpoint.
+type Node struct {
+	ID    string   `json:"id"`    // Unique, such as "signal:count"
+	Kind  NodeKind `json:"kind"`  // Kind of node
+	Label string   `json:"label"` // Component name, $signal, or "METHOD /url"
+}
+
+// Edge is a relationship between two nodes, by ID.
+type Edge struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind EdgeKind `json:"kind"`
+}
+
+// Graph is the architecture map of a page. Nodes are sorted by kind and
+// label and edges by their endpoints, so output is stable across builds.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Build renders b without output and returns its graph. Each element is
+// attributed to the innermost h.Named component it is built in, or Root.
+// Signals come from ds.ExtractSignals; endpoints come from hx-get, hx-post,
+// hx-put, hx-patch, and hx-delete, and from Datastar actions (@get, @post,
+// ...) with literal URLs. Rendering errors are returned as-is.
+func Build(b h.Builder) (*Graph, error) {
+	mapper := &mapper{nodes: map[string]Node{}, edges: map[Edge]bool{}}
+	mapper.node(ComponentNode, Root)
+	ctx := h.WithElementHooks(context.Background(), mapper)
+	if err := h.RenderContext(ctx, io.Discard, b); err != nil {
+		return nil, err
+	}
+
+	g := &Graph{}
+	for _, n := range mapper.nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+	kindOrder := map[NodeKind]int{ComponentNode: 0, SignalNode: 1, EndpointNode: 2}
+	slices.SortFunc(g.Nodes, func(a, b Node) int {
+		return cmp.Or(cmp.Compare(kindOrder[a.Kind], kindOrder[b.Kind]), cmp.Compare(a.Label, b.Label))
+	})
+	for e := range mapper.edges {
+		g.Edges = append(g.Edges, e)
+	}
+	slices.SortFunc(g.Edges, func(a, b Edge) int {
+		return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.To, b.To))
+	})
+	return g, nil
+}
+
+// DOT returns g in Graphviz DOT format, with components as boxes, signals as
+// ellipses, and endpoints as notes.
+func (g *Graph) DOT() string {
+	shapes := map[NodeKind]string{ComponentNode: "box", SignalNode: "ellipse", EndpointNode: "note"}
+	var sb strings.Builder
+	sb.WriteString("digraph app {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=%s];\n", strconv.Quote(n.ID), strconv.Quote(n.Label), shapes[n.Kind])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s", strconv.Quote(e.From), strconv.Quote(e.To))
+		if e.Kind != Contains {
+			fmt.Fprintf(&sb, " [label=%q]", e.Kind)
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// JSON returns g encoded as JSON.
+func (g *Graph) JSON() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+// mapper is the ElementHook used by Build.
+type mapper struct {
+	nodes map[string]Node
+	edges map[Edge]bool
+}
+
+func (m *mapper) BeforeOpen(w *h.Writer, tag string, as h.Attributes) (h.Attributes, error) {
+	component := m.node(ComponentNode, Root)
+	for _, name := range w.Components() {
+		if inner := m.node(ComponentNode, name); inner != component {
+			m.edges[Edge{component, inner, Contains}] = true
+			component = inner
+		}
+	}
+	for _, a := range as {
+		reads, writes := ds.ExtractSignals(a)
+		for _, sig := range reads {
+			m.edges[Edge{component, m.node(SignalNode, "$"+sig), Reads}] = true
+		}
+		for _, sig := range writes {
+			m.edges[Edge{component, m.node(SignalNode, "$"+sig), Writes}] = true
+		}
+		for _, endpoint := range endpoints(a) {
+			m.edges[Edge{component, m.node(EndpointNode, endpoint), Calls}] = true
+		}
+	}
+	return as, nil
+}
+
+func (m *mapper) AfterClose(w *h.Writer, tag string, as h.Attributes) error { return nil }
+
+// node adds the node of kind with label, if new, and returns its ID.
+func (m *mapper) node(kind NodeKind, label string) string {
+	id := string(kind) + ":" + label
+	if _, ok := m.nodes[id]; !ok {
+		m.nodes[id] = Node{ID: id, Kind: kind, Label: label}
+	}
+	return id
+}
+
+// actionPattern matches Datastar backend actions with a literal URL.
+var actionPattern = regexp.MustCompile("@(get|post|put|patch|delete)\\(\\s*(?:\"([^\"]*)\"|'([^']*)'|`([^`]*)`)")
+
+// endpoints returns the "METHOD /url" endpoints an attribute calls.
+func endpoints(a h.Attribute) []string {
+	name := strings.TrimPrefix(strings.ToLower(a.Name), "data-")
+	switch name {
+	case "hx-get", "hx-post", "hx-put", "hx-patch", "hx-delete":
+		if a.Value == "" {
+			return nil
+		}
+		return []string{strings.ToUpper(strings.TrimPrefix(name, "hx-")) + " " + a.Value}
+	}
+	if !strings.HasPrefix(strings.ToLower(a.Name), "data-") {
+		return nil
+	}
+	var result []string
+	for _, m := range actionPattern.FindAllStringSubmatch(a.Value, -1) {
+		url := m[2] + m[3] + m[4]
+		result = append(result, strings.ToUpper(m[1])+" "+url)
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package appmap
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/jeffh/htmlgen/ds"
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/hx"
+)
+
+func testPage() h.Builder {
+	search := h.Named("Search", h.Form(
+		ds.Signal("query", ""),
+		h.Input(ds.Bind("query")),
+		h.Button(ds.OnClick(ds.Get("/search")), h.Text("Go")),
+	))
+	cart := h.Named("Cart", h.Div(
+		ds.Text(ds.Raw("$count")),
+		h.Button(hx.Post("/cart/items"), h.Text("Add")),
+		h.Named("CartBadge", h.Span(ds.Text(ds.Raw("$count")))),
+	))
+	return h.Div(ds.Signal("count", 0), search, cart, h.A(h.Attr("data-hx-delete", "/session"), h.Text("Log out")))
+}
+
+func TestBuild(t *testing.T) {
+	g, err := Build(testPage())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var nodes []string
+	for _, n := range g.Nodes {
+		nodes = append(nodes, n.ID)
+	}
+	expectedNodes := []string{
+		"component:Cart", "component:CartBadge", "component:Search", "component:page",
+		"signal:$count", "signal:$query",
+		"endpoint:DELETE /session", "endpoint:GET /search", "endpoint:POST /cart/items",
+	}
+	if !slices.Equal(nodes, expectedNodes) {
+		t.Errorf("nodes = %q, want %q", nodes, expectedNodes)
+	}
+
+	var edges []string
+	for _, e := range g.Edges {
+		edges = append(edges, e.From+" "+string(e.Kind)+" "+e.To)
+	}
+	expectedEdges := []string{
+		"component:Cart calls endpoint:POST /cart/items",
+		"component:Cart contains component:CartBadge",
+		"component:Cart reads signal:$count",
+		"component:CartBadge reads signal:$count",
+		"component:Search calls endpoint:GET /search",
+		"component:Search reads signal:$query",
+		"component:Search writes signal:$query",
+		"component:page calls endpoint:DELETE /session",
+		"component:page contains component:Cart",
+		"component:page contains component:Search",
+		"component:page writes signal:$count",
+	}
+	if !slices.Equal(edges, expectedEdges) {
+		t.Errorf("edges =\n%q\nwant\n%q", edges, expectedEdges)
+	}
+}
+
+func TestDOT(t *testing.T) {
+	g, err := Build(h.Named("Counter", h.Button(ds.Signal("n", 0), ds.OnClick(ds.Raw("$n++")), hx.Get("/n"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph app {
+  "component:Counter" [label="Counter", shape=box];
+  "component:page" [label="page", shape=box];
+  "signal:$n" [label="$n", shape=ellipse];
+  "endpoint:GET /n" [label="GET /n", shape=note];
+  "component:Counter" -> "endpoint:GET /n" [label="calls"];
+  "component:Counter" -> "signal:$n" [label="reads"];
+  "component:Counter" -> "signal:$n" [label="writes"];
+  "component:page" -> "component:Counter";
+}
+`
+	if got := g.DOT(); got != expected {
+		t.Errorf("DOT() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	g, err := Build(h.Div(hx.Get("/x")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := g.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"nodes":[{"id":"component:page","kind":"component","label":"page"},{"id":"endpoint:GET /x","kind":"endpoint","label":"GET /x"}],"edges":[{"from":"component:page","to":"endpoint:GET /x","kind":"calls"}]}`
+	if string(data) != expected {
+		t.Errorf("JSON() = %s, want %s", data, expected)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Edges) != 1 {
+		t.Errorf("round trip = %+v, %v", decoded, err)
+	}
+}
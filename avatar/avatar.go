@@ -0,0 +1,192 @@
+// Package avatar renders deterministic inline SVG avatars from a user
+// identifier: the user's initials on a colored background, or a symmetric
+// identicon when no initials are available.
+//
+//	avatar.Avatar(user.ID, user.Name, avatar.Options{Size: 32})
+//	// <svg ... role="img" aria-label="Ada Lovelace">...AL...</svg>
+//
+// The same identifier always produces the same color and pattern, so avatars
+// stay stable across pages without storing anything.
+package avatar
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// DefaultPalette is the set of background colors used when Options.Palette is
+// empty. Each has at least 4.5:1 contrast with white text.
+var DefaultPalette = []string{
+	"#b91c1c", "#c2410c", "#a16207", "#15803d",
+	"#0f766e", "#1d4ed8", "#6d28d9", "#be185d",
+}
+
+// DefaultSize is the rendered width and height in pixels when Options.Size is 0.
+const DefaultSize = 40
+
+// identiconBackground is the background behind identicon cells.
+const identiconBackground = "#f3f4f6"
+
+// Shape is the outline of an avatar.
+type Shape int
+
+const (
+	// Circle clips the avatar to a circle.
+	Circle Shape = iota
+	// Square renders the avatar as a square with slightly rounded corners.
+	Square
+)
+
+// Options configures avatar rendering. The zero value renders a 40px circle
+// using DefaultPalette.
+type Options struct {
+	Size    int      // Width and height in pixels (default DefaultSize)
+	Shape   Shape    // Outline (default Circle)
+	Palette []string // Colors picked from by identifier (default DefaultPalette)
+}
+
+func (o Options) size() int {
+	if o.Size <= 0 {
+		return DefaultSize
+	}
+	return o.Size
+}
+
+func (o Options) palette() []string {
+	if len(o.Palette) == 0 {
+		return DefaultPalette
+	}
+	return o.Palette
+}
+
+// Avatar renders the initials of name on a background chosen by id, falling
+// back to Identicon when name has no letters or digits. The name is used as
+// the accessible label. Additional args are applied to the <svg> element.
+func Avatar(id, name string, opts Options, args ...h.TagArg) h.Builder {
+	if Initials(name) == "" {
+		return Identicon(id, opts, append([]h.TagArg{h.Attrs("aria-label", labelFor(name))}, args...)...)
+	}
+	return InitialsAvatar(id, name, opts, args...)
+}
+
+// InitialsAvatar renders the initials of name in white on a palette color
+// chosen by id.
+func InitialsAvatar(id, name string, opts Options, args ...h.TagArg) h.Builder {
+	sum := sha256.Sum256([]byte(id))
+	color := pick(opts.palette(), sum)
+	return svg(opts, labelFor(name), args,
+		background(opts.Shape, color),
+		h.CustomElement("text",
+			h.Attrs(
+				"x", "50", "y", "50",
+				"dy", ".35em",
+				"text-anchor", "middle",
+				"fill", "#fff",
+				"font-family", "system-ui, sans-serif",
+				"font-size", "40",
+				"font-weight", "600",
+			),
+			h.Text(Initials(name)),
+		),
+	)
+}
+
+// Identicon renders a horizontally symmetric 5x5 pattern in a palette color,
+// both derived from id.
+func Identicon(id string, opts Options, args ...h.TagArg) h.Builder {
+	sum := sha256.Sum256([]byte(id))
+	color := pick(opts.palette(), sum)
+
+	// The grid is 60 units square, centered in the 100 unit viewBox so its
+	// corners stay inside a Circle.
+	const cell, offset = 12, 20
+	cells := []h.Builder{background(opts.Shape, identiconBackground)}
+	for row := range 5 {
+		for col := range 3 {
+			bit := row*3 + col
+			if sum[4+bit/8]&(1<<(bit%8)) == 0 {
+				continue
+			}
+			for _, c := range mirror(col) {
+				cells = append(cells, h.CustomElement("rect", h.Attrs(
+					"x", strconv.Itoa(offset+c*cell),
+					"y", strconv.Itoa(offset+row*cell),
+					"width", strconv.Itoa(cell),
+					"height", strconv.Itoa(cell),
+					"fill", color,
+				)))
+			}
+		}
+	}
+	return svg(opts, "Avatar", args, cells...)
+}
+
+// Initials returns up to two uppercase initials from name: the first letter
+// or digit of its first and last words. It returns "" when name has none.
+func Initials(name string) string {
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		i := strings.IndexFunc(word, func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r)
+		})
+		if i < 0 {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(word[i:])
+		r = unicode.ToUpper(r)
+		if len(initials) < 2 {
+			initials = append(initials, r)
+		} else {
+			initials[1] = r
+		}
+	}
+	return string(initials)
+}
+
+func mirror(col int) []int {
+	if col == 2 {
+		return []int{2}
+	}
+	return []int{col, 4 - col}
+}
+
+func pick(palette []string, sum [sha256.Size]byte) string {
+	return palette[binary.BigEndian.Uint32(sum[:4])%uint32(len(palette))]
+}
+
+func labelFor(name string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	return "Avatar"
+}
+
+func background(shape Shape, fill string) h.Builder {
+	if shape == Square {
+		return h.CustomElement("rect", h.Attrs("width", "100", "height", "100", "rx", "12", "fill", fill))
+	}
+	return h.CustomElement("circle", h.Attrs("cx", "50", "cy", "50", "r", "50", "fill", fill))
+}
+
+func svg(opts Options, label string, args []h.TagArg, children ...h.Builder) h.Builder {
+	size := strconv.Itoa(opts.size())
+	tagArgs := []h.TagArg{h.Attrs(
+		"xmlns", "http://www.w3.org/2000/svg",
+		"viewBox", "0 0 100 100",
+		"width", size,
+		"height", size,
+		"role", "img",
+		"aria-label", label,
+	)}
+	tagArgs = append(tagArgs, args...)
+	for _, c := range children {
+		tagArgs = append(tagArgs, c)
+	}
+	return h.Svg(tagArgs...)
+}
@@ -0,0 +1,90 @@
+package avatar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestInitials(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Ada Lovelace", "AL"},
+		{"ada", "A"},
+		{"Grace Brewster Murray Hopper", "GH"},
+		{"  émile   zola ", "ÉZ"},
+		{"(Jeff) Hui", "JH"},
+		{"", ""},
+		{"!!! ???", ""},
+	}
+	for _, tt := range tests {
+		if got := Initials(tt.name); got != tt.expected {
+			t.Errorf("Initials(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestAvatarInitials(t *testing.T) {
+	got := h.RenderString(Avatar("user-1", "Ada Lovelace", Options{Size: 32}, h.Class("avatar")))
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100" width="32" height="32" role="img" aria-label="Ada Lovelace" class="avatar">`,
+		`<circle cx="50" cy="50" r="50" fill="#`,
+		`>AL</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+}
+
+func TestAvatarIsDeterministic(t *testing.T) {
+	a := h.RenderString(Avatar("user-1", "Ada", Options{}))
+	b := h.RenderString(Avatar("user-1", "Ada", Options{}))
+	if a != b {
+		t.Errorf("expected identical renders:\n%s\n%s", a, b)
+	}
+	palette := []string{"#111111", "#222222", "#333333"}
+	seen := map[string]bool{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		out := h.RenderString(InitialsAvatar(id, "X", Options{Palette: palette}))
+		for _, c := range palette {
+			if strings.Contains(out, c) {
+				seen[c] = true
+			}
+		}
+	}
+	if len(seen) < 2 {
+		t.Errorf("expected ids to spread across the palette, got %v", seen)
+	}
+}
+
+func TestAvatarFallsBackToIdenticon(t *testing.T) {
+	got := h.RenderString(Avatar("user-2", "", Options{Shape: Square}))
+	if !strings.Contains(got, `aria-label="Avatar"`) {
+		t.Errorf("expected default label, got %s", got)
+	}
+	if !strings.Contains(got, `<rect width="100" height="100" rx="12" fill="#f3f4f6">`) {
+		t.Errorf("expected square identicon background, got %s", got)
+	}
+	if strings.Contains(got, "<text") {
+		t.Errorf("expected no initials, got %s", got)
+	}
+}
+
+func TestIdenticonIsSymmetric(t *testing.T) {
+	for _, id := range []string{"alice", "bob", "carol"} {
+		got := h.RenderString(Identicon(id, Options{}))
+		for _, row := range []string{"20", "32", "44", "56", "68"} {
+			for _, pair := range [][2]string{{"20", "68"}, {"32", "56"}} {
+				left := `x="` + pair[0] + `" y="` + row + `"`
+				right := `x="` + pair[1] + `" y="` + row + `"`
+				if strings.Contains(got, left) != strings.Contains(got, right) {
+					t.Errorf("%s: row %s is not symmetric", id, row)
+				}
+			}
+		}
+	}
+}
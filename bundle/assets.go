@@ -0,0 +1,183 @@
+package bundle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/prefetch"
+)
+
+// Asset is a static file from the bundle's asset directory.
+type Asset struct {
+	Name        string // Path relative to the asset directory: "css/app.css"
+	URL         string // Fingerprinted URL: "/assets/css/app.1a2b3c4d.css"
+	ContentType string
+	Integrity   string // Subresource Integrity hash: "sha384-..."
+
+	data []byte
+	etag string
+}
+
+// Bytes returns the asset contents. The slice must not be modified.
+func (a *Asset) Bytes() []byte { return a.data }
+
+// Manifest maps logical asset names to fingerprinted URLs. It marshals to
+// JSON for use by build tooling or client-side code.
+type Manifest map[string]string
+
+func (b *Bundle) loadAssets(fsys fs.FS, dir string) error {
+	return walkFiles(fsys, dir, func(name string, data []byte) error {
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:4])
+		sri := sha512.Sum384(data)
+		ext := path.Ext(name)
+		hashed := strings.TrimSuffix(name, ext) + "." + hash + ext
+		ctype := mime.TypeByExtension(ext)
+		if ctype == "" {
+			ctype = http.DetectContentType(data)
+		}
+		a := &Asset{
+			Name:        name,
+			URL:         b.prefix + hashed,
+			ContentType: ctype,
+			Integrity:   "sha384-" + base64.StdEncoding.EncodeToString(sri[:]),
+			data:        data,
+			etag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		}
+		b.assets[name] = a
+		b.hashed[hashed] = a
+		return nil
+	})
+}
+
+// Asset returns the asset with the given path relative to the asset
+// directory.
+func (b *Bundle) Asset(name string) (*Asset, bool) {
+	a, ok := b.assets[strings.TrimPrefix(name, "/")]
+	return a, ok
+}
+
+// AssetURL returns the fingerprinted URL of an asset. Unknown names map to
+// their unfingerprinted URL under the asset prefix, which Handler answers
+// with 404.
+func (b *Bundle) AssetURL(name string) string {
+	if a, ok := b.Asset(name); ok {
+		return a.URL
+	}
+	return b.prefix + strings.TrimPrefix(name, "/")
+}
+
+// AssetPrefix returns the URL path assets are served under, with a
+// trailing slash, for registering Handler on a mux.
+func (b *Bundle) AssetPrefix() string { return b.prefix }
+
+// Manifest returns a copy of the logical name to URL mapping.
+func (b *Bundle) Manifest() Manifest {
+	m := make(Manifest, len(b.assets))
+	for name, a := range b.assets {
+		m[name] = a.URL
+	}
+	return m
+}
+
+// Stylesheet renders a <link rel="stylesheet"> for a CSS asset with its
+// integrity hash.
+func (b *Bundle) Stylesheet(name string, args ...h.TagArg) h.Builder {
+	attrs := h.Attrs("rel", "stylesheet", "href", b.AssetURL(name))
+	if a, ok := b.Asset(name); ok {
+		attrs = append(attrs, h.Attr("integrity", a.Integrity))
+	}
+	return h.Link(append([]h.TagArg{attrs}, args...)...)
+}
+
+// Script renders a <script src> for a JavaScript asset with its integrity
+// hash. Pass h.Attrs("defer", ""), h.Attr("type", "module"), and so on in
+// args.
+func (b *Bundle) Script(name string, args ...h.TagArg) h.Builder {
+	attrs := h.Attrs("src", b.AssetURL(name))
+	if a, ok := b.Asset(name); ok {
+		attrs = append(attrs, h.Attr("integrity", a.Integrity))
+	}
+	return h.Script(append([]h.TagArg{attrs}, args...)...)
+}
+
+// Preload returns the early hints for assets, by name, with their
+// fingerprinted URLs, for prefetch.EarlyHints:
+//
+//	mux.Handle("/", prefetch.EarlyHints(pages, b.Preload("css/app.css", "js/app.js")...))
+//
+// The destination is derived from the content type. Fonts are marked
+// crossorigin, as browsers require. Panics if an asset does not exist, so
+// typos are caught at startup.
+func (b *Bundle) Preload(names ...string) []prefetch.Preload {
+	result := make([]prefetch.Preload, len(names))
+	for i, name := range names {
+		a, ok := b.Asset(name)
+		if !ok {
+			panic("bundle: Preload: no asset " + name)
+		}
+		p := prefetch.Preload{URL: a.URL, As: destination(a.ContentType)}
+		if p.As == "font" {
+			p.Type, _, _ = strings.Cut(a.ContentType, ";")
+			p.CrossOrigin = "anonymous"
+		}
+		result[i] = p
+	}
+	return result
+}
+
+// destination returns the preload destination for a content type.
+func destination(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch {
+	case mediaType == "text/css":
+		return "style"
+	case strings.HasSuffix(mediaType, "javascript"):
+		return "script"
+	case strings.HasPrefix(mediaType, "font/"):
+		return "font"
+	case strings.HasPrefix(mediaType, "image/"):
+		return "image"
+	}
+	return "fetch"
+}
+
+// immutable is the Cache-Control for fingerprinted URLs, whose content can
+// never change.
+const immutable = "public, max-age=31536000, immutable"
+
+// Handler serves the assets under AssetPrefix. Fingerprinted URLs are
+// cached indefinitely; logical names are also served but revalidated with
+// an ETag on every use.
+func (b *Bundle) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutPrefix(r.URL.Path, b.prefix)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		a, cache := b.hashed[name], immutable
+		if a == nil {
+			a, cache = b.assets[name], "no-cache"
+		}
+		if a == nil {
+			http.NotFound(w, r)
+			return
+		}
+		hdr := w.Header()
+		hdr.Set("Content-Type", a.ContentType)
+		hdr.Set("Cache-Control", cache)
+		hdr.Set("ETag", a.etag)
+		http.ServeContent(w, r, a.Name, time.Time{}, bytes.NewReader(a.data))
+	})
+}
@@ -0,0 +1,198 @@
+// Package bundle compiles an embedded front-end (HTML templates, partials,
+// and static assets) into h values at startup, so a single Go binary
+// carries the whole site:
+//
+//	//go:embed web
+//	var web embed.FS
+//
+//	var site = bundle.MustLoad(web, bundle.Options{Root: "web"})
+//
+//	mux.Handle(site.AssetPrefix(), site.Handler())
+//	mux.Handle("/", h.HandlerFunc(func(r *http.Request) h.Builder {
+//	    return site.Template("index.html").With(
+//	        h.NewParam("title").Value(h.Text("Home")),
+//	    )
+//	}))
+//
+// Under Root, templates/ holds HTML templates and assets/ holds static
+// files. Templates are plain HTML with three directives:
+//
+//	{{title}}                  a parameter, bound with h.NewParam("title").Value(...)
+//	{{> partials/nav.html}}    the contents of another template, inlined at load
+//	{{asset "css/app.css"}}    the fingerprinted URL of an asset
+//
+// Each template is pre-rendered into an h.CompiledTemplate. Assets are
+// fingerprinted with a content hash so they can be cached forever, and the
+// Manifest maps their logical names to the fingerprinted URLs.
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// ErrSyntax is wrapped by Load errors for malformed template directives.
+var ErrSyntax = errors.New("bundle: template syntax error")
+
+// ErrMissing is wrapped by Load errors for includes and asset references
+// that name files not in the bundle.
+var ErrMissing = errors.New("bundle: missing file")
+
+// Options configures Load. The zero value loads templates/ and assets/
+// from the root of the FS and serves assets under /assets/.
+type Options struct {
+	Root        string // Directory within the FS holding the front-end (default ".")
+	Templates   string // Template directory under Root (default "templates")
+	Assets      string // Asset directory under Root (default "assets")
+	AssetPrefix string // URL path assets are served under (default "/assets/")
+}
+
+func (o Options) withDefaults() Options {
+	if o.Root == "" {
+		o.Root = "."
+	}
+	if o.Templates == "" {
+		o.Templates = "templates"
+	}
+	if o.Assets == "" {
+		o.Assets = "assets"
+	}
+	if o.AssetPrefix == "" {
+		o.AssetPrefix = "/assets/"
+	}
+	if !strings.HasSuffix(o.AssetPrefix, "/") {
+		o.AssetPrefix += "/"
+	}
+	return o
+}
+
+// Bundle is a loaded front-end. It is immutable and safe for concurrent use.
+type Bundle struct {
+	prefix    string
+	templates map[string]*h.CompiledTemplate
+	assets    map[string]*Asset // By logical name
+	hashed    map[string]*Asset // By fingerprinted name
+}
+
+// Load reads templates and assets from fsys and compiles them. A missing
+// templates or assets directory is treated as empty.
+func Load(fsys fs.FS, opts Options) (*Bundle, error) {
+	opts = opts.withDefaults()
+	b := &Bundle{
+		prefix:    opts.AssetPrefix,
+		templates: map[string]*h.CompiledTemplate{},
+		assets:    map[string]*Asset{},
+		hashed:    map[string]*Asset{},
+	}
+	if err := b.loadAssets(fsys, path.Join(opts.Root, opts.Assets)); err != nil {
+		return nil, err
+	}
+	if err := b.loadTemplates(fsys, path.Join(opts.Root, opts.Templates)); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MustLoad is like Load but panics on error. It is intended for package
+// level variables initialized from go:embed.
+func MustLoad(fsys fs.FS, opts Options) *Bundle {
+	b, err := Load(fsys, opts)
+	if err != nil {
+		panic("htmlgen: bundle.Load failed: " + err.Error())
+	}
+	return b
+}
+
+// walkFiles calls fn with the slash-separated path relative to dir and the
+// contents of every regular file under dir.
+func walkFiles(fsys fs.FS, dir string, fn func(name string, data []byte) error) error {
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return fn(strings.TrimPrefix(p, dir+"/"), data)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, statErr := fs.Stat(fsys, dir); errors.Is(statErr, fs.ErrNotExist) {
+			return nil
+		}
+	}
+	return err
+}
+
+func (b *Bundle) loadTemplates(fsys fs.FS, dir string) error {
+	sources := map[string]string{}
+	err := walkFiles(fsys, dir, func(name string, data []byte) error {
+		if strings.HasSuffix(name, ".html") {
+			sources[name] = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c := &compiler{sources: sources, bundle: b, parsed: map[string][]part{}}
+	for name := range sources {
+		parts, err := c.expand(name, nil)
+		if err != nil {
+			return err
+		}
+		args := make([]h.Builder, 0, len(parts))
+		params := map[string]*h.Param{}
+		for _, p := range parts {
+			if p.param == "" {
+				args = append(args, h.Raw(p.text))
+				continue
+			}
+			if params[p.param] == nil {
+				params[p.param] = h.NewParam(p.param)
+			}
+			args = append(args, params[p.param])
+		}
+		tmpl, err := h.CompileParams(h.Fragment(args...))
+		if err != nil {
+			return fmt.Errorf("bundle: %s: %w", name, err)
+		}
+		b.templates[name] = tmpl
+	}
+	return nil
+}
+
+// Lookup returns the compiled template with the given path relative to the
+// templates directory.
+func (b *Bundle) Lookup(name string) (*h.CompiledTemplate, bool) {
+	t, ok := b.templates[name]
+	return t, ok
+}
+
+// Template is like Lookup but panics if the template does not exist.
+func (b *Bundle) Template(name string) *h.CompiledTemplate {
+	t, ok := b.templates[name]
+	if !ok {
+		panic("htmlgen: bundle has no template " + name)
+	}
+	return t
+}
+
+// Templates returns the sorted names of all templates, partials included.
+func (b *Bundle) Templates() []string {
+	names := make([]string, 0, len(b.templates))
+	for name := range b.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
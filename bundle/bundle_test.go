@@ -0,0 +1,178 @@
+package bundle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"web/assets/css/app.css": {Data: []byte("body{margin:0}")},
+		"web/assets/app.js":      {Data: []byte("console.log(1)")},
+		"web/templates/index.html": {Data: []byte(
+			`<html><head><link rel="stylesheet" href="{{asset "css/app.css"}}"></head>` +
+				`<body>{{> partials/nav.html}}<h1>{{ title }}</h1>{{content}}</body></html>`)},
+		"web/templates/partials/nav.html":   {Data: []byte(`<nav>{{> links.html}} {{title}}</nav>`)},
+		"web/templates/partials/links.html": {Data: []byte(`<a href="/">Home</a>`)},
+		"web/templates/notes.txt":           {Data: []byte("ignored")},
+	}
+}
+
+func TestLoadTemplates(t *testing.T) {
+	b, err := Load(testFS(), Options{Root: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, expected := strings.Join(b.Templates(), ","), "index.html,partials/links.html,partials/nav.html"; got != expected {
+		t.Errorf("Templates() = %s, want %s", got, expected)
+	}
+
+	var sb strings.Builder
+	err = b.Template("index.html").Render(&sb,
+		h.NewParam("title").Value(h.Text("Home & Away")),
+		h.NewParam("content").Value(h.P(h.Text("Hi"))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	css := b.AssetURL("css/app.css")
+	expected := `<html><head><link rel="stylesheet" href="` + css + `"></head>` +
+		`<body><nav><a href="/">Home</a> Home &amp; Away</nav><h1>Home &amp; Away</h1><p>Hi</p></body></html>`
+	if sb.String() != expected {
+		t.Errorf("render:\n got: %s\nwant: %s", sb.String(), expected)
+	}
+
+	if _, ok := b.Lookup("missing.html"); ok {
+		t.Error("Lookup(missing.html) found a template")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Template(missing.html) did not panic")
+		}
+	}()
+	b.Template("missing.html")
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected error
+	}{
+		{"unclosed", `<p>{{title</p>`, ErrSyntax},
+		{"unknown directive", `{{ range .Items }}`, ErrSyntax},
+		{"unquoted asset", `{{asset app.css}}`, ErrSyntax},
+		{"missing asset", `{{asset "nope.css"}}`, ErrMissing},
+		{"missing include", `{{> nope.html}}`, ErrMissing},
+		{"cycle", `{{> page.html}}`, ErrSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"templates/page.html": {Data: []byte(tt.template)}}
+			if _, err := Load(fsys, Options{}); !errors.Is(err, tt.expected) {
+				t.Errorf("Load() error = %v, want %v", err, tt.expected)
+			}
+		})
+	}
+
+	if _, err := Load(fstest.MapFS{}, Options{}); err != nil {
+		t.Errorf("Load(empty) error = %v", err)
+	}
+}
+
+func TestAssets(t *testing.T) {
+	b := MustLoad(testFS(), Options{Root: "web", AssetPrefix: "/static"})
+
+	a, ok := b.Asset("css/app.css")
+	if !ok {
+		t.Fatal("css/app.css not found")
+	}
+	if !strings.HasPrefix(a.URL, "/static/css/app.") || !strings.HasSuffix(a.URL, ".css") || len(a.URL) != len("/static/css/app.12345678.css") {
+		t.Errorf("URL = %s", a.URL)
+	}
+	if a.ContentType != "text/css; charset=utf-8" || !strings.HasPrefix(a.Integrity, "sha384-") {
+		t.Errorf("ContentType = %q, Integrity = %q", a.ContentType, a.Integrity)
+	}
+	if m := b.Manifest(); len(m) != 2 || m["css/app.css"] != a.URL {
+		t.Errorf("Manifest() = %v", m)
+	}
+	if got := b.AssetURL("missing.js"); got != "/static/missing.js" {
+		t.Errorf("AssetURL(missing.js) = %s", got)
+	}
+
+	var sb strings.Builder
+	h.Render(&sb, h.Fragment(b.Stylesheet("css/app.css"), b.Script("app.js", h.Attrs("defer", ""))))
+	js, _ := b.Asset("app.js")
+	expected := `<link rel="stylesheet" href="` + a.URL + `" integrity="` + a.Integrity + `"/>` +
+		`<script src="` + js.URL + `" integrity="` + js.Integrity + `" defer></script>`
+	if sb.String() != expected {
+		t.Errorf("tags:\n got: %s\nwant: %s", sb.String(), expected)
+	}
+
+	tests := []struct {
+		path   string
+		status int
+		cache  string
+		body   string
+	}{
+		{a.URL, http.StatusOK, immutable, "body{margin:0}"},
+		{"/static/css/app.css", http.StatusOK, "no-cache", "body{margin:0}"},
+		{"/static/css/app.00000000.css", http.StatusNotFound, "", ""},
+		{"/elsewhere/app.js", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		b.Handler().ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("GET %s: status %d, want %d", tt.path, rec.Code, tt.status)
+			continue
+		}
+		if tt.status == http.StatusOK && (rec.Header().Get("Cache-Control") != tt.cache || rec.Body.String() != tt.body) {
+			t.Errorf("GET %s: Cache-Control %q body %q", tt.path, rec.Header().Get("Cache-Control"), rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest("GET", a.URL, nil)
+	req.Header.Set("If-None-Match", a.etag)
+	rec := httptest.NewRecorder()
+	b.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("conditional GET: status %d, want 304", rec.Code)
+	}
+}
+
+func TestPreload(t *testing.T) {
+	fsys := testFS()
+	fsys["web/assets/fonts/inter.woff2"] = &fstest.MapFile{Data: []byte("wOF2")}
+	b := MustLoad(fsys, Options{Root: "web"})
+	css, _ := b.Asset("css/app.css")
+	js, _ := b.Asset("app.js")
+	font, _ := b.Asset("fonts/inter.woff2")
+
+	var links []string
+	for _, p := range b.Preload("css/app.css", "app.js", "fonts/inter.woff2") {
+		links = append(links, p.Link())
+	}
+	expected := []string{
+		"<" + css.URL + ">; rel=preload; as=style",
+		"<" + js.URL + ">; rel=preload; as=script",
+		"<" + font.URL + `>; rel=preload; as=font; type="font/woff2"; crossorigin=anonymous`,
+	}
+	if strings.Join(links, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Preload links:\n got: %q\nwant: %q", links, expected)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for missing asset")
+		}
+	}()
+	b.Preload("missing.css")
+}
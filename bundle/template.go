@@ -0,0 +1,114 @@
+package bundle
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// part is a piece of an expanded template: literal HTML or a parameter.
+type part struct {
+	text  string
+	param string
+}
+
+// compiler expands template directives, caching parsed partials.
+type compiler struct {
+	sources map[string]string
+	bundle  *Bundle
+	parsed  map[string][]part
+}
+
+// expand returns name with includes inlined and asset URLs substituted.
+// stack holds the templates currently being expanded, to reject cycles.
+func (c *compiler) expand(name string, stack []string) ([]part, error) {
+	if parts, ok := c.parsed[name]; ok {
+		return parts, nil
+	}
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("%w: include cycle %s -> %s", ErrSyntax, strings.Join(stack, " -> "), name)
+		}
+	}
+	src, ok := c.sources[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: template %s included from %s", ErrMissing, name, stack[len(stack)-1])
+	}
+	stack = append(stack, name)
+
+	var parts []part
+	literal := func(s string) {
+		if s == "" {
+			return
+		}
+		if n := len(parts); n > 0 && parts[n-1].param == "" {
+			parts[n-1].text += s
+			return
+		}
+		parts = append(parts, part{text: s})
+	}
+	for {
+		start := strings.Index(src, "{{")
+		if start < 0 {
+			literal(src)
+			break
+		}
+		literal(src[:start])
+		end := strings.Index(src[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("%w: %s: unclosed {{", ErrSyntax, name)
+		}
+		directive := strings.TrimSpace(src[start+2 : start+end])
+		src = src[start+end+2:]
+
+		switch {
+		case strings.HasPrefix(directive, ">"):
+			include := strings.TrimSpace(directive[1:])
+			if !strings.HasPrefix(include, "/") {
+				include = path.Join(path.Dir(name), include)
+			}
+			include = strings.TrimPrefix(include, "/")
+			sub, err := c.expand(include, stack)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range sub {
+				if p.param == "" {
+					literal(p.text)
+				} else {
+					parts = append(parts, p)
+				}
+			}
+		case strings.HasPrefix(directive, "asset "):
+			ref, err := strconv.Unquote(strings.TrimSpace(directive[len("asset "):]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: asset name must be quoted: {{%s}}", ErrSyntax, name, directive)
+			}
+			a, ok := c.bundle.assets[strings.TrimPrefix(ref, "/")]
+			if !ok {
+				return nil, fmt.Errorf("%w: asset %s referenced from %s", ErrMissing, ref, name)
+			}
+			literal(html.EscapeString(a.URL))
+		case isParamName(directive):
+			parts = append(parts, part{param: directive})
+		default:
+			return nil, fmt.Errorf("%w: %s: unknown directive {{%s}}", ErrSyntax, name, directive)
+		}
+	}
+	c.parsed[name] = parts
+	return parts, nil
+}
+
+func isParamName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c == '_' || c == '-' || c == '.' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			return false
+		}
+	}
+	return true
+}
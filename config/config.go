@@ -0,0 +1,244 @@
+// Package config holds rendering defaults read by h and js. Defaults are set
+// once at startup with functional options instead of a positional parameter
+// or global variable per feature:
+//
+//	config.Set(
+//	    config.Indent("  "),
+//	    config.StrictUntrusted(true),
+//	    config.Nonce(cspNonce), // func(ctx context.Context) string
+//	)
+//
+// A request can override the defaults by attaching a Config to the render
+// context; options not given keep their default:
+//
+//	ctx := config.WithContext(r.Context(), config.New(config.BaseURL("/tenant")))
+//	h.RenderContext(ctx, w, page)
+//
+// Settings given explicitly to a render, such as the indent passed to
+// h.RenderIndent or a base URL from h.WithBaseURL, take precedence over
+// both.
+//
+// ds, hx, and the other attribute packages do not read a Config: they build
+// attributes before there is a render context. Their attributes follow the
+// h settings, such as the base URL and escaping, when they are rendered.
+package config
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"io"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// Config is a set of rendering defaults. A Config is immutable once
+// created; use With to derive a modified copy.
+type Config struct {
+	// Indent is the indentation prefix h uses for pretty-printing. Empty
+	// renders compact HTML.
+	Indent string
+
+	// MaxLineLength wraps attributes onto new lines when a tag would be
+	// longer, if Indent is set. Zero never wraps.
+	MaxLineLength int
+
+	// StrictUntrusted makes h fail renders that place an h.Untrusted value
+	// in an unsafe attribute.
+	StrictUntrusted bool
+
+	// BaseURL prefixes root-relative URLs in URL attributes and Datastar
+	// actions. See h.Writer.SetBaseURL.
+	BaseURL string
+
+	// Nonce returns the Content-Security-Policy nonce for a render. When
+	// set, h adds nonce="..." to <script> and <style> elements that do not
+	// have one. An empty result adds nothing.
+	Nonce func(ctx context.Context) string
+
+	// AlwaysParenthesize makes js wrap every binary and ternary expression
+	// in parentheses. See js.AlwaysParenthesize.
+	AlwaysParenthesize bool
+
+	// Newline is the line ending h writes for pretty-printing and after
+	// the doctype: LF or CRLF. Empty means LF.
+	Newline string
+
+	// TrailingNewline makes h's Render functions end the document with a
+	// newline. Indented output already ends with one.
+	TrailingNewline bool
+
+	// DuplicateAttrs is how h writes an attribute repeated on one element,
+	// as from an Attributes literal or an element hook. The zero value
+	// writes every occurrence.
+	DuplicateAttrs DuplicatePolicy
+
+	// Escape adds escaping to the &, <, >, ", and ' that h always escapes
+	// in text and attribute values. The zero value adds none.
+	Escape EscapePolicy
+
+	// Rand is the source of randomness for generated ids and nonces. nil
+	// uses crypto/rand. Set a Seeded source for static site builds and
+	// snapshot tests that must be byte-identical across runs.
+	Rand io.Reader
+}
+
+// Option sets a field of a Config.
+type Option func(*Config)
+
+// Indent sets Config.Indent.
+func Indent(prefix string) Option { return func(c *Config) { c.Indent = prefix } }
+
+// MaxLineLength sets Config.MaxLineLength.
+func MaxLineLength(n int) Option { return func(c *Config) { c.MaxLineLength = n } }
+
+// StrictUntrusted sets Config.StrictUntrusted.
+func StrictUntrusted(strict bool) Option { return func(c *Config) { c.StrictUntrusted = strict } }
+
+// BaseURL sets Config.BaseURL.
+func BaseURL(base string) Option { return func(c *Config) { c.BaseURL = base } }
+
+// Nonce sets Config.Nonce.
+func Nonce(fn func(ctx context.Context) string) Option { return func(c *Config) { c.Nonce = fn } }
+
+// AlwaysParenthesize sets Config.AlwaysParenthesize.
+func AlwaysParenthesize(always bool) Option {
+	return func(c *Config) { c.AlwaysParenthesize = always }
+}
+
+// Line endings for Config.Newline.
+const (
+	LF   = "\n"
+	CRLF = "\r\n"
+)
+
+// Newline sets Config.Newline.
+func Newline(nl string) Option { return func(c *Config) { c.Newline = nl } }
+
+// TrailingNewline sets Config.TrailingNewline.
+func TrailingNewline(trailing bool) Option { return func(c *Config) { c.TrailingNewline = trailing } }
+
+// DuplicatePolicy says how h writes an attribute that appears more than
+// once on an element. Browsers keep the first occurrence and ignore the
+// rest.
+type DuplicatePolicy uint8
+
+// Policies for Config.DuplicateAttrs. Attribute names are compared without
+// regard to case.
+const (
+	KeepAll   DuplicatePolicy = iota // Write every occurrence
+	KeepFirst                        // Write the first occurrence only
+	KeepLast                         // Write the last value, in the first occurrence's place
+	Merge                            // Join space-separated lists such as class and rel, and style declarations; keep the last value of other attributes
+	Error                            // Fail the render with h.ErrDuplicateAttribute
+)
+
+// DuplicateAttrs sets Config.DuplicateAttrs.
+func DuplicateAttrs(policy DuplicatePolicy) Option {
+	return func(c *Config) { c.DuplicateAttrs = policy }
+}
+
+// EscapePolicy is a set of escaping rules for Config.Escape, combined
+// with |.
+type EscapePolicy uint8
+
+// Rules for Config.Escape.
+const (
+	// EscapeControl writes C0 control characters other than tab, LF, and
+	// CR, and DEL, as numeric character references such as &#27;.
+	EscapeControl EscapePolicy = 1 << iota
+	// EscapeNewlines writes LF and CR in attribute values as &#10; and
+	// &#13;, so they survive tools that normalize or strip raw newlines.
+	EscapeNewlines
+	// EscapeLineSeparators writes U+2028 and U+2029 as &#8232; and
+	// &#8233;, so the page source never holds a raw line separator, which
+	// many editors and line-based tools treat as a line break.
+	EscapeLineSeparators
+	// RejectInvalidUTF8 fails the render with h.ErrInvalidUTF8 instead of
+	// writing invalid UTF-8 as-is.
+	RejectInvalidUTF8
+
+	// EscapeStrict enables every rule.
+	EscapeStrict = EscapeControl | EscapeNewlines | EscapeLineSeparators | RejectInvalidUTF8
+)
+
+// Escape sets Config.Escape.
+func Escape(policy EscapePolicy) Option { return func(c *Config) { c.Escape = policy } }
+
+// Rand sets Config.Rand.
+func Rand(r io.Reader) Option { return func(c *Config) { c.Rand = r } }
+
+// Seeded returns a deterministic source of randomness for Config.Rand:
+// every source with the same seed produces the same bytes. It is not safe
+// for concurrent use, so give each render its own source, and never use it
+// for nonces or anything else that must be unpredictable.
+func Seeded(seed uint64) io.Reader {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return rand.NewChaCha8(key)
+}
+
+// Random returns c.Rand, or crypto/rand.Reader if it is nil.
+func (c *Config) Random() io.Reader {
+	if c.Rand == nil {
+		return crand.Reader
+	}
+	return c.Rand
+}
+
+// With returns a copy of c with opts applied.
+func (c *Config) With(opts ...Option) *Config {
+	cp := *c
+	for _, opt := range opts {
+		opt(&cp)
+	}
+	return &cp
+}
+
+var defaults atomic.Pointer[Config]
+
+func init() { defaults.Store(&Config{}) }
+
+// Default returns the process-wide defaults. The result must not be
+// modified.
+func Default() *Config { return defaults.Load() }
+
+// New returns the process-wide defaults with opts applied.
+func New(opts ...Option) *Config { return Default().With(opts...) }
+
+// Set applies opts to the process-wide defaults. Call it during
+// initialization; renders already in progress may see either the old or
+// the new defaults.
+func Set(opts ...Option) {
+	for {
+		old := defaults.Load()
+		if defaults.CompareAndSwap(old, old.With(opts...)) {
+			return
+		}
+	}
+}
+
+// Reset restores the process-wide defaults to their zero values.
+func Reset() { defaults.Store(&Config{}) }
+
+type contextKey struct{}
+
+// WithContext returns a context whose renders use c instead of the
+// process-wide defaults.
+func WithContext(ctx context.Context, c *Config) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Config attached to ctx with WithContext, if any.
+func FromContext(ctx context.Context) (*Config, bool) {
+	c, ok := ctx.Value(contextKey{}).(*Config)
+	return c, ok && c != nil
+}
+
+// Get returns the Config attached to ctx, or the process-wide defaults.
+func Get(ctx context.Context) *Config {
+	if c, ok := FromContext(ctx); ok {
+		return c
+	}
+	return Default()
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+func TestSetAndReset(t *testing.T) {
+	defer Reset()
+
+	Set(Indent("  "), StrictUntrusted(true))
+	Set(BaseURL("/app"))
+	c := Default()
+	if c.Indent != "  " || !c.StrictUntrusted || c.BaseURL != "/app" {
+		t.Errorf("Set did not accumulate options: %+v", c)
+	}
+
+	Reset()
+	if got := Default(); got.Indent != "" || got.StrictUntrusted || got.BaseURL != "" {
+		t.Errorf("Reset left %+v", got)
+	}
+}
+
+func TestWithDoesNotModifyReceiver(t *testing.T) {
+	base := New(Indent("\t"))
+	derived := base.With(MaxLineLength(80), AlwaysParenthesize(true))
+	if base.MaxLineLength != 0 || base.AlwaysParenthesize {
+		t.Errorf("With modified its receiver: %+v", base)
+	}
+	if derived.Indent != "\t" || derived.MaxLineLength != 80 || !derived.AlwaysParenthesize {
+		t.Errorf("With = %+v", derived)
+	}
+}
+
+func TestContext(t *testing.T) {
+	defer Reset()
+	Set(Indent("  "))
+
+	ctx := context.Background()
+	if _, ok := FromContext(ctx); ok {
+		t.Error("FromContext reported a Config on a bare context")
+	}
+	if Get(ctx) != Default() {
+		t.Error("Get should fall back to Default")
+	}
+
+	c := New(BaseURL("/tenant"))
+	ctx = WithContext(ctx, c)
+	if got, ok := FromContext(ctx); !ok || got != c {
+		t.Errorf("FromContext = %v, %v", got, ok)
+	}
+	if got := Get(ctx); got.Indent != "  " || got.BaseURL != "/tenant" {
+		t.Errorf("Get = %+v, want defaults plus BaseURL", got)
+	}
+	if _, ok := FromContext(WithContext(context.Background(), nil)); ok {
+		t.Error("FromContext reported a nil Config")
+	}
+}
+
+func TestSeeded(t *testing.T) {
+	read := func(r io.Reader) []byte {
+		b := make([]byte, 16)
+		if _, err := io.ReadFull(r, b); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	if !bytes.Equal(read(Seeded(1)), read(Seeded(1))) {
+		t.Error("same seed produced different bytes")
+	}
+	if bytes.Equal(read(Seeded(1)), read(Seeded(2))) {
+		t.Error("different seeds produced the same bytes")
+	}
+	if (&Config{}).Random() == nil {
+		t.Error("Random should default to crypto/rand")
+	}
+	r := Seeded(3)
+	if New(Rand(r)).Random() != r {
+		t.Error("Random should return Config.Rand")
+	}
+}
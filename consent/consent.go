@@ -0,0 +1,263 @@
+// Package consent implements cookie-consent state, a consent banner component,
+// and script gating so third-party scripts only run for consented categories.
+//
+// Scripts are gated by rendering them with h.GatedScript (inert until activated)
+// or with Script, which emits a live script when the request already carries consent:
+//
+//	state := consent.FromRequest(r)
+//	h.Html(
+//	    h.Head(consent.Script(state, consent.Analytics, "https://stats.example.com/s.js")),
+//	    h.Body(
+//	        content,
+//	        consent.Banner(state, consent.BannerOptions{
+//	            Categories: []consent.Category{consent.Analytics},
+//	        }),
+//	    ),
+//	)
+//
+// The banner's buttons store the decision in a cookie and activate any gated
+// scripts on the page without a reload.
+package consent
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+// Category identifies a class of scripts or cookies requiring consent.
+type Category string
+
+const (
+	// Necessary covers strictly necessary functionality and is always allowed.
+	Necessary Category = "necessary"
+	// Preferences covers remembering user choices such as language or theme.
+	Preferences Category = "preferences"
+	// Analytics covers usage measurement.
+	Analytics Category = "analytics"
+	// Marketing covers advertising and cross-site tracking.
+	Marketing Category = "marketing"
+)
+
+// CookieName is the name of the cookie storing the consent decision.
+const CookieName = "consent"
+
+// DefaultMaxAge is how long a consent decision is remembered.
+const DefaultMaxAge = 180 * 24 * time.Hour
+
+// State is a user's consent decision.
+type State struct {
+	// Decided is true once the user has accepted or rejected.
+	Decided bool
+	// Granted lists the categories the user consented to.
+	Granted []Category
+}
+
+// Allows reports whether scripts in category c may run.
+// Necessary is always allowed.
+func (s State) Allows(c Category) bool {
+	return c == Necessary || slices.Contains(s.Granted, c)
+}
+
+// FromRequest reads the consent decision from the request's cookie.
+// Returns an undecided State if the cookie is missing.
+func FromRequest(r *http.Request) State {
+	c, err := r.Cookie(CookieName)
+	if err != nil {
+		return State{}
+	}
+	return Parse(c.Value)
+}
+
+// Parse decodes a consent cookie value (a "|"-separated category list).
+func Parse(value string) State {
+	s := State{Decided: true}
+	for _, part := range strings.Split(value, "|") {
+		if c := Category(strings.TrimSpace(part)); c != "" && !slices.Contains(s.Granted, c) {
+			s.Granted = append(s.Granted, c)
+		}
+	}
+	return s
+}
+
+// String encodes the granted categories as a cookie value.
+// Necessary is always included so that a "reject all" decision is non-empty.
+func (s State) String() string {
+	parts := []string{string(Necessary)}
+	for _, c := range s.Granted {
+		if c != Necessary {
+			parts = append(parts, string(c))
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// Cookie returns the cookie recording s, valid for DefaultMaxAge.
+func (s State) Cookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     CookieName,
+		Value:    s.String(),
+		Path:     "/",
+		MaxAge:   int(DefaultMaxAge / time.Second),
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// SetCookie records s on the response.
+func SetCookie(w http.ResponseWriter, s State) {
+	http.SetCookie(w, s.Cookie())
+}
+
+// Script returns a live <script src> when s allows category, or an inert
+// h.GatedScript placeholder that the banner activates once consent is given.
+func Script(s State, category Category, src string, args ...h.TagArg) h.Builder {
+	if s.Allows(category) {
+		return h.Script(append([]h.TagArg{h.Attr("src", src)}, args...)...)
+	}
+	return h.GatedScript(string(category), src, args...)
+}
+
+// BannerOptions configures Banner. Zero values use sensible defaults.
+type BannerOptions struct {
+	// ID is the banner element id. Defaults to "consent-banner".
+	ID string
+	// Message is the banner text.
+	Message string
+	// PolicyURL links to the privacy policy when non-empty.
+	PolicyURL string
+	// PolicyLabel is the policy link text. Defaults to "Privacy policy".
+	PolicyLabel string
+	// Categories are granted by the accept button (Necessary is implicit).
+	Categories []Category
+	// AcceptLabel defaults to "Accept all".
+	AcceptLabel string
+	// RejectLabel defaults to "Reject non-essential".
+	RejectLabel string
+	// MaxAge defaults to DefaultMaxAge.
+	MaxAge time.Duration
+}
+
+func (o BannerOptions) withDefaults() BannerOptions {
+	if o.ID == "" {
+		o.ID = "consent-banner"
+	}
+	if o.Message == "" {
+		o.Message = "We use cookies to improve your experience. Choose which optional cookies to allow."
+	}
+	if o.PolicyLabel == "" {
+		o.PolicyLabel = "Privacy policy"
+	}
+	if o.AcceptLabel == "" {
+		o.AcceptLabel = "Accept all"
+	}
+	if o.RejectLabel == "" {
+		o.RejectLabel = "Reject non-essential"
+	}
+	if o.MaxAge == 0 {
+		o.MaxAge = DefaultMaxAge
+	}
+	return o
+}
+
+// Banner renders an accessible consent dialog with accept and reject buttons.
+// Returns nil when the user has already decided, so it can be placed
+// unconditionally in a layout.
+func Banner(s State, opts BannerOptions) h.Builder {
+	if s.Decided {
+		return nil
+	}
+	opts = opts.withDefaults()
+	accepted := State{Decided: true, Granted: opts.Categories}
+	return h.Div(
+		h.Attrs(
+			"id", opts.ID,
+			"role", "dialog",
+			"aria-live", "polite",
+			"aria-label", "Cookie consent",
+			"class", "consent-banner",
+		),
+		h.P(
+			h.Text(opts.Message),
+			h.When(opts.PolicyURL != "", h.Fragment(
+				h.Text(" "),
+				h.A(h.Attrs("href", opts.PolicyURL), h.Text(opts.PolicyLabel)),
+			)),
+		),
+		h.Button(
+			h.Attrs("type", "button", "class", "consent-reject"),
+			js.OnClick(Decide(State{Decided: true}, opts.ID, opts.MaxAge)...),
+			h.Text(opts.RejectLabel),
+		),
+		h.Button(
+			h.Attrs("type", "button", "class", "consent-accept"),
+			js.OnClick(Decide(accepted, opts.ID, opts.MaxAge)...),
+			h.Text(opts.AcceptLabel),
+		),
+	)
+}
+
+// Decide returns client-side statements that store s in the consent cookie,
+// activate gated scripts for the granted categories, and remove the banner
+// element with bannerID (if non-empty).
+func Decide(s State, bannerID string, maxAge time.Duration) []js.Stmt {
+	cookie := CookieName + "=" + s.String() +
+		"; path=/; max-age=" + formatSeconds(maxAge) + "; samesite=lax"
+	stmts := []js.Stmt{
+		js.Assign(js.Prop(js.Document, "cookie"), js.String(cookie)),
+		Activate(s.Granted...),
+	}
+	if bannerID != "" {
+		stmts = append(stmts, js.ExprStmt(js.OptionalCall(js.GetElementById(js.String(bannerID)), "remove")))
+	}
+	return stmts
+}
+
+// Activate returns a statement that replaces inert h.GatedScript placeholders
+// for the given categories with live script elements. Attributes other than
+// the gating attributes (e.g., async, defer) are copied to the new element.
+func Activate(categories ...Category) js.Stmt {
+	names := make([]js.Expr, 0, len(categories)+1)
+	names = append(names, js.String(string(Necessary)))
+	for _, c := range categories {
+		names = append(names, js.String(string(c)))
+	}
+
+	el := js.Ident("el")
+	s := js.Ident("s")
+	a := js.Ident("a")
+	dataset := js.Prop(el, "dataset")
+	gatingAttrs := js.Array(js.String("type"), js.String("data-consent"), js.String("data-src"), js.String("data-type"))
+
+	copyAttrs := js.ExprStmt(js.Method(
+		js.Method(js.Array_, "from", js.Prop(el, "attributes")),
+		"forEach",
+		js.ArrowFuncStmts([]string{"a"},
+			js.If(js.Not(js.Method(gatingAttrs, "includes", js.Prop(a, "name"))),
+				js.ExprStmt(js.Method(s, "setAttribute", js.Prop(a, "name"), js.Prop(a, "value"))),
+			),
+		),
+	))
+
+	return js.ExprStmt(js.Method(
+		js.QuerySelectorAll(js.String(`script[type="text/plain"][data-consent]`)),
+		"forEach",
+		js.ArrowFuncStmts([]string{"el"},
+			js.If(js.Method(js.Array(names...), "includes", js.Prop(dataset, "consent")),
+				js.Const("s", js.CreateElement(js.String("script"))),
+				copyAttrs,
+				js.If(js.Prop(dataset, "type"), js.Assign(js.Prop(s, "type"), js.Prop(dataset, "type"))),
+				js.If(js.Prop(dataset, "src"), js.Assign(js.Prop(s, "src"), js.Prop(dataset, "src"))),
+				js.Assign(js.Prop(s, "text"), js.Prop(el, "text")),
+				js.ExprStmt(js.Method(el, "replaceWith", s)),
+			),
+		),
+	))
+}
+
+func formatSeconds(d time.Duration) string {
+	return js.ToJS(js.Int64(int64(d / time.Second)))
+}
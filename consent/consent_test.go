@@ -0,0 +1,114 @@
+package consent
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+func TestParseAndString(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		allows   []Category
+		denies   []Category
+		expected string
+	}{
+		{"reject all", "necessary", []Category{Necessary}, []Category{Analytics, Marketing}, "necessary"},
+		{"analytics", "necessary|analytics", []Category{Necessary, Analytics}, []Category{Marketing}, "necessary|analytics"},
+		{"duplicates and spaces", "analytics| analytics|marketing", []Category{Analytics, Marketing}, nil, "necessary|analytics|marketing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Parse(tt.value)
+			if !s.Decided {
+				t.Error("expected Decided")
+			}
+			for _, c := range tt.allows {
+				if !s.Allows(c) {
+					t.Errorf("expected %q to be allowed", c)
+				}
+			}
+			for _, c := range tt.denies {
+				if s.Allows(c) {
+					t.Errorf("expected %q to be denied", c)
+				}
+			}
+			if got := s.String(); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if s := FromRequest(r); s.Decided {
+		t.Error("expected undecided state without cookie")
+	}
+	r.AddCookie(State{Decided: true, Granted: []Category{Marketing}}.Cookie())
+	s := FromRequest(r)
+	if !s.Decided || !s.Allows(Marketing) || s.Allows(Analytics) {
+		t.Errorf("unexpected state: %+v", s)
+	}
+}
+
+func TestSetCookie(t *testing.T) {
+	rec := httptest.NewRecorder()
+	SetCookie(rec, State{Decided: true, Granted: []Category{Analytics}})
+	got := rec.Header().Get("Set-Cookie")
+	if !strings.HasPrefix(got, "consent=necessary|analytics; Path=/; Max-Age=15552000") {
+		t.Errorf("Set-Cookie = %q", got)
+	}
+}
+
+func TestScript(t *testing.T) {
+	src := "https://stats.example.com/s.js"
+	granted := State{Decided: true, Granted: []Category{Analytics}}
+
+	if got := h.RenderString(Script(granted, Analytics, src)); got != `<script src="https://stats.example.com/s.js"></script>` {
+		t.Errorf("granted Script = %q", got)
+	}
+	got := h.RenderString(Script(State{}, Analytics, src))
+	if !strings.Contains(got, `type="text/plain" data-consent="analytics"`) {
+		t.Errorf("ungranted Script = %q", got)
+	}
+}
+
+func TestBanner(t *testing.T) {
+	if b := Banner(State{Decided: true}, BannerOptions{}); b != nil {
+		t.Error("expected nil banner once decided")
+	}
+	got := h.RenderString(Banner(State{}, BannerOptions{
+		PolicyURL:  "/privacy",
+		Categories: []Category{Analytics},
+	}))
+	for _, want := range []string{
+		`id="consent-banner" role="dialog"`,
+		`<a href="/privacy">Privacy policy</a>`,
+		`consent=necessary|analytics; path=/; max-age=15552000; samesite=lax`,
+		`Accept all`,
+		`Reject non-essential`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Banner missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestActivate(t *testing.T) {
+	got := js.ToJSStmt(Activate(Analytics))
+	for _, want := range []string{
+		`document.querySelectorAll("script[type=\"text/plain\"][data-consent]").forEach(el => {`,
+		`if (["necessary", "analytics"].includes(el.dataset.consent))`,
+		`if (el.dataset.type) { s.type = el.dataset.type }`,
+		`el.replaceWith(s)`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Activate missing %q in:\n%s", want, got)
+		}
+	}
+}
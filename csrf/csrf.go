@@ -0,0 +1,84 @@
+// Package csrf carries a CSRF token from the server into rendered pages and
+// back in the requests that h forms, hx attributes, and ds actions make, so
+// each project does not wire the token through by hand.
+//
+// It does not create or check tokens. Take them from the protection the
+// application already uses, such as gorilla/csrf, and store them in the
+// request context with Middleware:
+//
+//	mux.Handle("/", csrf.Middleware(gorillacsrf.Token)(h.Handler(page)))
+//
+//	func page(r *http.Request) h.Builder {
+//	    token := csrf.Token(r.Context())
+//	    return h.Document(h.DocOptions{Title: "Cart", Head: []h.Builder{csrf.Meta(token)}},
+//	        h.Form(h.Attrs("method", "post", "action", "/cart"), csrf.Field(token), ...),
+//	        h.Button(hx.Post("/cart/clear"), hx.CSRFHeaders(token), h.Text("Clear")),
+//	        h.Button(ds.OnClick(ds.Request("/cart/buy").Method("post").CSRFHeader(token)), h.Text("Buy")),
+//	    )
+//	}
+//
+// Pages that are cached without a per-request token can render Meta on each
+// request instead and read it on the client with MetaValue, as
+// hx.CSRFHeadersFromMeta and ds.RequestBuilder.CSRFHeaderFromMeta do.
+package csrf
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+// Names used for the token in headers, forms, and the page.
+const (
+	HeaderName = "X-CSRF-Token" // Request header sent by hx and ds
+	FieldName  = "csrf_token"   // Hidden form field written by Field
+	MetaName   = "csrf-token"   // <meta name> written by Meta
+)
+
+// TokenSource returns the CSRF token for a request.
+type TokenSource func(r *http.Request) string
+
+type tokenKey struct{}
+
+// WithToken returns a copy of ctx carrying token for Token.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// Token returns the token stored in ctx by WithToken or Middleware, or ""
+// if there is none.
+func Token(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+// Middleware stores the token from source in each request's context, where
+// Token finds it.
+func Middleware(source TokenSource) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token := source(r); token != "" {
+				r = r.WithContext(WithToken(r.Context(), token))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Meta creates <meta name="csrf-token" content="token"> for the page head.
+func Meta(token string) h.Builder {
+	return h.Meta(h.Attrs("name", MetaName, "content", token))
+}
+
+// Field creates a hidden form input holding token.
+func Field(token string) h.Builder {
+	return h.Input(h.Attrs("type", "hidden", "name", FieldName, "value", token))
+}
+
+// MetaValue reads the token written by Meta on the client:
+// document.querySelector("meta[name=\"csrf-token\"]")?.content
+func MetaValue() js.Callable {
+	return js.OptionalProp(js.QuerySelector(js.String(`meta[name="`+MetaName+`"]`)), "content")
+}
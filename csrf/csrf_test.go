@@ -0,0 +1,63 @@
+package csrf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	handler := Middleware(func(r *http.Request) string {
+		return r.Header.Get("Cookie")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = Token(r.Context())
+	}))
+
+	tests := []struct {
+		name   string
+		cookie string
+		want   string
+	}{
+		{"token", "abc", "abc"},
+		{"no token", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = "unset"
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != "" {
+				r.Header.Set("Cookie", tt.cookie)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			if got != tt.want {
+				t.Errorf("Token() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilders(t *testing.T) {
+	tests := []struct {
+		name string
+		b    h.Builder
+		want string
+	}{
+		{"meta", Meta(`a"b`), `<meta name="csrf-token" content="a&#34;b"/>`},
+		{"field", Field("abc"), `<input type="hidden" name="csrf_token" value="abc"/>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.RenderString(tt.b); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := js.ToJS(MetaValue()), `document.querySelector("meta[name=\"csrf-token\"]")?.content`; got != want {
+		t.Errorf("MetaValue() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,217 @@
+// Package diff compares two texts and renders the differences with <ins> and
+// <del> elements, for audit logs and version history pages.
+//
+// Inline renders a word-level diff as running text:
+//
+//	diff.Inline("The quick fox", "The slow fox")
+//	// <div class="diff diff-inline">The <del>quick</del><ins>slow</ins> fox</div>
+//
+// SideBySide renders a line-level table with the old text on the left and
+// the new text on the right, highlighting changed words within changed lines.
+//
+// Elements carry diff-* classes for styling; no CSS is included. To compare
+// rendered Builders, render them with h.RenderString first.
+package diff
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Op is the kind of an Edit.
+type Op int
+
+const (
+	Equal  Op = iota // Text present in both
+	Delete           // Text only in the old version
+	Insert           // Text only in the new version
+)
+
+// Edit is one run of text in a diff.
+type Edit struct {
+	Op   Op
+	Text string
+}
+
+// Lines returns the line-level edits that turn old into new. Each Edit holds
+// a single line without its trailing newline.
+func Lines(old, new string) []Edit {
+	return compare(splitLines(old), splitLines(new))
+}
+
+// Words returns the word-level edits that turn old into new. Words,
+// whitespace runs, and punctuation are compared as separate tokens, and
+// adjacent edits of the same kind are merged.
+func Words(old, new string) []Edit {
+	return merge(compare(splitWords(old), splitWords(new)))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func splitWords(s string) []string {
+	var tokens []string
+	class := func(r rune) int {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			return 1
+		case unicode.IsSpace(r):
+			return 2
+		default:
+			return 0 // Each punctuation rune is its own token
+		}
+	}
+	start, prev := 0, -1
+	for i, r := range s {
+		c := class(r)
+		if i > start && (c != prev || c == 0) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prev = c
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func merge(edits []Edit) []Edit {
+	var out []Edit
+	for _, e := range edits {
+		if n := len(out); n > 0 && out[n-1].Op == e.Op {
+			out[n-1].Text += e.Text
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// compare computes a shortest edit script with Myers' algorithm, listing
+// deletions before insertions within each changed region. It uses the
+// linear-space variant, splitting at the middle of an optimal path and
+// recursing, so memory stays proportional to the input size.
+func compare(a, b []string) []Edit {
+	edits := bisect(nil, a, b)
+
+	// Group each changed region's deletions ahead of its insertions.
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			i++
+			continue
+		}
+		j := i
+		for j < len(edits) && edits[j].Op != Equal {
+			j++
+		}
+		slices.SortStableFunc(edits[i:j], func(x, y Edit) int { return int(x.Op) - int(y.Op) })
+		i = j
+	}
+	return edits
+}
+
+// bisect appends the edits that turn a into b.
+func bisect(edits []Edit, a, b []string) []Edit {
+	// Common prefixes and suffixes need no search.
+	p := 0
+	for p < len(a) && p < len(b) && a[p] == b[p] {
+		edits = append(edits, Edit{Equal, a[p]})
+		p++
+	}
+	a, b = a[p:], b[p:]
+	s := 0
+	for s < len(a) && s < len(b) && a[len(a)-1-s] == b[len(b)-1-s] {
+		s++
+	}
+	suffix := a[len(a)-s:]
+	a, b = a[:len(a)-s], b[:len(b)-s]
+
+	switch {
+	case len(a) == 0:
+		for _, t := range b {
+			edits = append(edits, Edit{Insert, t})
+		}
+	case len(b) == 0:
+		for _, t := range a {
+			edits = append(edits, Edit{Delete, t})
+		}
+	default:
+		x, y := middle(a, b)
+		edits = bisect(edits, a[:x], b[:y])
+		edits = bisect(edits, a[x:], b[y:])
+	}
+
+	for _, t := range suffix {
+		edits = append(edits, Edit{Equal, t})
+	}
+	return edits
+}
+
+// middle returns a point on a shortest edit path from a to b found by
+// searching forward from the start and backward from the end at once until
+// the two searches overlap. a and b must both be non-empty and differ in
+// their first and last elements, so the point splits the path into two
+// shorter ones.
+func middle(a, b []string) (x, y int) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	off := maxD + 1
+	fwd := make([]int, 2*off+1) // Furthest x on each diagonal k = x - y
+	rev := make([]int, 2*off+1) // Same, counted back from the ends
+	for i := range fwd {
+		fwd[i], rev[i] = -1, -1
+	}
+	fwd[off+1], rev[off+1] = 0, 0
+	delta := n - m
+	odd := delta%2 != 0
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && fwd[off+k-1] < fwd[off+k+1]) {
+				x = fwd[off+k+1]
+			} else {
+				x = fwd[off+k-1] + 1
+			}
+			y := x - k
+			if x < 0 || y < 0 || x > n || y > m {
+				continue
+			}
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			fwd[off+k] = x
+			if r := delta - k; odd && r >= -(d-1) && r <= d-1 && rev[off+r] >= 0 && x >= n-rev[off+r] {
+				return x, y
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && rev[off+k-1] < rev[off+k+1]) {
+				x = rev[off+k+1]
+			} else {
+				x = rev[off+k-1] + 1
+			}
+			y := x - k
+			if x < 0 || y < 0 || x > n || y > m {
+				continue
+			}
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			rev[off+k] = x
+			if f := delta - k; !odd && f >= -d && f <= d && fwd[off+f] >= 0 && fwd[off+f] >= n-x {
+				return fwd[off+f], fwd[off+f] - f
+			}
+		}
+	}
+	return n, m // Unreachable: the searches meet by maxD
+}
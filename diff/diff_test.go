@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		expected []Edit
+	}{
+		{"identical", "a\nb\n", "a\nb", []Edit{{Equal, "a"}, {Equal, "b"}}},
+		{"empty", "", "", nil},
+		{"all inserted", "", "a\nb", []Edit{{Insert, "a"}, {Insert, "b"}}},
+		{"all deleted", "a", "", []Edit{{Delete, "a"}}},
+		{
+			"replace middle",
+			"a\nb\nc", "a\nx\nc",
+			[]Edit{{Equal, "a"}, {Delete, "b"}, {Insert, "x"}, {Equal, "c"}},
+		},
+		{
+			"insert and delete",
+			"a\nb\nc\nd", "b\nc\ne\nd",
+			[]Edit{{Delete, "a"}, {Equal, "b"}, {Equal, "c"}, {Insert, "e"}, {Equal, "d"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.old, tt.new); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWords(t *testing.T) {
+	got := Words("The quick brown fox.", "The slow brown fox!")
+	expected := []Edit{
+		{Equal, "The "},
+		{Delete, "quick"},
+		{Insert, "slow"},
+		{Equal, " brown fox"},
+		{Delete, "."},
+		{Insert, "!"},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got %v, want %v", got, expected)
+	}
+}
+
+func TestEditsReconstructInputs(t *testing.T) {
+	old := "one two three four five six"
+	new := "zero one three four 4.5 five seven"
+	var a, b strings.Builder
+	for _, e := range Words(old, new) {
+		if e.Op != Insert {
+			a.WriteString(e.Text)
+		}
+		if e.Op != Delete {
+			b.WriteString(e.Text)
+		}
+	}
+	if a.String() != old || b.String() != new {
+		t.Errorf("edits reconstruct %q and %q", a.String(), b.String())
+	}
+}
+
+func TestLinesLargeInput(t *testing.T) {
+	// Entirely different inputs are the worst case for the edit search;
+	// it must run in space linear in the input.
+	var old, new strings.Builder
+	for i := range 5000 {
+		fmt.Fprintf(&old, "old %d\n", i)
+		fmt.Fprintf(&new, "new %d\n", i)
+	}
+	edits := Lines(old.String(), new.String())
+	if len(edits) != 10000 || edits[0] != (Edit{Delete, "old 0"}) || edits[9999] != (Edit{Insert, "new 4999"}) {
+		t.Errorf("got %d edits", len(edits))
+	}
+}
+
+func TestInline(t *testing.T) {
+	got := h.RenderString(Inline("The quick <fox>", "The slow <fox>", h.ID("rev-2")))
+	expected := `<div class="diff diff-inline" id="rev-2">The <del>quick</del><ins>slow</ins> &lt;fox&gt;</div>`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	got := h.RenderString(SideBySide("a\nold line\nb", "a\nnew line\nb\nc"))
+	for _, want := range []string{
+		`<table class="diff diff-split"><tbody>`,
+		`<tr class="diff-equal"><td class="diff-num">1</td><td>a</td><td class="diff-num">1</td><td>a</td></tr>`,
+		`<tr class="diff-change"><td class="diff-num">2</td><td class="diff-delete"><del>old</del> line</td><td class="diff-num">2</td><td class="diff-insert"><ins>new</ins> line</td></tr>`,
+		`<tr class="diff-insert"><td class="diff-num"></td><td class="diff-empty"></td><td class="diff-num">4</td><td class="diff-insert"><ins>c</ins></td></tr>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package diff
+
+import (
+	"strconv"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Inline renders the word-level diff of old and new as running text in a
+// <div class="diff diff-inline">, with removed text in <del> and added text
+// in <ins>. Additional args are applied to the <div>.
+func Inline(old, new string, args ...h.TagArg) h.Builder {
+	return h.Div(append(append([]h.TagArg{h.Class("diff diff-inline")}, args...), words(Words(old, new), true, true))...)
+}
+
+// SideBySide renders the line-level diff of old and new as a
+// <table class="diff diff-split"> with line numbers, the old text on the
+// left, and the new text on the right. Lines replaced one-for-one are shown
+// on the same row with word-level highlights. Additional args are applied
+// to the <table>.
+func SideBySide(old, new string, args ...h.TagArg) h.Builder {
+	edits := Lines(old, new)
+	var rows []h.Builder
+	oldNum, newNum := 0, 0
+	for i := 0; i < len(edits); {
+		if edits[i].Op == Equal {
+			oldNum++
+			newNum++
+			rows = append(rows, h.Tr(h.Class("diff-equal"),
+				num(oldNum), h.Td(h.Text(edits[i].Text)),
+				num(newNum), h.Td(h.Text(edits[i].Text)),
+			))
+			i++
+			continue
+		}
+
+		var dels, ins []string
+		for ; i < len(edits) && edits[i].Op == Delete; i++ {
+			dels = append(dels, edits[i].Text)
+		}
+		for ; i < len(edits) && edits[i].Op == Insert; i++ {
+			ins = append(ins, edits[i].Text)
+		}
+		for j := range max(len(dels), len(ins)) {
+			var left, right []h.Builder
+			class := "diff-change"
+			switch {
+			case j < len(dels) && j < len(ins):
+				oldNum++
+				newNum++
+				ws := Words(dels[j], ins[j])
+				left = []h.Builder{num(oldNum), h.Td(h.Class("diff-delete"), words(ws, true, false))}
+				right = []h.Builder{num(newNum), h.Td(h.Class("diff-insert"), words(ws, false, true))}
+			case j < len(dels):
+				oldNum++
+				class = "diff-delete"
+				left = []h.Builder{num(oldNum), h.Td(h.Class("diff-delete"), h.Del(h.Text(dels[j])))}
+				right = []h.Builder{h.Td(h.Class("diff-num")), h.Td(h.Class("diff-empty"))}
+			default:
+				newNum++
+				class = "diff-insert"
+				left = []h.Builder{h.Td(h.Class("diff-num")), h.Td(h.Class("diff-empty"))}
+				right = []h.Builder{num(newNum), h.Td(h.Class("diff-insert"), h.Ins(h.Text(ins[j])))}
+			}
+			rows = append(rows, h.Tr(h.Class(class), h.Fragment(left...), h.Fragment(right...)))
+		}
+	}
+	return h.Table(append(append([]h.TagArg{h.Class("diff diff-split")}, args...), h.Tbody(h.Fragment(rows...)))...)
+}
+
+// words renders word-level edits, keeping deletions and insertions as requested.
+func words(edits []Edit, deletions, insertions bool) h.Builder {
+	var out []h.Builder
+	for _, e := range edits {
+		switch {
+		case e.Op == Equal:
+			out = append(out, h.Text(e.Text))
+		case e.Op == Delete && deletions:
+			out = append(out, h.Del(h.Text(e.Text)))
+		case e.Op == Insert && insertions:
+			out = append(out, h.Ins(h.Text(e.Text)))
+		}
+	}
+	return h.Fragment(out...)
+}
+
+func num(n int) h.Builder {
+	return h.Td(h.Class("diff-num"), h.Text(strconv.Itoa(n)))
+}
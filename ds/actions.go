@@ -3,9 +3,12 @@ package ds
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/jeffh/htmlgen/csrf"
 	"github.com/jeffh/htmlgen/js"
 )
 
@@ -170,25 +173,70 @@ func (b RequestOptionsBuilder) Selector(sel string) RequestOptionsBuilder {
 	return b
 }
 
-// Headers sets custom HTTP headers for the request.
+// Headers sets custom HTTP headers for the request, in key order. Headers
+// from repeated calls and CSRFHeader are merged into one headers option.
 func (b RequestOptionsBuilder) Headers(headers map[string]string) RequestOptionsBuilder {
-	b.options = append(b.options, requestOptionFunc(func(sb *strings.Builder) {
-		sb.WriteString("headers: {")
-		i := 0
-		for k, v := range headers {
-			if i > 0 {
-				sb.WriteString(", ")
-			}
-			sb.WriteString(strconv.Quote(k))
-			sb.WriteString(": ")
-			sb.WriteString(strconv.Quote(v))
-			i++
+	pairs := make([]js.KV, 0, len(headers))
+	for _, k := range slices.Sorted(maps.Keys(headers)) {
+		pairs = append(pairs, js.Pair(k, js.String(headers[k])))
+	}
+	return b.withHeaders(pairs...)
+}
+
+// CSRFHeader sends token in the csrf.HeaderName header.
+func (b RequestOptionsBuilder) CSRFHeader(token string) RequestOptionsBuilder {
+	return b.withHeaders(js.Pair(csrf.HeaderName, js.String(token)))
+}
+
+// CSRFHeaderFromMeta sends the token from the page's csrf.Meta tag in the
+// csrf.HeaderName header, reading it when the request is made.
+func (b RequestOptionsBuilder) CSRFHeaderFromMeta() RequestOptionsBuilder {
+	return b.withHeaders(js.Pair(csrf.HeaderName, csrf.MetaValue()))
+}
+
+// withHeaders adds pairs to the headers option, replacing earlier values
+// for the same header.
+func (b RequestOptionsBuilder) withHeaders(pairs ...js.KV) RequestOptionsBuilder {
+	i := slices.IndexFunc(b.options, func(opt RequestOption) bool {
+		_, ok := opt.(headersOption)
+		return ok
+	})
+	if i < 0 {
+		b.options = append(b.options, headersOption{pairs})
+		return b
+	}
+	merged := slices.Clone(b.options[i].(headersOption).pairs)
+	for _, p := range pairs {
+		j := slices.IndexFunc(merged, func(kv js.KV) bool { return strings.EqualFold(kv.Key, p.Key) })
+		if j < 0 {
+			merged = append(merged, p)
+		} else {
+			merged[j] = p
 		}
-		sb.WriteString("}")
-	}))
+	}
+	b.options = slices.Clone(b.options)
+	b.options[i] = headersOption{merged}
 	return b
 }
 
+// headersOption writes the headers request option.
+type headersOption struct {
+	pairs []js.KV
+}
+
+func (o headersOption) appendOption(sb *strings.Builder) {
+	sb.WriteString("headers: {")
+	for i, kv := range o.pairs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(js.ToJS(js.String(kv.Key)))
+		sb.WriteString(": ")
+		sb.WriteString(js.ToJS(kv.Value))
+	}
+	sb.WriteString("}")
+}
+
 // OpenWhenHidden keeps the connection alive when the tab is hidden.
 func (b RequestOptionsBuilder) OpenWhenHidden(open bool) RequestOptionsBuilder {
 	b.options = append(b.options, requestOptionFunc(func(sb *strings.Builder) {
@@ -293,3 +341,40 @@ func OnSuccess(expr Value) PromiseChain {
 func OnFailure(expr Value) PromiseChain {
 	return CatchChain(expr.expr)
 }
+
+// All runs actions concurrently and returns a Value resolving when every
+// action has finished, so one handler can refresh several endpoints.
+// Example: OnClick(All(Get("/cart"), Get("/recommendations")))
+// Produces: Promise.all([@get("/cart"), @get("/recommendations")])
+func All(actions ...Value) Value {
+	exprs := make([]js.Expr, len(actions))
+	for i, a := range actions {
+		exprs[i] = a.expr
+	}
+	return V(js.PromiseAll(js.Array(exprs...)))
+}
+
+// Sequence runs actions one after another, starting each when the previous
+// one has finished, for requests that depend on earlier ones.
+// Example: OnClick(Sequence(Post("/cart/items"), Get("/cart")))
+// Produces: @post("/cart/items").then(() => @get("/cart"))
+// An empty Sequence resolves immediately.
+func Sequence(actions ...Value) Value {
+	if len(actions) == 0 {
+		return V(js.PromiseResolve(js.Undefined()))
+	}
+	chain := callable(actions[0].expr)
+	for _, a := range actions[1:] {
+		chain = js.PromiseThen(chain, js.ArrowFunc(nil, a.expr))
+	}
+	return V(chain)
+}
+
+// callable returns expr as a js.Callable, parenthesizing it if it cannot
+// be called or have methods invoked on it directly.
+func callable(expr js.Expr) js.Callable {
+	if c, ok := expr.(js.Callable); ok {
+		return c
+	}
+	return js.Raw("(" + js.ToJS(expr) + ")")
+}
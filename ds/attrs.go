@@ -1,6 +1,7 @@
 package ds
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/jeffh/htmlgen/h"
@@ -55,6 +56,32 @@ func OnChange(options ...AttrMutator) h.Attribute { return exprAttr("data-on:cha
 // The action will be encoded as a JavaScript expression.
 func OnClick(options ...AttrMutator) h.Attribute { return exprAttr("data-on:click", options...) }
 
+// Sets an action to be executed when a key is pressed.
+// Use Key or KeyCombo to filter by key.
+func OnKeyDown(options ...AttrMutator) h.Attribute { return exprAttr("data-on:keydown", options...) }
+
+// Sets an action to be executed when a key is released.
+// Use Key or KeyCombo to filter by key.
+func OnKeyUp(options ...AttrMutator) h.Attribute { return exprAttr("data-on:keyup", options...) }
+
+// Sets an action to be executed when a click lands outside the element,
+// for dismissing dropdowns and popovers: data-on:click__outside
+func OnClickOutside(options ...AttrMutator) h.Attribute {
+	return exprAttr("data-on:click", append([]AttrMutator{Outside()}, options...)...)
+}
+
+// Sets an action to be executed when Escape is pressed anywhere on the page,
+// for closing modals and menus: data-on:keydown__window__key.escape
+func OnEscape(options ...AttrMutator) h.Attribute {
+	return exprAttr("data-on:keydown", append([]AttrMutator{Window(), Key("Escape")}, options...)...)
+}
+
+// Sets an action to be executed when Enter is pressed while the element
+// has focus: data-on:keydown__key.enter
+func OnEnter(options ...AttrMutator) h.Attribute {
+	return exprAttr("data-on:keydown", append([]AttrMutator{Key("Enter")}, options...)...)
+}
+
 // Sets an action to be executed when the element is loaded.
 // The action will be encoded as a JavaScript expression.
 func OnLoad(options ...AttrMutator) h.Attribute { return exprAttr("data-on:load", options...) }
@@ -67,7 +94,8 @@ func On(eventName string, options ...AttrMutator) h.Attribute {
 }
 
 // OnIntersect runs an expression when the element intersects the viewport.
-// Use Half() for 50% visibility, Full() for 100% visibility.
+// Use Half() for 50% visibility, Full() for 100% visibility, Threshold() for
+// any other fraction, and RootMargin() to fire before the element is visible.
 // Example: OnIntersect(Once(), Raw("$seen = true"))
 // Produces: data-on-intersect__once="$seen = true"
 func OnIntersect(options ...AttrMutator) h.Attribute {
@@ -104,7 +132,7 @@ func OnSignalPatchFilter(options *FilterOptions) h.Attribute {
 // The signal's default value will be appended to the attribute name.
 // The signal name will automatically be prefixed with "$".
 func SignalExpr(name string, defaultExpression Value) h.Attribute {
-	return exprAttr("data-signals:", appendName(name), AttrFunc(func(attr *attrBuilder) {
+	return exprAttr("data-signals:", signalKeyName(name), AttrFunc(func(attr *attrBuilder) {
 		attr.name.WriteString(js.ToJS(defaultExpression.expr))
 	}))
 }
@@ -112,8 +140,11 @@ func SignalExpr(name string, defaultExpression Value) h.Attribute {
 // Signal defines a signal with a default value.
 // The signal's default value will be encoded as a JSON value.
 // The signal name will automatically be prefixed with "$".
+// camelCase names are written in kebab-case, which Datastar converts back:
+// Signal("userName", "") produces data-signals:user-name and defines $userName.
+// Panics with ErrInvalidSignalName if name is invalid; see TrySignal.
 func Signal(name string, defaultJsValue any) h.Attribute {
-	return exprAttr("data-signals:", appendName(name), JsonValue(defaultJsValue))
+	return exprAttr("data-signals:", signalKeyName(name), JsonValue(defaultJsValue))
 }
 
 // Signals defines multiple signals with default values using object syntax.
@@ -128,16 +159,69 @@ func Signals(signals map[string]any) h.Attribute {
 // Updates to the element will be reflected in the signal.
 // The signal name will automatically be prefixed with "$".
 func Bind(signalName string) h.Attribute {
+	if err := ValidateSignalName(signalName); err != nil {
+		panic(err)
+	}
 	return exprAttr("data-bind", Raw(signalName))
 }
 
 // Class sets a class to be used as the value of the element.
 // Updates to the class will be reflected in the element.
+//
+// Deprecated: Class writes the name without a colon, producing
+// data-classactive for Class("active", ...). Use ClassKey.
 func Class(clsName string, value ...AttrMutator) h.Attribute {
 	value = append(value, appendName(clsName))
 	return exprAttr("data-class", value...)
 }
 
+// ClassKey toggles a single CSS class on the element when the expression is
+// truthy.
+// Example: ClassKey("font-bold", Raw("$isActive"))
+// Produces: data-class:font-bold="$isActive"
+// Panics if name is empty or contains whitespace or uppercase letters, which
+// HTML lowercases in attribute names; use ClassesExpr for those.
+func ClassKey(name string, expression ...AttrMutator) h.Attribute {
+	if name == "" || strings.ToLower(name) != name || strings.ContainsAny(name, " \t\n\r\f") {
+		panic(fmt.Sprintf("invalid class name %q", name))
+	}
+	opts := append([]AttrMutator{appendName(name)}, expression...)
+	return exprAttr("data-class:", opts...)
+}
+
+// ClassPair is a CSS class and the expression that toggles it, for
+// ClassesExpr.
+type ClassPair struct {
+	Name string
+	Expr Value
+}
+
+// ClassesExpr toggles several CSS classes using object syntax. Unlike
+// Classes, the expressions are written as JavaScript rather than strings,
+// and each class name is a quoted key, so hyphenated and mixed-case names
+// work. Pairs are written in order.
+// Example: ClassesExpr(ClassPair{"hidden", Raw("$foo")}, ClassPair{"font-bold", Raw("$bar")})
+// Produces: data-class="{\"hidden\": $foo, \"font-bold\": $bar}"
+// Panics if a name is empty or repeated, or has no expression.
+func ClassesExpr(pairs ...ClassPair) h.Attribute {
+	kvs := make([]js.KV, len(pairs))
+	for i, p := range pairs {
+		if p.Name == "" {
+			panic("class name cannot be empty")
+		}
+		if p.Expr.expr == nil {
+			panic(fmt.Sprintf("class %q has no expression", p.Name))
+		}
+		for _, prev := range pairs[:i] {
+			if prev.Name == p.Name {
+				panic(fmt.Sprintf("duplicate class name %q", p.Name))
+			}
+		}
+		kvs[i] = js.Pair(p.Name, p.Expr.expr)
+	}
+	return exprAttr("data-class", V(js.Object(kvs...)))
+}
+
 // Text sets the text of the element to be the value of the signal.
 // Updates to the signal will be reflected in the element.
 func Text(value ...AttrMutator) h.Attribute {
@@ -189,15 +273,13 @@ func Peek(action Value) Value {
 // Example: Computed("total", Raw("$price * $quantity"))
 // Produces: data-computed:total="$price * $quantity"
 func Computed(name string, expression Value) h.Attribute {
-	return exprAttr("data-computed:", appendName(name), AttrFunc(func(attr *attrBuilder) {
-		attr.name.WriteString(js.ToJS(expression.expr))
-	}))
+	return exprAttr("data-computed:", signalKeyName(name), expression)
 }
 
 // ComputedExpr creates a computed signal with modifiers.
 // Example: ComputedExpr("total", Case(CamelCase), Raw("$price * $quantity"))
 func ComputedExpr(name string, options ...AttrMutator) h.Attribute {
-	opts := append([]AttrMutator{appendName(name)}, options...)
+	opts := append([]AttrMutator{signalKeyName(name)}, options...)
 	return exprAttr("data-computed:", opts...)
 }
 
@@ -210,20 +292,35 @@ func Init(options ...AttrMutator) h.Attribute {
 
 // Ref creates a signal referencing a DOM element.
 // Example: Ref("myElement")
-// Produces: data-ref:myElement
+// Produces: data-ref:my-element (referenced as $myElement)
 func Ref(signalName string, options ...AttrMutator) h.Attribute {
-	opts := append([]AttrMutator{appendName(signalName)}, options...)
+	opts := append([]AttrMutator{signalKeyName(signalName)}, options...)
 	return exprAttr("data-ref:", opts...)
 }
 
 // Style sets an inline CSS style property reactively.
 // Example: Style("background-color", Raw("$isRed ? 'red' : 'blue'"))
 // Produces: data-style:background-color="$isRed ? 'red' : 'blue'"
+// Panics with ErrInvalidStyleProperty if property is invalid; see
+// ValidateStyleProperty.
 func Style(property string, expression ...AttrMutator) h.Attribute {
+	if err := ValidateStyleProperty(property); err != nil {
+		panic(err)
+	}
 	opts := append([]AttrMutator{appendName(property)}, expression...)
 	return exprAttr("data-style:", opts...)
 }
 
+// StyleKey sets one inline CSS style property to a JavaScript expression.
+// Example: StyleKey(StyleOpacity, js.Ternary(js.Raw("$visible"), js.Int(1), js.Int(0)))
+// Produces: data-style:opacity="$visible ? 1 : 0"
+// Panics with ErrInvalidStyleProperty if property is invalid; see
+// ValidateStyleProperty.
+func StyleKey(property StyleProp, value js.Expr, options ...AttrMutator) h.Attribute {
+	opts := append(options[:len(options):len(options)], V(value))
+	return Style(string(property), opts...)
+}
+
 // Styles sets multiple inline CSS styles reactively using object syntax.
 // Example: Styles(map[string]string{"display": "$hidden ? 'none' : 'block'", "color": "$red ? 'red' : 'green'"})
 func Styles(styles map[string]string) h.Attribute {
@@ -273,7 +370,7 @@ func JsonSignalsDebug(options *FilterOptions, modifiers ...AttrMutator) h.Attrib
 // Example: BindKey("foo", Case(CamelCase))
 // Produces: data-bind:foo__case.camel
 func BindKey(signalName string, options ...AttrMutator) h.Attribute {
-	opts := append([]AttrMutator{appendName(signalName)}, options...)
+	opts := append([]AttrMutator{signalKeyName(signalName)}, options...)
 	return exprAttr("data-bind:", opts...)
 }
 
@@ -282,7 +379,7 @@ func BindKey(signalName string, options ...AttrMutator) h.Attribute {
 // Produces: data-indicator:fetching__case.camel
 func IndicatorKey(signalName string, options ...AttrMutator) h.Attribute {
 	signalName = strings.TrimLeft(signalName, "$")
-	opts := append([]AttrMutator{appendName(signalName)}, options...)
+	opts := append([]AttrMutator{signalKeyName(signalName)}, options...)
 	return exprAttr("data-indicator:", opts...)
 }
 
@@ -19,6 +19,7 @@ func (f AttrFunc) Modify(attr *attrBuilder) { f(attr) }
 type attrBuilder struct {
 	name       strings.Builder
 	statements []string
+	hints      []string // Ordering hints written as a leading comment
 }
 
 // AppendStatement adds a JavaScript statement to the attribute value.
@@ -44,8 +45,13 @@ func buildAttr(name string, options ...AttrMutator) *attrBuilder {
 }
 
 // exprAttr builds an h.Attribute from a base name and mutators.
-// Statements are joined with "; " as the attribute value.
+// Statements are joined with "; " as the attribute value, after a comment
+// holding any ordering hints (see DependsOn).
 func exprAttr(name string, options ...AttrMutator) h.Attribute {
 	attr := buildAttr(name, options...)
-	return h.Attr(attr.name.String(), strings.Join(attr.statements, "; "))
+	value := strings.Join(attr.statements, "; ")
+	if len(attr.hints) > 0 {
+		value = "/* " + strings.Join(attr.hints, "; ") + " */ " + value
+	}
+	return h.Attr(attr.name.String(), value)
 }
@@ -0,0 +1,159 @@
+// Package components provides ready-made Datastar widgets that compose ds
+// signals, Show, and event handlers into h.Builder fragments: a modal
+// dialog, a dropdown menu, and tabs.
+//
+// Each component declares the signal it is driven by, so other elements
+// can open or inspect it by name:
+//
+//	h.Div(
+//	    h.Button(ds.OnClick(components.Open("confirm")), h.Text("Delete…")),
+//	    components.Modal("confirm",
+//	        h.P(h.Text("Delete this item?")),
+//	        h.Button(ds.OnClick(components.Close("confirm")), h.Text("Cancel")),
+//	    ),
+//	)
+//
+// Components render with class names (modal, dropdown, tabs, ...) and ARIA
+// roles but no styles. Hidden parts carry style="display: none" so they do
+// not flash before Datastar initializes.
+package components
+
+import (
+	"github.com/jeffh/htmlgen/ds"
+	"github.com/jeffh/htmlgen/h"
+)
+
+// hidden keeps an element hidden until data-show takes over.
+var hidden = h.Attr("style", "display: none")
+
+// Open returns an expression that opens the Modal or Dropdown driven by
+// signal: $confirm = true
+func Open(signal string) ds.Value { return ds.NewSignal(signal, false).Set(true) }
+
+// Close returns an expression that closes the Modal or Dropdown driven by
+// signal: $confirm = false
+func Close(signal string) ds.Value { return ds.NewSignal(signal, false).Set(false) }
+
+// Toggle returns an expression that toggles the Modal or Dropdown driven by
+// signal: $confirm = !$confirm
+func Toggle(signal string) ds.Value { return ds.NewSignal(signal, false).Toggle() }
+
+// Modal renders a dialog shown while the boolean signal is true. Clicking
+// the backdrop or pressing Escape closes it. args are applied to the
+// dialog panel, so they may be attributes or content (a Class in args is
+// added to modal-dialog):
+//
+//	components.Modal("settings", h.Attr("aria-labelledby", "settings-title"), h.H2(h.Attr("id", "settings-title"), h.Text("Settings")), form)
+//
+// Renders:
+//
+//	<div class="modal" data-signals:settings="false" data-show="$settings" data-on:keydown__window__key.escape="$settings = false" style="display: none">
+//	  <div class="modal-backdrop" data-on:click="$settings = false"></div>
+//	  <div class="modal-dialog" role="dialog" aria-modal="true">...</div>
+//	</div>
+func Modal(signal string, args ...h.TagArg) h.Builder {
+	open := ds.NewSignal(signal, false)
+	return h.Div(
+		h.Attributes{
+			h.Class("modal"),
+			open.Attr(),
+			ds.Show(open.Ref()),
+			ds.OnEscape(open.Set(false)),
+			hidden,
+		},
+		h.Div(h.Class("modal-backdrop"), ds.OnClick(open.Set(false))),
+		h.Div(append([]h.TagArg{h.Class("modal-dialog"), h.Attrs("role", "dialog", "aria-modal", "true")}, args...)...),
+	)
+}
+
+// Dropdown renders a button that toggles a menu while the boolean signal
+// is true. Clicking outside the dropdown or pressing Escape closes it.
+// menu is applied to the menu element:
+//
+//	components.Dropdown("account", h.Text("Account"),
+//	    h.A(h.Href("/profile"), h.Attr("role", "menuitem"), h.Text("Profile")),
+//	    h.A(h.Href("/logout"), h.Attr("role", "menuitem"), h.Text("Log out")),
+//	)
+func Dropdown(signal string, label h.Builder, menu ...h.TagArg) h.Builder {
+	open := ds.NewSignal(signal, false)
+	return h.Div(
+		h.Attributes{
+			h.Class("dropdown"),
+			open.Attr(),
+			ds.OnClickOutside(open.Set(false)),
+			ds.OnEscape(open.Set(false)),
+		},
+		h.Button(
+			h.Attributes{
+				h.Attr("type", "button"),
+				h.Class("dropdown-toggle"),
+				h.Attr("aria-haspopup", "menu"),
+				h.Attr("aria-expanded", "false"),
+				ds.Attribute("aria-expanded", open.Ref()),
+				ds.OnClick(open.Toggle()),
+			},
+			label,
+		),
+		h.Div(append([]h.TagArg{h.Class("dropdown-menu"), h.Attr("role", "menu"), ds.Show(open.Ref()), hidden}, menu...)...),
+	)
+}
+
+// Tab is one tab of Tabs.
+type Tab struct {
+	ID    string    // Value of the signal while the tab is selected; unique within the Tabs
+	Label h.Builder // Content of the tab button
+	Panel h.Builder // Content shown while the tab is selected
+}
+
+// Tabs renders a tab list and panels driven by a string signal holding the
+// selected Tab's ID. The first tab is selected initially:
+//
+//	components.Tabs("section",
+//	    components.Tab{ID: "profile", Label: h.Text("Profile"), Panel: profileForm},
+//	    components.Tab{ID: "billing", Label: h.Text("Billing"), Panel: billingForm},
+//	)
+//
+// Tab buttons and panels get ids derived from the signal and tab ID
+// (section-tab-profile, section-panel-profile) linking them for assistive
+// technology.
+func Tabs(signal string, tabs ...Tab) h.Builder {
+	if len(tabs) == 0 {
+		return nil
+	}
+	selected := ds.NewSignal(signal, tabs[0].ID)
+	list := make([]h.TagArg, 0, len(tabs)+2)
+	list = append(list, h.Class("tabs-list"), h.Attr("role", "tablist"))
+	panels := make([]h.Builder, 0, len(tabs))
+	for i, tab := range tabs {
+		tabID := signal + "-tab-" + tab.ID
+		panelID := signal + "-panel-" + tab.ID
+		isSelected := ds.V(ds.Eq(selected.Expr(), ds.Str(tab.ID)))
+		initial := "false"
+		if i == 0 {
+			initial = "true"
+		}
+		list = append(list, h.Button(
+			h.Attributes{
+				h.Attr("type", "button"),
+				h.Attr("role", "tab"),
+				h.Attr("id", tabID),
+				h.Attr("aria-controls", panelID),
+				h.Attr("aria-selected", initial),
+				ds.Attribute("aria-selected", isSelected),
+				ds.OnClick(selected.Set(tab.ID)),
+			},
+			tab.Label,
+		))
+		panel := h.Attributes{
+			h.Attr("role", "tabpanel"),
+			h.Attr("id", panelID),
+			h.Attr("aria-labelledby", tabID),
+			ds.Show(isSelected),
+		}
+		if i > 0 {
+			panel = append(panel, hidden)
+		}
+		panels = append(panels, h.Div(panel, tab.Panel))
+	}
+	return h.Div(h.Class("tabs"), selected.Attr(), h.Div(list...), h.Fragment(panels...))
+}
@@ -0,0 +1,70 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/ds"
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestComponents(t *testing.T) {
+	tabs := Tabs("sec",
+		Tab{ID: "a", Label: h.Text("A"), Panel: h.Text("panel a")},
+		Tab{ID: "b", Label: h.Text("B"), Panel: h.Text("panel b")},
+	)
+	tests := []struct {
+		name     string
+		b        h.Builder
+		contains []string
+	}{
+		{
+			"modal",
+			Modal("confirm", h.Attr("aria-labelledby", "t"), h.P(h.Text("Sure?"))),
+			[]string{
+				`<div class="modal" data-signals:confirm="false" data-show="$confirm" data-on:keydown__window__key.escape="$confirm = false" style="display: none">`,
+				`<div class="modal-backdrop" data-on:click="$confirm = false"></div>`,
+				`<div class="modal-dialog" role="dialog" aria-modal="true" aria-labelledby="t"><p>Sure?</p></div>`,
+			},
+		},
+		{
+			"dropdown",
+			Dropdown("menu", h.Text("Menu"), h.A(h.Href("/p"), h.Text("P"))),
+			[]string{
+				`data-signals:menu="false" data-on:click__outside="$menu = false" data-on:keydown__window__key.escape="$menu = false"`,
+				`aria-expanded="false" data-attr:aria-expanded="$menu" data-on:click="$menu = !$menu">Menu</button>`,
+				`<div class="dropdown-menu" role="menu" data-show="$menu" style="display: none"><a href="/p">P</a></div>`,
+			},
+		},
+		{
+			"tabs",
+			tabs,
+			[]string{
+				`<div class="tabs" data-signals:sec="&#34;a&#34;">`,
+				`id="sec-tab-a" aria-controls="sec-panel-a" aria-selected="true" data-attr:aria-selected="$sec === &#34;a&#34;" data-on:click="$sec = &#34;a&#34;">A</button>`,
+				`id="sec-tab-b" aria-controls="sec-panel-b" aria-selected="false"`,
+				`<div role="tabpanel" id="sec-panel-a" aria-labelledby="sec-tab-a" data-show="$sec === &#34;a&#34;">panel a</div>`,
+				`data-show="$sec === &#34;b&#34;" style="display: none">panel b</div>`,
+			},
+		},
+		{
+			"controls",
+			h.Div(ds.OnClick(Open("m")), ds.OnInput(Close("m")), ds.OnChange(Toggle("m"))),
+			[]string{`data-on:click="$m = true"`, `="$m = false"`, `data-on:change="$m = !$m"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := h.RenderString(tt.b)
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing %q in\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	if Tabs("empty") != nil {
+		t.Error("Tabs with no tabs should render nothing")
+	}
+}
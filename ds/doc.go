@@ -1,14 +1,36 @@
 // Package ds provides helpers for building Datastar (https://data-star.dev/) reactive attributes.
 //
+// Attribute helpers take AttrMutators. Modifiers such as Debounce and Once
+// extend the attribute name; a Value becomes the attribute's JavaScript
+// expression. Values wrap js.Expr, so expressions from the js package, the
+// methods of a typed SignalHandle, and HTTP actions with OnSuccess and
+// OnFailure promise chains all combine:
+//
+//	count := ds.NewSignal("count", 0)
+//	h.Div(
+//	    count.Attr(),                                  // data-signals:count="0"
+//	    h.Span(ds.Text(count.Ref())),                  // data-text="$count"
+//	    h.Button(ds.OnClick(count.Incr())),            // data-on:click="$count++"
+//	    h.Button(ds.OnClick(ds.Post("/save", ds.OnSuccess(count.Set(0))))),
+//	)
+//
 // This package includes:
-//   - Signal management: Signal, Signals, Computed, Bind, BindKey
-//   - Event handlers: On, OnClick, OnSubmit, OnInput, OnChange, OnLoad, OnIntersect, OnInterval, OnSignalPatch
-//   - Reactive display: Show, Text, Class, Classes, Style, Styles, Attribute, Attrs
+//   - Signal management: Signal, TrySignal, ValidateSignalName, Signals, SignalPath, SignalsNested, Computed, Bind, BindKey, and typed
+//     handles from NewSignal or from a view-model struct via SignalsFromStruct
+//   - Event handlers: On, OnClick, OnSubmit, OnInput, OnChange, OnKeyDown, OnKeyUp, OnClickOutside, OnEscape, OnEnter, OnLoad, OnIntersect, OnInterval, OnSignalPatch
+//   - Reactive display: Show, Text, ClassKey, ClassesExpr, Classes, Style, StyleKey, Styles, Attribute, Attrs
 //   - DOM control: Ref, Indicator, IndicatorKey, Ignore, IgnoreSelf, IgnoreMorph, PreserveAttr, Effect, Init
-//   - HTTP actions: Get, Post, Put, Patch, Delete (and Dynamic variants)
-//   - HTTP options: RequestOptions, ContentType, FilterSignals, Headers, OpenWhenHidden, retry config
+//   - HTTP actions: Get, Post, Put, Patch, Delete (and Dynamic variants), combined with All and Sequence
+//   - Promise chains: OnSuccess, OnFailure, OnSuccessSetSignal, and Then, Catch, and Finally with js callbacks
+//   - HTTP options: Request (fluent builder), RequestOptions, ContentType, FilterSignals, Headers, OpenWhenHidden, retry config
 //   - Core actions: Peek, SetAll, ToggleAll
-//   - Modifiers: Debounce, Throttle, Delay, Duration, Once, PreventDefault, ViewTransition, etc.
+//   - Run-once guards: OncePerSession, OncePerBrowser
+//   - Initialization order: DependsOn and Priority hints for Effect and Init,
+//     checked by ValidateInitOrder
+//   - Tooling: ExtractSignals reports the signals an attribute reads and writes
+//   - js integration: SignalIdent, V, E, and Stmts build attribute values
+//     from typed js expressions and statements
+//   - Modifiers: Debounce, Throttle, Delay, Duration, Once, PreventDefault, Key, KeyCombo, ViewTransition, etc.
 //
 // Pro attributes (require commercial license) are available in this package but documented
 // as requiring a Datastar Pro license: Animate, CustomValidity, OnRAF (requestAnimationFrame),
@@ -1,10 +1,13 @@
 package ds
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jeffh/htmlgen/h"
 	"github.com/jeffh/htmlgen/js"
 )
 
@@ -154,6 +157,90 @@ func TestOnFailure(t *testing.T) {
 	}
 }
 
+func TestPromiseCallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{
+			"then with argument",
+			Get("/api/items", Then(js.ArrowFunc([]string{"r"}, js.ConsoleLog(js.Ident("r"))))),
+			`@get("/api/items").then(r => console.log(r))`,
+		},
+		{
+			"catch",
+			Post("/save", Catch(js.ArrowFunc([]string{"err"}, js.ConsoleLog(js.Ident("err"))))),
+			`@post("/save").catch(err => console.log(err))`,
+		},
+		{
+			"finally",
+			Post("/save", Finally(js.ArrowFunc(nil, js.Raw("$saving = false")))),
+			`@post("/save").finally(() => $saving = false)`,
+		},
+		{
+			"set signal",
+			Post("/save", OnSuccessSetSignal("done", true)),
+			`@post("/save").then(() => $done = true)`,
+		},
+		{
+			"set signal expr",
+			Post("/save", OnSuccessSetSignal("$count", Raw("$count + 1")), Finally(js.Ident("reset"))),
+			`@post("/save").then(() => $count = $count + 1).finally(reset)`,
+		},
+		{
+			"set signal string",
+			Delete("/items/1", OnSuccessSetSignal("status", "deleted")),
+			`@delete("/items/1").then(() => $status = "deleted")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := js.ToJS(tt.value.Expr()); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrInvalidSignalName) {
+			t.Errorf("OnSuccessSetSignal with invalid name should panic with ErrInvalidSignalName, got %v", err)
+		}
+	}()
+	OnSuccessSetSignal("my signal", true)
+}
+
+func TestAllAndSequence(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{"all", All(Get("/a"), Post("/b")), `Promise.all([@get("/a"), @post("/b")])`},
+		{"all empty", All(), `Promise.all([])`},
+		{"sequence", Sequence(Post("/cart/items"), Get("/cart"), Get("/totals")),
+			`@post("/cart/items").then(() => @get("/cart")).then(() => @get("/totals"))`},
+		{"sequence single", Sequence(Get("/a")), `@get("/a")`},
+		{"sequence empty", Sequence(), `Promise.resolve(undefined)`},
+		{"sequence with chains", Sequence(Post("/save", OnSuccessSetSignal("saved", true)), Get("/list")),
+			`@post("/save").then(() => $saved = true).then(() => @get("/list"))`},
+		{"all in sequence", Sequence(All(Get("/a"), Get("/b")), Get("/c")),
+			`Promise.all([@get("/a"), @get("/b")]).then(() => @get("/c"))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := js.ToJS(tt.value.Expr()); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	attr := OnClick(All(Get("/cart"), Get("/recommendations")))
+	if want := `Promise.all([@get("/cart"), @get("/recommendations")])`; attr.Value != want {
+		t.Errorf("OnClick(All(...)).Value = %q, want %q", attr.Value, want)
+	}
+}
+
 func TestConsoleLog(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -182,8 +269,8 @@ func TestAnd(t *testing.T) {
 		actions  []js.Expr
 		expected string
 	}{
-		{"two actions", []js.Expr{js.Raw("$a"), js.Raw("$b")}, "($a && $b)"},
-		{"three actions", []js.Expr{js.Raw("$a"), js.Raw("$b"), js.Raw("$c")}, "(($a && $b) && $c)"},
+		{"two actions", []js.Expr{js.Raw("$a"), js.Raw("$b")}, "$a && $b"},
+		{"three actions", []js.Expr{js.Raw("$a"), js.Raw("$b"), js.Raw("$c")}, "$a && $b && $c"},
 	}
 
 	for _, tt := range tests {
@@ -420,6 +507,69 @@ func TestThreshold(t *testing.T) {
 	}
 }
 
+func TestRootMargin(t *testing.T) {
+	tests := []struct {
+		name     string
+		margins  []string
+		expected string
+	}{
+		{"single", []string{"100px"}, "data-on-intersect__rootmargin.100px"},
+		{"two", []string{"100px", "0px"}, "data-on-intersect__rootmargin.100px.0px"},
+		{"negative percent", []string{"-10%"}, "data-on-intersect__rootmargin.-10%"},
+		{"lowercased", []string{"10PX"}, "data-on-intersect__rootmargin.10px"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrName, _ := buildTestAttr("data-on-intersect", RootMargin(tt.margins...))
+			if attrName != tt.expected {
+				t.Errorf("RootMargin(%q) = %q, want %q", tt.margins, attrName, tt.expected)
+			}
+		})
+	}
+
+	t.Run("with threshold", func(t *testing.T) {
+		attr := OnIntersect(Once(), Threshold(0.25), RootMargin("200px"), Raw("$seen = true"))
+		if want := "data-on-intersect__once__threshold.0.25__rootmargin.200px"; attr.Name != want {
+			t.Errorf("got %q, want %q", attr.Name, want)
+		}
+	})
+
+	for _, margins := range [][]string{nil, {""}, {"0.5rem"}, {"10px 0"}, {"1px", "2px", "3px", "4px", "5px"}} {
+		t.Run("invalid "+strings.Join(margins, "|"), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RootMargin(%q) should panic", margins)
+				}
+			}()
+			RootMargin(margins...)
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutator  AttrMutator
+		expected string
+	}{
+		{"key", Key("Enter"), "data-on:keydown__key.enter"},
+		{"space", Key(" "), "data-on:keydown__key.space"},
+		{"period", Key("."), "data-on:keydown__key.period"},
+		{"combo", KeyCombo("ctrl", "s"), "data-on:keydown__key.ctrl.s"},
+		{"control alias", KeyCombo("Control", "Shift", "ArrowUp"), "data-on:keydown__key.ctrl.shift.arrowup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrName, _ := buildTestAttr("data-on:keydown", tt.mutator)
+			if attrName != tt.expected {
+				t.Errorf("got %q, want %q", attrName, tt.expected)
+			}
+		})
+	}
+}
+
 // ============ attrs.go tests ============
 
 func TestSetSignalExpr(t *testing.T) {
@@ -502,6 +652,30 @@ func TestOnClick(t *testing.T) {
 	}
 }
 
+func TestCompositeHandlers(t *testing.T) {
+	tests := []struct {
+		name         string
+		attr         h.Attribute
+		expectedName string
+	}{
+		{"click outside", OnClickOutside(Raw("$open = false")), "data-on:click__outside"},
+		{"escape", OnEscape(Raw("$open = false")), "data-on:keydown__window__key.escape"},
+		{"enter", OnEnter(Raw("$open = false")), "data-on:keydown__key.enter"},
+		{"extra modifiers", OnEnter(PreventDefault(), Raw("$open = false")), "data-on:keydown__key.enter__prevent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr.Name != tt.expectedName {
+				t.Errorf("Name = %q, want %q", tt.attr.Name, tt.expectedName)
+			}
+			if tt.attr.Value != "$open = false" {
+				t.Errorf("Value = %q, want %q", tt.attr.Value, "$open = false")
+			}
+		})
+	}
+}
+
 func TestOnLoad(t *testing.T) {
 	attr := OnLoad(Raw("$init()"))
 	if attr.Name != "data-on:load" {
@@ -704,12 +878,11 @@ func TestPeek(t *testing.T) {
 
 func TestComputed(t *testing.T) {
 	attr := Computed("total", Raw("$price * $qty"))
-	// The expression is appended to the name, value is empty
-	if attr.Name != "data-computed:total$price * $qty" {
-		t.Errorf("Computed().Name = %q, want %q", attr.Name, "data-computed:total$price * $qty")
+	if attr.Name != "data-computed:total" {
+		t.Errorf("Computed().Name = %q, want %q", attr.Name, "data-computed:total")
 	}
-	if attr.Value != "" {
-		t.Errorf("Computed().Value = %q, want empty", attr.Value)
+	if attr.Value != "$price * $qty" {
+		t.Errorf("Computed().Value = %q, want %q", attr.Value, "$price * $qty")
 	}
 }
 
@@ -735,8 +908,8 @@ func TestInit(t *testing.T) {
 
 func TestRef(t *testing.T) {
 	attr := Ref("myElement")
-	if attr.Name != "data-ref:myElement" {
-		t.Errorf("Ref().Name = %q, want %q", attr.Name, "data-ref:myElement")
+	if attr.Name != "data-ref:my-element" {
+		t.Errorf("Ref().Name = %q, want %q", attr.Name, "data-ref:my-element")
 	}
 }
 
@@ -761,6 +934,115 @@ func TestAttrs(t *testing.T) {
 	}
 }
 
+func TestStyleKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		attr      h.Attribute
+		wantName  string
+		wantValue string
+	}{
+		{"typed", StyleKey(StyleOpacity, js.Ternary(js.Raw("$visible"), js.Int(1), js.Int(0))), "data-style:opacity", "$visible ? 1 : 0"},
+		{"string", StyleKey("background-color", js.Raw("$bg")), "data-style:background-color", "$bg"},
+		{"custom property", StyleKey("--accent", js.String("red")), "data-style:--accent", `"red"`},
+		{"vendor prefix", StyleKey("-webkit-line-clamp", js.Int(3)), "data-style:-webkit-line-clamp", "3"},
+		{"modifier", StyleKey(StyleDisplay, js.Raw("$d"), ViewTransition()), "data-style:display__viewtransition", "$d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr.Name != tt.wantName {
+				t.Errorf("StyleKey().Name = %q, want %q", tt.attr.Name, tt.wantName)
+			}
+			if tt.attr.Value != tt.wantValue {
+				t.Errorf("StyleKey().Value = %q, want %q", tt.attr.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestValidateStyleProperty(t *testing.T) {
+	for _, name := range []string{"color", "background-color", "-webkit-line-clamp", "--accent", "--grid_gap", "h1-size"} {
+		if err := ValidateStyleProperty(name); err != nil {
+			t.Errorf("ValidateStyleProperty(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "-", "--", "backgroundColor", "color:red", "a b", "a__b", "_a", "1a", `x"y`} {
+		err := ValidateStyleProperty(name)
+		if !errors.Is(err, ErrInvalidStyleProperty) {
+			t.Errorf("ValidateStyleProperty(%q) = %v, want ErrInvalidStyleProperty", name, err)
+		}
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrInvalidStyleProperty) {
+			t.Errorf("Style with invalid property should panic with ErrInvalidStyleProperty, got %v", err)
+		}
+	}()
+	Style("backgroundColor", Raw("$bg"))
+}
+
+func TestClassKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     h.Attribute
+		wantName string
+	}{
+		{"simple", ClassKey("active", Raw("$isActive")), "data-class:active"},
+		{"hyphenated", ClassKey("font-bold", Raw("$isActive")), "data-class:font-bold"},
+		{"tailwind variant", ClassKey("md:flex", Raw("$isActive")), "data-class:md:flex"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr.Name != tt.wantName {
+				t.Errorf("ClassKey().Name = %q, want %q", tt.attr.Name, tt.wantName)
+			}
+			if tt.attr.Value != "$isActive" {
+				t.Errorf("ClassKey().Value = %q, want %q", tt.attr.Value, "$isActive")
+			}
+		})
+	}
+
+	for _, name := range []string{"", "fontBold", "a b"} {
+		t.Run("invalid "+name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ClassKey(%q) should panic", name)
+				}
+			}()
+			ClassKey(name, Raw("$x"))
+		})
+	}
+}
+
+func TestClassesExpr(t *testing.T) {
+	attr := ClassesExpr(
+		ClassPair{"hidden", Raw("$foo")},
+		ClassPair{"font-bold", Raw("$bar && !$baz")},
+		ClassPair{"isOpen", V(Bool(true))},
+	)
+	if attr.Name != "data-class" {
+		t.Errorf("ClassesExpr().Name = %q, want %q", attr.Name, "data-class")
+	}
+	want := `{"hidden": $foo, "font-bold": $bar && !$baz, "isOpen": true}`
+	if attr.Value != want {
+		t.Errorf("ClassesExpr().Value = %q, want %q", attr.Value, want)
+	}
+
+	for name, pairs := range map[string][]ClassPair{
+		"empty name": {{"", Raw("$x")}},
+		"duplicate":  {{"a", Raw("$x")}, {"a", Raw("$y")}},
+		"no expr":    {{Name: "a"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("ClassesExpr(%v) should panic", pairs)
+				}
+			}()
+			ClassesExpr(pairs...)
+		})
+	}
+}
+
 func TestClasses(t *testing.T) {
 	attr := Classes(map[string]string{"hidden": "$foo"})
 	if attr.Name != "data-class" {
@@ -1057,6 +1339,50 @@ func TestHeaders(t *testing.T) {
 	}
 }
 
+func TestCSRFHeader(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{
+			"token",
+			Request("/cart").Method("post").CSRFHeader("abc").Value(),
+			`@post("/cart", {headers: {"X-CSRF-Token": "abc"}})`,
+		},
+		{
+			"from meta",
+			PostWithOptions("/cart", RequestOptions().CSRFHeaderFromMeta()),
+			`@post("/cart", {headers: {"X-CSRF-Token": document.querySelector("meta[name=\"csrf-token\"]")?.content}})`,
+		},
+		{
+			"merged with headers",
+			Request("/cart").Method("post").Headers(map[string]string{"X-B": "2", "X-A": "1"}).ContentType(ContentForm).CSRFHeader("abc").Value(),
+			`@post("/cart", {headers: {"X-A": "1", "X-B": "2", "X-CSRF-Token": "abc"}, contentType: "form"})`,
+		},
+		{
+			"replaces same header",
+			Request("/cart").Method("post").CSRFHeader("old").Headers(map[string]string{"x-csrf-token": "new"}).Value(),
+			`@post("/cart", {headers: {"x-csrf-token": "new"}})`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJS(tt.value.expr); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("branches do not share headers", func(t *testing.T) {
+		base := Request("/cart").Method("post").Headers(map[string]string{"X-A": "1"})
+		_ = base.CSRFHeader("abc")
+		if got, want := ToJS(base.Value().expr), `@post("/cart", {headers: {"X-A": "1"}})`; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
 func TestFilterSignals(t *testing.T) {
 	include := "^user"
 	v := GetWithOptions("/api", RequestOptions().FilterSignals(&FilterOptions{IncludeReg: &include}))
@@ -1139,3 +1465,520 @@ func TestMultipleStatements(t *testing.T) {
 		t.Errorf("multiple statements = %q, want %q", attr.Value, "$a = 1; $b = 2")
 	}
 }
+
+// ============ signal.go tests ============
+
+func TestSignalHandle(t *testing.T) {
+	count := NewSignal("count", 0)
+	open := NewSignal("$open", false)
+	label := NewSignal("label", "hi")
+
+	attrs := []struct {
+		name          string
+		attr          h.Attribute
+		expectedName  string
+		expectedValue string
+	}{
+		{"Attr int", count.Attr(), "data-signals:count", "0"},
+		{"Attr bool", open.Attr(), "data-signals:open", "false"},
+		{"Attr string", label.Attr(), "data-signals:label", `"hi"`},
+		{"Bind", label.Bind(), "data-bind", "label"},
+		{"Text", Text(count.Ref()), "data-text", "$count"},
+		{"Show", Show(open.Ref()), "data-show", "$open"},
+	}
+	for _, tt := range attrs {
+		if tt.attr.Name != tt.expectedName || tt.attr.Value != tt.expectedValue {
+			t.Errorf("%s = %s=%q, want %s=%q", tt.name, tt.attr.Name, tt.attr.Value, tt.expectedName, tt.expectedValue)
+		}
+	}
+
+	values := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{"Ref", count.Ref(), "$count"},
+		{"Set", count.Set(5), "$count = 5"},
+		{"Set string", label.Set(`"quoted"`), `$label = "\"quoted\""`},
+		{"SetExpr", count.SetExpr(js.Mul(count.Expr(), js.Int(2))), "$count = $count * 2"},
+		{"Incr", count.Incr(), "$count++"},
+		{"Decr", count.Decr(), "$count--"},
+		{"Toggle", open.Toggle(), "$open = !$open"},
+	}
+	for _, tt := range values {
+		if got := js.ToJS(tt.value.Expr()); got != tt.expected {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+
+	if attr := OnClick(count.Incr(), count.Set(0)); attr.Value != "$count++; $count = 0" {
+		t.Errorf("OnClick(Incr, Set) = %q", attr.Value)
+	}
+	if open.Name() != "open" || count.Initial() != 0 {
+		t.Errorf("Name() = %q, Initial() = %v", open.Name(), count.Initial())
+	}
+}
+
+// ============ structsignals.go tests ============
+
+type testAudit struct {
+	Created time.Time `json:"created"`
+}
+
+type testViewModel struct {
+	Query   string
+	Page    int    `json:"page,omitempty"`
+	Secret  string `ds:"-"`
+	private int
+	User    struct {
+		Name  string `ds:"name"`
+		Admin bool   `json:"isAdmin"`
+	}
+	testAudit
+}
+
+func TestSignalsFromStruct(t *testing.T) {
+	vm := &testViewModel{Query: "go", Page: 2, Secret: "x", private: 1}
+	vm.User.Name = "Ada"
+	vm.Created = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	sigs := SignalsFromStruct(vm, IfMissing())
+	attr := sigs.Attr()
+	if attr.Name != "data-signals__ifmissing" {
+		t.Errorf("Attr().Name = %q", attr.Name)
+	}
+	expected := `{"created":"2024-01-02T03:04:05Z","page":2,"query":"go","user":{"isAdmin":false,"name":"Ada"}}`
+	if attr.Value != expected {
+		t.Errorf("Attr().Value = %s, want %s", attr.Value, expected)
+	}
+
+	query := SignalField(sigs, &vm.Query)
+	name := SignalField(sigs, &vm.User.Name)
+	admin := SignalField(sigs, &vm.User.Admin)
+	created := SignalField(sigs, &vm.Created)
+	tests := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{"top level", query.Ref(), "$query"},
+		{"json tag", SignalField(sigs, &vm.Page).Set(3), "$page = 3"},
+		{"nested ds tag", name.Ref(), "$user.name"},
+		{"nested json tag", admin.Toggle(), "$user.isAdmin = !$user.isAdmin"},
+		{"embedded marshaler", created.Ref(), "$created"},
+	}
+	for _, tt := range tests {
+		if got := js.ToJS(tt.value.Expr()); got != tt.expected {
+			t.Errorf("%s = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+	if query.Initial() != "go" || name.Initial() != "Ada" {
+		t.Errorf("Initial() = %q, %q", query.Initial(), name.Initial())
+	}
+	if got := SignalField(sigs, &vm.User).Name(); got != "user" {
+		t.Errorf("SignalField(&vm.User).Name() = %q, want user", got)
+	}
+
+	for name, fn := range map[string]func(){
+		"skipped field":  func() { SignalField(sigs, &vm.Secret) },
+		"other struct":   func() { SignalField(sigs, &(&testViewModel{}).Query) },
+		"nil pointer":    func() { SignalsFromStruct[testViewModel](nil) },
+		"non-struct ptr": func() { n := 1; SignalsFromStruct(&n) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected panic", name)
+				}
+			}()
+			fn()
+		}()
+	}
+}
+
+// ============ expr.go tests ============
+
+func TestSignalIdent(t *testing.T) {
+	count := SignalIdent("count")
+	tests := []struct {
+		name     string
+		attr     h.Attribute
+		expected string
+	}{
+		{"ident", Text(V(count)), "$count"},
+		{"$ prefix", Text(V(SignalIdent("$count"))), "$count"},
+		{"operators", Show(V(js.And(js.Gt(count, js.Int(0)), js.Not(SignalIdent("hidden"))))), "$count > 0 && !$hidden"},
+		{"ternary", Text(V(Ternary(js.Eq(count, js.Int(1)), Str("item"), Str("items")))), `$count === 1 ? "item" : "items"`},
+		{"template", Text(V(Template("Hi ", SignalIdent("user.name"), "!"))), "`Hi ${$user.name}!`"},
+		{"stmts", OnClick(Stmts(js.AddAssign(count, js.Int(1)), js.Assign(SignalIdent("open"), js.Bool(false)))), "$count += 1; $open = false"},
+		{"stmts with action", OnClick(Stmts(js.Incr(count)), V(ActionPost(js.String("/save")))), `$count++; @post("/save")`},
+	}
+	for _, tt := range tests {
+		if tt.attr.Value != tt.expected {
+			t.Errorf("%s = %q, want %q", tt.name, tt.attr.Value, tt.expected)
+		}
+	}
+}
+
+// ============ once.go tests ============
+
+func TestOncePer(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    Value
+		expected string
+	}{
+		{
+			"session",
+			OncePerSession("welcome", Raw("$showWelcome = true")),
+			`!sessionStorage.getItem("welcome") && (sessionStorage.setItem("welcome", "1"), $showWelcome = true)`,
+		},
+		{
+			"browser",
+			OncePerBrowser("migrate-v2", V(ActionPost(Str("/migrate")))),
+			`!localStorage.getItem("migrate-v2") && (localStorage.setItem("migrate-v2", "1"), @post("/migrate"))`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attr := Init(tt.value)
+			if attr.Value != tt.expected {
+				t.Errorf("got %q, want %q", attr.Value, tt.expected)
+			}
+		})
+	}
+}
+
+// ============ request.go tests ============
+
+func TestRequestBuilder(t *testing.T) {
+	include := "^form"
+	base := Request("/api").Method("POST")
+	tests := []struct {
+		name     string
+		builder  RequestBuilder
+		expected string
+	}{
+		{"default get", Request("/api"), `@get("/api")`},
+		{"method", base, `@post("/api")`},
+		{
+			"options",
+			base.ContentType(ContentForm).Filter(&FilterOptions{IncludeReg: &include}).Retry("error").RetryMaxCount(3),
+			`@post("/api", {contentType: "form", filterSignals: {include: /^form/}, retry: "error", retryMaxCount: 3})`,
+		},
+		{
+			"chains",
+			base.OnSuccess(Raw("$saved = true")).OnFailure(Raw("$failed = true")),
+			`@post("/api").then(() => $saved = true).catch((error) => $failed = true)`,
+		},
+		{"shared options", Request("/api").Options(RequestOptions().OpenWhenHidden(true)), `@get("/api", {openWhenHidden: true})`},
+		{"dynamic", RequestDynamic(Raw("`/items/${$id}`")).Method("delete"), "@delete(`/items/${$id}`)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJS(tt.builder.Value().expr); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	// Branches from a shared prefix do not leak options into each other.
+	prefix := Request("/api").ContentType(ContentForm)
+	a := prefix.Retry("never")
+	b := prefix.Selector("#f")
+	if got := ToJS(a.Value().expr); got != `@get("/api", {contentType: "form", retry: "never"})` {
+		t.Errorf("branch a = %q", got)
+	}
+	if got := ToJS(b.Value().expr); got != `@get("/api", {contentType: "form", selector: "#f"})` {
+		t.Errorf("branch b = %q", got)
+	}
+
+	if attr := OnClick(base); attr.Value != `@post("/api")` {
+		t.Errorf("OnClick(builder) = %q", attr.Value)
+	}
+}
+
+// ============ signalname.go tests ============
+
+func TestValidateSignalName(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"count", true},
+		{"$count", true},
+		{"user.firstName", true},
+		{"_private", true},
+		{"my_signal2", true},
+		{"my-signal", false},
+		{"a-", false},
+		{"", false},
+		{"my signal!", false},
+		{"2fast", false},
+		{"user..name", false},
+		{"user.", false},
+		{"a__b", false},
+		{"-x", false},
+	}
+	for _, tt := range tests {
+		err := ValidateSignalName(tt.name)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateSignalName(%q) = %v, want valid=%v", tt.name, err, tt.valid)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidSignalName) {
+			t.Errorf("ValidateSignalName(%q) error does not wrap ErrInvalidSignalName: %v", tt.name, err)
+		}
+	}
+}
+
+func TestSignalKeyCase(t *testing.T) {
+	tests := []struct {
+		attr     h.Attribute
+		expected string
+	}{
+		{Signal("userName", ""), "data-signals:user-name"},
+		{Signal("user.firstName", ""), "data-signals:user.first-name"},
+		{Signal("item2Name", 0), "data-signals:item2-name"},
+		{Signal("snake_case", 0), "data-signals:snake_case"},
+		{ComputedExpr("fullName", Raw("1")), "data-computed:full-name"},
+		{BindKey("firstName", Case(CamelCase)), "data-bind:first-name__case.camel"},
+		{IndicatorKey("$isLoading"), "data-indicator:is-loading"},
+	}
+	for _, tt := range tests {
+		if tt.attr.Name != tt.expected {
+			t.Errorf("got %q, want %q", tt.attr.Name, tt.expected)
+		}
+	}
+}
+
+func TestTrySignal(t *testing.T) {
+	if _, err := TrySignal("my signal!", 1); !errors.Is(err, ErrInvalidSignalName) {
+		t.Errorf("TrySignal error = %v", err)
+	}
+	// Keys Datastar would read back under another name are rejected.
+	for _, name := range []string{"userID", "HTMLParser", "User", "user_Name", "my-signal", "a-"} {
+		if _, err := TrySignal(name, 1); !errors.Is(err, ErrInvalidSignalName) {
+			t.Errorf("TrySignal(%q) error = %v, want ErrInvalidSignalName", name, err)
+		}
+	}
+	_, err := TrySignal("userID", 1)
+	if want := `invalid Datastar signal name "userID": Datastar reads its attribute key "user-id" back as $userId; spell it "userId"`; err == nil || err.Error() != want {
+		t.Errorf("TrySignal error = %v, want %q", err, want)
+	}
+	attr, err := TrySignal("count", 1)
+	if err != nil || attr.Name != "data-signals:count" || attr.Value != "1" {
+		t.Errorf("TrySignal = %+v, %v", attr, err)
+	}
+
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, ErrInvalidSignalName) {
+			t.Errorf("Signal with an invalid name should panic with ErrInvalidSignalName, got %v", r)
+		}
+	}()
+	Signal("my signal!", 1)
+}
+
+func TestSignalPath(t *testing.T) {
+	path := SignalPath("user", "profile", "firstName")
+	if path != "user.profile.firstName" {
+		t.Errorf("SignalPath = %q", path)
+	}
+	if got := Signal(path, "").Name; got != "data-signals:user.profile.first-name" {
+		t.Errorf("Signal(path).Name = %q", got)
+	}
+	if got := ToJS(SignalRef(path).expr); got != "$user.profile.firstName" {
+		t.Errorf("SignalRef(path) = %q", got)
+	}
+
+	for _, segs := range [][]string{{"user", "a.b"}, {"user", ""}, {"my user"}, {"user", "accountID"}, {"user", "my-name"}, {"user", "$name"}} {
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, ErrInvalidSignalName) {
+					t.Errorf("SignalPath(%q) should panic with ErrInvalidSignalName, got %v", segs, err)
+				}
+			}()
+			SignalPath(segs...)
+		}()
+	}
+}
+
+func TestSignalsNested(t *testing.T) {
+	attr := SignalsNested(map[string]any{
+		"user.name":  "Ada",
+		"user.prefs": map[string]any{"theme": "dark"},
+		"user":       map[string]any{"prefs.size": 2},
+		"count":      0,
+	}, IfMissing())
+	if attr.Name != "data-signals__ifmissing" {
+		t.Errorf("Name = %q", attr.Name)
+	}
+	if want := `{"count":0,"user":{"name":"Ada","prefs":{"size":2,"theme":"dark"}}}`; attr.Value != want {
+		t.Errorf("Value = %q, want %q", attr.Value, want)
+	}
+
+	for _, signals := range []map[string]any{
+		{"user": 1, "user.name": "x"},
+		{"user.name": "x", "user.name.first": "y"},
+		{"bad key": 1},
+	} {
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, ErrInvalidSignalName) {
+					t.Errorf("SignalsNested(%v) should panic with ErrInvalidSignalName, got %v", signals, err)
+				}
+			}()
+			SignalsNested(signals)
+		}()
+	}
+}
+
+// ============ order.go tests ============
+
+func TestOrderingHints(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     h.Attribute
+		expected string
+	}{
+		{"deps", Effect(DependsOn("user", "$prefs"), Raw("applyTheme()")), `/* deps: user, prefs */ applyTheme()`},
+		{"priority", Init(Raw("$ready = true"), Priority(10)), `/* priority: 10 */ $ready = true`},
+		{"both", Effect(Priority(-1), DependsOn("a.b")), `/* priority: -1; deps: a.b */ `},
+		{"none", Effect(Raw("$x")), `$x`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr.Value != tt.expected {
+				t.Errorf("Value = %q, want %q", tt.attr.Value, tt.expected)
+			}
+		})
+	}
+
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrInvalidSignalName) {
+			t.Errorf("DependsOn should panic with ErrInvalidSignalName, got %v", err)
+		}
+	}()
+	DependsOn("bad name")
+}
+
+func TestValidateInitOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     h.Builder
+		expected []string // Signal of each error, or "priority"
+	}{
+		{
+			"defined first",
+			h.Div(Signal("count", 0), h.Div(Effect(Raw("console.log($count)")))),
+			nil,
+		},
+		{
+			"defined later",
+			h.Div(h.Div(Effect(Raw("console.log($count)"))), h.Div(Signal("count", 0))),
+			[]string{"count"},
+		},
+		{
+			"same element",
+			h.Div(Init(Raw("$userName = 'x'")), Signal("userName", "")),
+			nil,
+		},
+		{
+			"kebab key",
+			h.Div(h.Div(Init(Raw("$userName = 'x'"))), h.Div(Signal("userName", ""))),
+			[]string{"userName"},
+		},
+		{
+			"declared dependency",
+			h.Div(h.Div(Effect(DependsOn("theme"), Raw("applyTheme()"))), h.Input(Bind("theme"))),
+			[]string{"theme"},
+		},
+		{
+			"nested object",
+			h.Div(h.Div(Effect(Raw("$user.name"))), h.Div(Signals(map[string]any{"user": map[string]any{"name": "Ada"}}))),
+			[]string{"user.name"},
+		},
+		{
+			"computed later",
+			h.Div(h.Div(Effect(Raw("$total"))), h.Div(ComputedExpr("total", Raw("$a + $b")))),
+			[]string{"total"},
+		},
+		{
+			"never defined",
+			h.Div(Effect(Raw("$fromServer"))),
+			nil,
+		},
+		{
+			"priority in order",
+			h.Div(h.Div(Init(Priority(10), Raw("a()"))), h.Div(Init(Priority(1), Raw("b()")))),
+			nil,
+		},
+		{
+			"priority out of order",
+			h.Div(h.Div(Init(Priority(1), Raw("b()"))), h.Div(Effect(Priority(10), Raw("a()")))),
+			[]string{"priority"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateInitOrder(tt.page)
+			var got []string
+			if err != nil {
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					var order *InitOrderError
+					if !errors.As(e, &order) {
+						t.Fatalf("unexpected error %v", e)
+					}
+					if order.Signal == "" {
+						got = append(got, "priority")
+					} else {
+						got = append(got, order.Signal)
+					}
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("ValidateInitOrder() reported %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	err := ValidateInitOrder(h.Div(h.P(Effect(Raw("$count"))), h.Div(Signal("count", 0))))
+	expected := "<p>: initialization race in Datastar attributes: data-effect: reads $count before data-signals:count defines it later in the document; define the signal on this element or an earlier one"
+	if !errors.Is(err, ErrInitOrder) || err.Error() != expected {
+		t.Errorf("ValidateInitOrder() = %v, want %q", err, expected)
+	}
+}
+
+// ============ extract.go tests ============
+
+func TestExtractSignals(t *testing.T) {
+	tests := []struct {
+		name   string
+		attr   h.Attribute
+		reads  []string
+		writes []string
+	}{
+		{"assignment", OnClick(Raw("$count = $count + $step")), []string{"count", "step"}, []string{"count"}},
+		{"increment", OnClick(Raw("$count++")), []string{"count"}, []string{"count"}},
+		{"comparison", Show(Raw("$count == 0 || $open")), []string{"count", "open"}, nil},
+		{"signal key", Signal("userName", ""), nil, []string{"userName"}},
+		{"signals object", Signals(map[string]any{"user": map[string]any{"name": "Ada"}}), nil, []string{"user", "user.name"}},
+		{"computed", ComputedExpr("total", Raw("$price * $qty")), []string{"price", "qty"}, []string{"total"}},
+		{"bind value", Bind("query"), []string{"query"}, []string{"query"}},
+		{"bind key", BindKey("query"), []string{"query"}, []string{"query"}},
+		{"ref", Ref("input"), nil, []string{"input"}},
+		{"indicator", Indicator("$loading"), nil, []string{"loading"}},
+		{"nested path", Text(Raw("$user.name")), []string{"user.name"}, nil},
+		{"action", OnClick(Post("/save")), nil, nil},
+		{"not datastar", h.Attr("hx-vals", "$x"), nil, nil},
+		{"htmx data prefix", h.Attr("data-hx-get", "/$x"), nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads, writes := ExtractSignals(tt.attr)
+			if !slices.Equal(reads, tt.reads) || !slices.Equal(writes, tt.writes) {
+				t.Errorf("ExtractSignals(%s=%q) = %v, %v; want %v, %v", tt.attr.Name, tt.attr.Value, reads, writes, tt.reads, tt.writes)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package ds_test
+
+import (
+	"fmt"
+
+	"github.com/jeffh/htmlgen/ds"
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+func Example_counter() {
+	count := ds.NewSignal("count", 0)
+	fmt.Println(h.RenderString(h.Div(
+		count.Attr(),
+		h.Button(ds.OnClick(count.Decr()), h.Text("-")),
+		h.Span(ds.Text(count.Ref())),
+		h.Button(ds.OnClick(count.Incr()), h.Text("+")),
+	)))
+	// Output:
+	// <div data-signals:count="0"><button data-on:click="$count--">-</button><span data-text="$count"></span><button data-on:click="$count++">+</button></div>
+}
+
+func Example_computedAndShow() {
+	price := ds.NewSignal("price", 10)
+	quantity := ds.NewSignal("quantity", 2)
+	fmt.Println(h.RenderString(h.Div(
+		price.Attr(),
+		quantity.Attr(),
+		h.Input(quantity.Bind()),
+		ds.Computed("total", ds.V(js.Mul(price.Expr(), quantity.Expr()))),
+		h.P(ds.Show(ds.V(js.Gt(ds.SignalIdent("total"), js.Int(100)))), h.Text("Free shipping")),
+	)))
+	// Output:
+	// <div data-signals:price="10" data-signals:quantity="2" data-computed:total="$price * $quantity"><input data-bind="quantity"/><p data-show="$total &gt; 100">Free shipping</p></div>
+}
+
+func Example_request() {
+	saved := ds.NewSignal("saved", false)
+	fmt.Println(h.RenderString(h.Form(
+		ds.OnSubmit(ds.PreventDefault(), ds.Post("/contacts", ds.OnSuccess(saved.Set(true)))),
+		ds.ClassKey("opacity-50", ds.Raw("$saving")),
+		ds.Indicator("saving"),
+	)))
+	// Output:
+	// <form data-on:submit__prevent="@post(&#34;/contacts&#34;).then(() =&gt; $saved = true)" data-class:opacity-50="$saving" data-indicator="saving"></form>
+}
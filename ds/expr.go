@@ -75,6 +75,9 @@ var (
 	Ident = js.Ident
 	// This creates the special "this" identifier.
 	This = js.This
+	// Template creates a JavaScript template literal from alternating
+	// strings and expressions.
+	Template = js.Template
 	// ToJS converts an expression to its JavaScript string representation.
 	ToJS = js.ToJS
 	// ToJSStmt converts a statement to its JavaScript string representation.
@@ -147,9 +150,31 @@ var (
 // Use this to reference a signal value in expressions.
 // Example: SignalRef("count") produces $count
 func SignalRef(name string) Value {
-	// Remove $ prefix if already present
-	name = strings.TrimPrefix(name, "$")
-	return Value{expr: js.Raw("$" + name)}
+	return Value{expr: SignalIdent(name)}
+}
+
+// SignalIdent returns a Datastar signal as a js.Callable so handlers can be
+// built with the js package's operators, ternaries, and templates instead
+// of Raw strings. A leading "$" in name is ignored.
+// Example:
+//
+//	count := ds.SignalIdent("count")
+//	ds.Text(ds.V(js.Ternary(js.Gt(count, js.Int(0)), js.Template("", count, " items"), js.String("Empty"))))
+//	ds.OnClick(ds.Stmts(js.AddAssign(count, js.Int(1))))
+func SignalIdent(name string) js.Callable {
+	return js.Ident("$" + strings.TrimPrefix(name, "$"))
+}
+
+// Stmts appends js statements to an attribute's expression, so handlers
+// with assignments can be written with the js package:
+// Example: OnClick(Stmts(js.Assign(SignalIdent("open"), js.Bool(false))))
+// Produces: data-on:click="$open = false"
+func Stmts(stmts ...js.Stmt) AttrMutator {
+	return AttrFunc(func(attr *attrBuilder) {
+		for _, s := range stmts {
+			attr.AppendStatement(js.ToJSStmt(s))
+		}
+	})
 }
 
 // DatastarAction creates a Datastar action call: @action(args...)
@@ -257,7 +282,7 @@ func ActionFitClampedRounded(v, oldMin, oldMax, newMin, newMax js.Expr) js.Calla
 	return DatastarAction("fit", v, oldMin, oldMax, newMin, newMax, js.Bool(true), js.Bool(true))
 }
 
-// PromiseChain represents a chainable action for HTTP requests (then/catch)
+// PromiseChain represents a chainable action for HTTP requests (then/catch/finally)
 type PromiseChain interface {
 	appendChain(sb *strings.Builder)
 }
@@ -294,6 +319,64 @@ func CatchChain(expr js.Expr) PromiseChain {
 	return catchChain{expr}
 }
 
+// callbackChain represents .method(fn) with a callback written as-is
+type callbackChain struct {
+	method string
+	fn     js.Expr
+}
+
+func (c callbackChain) appendChain(sb *strings.Builder) {
+	sb.WriteString(".")
+	sb.WriteString(c.method)
+	sb.WriteString("(")
+	sb.WriteString(js.ToJS(c.fn))
+	sb.WriteString(")")
+}
+
+// Then creates a .then(fn) chain. Unlike ThenChain, fn is the callback
+// itself, so it can use the resolved value.
+// Example: Get("/api/items", Then(js.ArrowFunc([]string{"r"}, js.ConsoleLog(js.Ident("r")))))
+// Produces: @get("/api/items").then(r => console.log(r))
+func Then(fn js.Expr) PromiseChain {
+	return callbackChain{"then", fn}
+}
+
+// Catch creates a .catch(fn) chain with fn as the error callback.
+// Example: Post("/save", Catch(js.ArrowFunc([]string{"err"}, js.ConsoleLog(js.Ident("err")))))
+func Catch(fn js.Expr) PromiseChain {
+	return callbackChain{"catch", fn}
+}
+
+// Finally creates a .finally(fn) chain, run whether the request succeeds
+// or fails.
+// Example: Post("/save", Finally(js.ArrowFunc(nil, js.Raw("$saving = false"))))
+func Finally(fn js.Expr) PromiseChain {
+	return callbackChain{"finally", fn}
+}
+
+// OnSuccessSetSignal creates a .then() chain that sets a signal, with
+// value encoded like SetSignal: a js.Expr or Value is written as-is and
+// anything else as JSON. The signal name will automatically be prefixed
+// with "$".
+// Example: Post("/save", OnSuccessSetSignal("done", true))
+// Produces: @post("/save").then(() => $done = true)
+// Panics with ErrInvalidSignalName if name is invalid.
+func OnSuccessSetSignal(name string, value any) PromiseChain {
+	if err := ValidateSignalName(name); err != nil {
+		panic(err)
+	}
+	var expr js.Expr
+	switch v := value.(type) {
+	case js.Expr:
+		expr = v
+	case Value:
+		expr = v.expr
+	default:
+		expr = js.JSON(value)
+	}
+	return thenChain{js.Raw(js.ToJSStmt(js.Assign(SignalIdent(name), expr)))}
+}
+
 // WithChains adds promise chains to a Datastar action, returning a new Callable
 func WithChains(action js.Callable, chains ...PromiseChain) js.Callable {
 	if len(chains) == 0 {
@@ -0,0 +1,141 @@
+package ds
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// ExtractSignals returns the signals a Datastar attribute reads and writes,
+// each once in order of appearance, for tools that map how a page uses its
+// signals. Writes are the signals defined by data-signals, data-computed,
+// data-bind, data-ref, and data-indicator, and the targets of assignments
+// ($x = ..., $x += ..., $x++) in expressions. Reads are the other $signal
+// references in expressions, and the signal of data-bind, which is both
+// read and written. Other attributes, including data-hx-*, return nil.
+//
+//	ds.ExtractSignals(ds.OnClick(ds.Raw("$count = $count + $step")))
+//	// reads [count step], writes [count]
+func ExtractSignals(a h.Attribute) (reads, writes []string) {
+	name := strings.ToLower(a.Name)
+	if !strings.HasPrefix(name, "data-") || strings.HasPrefix(name, "data-hx-") {
+		return nil, nil
+	}
+	add := func(list []string, sig string) []string {
+		if slices.Contains(list, sig) {
+			return list
+		}
+		return append(list, sig)
+	}
+	defs := signalDefinitions(a)
+	for _, sig := range defs {
+		writes = add(writes, sig)
+	}
+	base, _, _ := strings.Cut(name, "__")
+	switch plugin, _, hasKey := strings.Cut(base, ":"); {
+	case plugin == "data-bind":
+		for _, sig := range defs {
+			reads = add(reads, sig)
+		}
+		if !hasKey {
+			return reads, writes
+		}
+	case plugin == "data-signals" && !hasKey,
+		plugin == "data-ref", plugin == "data-indicator":
+		return reads, writes
+	}
+	for _, m := range signalRefPattern.FindAllStringSubmatchIndex(a.Value, -1) {
+		sig := a.Value[m[2]:m[3]]
+		read, write := assignment(a.Value[m[1]:])
+		if read {
+			reads = add(reads, sig)
+		}
+		if write {
+			writes = add(writes, sig)
+		}
+	}
+	return reads, writes
+}
+
+// assignment reports whether a signal reference followed by rest is read,
+// written, or both.
+func assignment(rest string) (read, write bool) {
+	rest = strings.TrimLeft(rest, " \t\n")
+	for _, op := range []string{"++", "--", "+=", "-=", "*=", "/=", "%=", "||=", "&&=", "??="} {
+		if strings.HasPrefix(rest, op) {
+			return true, true
+		}
+	}
+	if strings.HasPrefix(rest, "=") && !strings.HasPrefix(rest, "==") && !strings.HasPrefix(rest, "=>") {
+		return false, true
+	}
+	return true, false
+}
+
+// signalRefPattern matches $signal references, capturing the dotted name.
+var signalRefPattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*)`)
+
+// signalDefinitions returns the signals an attribute defines.
+func signalDefinitions(a h.Attribute) []string {
+	base, mods, _ := strings.Cut(strings.ToLower(a.Name), "__")
+	plugin, key, hasKey := strings.Cut(base, ":")
+	switch plugin {
+	case "data-signals":
+		if hasKey {
+			return []string{keySignal(key, mods)}
+		}
+		var signals map[string]any
+		if json.Unmarshal([]byte(a.Value), &signals) != nil {
+			return nil
+		}
+		return objectSignals("", signals)
+	case "data-computed", "data-bind", "data-ref", "data-indicator":
+		if hasKey {
+			return []string{keySignal(key, mods)}
+		}
+		if name := strings.TrimPrefix(a.Value, "$"); ValidateSignalName(name) == nil {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// keySignal returns the signal named by an attribute key, undoing the
+// kebab-case conversion of signalKey unless a Case modifier is present.
+func keySignal(key, mods string) string {
+	if strings.Contains(mods, "case.") {
+		return key
+	}
+	var sb strings.Builder
+	upper := false
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c == '-':
+			upper = true
+		case upper && c >= 'a' && c <= 'z':
+			sb.WriteByte(c - ('a' - 'A'))
+			upper = false
+		default:
+			sb.WriteByte(c)
+			upper = false
+		}
+	}
+	return sb.String()
+}
+
+// objectSignals returns the dotted paths of the signals in a data-signals
+// object.
+func objectSignals(prefix string, signals map[string]any) []string {
+	var paths []string
+	for k, v := range signals {
+		path := prefix + k
+		paths = append(paths, path)
+		if nested, ok := v.(map[string]any); ok {
+			paths = append(paths, objectSignals(path+".", nested)...)
+		}
+	}
+	return paths
+}
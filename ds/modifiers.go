@@ -204,6 +204,32 @@ func Threshold(value float64) AttrMutator {
 	})
 }
 
+// RootMargin grows (or with negative values, shrinks) the viewport used for
+// intersection, so OnIntersect fires before the element scrolls into view.
+// It takes one to four CSS lengths, like the rootMargin option of
+// IntersectionObserver: RootMargin("100px") produces __rootmargin.100px and
+// RootMargin("100px", "0px") produces __rootmargin.100px.0px
+// Lengths cannot contain dots, since those separate modifier values, so use
+// whole numbers such as "8px" rather than "0.5rem".
+// Example: OnIntersect(Once(), Threshold(0.25), RootMargin("200px"), Get("/more"))
+func RootMargin(margins ...string) AttrMutator {
+	if len(margins) == 0 || len(margins) > 4 {
+		panic("RootMargin takes one to four lengths")
+	}
+	for _, m := range margins {
+		if m == "" || strings.ContainsAny(m, " \t\n\r._") {
+			panic("invalid RootMargin length: " + strconv.Quote(m))
+		}
+	}
+	return AttrFunc(func(attr *attrBuilder) {
+		attr.name.WriteString("__rootmargin")
+		for _, m := range margins {
+			attr.name.WriteByte('.')
+			attr.name.WriteString(strings.ToLower(m))
+		}
+	})
+}
+
 type DurationMutatorFunc func(*strings.Builder)
 
 // DurationLeading causes the first interval to fire immediately.
@@ -228,3 +254,39 @@ func Duration(d time.Duration, opts ...DurationMutatorFunc) AttrMutator {
 		}
 	})
 }
+
+// Key filters keyboard events to a single key, matched against the event's
+// key property: Key("Enter") produces __key.enter
+// Key names are lowercased because HTML attribute names are case-insensitive;
+// " " is written as "space" and "." as "period".
+// Example: OnKeyDown(Key("Escape"), Raw("$open = false"))
+func Key(key string) AttrMutator {
+	return KeyCombo(key)
+}
+
+// KeyCombo filters keyboard events to a key pressed together with modifier
+// keys (ctrl, shift, alt, meta), with the key itself last:
+// KeyCombo("ctrl", "s") produces __key.ctrl.s
+// Example: OnKeyDown(Window(), KeyCombo("ctrl", "s"), PreventDefault(), Post("/save"))
+func KeyCombo(keys ...string) AttrMutator {
+	return AttrFunc(func(attr *attrBuilder) {
+		attr.name.WriteString("__key")
+		for _, key := range keys {
+			attr.name.WriteByte('.')
+			attr.name.WriteString(keyName(key))
+		}
+	})
+}
+
+// keyName converts a KeyboardEvent.key value to its modifier form.
+func keyName(key string) string {
+	switch key {
+	case " ":
+		return "space"
+	case ".":
+		return "period"
+	case "Control":
+		return "ctrl"
+	}
+	return strings.ToLower(key)
+}
@@ -0,0 +1,29 @@
+package ds
+
+import "github.com/jeffh/htmlgen/js"
+
+// OncePerSession guards expr so it runs at most once per browser tab
+// session, recording key in sessionStorage. Use it with OnLoad or Init for
+// one-time hints:
+//
+//	h.Div(ds.Init(ds.OncePerSession("welcome-toast", ds.Raw("$showWelcome = true"))))
+//
+// Produces: !sessionStorage.getItem("welcome-toast") && (sessionStorage.setItem("welcome-toast", "1"), $showWelcome = true)
+func OncePerSession(key string, expr Value) Value {
+	return oncePer(js.SessionStorage, key, expr)
+}
+
+// OncePerBrowser is like OncePerSession but records key in localStorage,
+// so expr runs at most once per browser profile, for onboarding tours and
+// client-side migrations.
+func OncePerBrowser(key string, expr Value) Value {
+	return oncePer(js.LocalStorage, key, expr)
+}
+
+func oncePer(storage js.Callable, key string, expr Value) Value {
+	k := js.String(key)
+	return V(js.And(
+		js.Not(js.Method(storage, "getItem", k)),
+		js.Comma(js.Method(storage, "setItem", k, js.String("1")), expr.expr),
+	))
+}
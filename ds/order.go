@@ -0,0 +1,204 @@
+package ds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// DependsOn declares the signals an Effect or Init expression reads, for
+// readers and for ValidateInitOrder, beyond the $signal references it finds
+// in the expression itself. The names are written as a leading comment,
+// which Datastar ignores:
+//
+//	ds.Effect(ds.DependsOn("user", "prefs"), ds.Raw("applyTheme()"))
+//
+// Produces: data-effect="/* deps: user, prefs */ applyTheme()"
+//
+// Panics with ErrInvalidSignalName if a name is invalid.
+func DependsOn(signals ...string) AttrMutator {
+	names := make([]string, len(signals))
+	for i, name := range signals {
+		if err := ValidateSignalName(name); err != nil {
+			panic(err)
+		}
+		names[i] = strings.TrimPrefix(name, "$")
+	}
+	return AttrFunc(func(attr *attrBuilder) {
+		attr.hints = append(attr.hints, "deps: "+strings.Join(names, ", "))
+	})
+}
+
+// Priority documents that an Effect or Init should run before those with a
+// lower priority. Datastar runs them in document order, so the hint is not
+// enforced in the browser; ValidateInitOrder reports an Effect or Init that
+// comes after one with a lower priority.
+//
+//	ds.Init(ds.Priority(10), ds.Raw("$ready = true"))
+//
+// Produces: data-init="/* priority: 10 */ $ready = true"
+func Priority(n int) AttrMutator {
+	return AttrFunc(func(attr *attrBuilder) {
+		attr.hints = append(attr.hints, "priority: "+strconv.Itoa(n))
+	})
+}
+
+// ErrInitOrder is wrapped by InitOrderError.
+var ErrInitOrder = errors.New("initialization race in Datastar attributes")
+
+// InitOrderError describes a data-effect or data-init attribute that can run
+// before what it depends on, found by ValidateInitOrder.
+type InitOrderError struct {
+	Element string // Element name
+	Attr    string // The data-effect or data-init attribute
+	Signal  string // The signal defined later, or empty for a priority conflict
+	Problem string // What goes wrong at runtime
+	Hint    string // How to fix it
+}
+
+func (e *InitOrderError) Error() string {
+	return fmt.Sprintf("<%s>: %v: %s: %s; %s", e.Element, ErrInitOrder, e.Attr, e.Problem, e.Hint)
+}
+
+func (e *InitOrderError) Unwrap() error { return ErrInitOrder }
+
+// ValidateInitOrder renders b without output and returns an InitOrderError,
+// joined, for each Effect or Init that can run too early, or nil. Rendering
+// errors are returned as-is. It reports:
+//
+//   - A signal read by an Effect or Init (a $signal reference or a name
+//     from DependsOn) that is defined later in the document by
+//     data-signals, data-computed, data-bind, data-ref, or data-indicator,
+//     so the expression first runs without it.
+//   - An Effect or Init that comes after one with a lower Priority.
+//
+// Signals that are never defined are not reported, since the server may
+// patch them in. Intended for test suites and development builds:
+//
+//	if err := ds.ValidateInitOrder(page); err != nil {
+//	    t.Error(err)
+//	}
+func ValidateInitOrder(b h.Builder) error {
+	tracker := &initOrderTracker{}
+	ctx := h.WithElementHooks(context.Background(), tracker)
+	if err := h.RenderContext(ctx, io.Discard, b); err != nil {
+		return err
+	}
+	return errors.Join(tracker.errs...)
+}
+
+// initRead is an Effect or Init seen by initOrderTracker.
+type initRead struct {
+	element, attr string
+	signal        string // For reads waiting on a definition
+	priority      int
+}
+
+// initOrderTracker is the ElementHook used by ValidateInitOrder.
+type initOrderTracker struct {
+	defined     []string
+	pending     []initRead // Reads of signals not yet defined
+	prioritized []initRead
+	errs        []error
+}
+
+func (t *initOrderTracker) BeforeOpen(w *h.Writer, tag string, as h.Attributes) (h.Attributes, error) {
+	// Definitions on the element itself are treated as coming first.
+	for _, a := range as {
+		for _, def := range signalDefinitions(a) {
+			t.define(def, a.Name)
+		}
+	}
+	for _, a := range as {
+		base, _, _ := strings.Cut(strings.ToLower(a.Name), "__")
+		if base != "data-effect" && base != "data-init" {
+			continue
+		}
+		deps, priority, hasPriority := parseHints(a.Value)
+		for _, m := range signalRefPattern.FindAllStringSubmatch(a.Value, -1) {
+			deps = append(deps, m[1])
+		}
+		for _, sig := range deps {
+			if !t.isDefined(sig) {
+				t.pending = append(t.pending, initRead{element: tag, attr: a.Name, signal: sig})
+			}
+		}
+		if !hasPriority {
+			continue
+		}
+		for _, prev := range t.prioritized {
+			if prev.priority < priority {
+				t.errs = append(t.errs, &InitOrderError{
+					Element: tag, Attr: a.Name,
+					Problem: fmt.Sprintf("priority %d runs after %s on <%s> with priority %d", priority, prev.attr, prev.element, prev.priority),
+					Hint:    "move it before the lower priority one",
+				})
+				break
+			}
+		}
+		t.prioritized = append(t.prioritized, initRead{element: tag, attr: a.Name, priority: priority})
+	}
+	return as, nil
+}
+
+func (t *initOrderTracker) AfterClose(w *h.Writer, tag string, as h.Attributes) error { return nil }
+
+// define records sig as defined by attr, reporting the reads waiting on it.
+func (t *initOrderTracker) define(sig, attr string) {
+	waiting := t.pending[:0]
+	for _, read := range t.pending {
+		if !sameSignal(read.signal, sig) {
+			waiting = append(waiting, read)
+			continue
+		}
+		t.errs = append(t.errs, &InitOrderError{
+			Element: read.element, Attr: read.attr, Signal: read.signal,
+			Problem: fmt.Sprintf("reads $%s before %s defines it later in the document", read.signal, attr),
+			Hint:    "define the signal on this element or an earlier one",
+		})
+	}
+	t.pending = waiting
+	t.defined = append(t.defined, sig)
+}
+
+func (t *initOrderTracker) isDefined(sig string) bool {
+	for _, def := range t.defined {
+		if sameSignal(sig, def) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameSignal reports whether a and b name the same signal, or one is nested
+// in the other.
+func sameSignal(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// hintPattern matches the comment written by DependsOn and Priority.
+var hintPattern = regexp.MustCompile(`^/\* (.*?) \*/ `)
+
+// parseHints returns the ordering hints at the start of an attribute value.
+func parseHints(value string) (deps []string, priority int, hasPriority bool) {
+	m := hintPattern.FindStringSubmatch(value)
+	if m == nil {
+		return nil, 0, false
+	}
+	for hint := range strings.SplitSeq(m[1], "; ") {
+		if names, ok := strings.CutPrefix(hint, "deps: "); ok {
+			deps = append(deps, strings.Split(names, ", ")...)
+		} else if n, ok := strings.CutPrefix(hint, "priority: "); ok {
+			if p, err := strconv.Atoi(n); err == nil {
+				priority, hasPriority = p, true
+			}
+		}
+	}
+	return deps, priority, hasPriority
+}
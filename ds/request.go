@@ -0,0 +1,151 @@
+package ds
+
+import (
+	"strings"
+
+	"github.com/jeffh/htmlgen/js"
+)
+
+// Content types for RequestBuilder.ContentType and RequestOptionsBuilder.ContentType.
+const (
+	ContentJSON = "json"
+	ContentForm = "form"
+)
+
+// RequestBuilder builds a Datastar request action with chained calls, so
+// requests with many options read top to bottom and options can be added
+// conditionally:
+//
+//	req := ds.Request("/api/search").Method(http.MethodPost).ContentType(ds.ContentForm)
+//	if live {
+//	    req = req.OpenWhenHidden(true)
+//	}
+//	ds.OnSubmit(ds.PreventDefault(), req.Retry("error").OnSuccess(ds.Raw("$saved = true")))
+//
+// Produces: @post("/api/search", {contentType: "form", retry: "error"}).then(() => $saved = true)
+//
+// A RequestBuilder is an AttrMutator; Value returns the action for use in
+// larger expressions. Builders are values, so branching from a shared
+// prefix does not affect the original.
+type RequestBuilder struct {
+	method string
+	path   js.Expr
+	opts   RequestOptionsBuilder
+	chains []PromiseChain
+}
+
+// Request starts a GET request to path. Use Method to change the method.
+func Request(path string) RequestBuilder {
+	return RequestBuilder{method: "get", path: js.String(path)}
+}
+
+// RequestDynamic starts a GET request to a path computed on the client.
+// Example: RequestDynamic(Raw("`/items/${$id}`"))
+func RequestDynamic(path Value) RequestBuilder {
+	return RequestBuilder{method: "get", path: path.expr}
+}
+
+// Method sets the HTTP method, e.g. http.MethodPost or "post".
+func (b RequestBuilder) Method(method string) RequestBuilder {
+	b.method = strings.ToLower(method)
+	return b
+}
+
+// ContentType sets the request content type: ContentJSON (default) or ContentForm.
+func (b RequestBuilder) ContentType(ct string) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.ContentType(ct) })
+}
+
+// Selector specifies a CSS selector for the form to send with ContentForm.
+func (b RequestBuilder) Selector(sel string) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.Selector(sel) })
+}
+
+// Filter filters which signals are sent with the request.
+func (b RequestBuilder) Filter(filter *FilterOptions) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.FilterSignals(filter) })
+}
+
+// Headers sets custom HTTP headers for the request.
+func (b RequestBuilder) Headers(headers map[string]string) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.Headers(headers) })
+}
+
+// CSRFHeader sends token in the csrf.HeaderName header.
+func (b RequestBuilder) CSRFHeader(token string) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.CSRFHeader(token) })
+}
+
+// CSRFHeaderFromMeta sends the token from the page's csrf.Meta tag in the
+// csrf.HeaderName header.
+func (b RequestBuilder) CSRFHeaderFromMeta() RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.CSRFHeaderFromMeta() })
+}
+
+// OpenWhenHidden keeps the connection alive when the tab is hidden.
+func (b RequestBuilder) OpenWhenHidden(open bool) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.OpenWhenHidden(open) })
+}
+
+// Retry sets the retry strategy: "auto", "error", "always", or "never".
+func (b RequestBuilder) Retry(mode string) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.Retry(mode) })
+}
+
+// RetryInterval sets the retry interval in milliseconds.
+func (b RequestBuilder) RetryInterval(ms int) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.RetryInterval(ms) })
+}
+
+// RetryMaxCount sets the maximum number of retry attempts.
+func (b RequestBuilder) RetryMaxCount(count int) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.RetryMaxCount(count) })
+}
+
+// RequestCancellation sets the request cancellation mode: "auto" or "disabled".
+func (b RequestBuilder) RequestCancellation(mode string) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.RequestCancellation(mode) })
+}
+
+// Payload overrides the request body with custom JSON data.
+func (b RequestBuilder) Payload(data any) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder { return o.Payload(data) })
+}
+
+// Options appends every option from opts, for sharing option sets between
+// requests.
+func (b RequestBuilder) Options(opts RequestOptionsBuilder) RequestBuilder {
+	return b.withOptions(func(o RequestOptionsBuilder) RequestOptionsBuilder {
+		o.options = append(o.options, opts.options...)
+		return o
+	})
+}
+
+// OnSuccess chains expr to run when the request succeeds.
+func (b RequestBuilder) OnSuccess(expr Value) RequestBuilder {
+	b.chains = append(b.chains[:len(b.chains):len(b.chains)], ThenChain(expr.expr))
+	return b
+}
+
+// OnFailure chains expr to run when the request fails. The error is
+// available as error.
+func (b RequestBuilder) OnFailure(expr Value) RequestBuilder {
+	b.chains = append(b.chains[:len(b.chains):len(b.chains)], CatchChain(expr.expr))
+	return b
+}
+
+// Value returns the request action.
+func (b RequestBuilder) Value() Value {
+	return requestValueWithOptions(b.method, b.path, b.opts, b.chains...)
+}
+
+// Modify implements AttrMutator
+func (b RequestBuilder) Modify(attr *attrBuilder) {
+	b.Value().Modify(attr)
+}
+
+func (b RequestBuilder) withOptions(f func(RequestOptionsBuilder) RequestOptionsBuilder) RequestBuilder {
+	b.opts.options = b.opts.options[:len(b.opts.options):len(b.opts.options)]
+	b.opts = f(b.opts)
+	return b
+}
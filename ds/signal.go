@@ -0,0 +1,70 @@
+package ds
+
+import (
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+// SignalHandle is a typed reference to a single Datastar signal. Declare it
+// once with NewSignal and use it wherever the signal is defined, read, or
+// written, so its name cannot drift between attributes:
+//
+//	count := ds.NewSignal("count", 0)
+//	h.Div(
+//	    count.Attr(),
+//	    h.Span(ds.Text(count.Ref())),
+//	    h.Button(ds.OnClick(count.Incr()), h.Text("+1")),
+//	    h.Button(ds.OnClick(count.Set(0)), h.Text("Reset")),
+//	)
+//
+// (SignalRef is already the name of the untyped $name helper, so the
+// typed handle is called SignalHandle.)
+type SignalHandle[T any] struct {
+	name    string
+	initial T
+}
+
+// NewSignal declares a signal with an initial value. A leading "$" in name
+// is ignored.
+func NewSignal[T any](name string, initial T) SignalHandle[T] {
+	return SignalHandle[T]{name: strings.TrimPrefix(name, "$"), initial: initial}
+}
+
+// Name returns the signal name without the "$" prefix.
+func (s SignalHandle[T]) Name() string { return s.name }
+
+// Initial returns the value the signal is declared with.
+func (s SignalHandle[T]) Initial() T { return s.initial }
+
+// Attr defines the signal with its initial value: data-signals:count="0"
+func (s SignalHandle[T]) Attr() h.Attribute { return Signal(s.name, s.initial) }
+
+// Bind binds the signal to the element's value: data-bind="count"
+func (s SignalHandle[T]) Bind() h.Attribute { return Bind(s.name) }
+
+// Ref references the signal in Datastar expressions: $count
+// Use it with Text, Show, Class, and the other expression attributes.
+func (s SignalHandle[T]) Ref() Value { return SignalRef(s.name) }
+
+// Expr returns the signal as a js.Callable for building larger expressions
+// with the js package: js.Add(count.Expr(), js.Int(1))
+func (s SignalHandle[T]) Expr() js.Callable { return SignalIdent(s.name) }
+
+// Set assigns a value of the signal's type: $count = 5
+func (s SignalHandle[T]) Set(value T) Value { return s.SetExpr(js.JSON(value)) }
+
+// SetExpr assigns an arbitrary expression: $count = $count * 2
+func (s SignalHandle[T]) SetExpr(expr js.Expr) Value {
+	return Raw(js.ToJSStmt(js.Assign(s.Expr(), expr)))
+}
+
+// Incr increments the signal: $count++
+func (s SignalHandle[T]) Incr() Value { return V(js.PostIncr(s.Expr())) }
+
+// Decr decrements the signal: $count--
+func (s SignalHandle[T]) Decr() Value { return V(js.PostDecr(s.Expr())) }
+
+// Toggle negates the signal: $open = !$open
+func (s SignalHandle[T]) Toggle() Value { return s.SetExpr(js.Not(s.Expr())) }
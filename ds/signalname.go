@@ -0,0 +1,227 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// ErrInvalidSignalName is returned by ValidateSignalName and TrySignal, and
+// is the cause of the panic from Signal and the other signal constructors,
+// when a signal name does not follow Datastar's naming rules.
+var ErrInvalidSignalName = errors.New("invalid Datastar signal name")
+
+// ValidateSignalName reports whether name is a valid Datastar signal name:
+// one or more dot-separated segments, each starting with a letter or
+// underscore and containing only letters, digits, and underscores. Hyphens
+// are not allowed: Datastar reads data-signals:my-signal as $mySignal, and
+// $my-signal is a subtraction. "__" is reserved for modifiers. A leading
+// "$" is ignored.
+//
+//	ds.ValidateSignalName("user.firstName") // nil
+//	ds.ValidateSignalName("my signal!")     // error wrapping ErrInvalidSignalName
+func ValidateSignalName(name string) error {
+	name = strings.TrimPrefix(name, "$")
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidSignalName)
+	}
+	if strings.Contains(name, "__") {
+		return fmt.Errorf("%w %q: \"__\" is reserved for modifiers", ErrInvalidSignalName, name)
+	}
+	for seg := range strings.SplitSeq(name, ".") {
+		if seg == "" {
+			return fmt.Errorf("%w %q: empty path segment", ErrInvalidSignalName, name)
+		}
+		for i, r := range seg {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+			case i > 0 && r >= '0' && r <= '9':
+			default:
+				return fmt.Errorf("%w %q: unexpected %q", ErrInvalidSignalName, name, r)
+			}
+		}
+	}
+	return nil
+}
+
+// TrySignal is like Signal but returns an error wrapping
+// ErrInvalidSignalName instead of panicking when name is invalid, for names
+// that come from configuration or user input.
+func TrySignal(name string, defaultJsValue any) (h.Attribute, error) {
+	if _, err := trySignalKey(name); err != nil {
+		return h.Attribute{}, err
+	}
+	return Signal(name, defaultJsValue), nil
+}
+
+// signalKey validates name and returns it as it should appear in an
+// attribute key. Browsers lowercase attribute names, so data-signals:userName
+// would define $username; camelCase names are written in kebab-case
+// (data-signals:user-name), which Datastar converts back to $userName, or to
+// the casing chosen with the Case modifier. Panics if name is invalid.
+func signalKey(name string) string {
+	key, err := trySignalKey(name)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// trySignalKey is signalKey returning an error. Names whose kebab-case key
+// Datastar reads back differently, such as "userID" (data-signals:user-id,
+// read as $userId), are rejected, since SignalRef(name) would not match.
+func trySignalKey(name string) (string, error) {
+	if err := ValidateSignalName(name); err != nil {
+		return "", err
+	}
+	name = strings.TrimPrefix(name, "$")
+	var sb strings.Builder
+	sb.Grow(len(name) + 4)
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 'A' && c <= 'Z' {
+			if i > 0 && wordBoundary(name, i) {
+				sb.WriteByte('-')
+			}
+			c += 'a' - 'A'
+		}
+		sb.WriteByte(c)
+	}
+	key := sb.String()
+	if camel := camelKey(key); camel != name {
+		return "", fmt.Errorf("%w %q: Datastar reads its attribute key %q back as $%s; spell it %q", ErrInvalidSignalName, name, key, camel, camel)
+	}
+	return key, nil
+}
+
+// camelKey converts a kebab-case attribute key to the signal name Datastar
+// reads from it: user.first-name becomes user.firstName.
+func camelKey(key string) string {
+	var sb strings.Builder
+	sb.Grow(len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if c == '-' && i+1 < len(key) && key[i+1] >= 'a' && key[i+1] <= 'z' {
+			i++
+			c = key[i] - ('a' - 'A')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// wordBoundary reports whether the uppercase letter at name[i] starts a new
+// word: after a lowercase letter or digit (userName), or at the end of an
+// acronym followed by a lowercase letter (HTMLParser).
+func wordBoundary(name string, i int) bool {
+	prev := name[i-1]
+	if prev >= 'a' && prev <= 'z' || prev >= '0' && prev <= '9' {
+		return true
+	}
+	if prev >= 'A' && prev <= 'Z' && i+1 < len(name) {
+		next := name[i+1]
+		return next >= 'a' && next <= 'z'
+	}
+	return false
+}
+
+// signalKeyName appends a signal name to an attribute key. See signalKey.
+func signalKeyName(name string) AttrMutator {
+	key := signalKey(name)
+	return appendName(key)
+}
+
+// SignalPath joins segments into a dotted nested signal name that works
+// everywhere a signal name is accepted: Signal(SignalPath("user",
+// "profile", "firstName"), "") produces data-signals:user.profile.first-name,
+// and SignalRef of the same path produces $user.profile.firstName.
+// Panics with ErrInvalidSignalName if a segment is not a valid signal name
+// on its own (see ValidateSignalName), for example because it is empty or
+// contains a dot or hyphen, or if the path would not round-trip through an
+// attribute key, as with "userID".
+func SignalPath(segments ...string) string {
+	for _, seg := range segments {
+		if strings.Contains(seg, ".") {
+			panic(fmt.Errorf("%w %q: path segment contains \".\"", ErrInvalidSignalName, seg))
+		}
+		if strings.HasPrefix(seg, "$") {
+			panic(fmt.Errorf("%w %q: path segment starts with \"$\"", ErrInvalidSignalName, seg))
+		}
+		if err := ValidateSignalName(seg); err != nil {
+			panic(err)
+		}
+	}
+	path := strings.Join(segments, ".")
+	if _, err := trySignalKey(path); err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// SignalsNested defines signals from a map whose keys may be dotted paths
+// or nested maps, merging them into one nested object:
+//
+//	ds.SignalsNested(map[string]any{
+//	    "user.name":  "Ada",
+//	    "user.prefs": map[string]any{"theme": "dark"},
+//	    "count":      0,
+//	})
+//
+// Produces: data-signals="{"count":0,"user":{"name":"Ada","prefs":{"theme":"dark"}}}"
+//
+// Panics with ErrInvalidSignalName if a key is invalid or a path is given
+// both a value and nested signals.
+func SignalsNested(signals map[string]any, modifiers ...AttrMutator) h.Attribute {
+	root := map[string]any{}
+	if err := mergeSignals(root, "", signals); err != nil {
+		panic(err)
+	}
+	return exprAttr("data-signals", append(modifiers[:len(modifiers):len(modifiers)], JsonValue(root))...)
+}
+
+// mergeSignals merges signals into dst, expanding dotted keys. prefix is
+// the path of dst, for error messages.
+func mergeSignals(dst map[string]any, prefix string, signals map[string]any) error {
+	for key, value := range signals {
+		if err := ValidateSignalName(key); err != nil {
+			return err
+		}
+		key = strings.TrimPrefix(key, "$")
+		segs := strings.Split(key, ".")
+		m, path := dst, prefix
+		for _, seg := range segs[:len(segs)-1] {
+			path += seg + "."
+			next, ok := m[seg].(map[string]any)
+			if !ok {
+				if _, exists := m[seg]; exists {
+					return fmt.Errorf("%w %q: has both a value and nested signals", ErrInvalidSignalName, strings.TrimSuffix(path, "."))
+				}
+				next = map[string]any{}
+				m[seg] = next
+			}
+			m = next
+		}
+		last := segs[len(segs)-1]
+		path += last
+		nested, isMap := value.(map[string]any)
+		existing, exists := m[last]
+		existingMap, existingIsMap := existing.(map[string]any)
+		switch {
+		case isMap && (!exists || existingIsMap):
+			if !exists {
+				existingMap = map[string]any{}
+				m[last] = existingMap
+			}
+			if err := mergeSignals(existingMap, path+".", nested); err != nil {
+				return err
+			}
+		case exists:
+			return fmt.Errorf("%w %q: has both a value and nested signals", ErrInvalidSignalName, path)
+		default:
+			m[last] = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Package sse writes Datastar server-sent events: element patches, signal
+// patches, and scripts executed on the client.
+//
+// Together with the ds attribute helpers it allows a complete Datastar
+// application to be built with this module:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//	    var signals struct{ Count int `json:"count"` }
+//	    if err := sse.ReadSignals(r, &signals); err != nil {
+//	        http.Error(w, err.Error(), http.StatusBadRequest)
+//	        return
+//	    }
+//	    stream := sse.NewWriter(w)
+//	    stream.PatchElements(h.Div(h.Attrs("id", "count"), h.Textf("%d", signals.Count)))
+//	    stream.PatchSignals(map[string]any{"count": signals.Count + 1})
+//	}
+package sse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+// Datastar event types.
+const (
+	EventPatchElements = "datastar-patch-elements"
+	EventPatchSignals  = "datastar-patch-signals"
+)
+
+// DefaultRetry is the client reconnect delay assumed by Datastar. It is not
+// sent unless an event overrides it.
+const DefaultRetry = time.Second
+
+// Mode controls how patched elements are applied to the DOM.
+type Mode string
+
+// Element patch modes.
+const (
+	ModeOuter   Mode = "outer" // Morph the target element (default)
+	ModeInner   Mode = "inner" // Morph the target's children
+	ModeReplace Mode = "replace"
+	ModePrepend Mode = "prepend"
+	ModeAppend  Mode = "append"
+	ModeBefore  Mode = "before"
+	ModeAfter   Mode = "after"
+	ModeRemove  Mode = "remove"
+)
+
+// Option configures a single event.
+type Option func(*eventConfig)
+
+type eventConfig struct {
+	id             string
+	retry          time.Duration
+	selector       string
+	mode           Mode
+	viewTransition bool
+	onlyIfMissing  bool
+	keepScript     bool
+}
+
+// WithSelector targets elements matching the CSS selector instead of
+// matching patched elements by id. A selector containing CR or LF fails the
+// send with ErrInvalidField.
+func WithSelector(selector string) Option {
+	return func(c *eventConfig) { c.selector = selector }
+}
+
+// WithMode sets how patched elements are applied. The default is ModeOuter.
+func WithMode(mode Mode) Option {
+	return func(c *eventConfig) { c.mode = mode }
+}
+
+// WithViewTransition applies the patch inside a View Transition.
+func WithViewTransition() Option {
+	return func(c *eventConfig) { c.viewTransition = true }
+}
+
+// WithOnlyIfMissing only patches signals that do not already exist on the client.
+func WithOnlyIfMissing() Option {
+	return func(c *eventConfig) { c.onlyIfMissing = true }
+}
+
+// WithEventID sets the event id, which the browser sends back as
+// Last-Event-ID when reconnecting. An id containing CR or LF fails the send
+// with ErrInvalidField.
+func WithEventID(id string) Option {
+	return func(c *eventConfig) { c.id = id }
+}
+
+// WithRetry sets the client reconnect delay.
+func WithRetry(d time.Duration) Option {
+	return func(c *eventConfig) { c.retry = d }
+}
+
+// WithKeepScript leaves scripts sent by ExecuteScript in the DOM after they run.
+func WithKeepScript() Option {
+	return func(c *eventConfig) { c.keepScript = true }
+}
+
+// Writer writes Datastar events to an HTTP response. It is safe for
+// concurrent use.
+type Writer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	rc  *http.ResponseController
+	buf bytes.Buffer
+}
+
+// NewWriter sets the event stream headers on w, sends them, and returns a
+// Writer for the response.
+func NewWriter(w http.ResponseWriter) *Writer {
+	hdr := w.Header()
+	hdr.Set("Content-Type", "text/event-stream")
+	hdr.Set("Cache-Control", "no-cache")
+	if hdr.Get("Connection") == "" {
+		hdr.Set("Connection", "keep-alive")
+	}
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	rc.Flush()
+	return &Writer{w: w, rc: rc}
+}
+
+// PatchElements renders b and sends it as a datastar-patch-elements event.
+// By default, top-level elements are morphed into existing elements with
+// the same id.
+func (s *Writer) PatchElements(b h.Builder, opts ...Option) error {
+	var html bytes.Buffer
+	if err := h.Render(&html, b); err != nil {
+		return err
+	}
+	return s.patchElements(html.String(), opts)
+}
+
+// RemoveElements removes the elements matching selector.
+func (s *Writer) RemoveElements(selector string, opts ...Option) error {
+	return s.patchElements("", append([]Option{WithSelector(selector), WithMode(ModeRemove)}, opts...))
+}
+
+func (s *Writer) patchElements(html string, opts []Option) error {
+	c := newConfig(opts)
+	var data []string
+	if c.selector != "" {
+		data = append(data, "selector "+c.selector)
+	}
+	if c.mode != "" && c.mode != ModeOuter {
+		data = append(data, "mode "+string(c.mode))
+	}
+	if c.viewTransition {
+		data = append(data, "useViewTransition true")
+	}
+	if html != "" {
+		for line := range strings.Lines(html) {
+			data = append(data, "elements "+strings.TrimRight(line, "\r\n"))
+		}
+	}
+	return s.send(EventPatchElements, c, data)
+}
+
+// PatchSignals merges signals into the client's signals. Setting a signal
+// to nil removes it.
+func (s *Writer) PatchSignals(signals map[string]any, opts ...Option) error {
+	raw, err := json.Marshal(signals)
+	if err != nil {
+		return err
+	}
+	return s.PatchSignalsRaw(raw, opts...)
+}
+
+// PatchSignalsRaw merges pre-encoded JSON into the client's signals.
+func (s *Writer) PatchSignalsRaw(signalsJSON []byte, opts ...Option) error {
+	c := newConfig(opts)
+	var data []string
+	if c.onlyIfMissing {
+		data = append(data, "onlyIfMissing true")
+	}
+	for line := range strings.Lines(string(signalsJSON)) {
+		data = append(data, "signals "+strings.TrimRight(line, "\r\n"))
+	}
+	return s.send(EventPatchSignals, c, data)
+}
+
+// ExecuteScript runs stmt on the client by appending a <script> element to
+// the body. Use js.Stmts to send several statements. The script removes
+// itself after running unless WithKeepScript is given.
+func (s *Writer) ExecuteScript(stmt js.Stmt, opts ...Option) error {
+	c := newConfig(opts)
+	var attrs h.Attributes
+	if !c.keepScript {
+		attrs = h.Attrs("data-effect", "el.remove()")
+	}
+	script := h.Script(attrs, h.Raw(js.ToJSStmt(stmt)))
+	return s.PatchElements(script, append([]Option{WithSelector("body"), WithMode(ModeAppend)}, opts...)...)
+}
+
+func newConfig(opts []Option) eventConfig {
+	var c eventConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// ErrInvalidField is returned when an event id or selector contains CR or
+// LF, which would end its line and let the rest inject event fields.
+var ErrInvalidField = errors.New("line break in event field")
+
+func (s *Writer) send(event string, c eventConfig, data []string) error {
+	if strings.ContainsAny(c.id, "\r\n") {
+		return fmt.Errorf("%w: id %q", ErrInvalidField, c.id)
+	}
+	if strings.ContainsAny(c.selector, "\r\n") {
+		return fmt.Errorf("%w: selector %q", ErrInvalidField, c.selector)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buf.Reset()
+	s.buf.WriteString("event: ")
+	s.buf.WriteString(event)
+	s.buf.WriteByte('\n')
+	if c.id != "" {
+		s.buf.WriteString("id: ")
+		s.buf.WriteString(c.id)
+		s.buf.WriteByte('\n')
+	}
+	if c.retry > 0 && c.retry != DefaultRetry {
+		s.buf.WriteString("retry: ")
+		s.buf.WriteString(strconv.FormatInt(c.retry.Milliseconds(), 10))
+		s.buf.WriteByte('\n')
+	}
+	for _, line := range data {
+		s.buf.WriteString("data: ")
+		s.buf.WriteString(line)
+		s.buf.WriteByte('\n')
+	}
+	s.buf.WriteByte('\n')
+
+	if _, err := s.w.Write(s.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// ReadSignals decodes the signals Datastar sends with a request into v.
+// GET requests carry signals in the "datastar" query parameter; other
+// methods send them as the JSON request body.
+func ReadSignals(r *http.Request, v any) error {
+	if r.Method == http.MethodGet {
+		raw := r.URL.Query().Get("datastar")
+		if raw == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(raw), v)
+	}
+	if r.Body == nil {
+		return nil
+	}
+	err := json.NewDecoder(r.Body).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package sse
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+func TestNewWriterHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewWriter(rec)
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	if !rec.Flushed {
+		t.Error("expected headers to be flushed")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		send     func(*Writer) error
+		expected string
+	}{
+		{
+			"patch elements",
+			func(s *Writer) error {
+				return s.PatchElements(h.Div(h.Attrs("id", "a"), h.Text("hi")))
+			},
+			"event: datastar-patch-elements\ndata: elements <div id=\"a\">hi</div>\n\n",
+		},
+		{
+			"patch elements with options",
+			func(s *Writer) error {
+				return s.PatchElements(h.Raw("<li>1</li>\n<li>2</li>"),
+					WithSelector("#list"), WithMode(ModeAppend), WithViewTransition(),
+					WithEventID("7"), WithRetry(5*time.Second))
+			},
+			"event: datastar-patch-elements\nid: 7\nretry: 5000\n" +
+				"data: selector #list\ndata: mode append\ndata: useViewTransition true\n" +
+				"data: elements <li>1</li>\ndata: elements <li>2</li>\n\n",
+		},
+		{
+			"default mode and retry omitted",
+			func(s *Writer) error {
+				return s.PatchElements(h.Br(), WithMode(ModeOuter), WithRetry(DefaultRetry))
+			},
+			"event: datastar-patch-elements\ndata: elements <br/>\n\n",
+		},
+		{
+			"remove elements",
+			func(s *Writer) error { return s.RemoveElements("#toast") },
+			"event: datastar-patch-elements\ndata: selector #toast\ndata: mode remove\n\n",
+		},
+		{
+			"patch signals",
+			func(s *Writer) error {
+				return s.PatchSignals(map[string]any{"count": 1}, WithOnlyIfMissing())
+			},
+			"event: datastar-patch-signals\ndata: onlyIfMissing true\ndata: signals {\"count\":1}\n\n",
+		},
+		{
+			"execute script",
+			func(s *Writer) error {
+				return s.ExecuteScript(js.ExprStmt(js.Call(js.Ident("alert"), js.String("hi"))))
+			},
+			"event: datastar-patch-elements\ndata: selector body\ndata: mode append\n" +
+				"data: elements <script data-effect=\"el.remove()\">alert(\"hi\")</script>\n\n",
+		},
+		{
+			"execute script kept",
+			func(s *Writer) error {
+				return s.ExecuteScript(js.ExprStmt(js.Ident("go")), WithKeepScript())
+			},
+			"event: datastar-patch-elements\ndata: selector body\ndata: mode append\n" +
+				"data: elements <script>go</script>\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s := NewWriter(rec)
+			if err := tt.send(s); err != nil {
+				t.Fatal(err)
+			}
+			if got := rec.Body.String(); got != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestInvalidField(t *testing.T) {
+	for _, opt := range []Option{
+		WithSelector("#a\ndata: elements <img src=x onerror=alert(1)>"),
+		WithEventID("1\r\nevent: datastar-patch-signals"),
+	} {
+		rec := httptest.NewRecorder()
+		s := NewWriter(rec)
+		if err := s.PatchElements(h.Div(), opt); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("PatchElements() = %v, want ErrInvalidField", err)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("wrote %q", rec.Body.String())
+		}
+	}
+}
+
+func TestReadSignals(t *testing.T) {
+	type signals struct {
+		Count int `json:"count"`
+	}
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected int
+	}{
+		{"get", "GET", "/?datastar=" + `%7B%22count%22%3A3%7D`, "", 3},
+		{"get without signals", "GET", "/", "", 0},
+		{"post", "POST", "/", `{"count":4}`, 4},
+		{"post empty", "POST", "/", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			var got signals
+			if err := ReadSignals(r, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Count != tt.expected {
+				t.Errorf("Count = %d, want %d", got.Count, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package ds
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// StructSignals is the set of signals derived from a view-model struct by
+// SignalsFromStruct.
+type StructSignals struct {
+	attr  h.Attribute
+	base  uintptr
+	paths map[fieldKey]string
+}
+
+// fieldKey identifies a field by its offset from the struct and its type.
+// The type disambiguates a nested struct from its own first field.
+type fieldKey struct {
+	offset uintptr
+	typ    reflect.Type
+}
+
+// SignalsFromStruct derives data-signals from a struct so the server-side
+// view model and the client-side signals share one definition:
+//
+//	type Form struct {
+//	    Query string
+//	    Page  int
+//	    User  struct {
+//	        Name  string `ds:"name"`
+//	        Admin bool   `ds:"-"`
+//	    }
+//	}
+//	vm := &Form{Page: 1}
+//	sigs := ds.SignalsFromStruct(vm)
+//	query := ds.SignalField(sigs, &vm.Query) // SignalHandle[string]: $query
+//	name := ds.SignalField(sigs, &vm.User.Name) // $user.name
+//	h.Div(sigs.Attr(), h.Input(query.Bind()), h.Span(ds.Text(name.Ref())))
+//
+// Signal names come from the ds struct tag, then the json tag, then the
+// field name with its first letter lowercased. A name of "-" skips the
+// field, as do unexported fields. Nested structs become nested signals and
+// embedded structs are flattened. Structs that implement json.Marshaler or
+// encoding.TextMarshaler, such as time.Time, are single values.
+//
+// modifiers are applied to the data-signals attribute, e.g. IfMissing().
+// SignalsFromStruct panics if v is not a non-nil pointer to a struct.
+func SignalsFromStruct[T any](v *T, modifiers ...AttrMutator) StructSignals {
+	rv := reflect.ValueOf(v)
+	if v == nil || rv.Elem().Kind() != reflect.Struct {
+		panic("ds.SignalsFromStruct: v must be a non-nil pointer to a struct")
+	}
+	s := StructSignals{base: rv.Pointer(), paths: map[fieldKey]string{}}
+	values := s.collect(rv.Elem(), "")
+	s.attr = exprAttr("data-signals", append(modifiers[:len(modifiers):len(modifiers)], JsonValue(values))...)
+	return s
+}
+
+var (
+	jsonMarshaler = reflect.TypeFor[json.Marshaler]()
+	textMarshaler = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// collect records the signal path of each field of rv and returns the
+// signal values as a JSON-ready map.
+func (s *StructSignals) collect(rv reflect.Value, prefix string) map[string]any {
+	values := map[string]any{}
+	for i := range rv.NumField() {
+		f := rv.Type().Field(i)
+		fv := rv.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("ds") == "" && f.Tag.Get("json") == "" {
+			for k, v := range s.collect(fv, prefix) {
+				values[k] = v
+			}
+			continue
+		}
+		name := signalFieldName(f)
+		if name == "" {
+			continue
+		}
+		path := prefix + name
+		s.paths[fieldKey{fv.Addr().Pointer() - s.base, f.Type}] = path
+		if isNestedSignals(f.Type) {
+			values[name] = s.collect(fv, path+".")
+		} else {
+			values[name] = fv.Interface()
+		}
+	}
+	return values
+}
+
+// signalFieldName returns the signal name for f, or "" to skip it.
+func signalFieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	for _, key := range []string{"ds", "json"} {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	r, size := utf8.DecodeRuneInString(f.Name)
+	return string(unicode.ToLower(r)) + f.Name[size:]
+}
+
+func isNestedSignals(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !t.Implements(jsonMarshaler) && !t.Implements(textMarshaler) &&
+		!reflect.PointerTo(t).Implements(jsonMarshaler) && !reflect.PointerTo(t).Implements(textMarshaler)
+}
+
+// Attr returns the data-signals attribute defining every signal with the
+// struct's values at the time SignalsFromStruct was called.
+func (s StructSignals) Attr() h.Attribute { return s.attr }
+
+// SignalField returns a typed handle for the signal derived from field,
+// which must point into the struct passed to SignalsFromStruct. The
+// handle's initial value is the field's current value.
+//
+// SignalField panics if field is not a signal field of that struct.
+func SignalField[F any](s StructSignals, field *F) SignalHandle[F] {
+	if field == nil {
+		panic("ds.SignalField: nil field")
+	}
+	ptr := reflect.ValueOf(field).Pointer()
+	path, ok := "", false
+	if ptr >= s.base {
+		path, ok = s.paths[fieldKey{ptr - s.base, reflect.TypeFor[F]()}]
+	}
+	if !ok {
+		panic("ds.SignalField: field is not a signal of the struct passed to SignalsFromStruct")
+	}
+	return NewSignal(path, *field)
+}
@@ -0,0 +1,61 @@
+package ds
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidStyleProperty is returned by ValidateStyleProperty, and is the
+// cause of the panic from Style and StyleKey, when a CSS property name
+// cannot be written in a data-style attribute.
+var ErrInvalidStyleProperty = errors.New("invalid CSS property name")
+
+// StyleProp is a CSS property name for StyleKey. The constants cover the
+// properties most often bound to signals; any valid name can be converted.
+type StyleProp string
+
+const (
+	StyleDisplay         StyleProp = "display"
+	StyleVisibility      StyleProp = "visibility"
+	StyleOpacity         StyleProp = "opacity"
+	StyleColor           StyleProp = "color"
+	StyleBackgroundColor StyleProp = "background-color"
+	StyleBorderColor     StyleProp = "border-color"
+	StyleWidth           StyleProp = "width"
+	StyleHeight          StyleProp = "height"
+	StyleTop             StyleProp = "top"
+	StyleLeft            StyleProp = "left"
+	StyleTransform       StyleProp = "transform"
+	StyleCursor          StyleProp = "cursor"
+	StylePointerEvents   StyleProp = "pointer-events"
+)
+
+// ValidateStyleProperty reports whether name is a CSS property name that
+// can follow data-style: lowercase letters, digits, hyphens, and underscores, starting
+// with a letter or a hyphen, as in "-webkit-line-clamp" and custom
+// properties like "--accent". Uppercase letters are rejected because HTML
+// lowercases attribute names, and "__" is reserved for modifiers.
+//
+//	ds.ValidateStyleProperty("background-color") // nil
+//	ds.ValidateStyleProperty("backgroundColor")  // error wrapping ErrInvalidStyleProperty
+func ValidateStyleProperty(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidStyleProperty)
+	}
+	if strings.Trim(name, "-") == "" {
+		return fmt.Errorf("%w %q: missing name after hyphen", ErrInvalidStyleProperty, name)
+	}
+	if strings.Contains(name, "__") {
+		return fmt.Errorf("%w %q: \"__\" is reserved for modifiers", ErrInvalidStyleProperty, name)
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r == '-':
+		case i > 0 && (r >= '0' && r <= '9' || r == '_'):
+		default:
+			return fmt.Errorf("%w %q: unexpected %q", ErrInvalidStyleProperty, name, r)
+		}
+	}
+	return nil
+}
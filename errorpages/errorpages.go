@@ -0,0 +1,160 @@
+// Package errorpages provides accessible default error pages (404, 500,
+// maintenance, ...) built from h components, with per-status overrides.
+//
+// Install the pages as the error path of h.ErrorHandlerFunc handlers:
+//
+//	errorpages.Register(http.StatusNotFound, func(r *http.Request, err error) h.Builder {
+//	    return layout("Not found", h.P(h.Text("No such page.")))
+//	})
+//	errorpages.Install()
+//
+// Error details are never rendered, so internal errors do not leak to clients.
+package errorpages
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// PageFunc builds the page for an error response. err is the error that
+// caused the response and may be nil.
+type PageFunc func(r *http.Request, err error) h.Builder
+
+var (
+	mu    sync.RWMutex
+	pages = map[int]PageFunc{}
+)
+
+// Register overrides the page rendered for status. Passing a nil fn restores
+// the default page.
+func Register(status int, fn PageFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	if fn == nil {
+		delete(pages, status)
+	} else {
+		pages[status] = fn
+	}
+}
+
+// Page returns the registered page for status, or the default page.
+func Page(r *http.Request, status int, err error) h.Builder {
+	mu.RLock()
+	fn := pages[status]
+	mu.RUnlock()
+	if fn != nil {
+		return fn(r, err)
+	}
+	return Default(status)
+}
+
+// Handle writes the error page for err with the status from h.StatusCode.
+// It has the signature of h.HTTPErrorHandler.
+func Handle(w http.ResponseWriter, r *http.Request, err error) {
+	Write(w, r, h.StatusCode(err), err)
+}
+
+// Write writes the error page for status to w. The page is rendered before
+// anything is written; if rendering fails, the error is logged and a plain
+// text response with the status text is written instead.
+func Write(w http.ResponseWriter, r *http.Request, status int, err error) {
+	hdr := w.Header()
+	hdr.Del("Content-Encoding")
+	hdr.Del("Content-Length")
+	hdr.Set("Cache-Control", "no-store")
+	var buf bytes.Buffer
+	if r.Method != http.MethodHead {
+		if rerr := h.RenderContext(r.Context(), &buf, Page(r, status, err)); rerr != nil {
+			log.Printf("errorpages: rendering %d page for %s %s: %v", status, r.Method, r.URL.Path, rerr)
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+	}
+	hdr.Set("Content-Type", h.ContentTypeHTML)
+	w.WriteHeader(status)
+	if _, werr := w.Write(buf.Bytes()); werr != nil {
+		log.Printf("errorpages: writing %d page for %s %s: %v", status, r.Method, r.URL.Path, werr)
+	}
+}
+
+// Install sets h.HTTPErrorHandler to Handle so that errors returned from
+// h.ErrorHandlerFunc handlers render error pages.
+func Install() {
+	h.HTTPErrorHandler = Handle
+}
+
+// NotFound returns a handler that responds with the 404 page, suitable as
+// a catch-all route.
+func NotFound() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, http.StatusNotFound, nil)
+	})
+}
+
+// Maintenance returns a handler that responds with the 503 page and a
+// Retry-After header, for use while a site is down for maintenance.
+func Maintenance(retryAfter time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter/time.Second)))
+		}
+		Write(w, r, http.StatusServiceUnavailable, nil)
+	})
+}
+
+// messages are the default explanations shown for common statuses.
+var messages = map[int]string{
+	http.StatusBadRequest:          "The request could not be understood.",
+	http.StatusUnauthorized:        "You need to sign in to view this page.",
+	http.StatusForbidden:           "You don't have permission to view this page.",
+	http.StatusNotFound:            "The page you were looking for doesn't exist or has moved.",
+	http.StatusMethodNotAllowed:    "This action isn't supported here.",
+	http.StatusTooManyRequests:     "Too many requests. Please wait a moment and try again.",
+	http.StatusInternalServerError: "Something went wrong on our end. Please try again later.",
+	http.StatusBadGateway:          "We're having trouble reaching an upstream service. Please try again later.",
+	http.StatusServiceUnavailable:  "We're down for maintenance and will be back shortly.",
+	http.StatusGatewayTimeout:      "The server took too long to respond. Please try again later.",
+}
+
+const defaultStyle = `body{margin:0;font-family:system-ui,sans-serif;color:#1f2328;background:#f6f8fa}` +
+	`main{max-width:32rem;margin:15vh auto;padding:2rem;text-align:center}` +
+	`h1{font-size:4rem;margin:0;color:#57606a}` +
+	`h2{font-size:1.5rem;margin:.5rem 0 1rem}` +
+	`a{color:#0969da}` +
+	`@media (prefers-color-scheme:dark){body{color:#e6edf3;background:#0d1117}h1{color:#8d96a0}a{color:#4493f8}}`
+
+// Default returns the built-in page for status: a standalone document with
+// the status code, its text, a short explanation, and a link home.
+func Default(status int) h.Builder {
+	title := http.StatusText(status)
+	if title == "" {
+		title = "Error"
+	}
+	message, ok := messages[status]
+	if !ok {
+		message = "An unexpected error occurred."
+	}
+	return h.Html(
+		h.Head(
+			h.Meta(h.Attrs("charset", "utf-8")),
+			h.Meta(h.Attrs("name", "viewport", "content", "width=device-width, initial-scale=1")),
+			h.Meta(h.Attrs("name", "robots", "content", "noindex")),
+			h.Title(h.Textf("%d %s", status, title)),
+			h.Style(h.Raw(defaultStyle)),
+		),
+		h.Body(
+			h.Main(h.Attrs("aria-labelledby", "error-title"),
+				h.H1(h.Attrs("aria-hidden", "true"), h.Text(strconv.Itoa(status))),
+				h.H2(h.Attrs("id", "error-title"), h.Text(title)),
+				h.P(h.Text(message)),
+				h.P(h.A(h.Attrs("href", "/"), h.Text("Go to the home page"))),
+			),
+		),
+	)
+}
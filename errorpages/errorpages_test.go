@@ -0,0 +1,115 @@
+package errorpages
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		status int
+		want   []string
+	}{
+		{http.StatusNotFound, []string{"<title>404 Not Found</title>", `<h2 id="error-title">Not Found</h2>`, "doesn&#39;t exist"}},
+		{http.StatusInternalServerError, []string{"<title>500 Internal Server Error</title>", "Something went wrong"}},
+		{599, []string{"<title>599 Error</title>", "An unexpected error occurred."}},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			got := h.RenderString(Default(tt.status))
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("missing %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register(http.StatusTeapot, func(r *http.Request, err error) h.Builder {
+		return h.P(h.Text("short and stout"))
+	})
+	defer Register(http.StatusTeapot, nil)
+
+	rec := httptest.NewRecorder()
+	Write(rec, httptest.NewRequest("GET", "/", nil), http.StatusTeapot, nil)
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if got := rec.Body.String(); got != "<p>short and stout</p>" {
+		t.Errorf("body = %q", got)
+	}
+
+	Register(http.StatusTeapot, nil)
+	if got := h.RenderString(Page(nil, http.StatusTeapot, nil)); !strings.Contains(got, "I&#39;m a teapot") {
+		t.Errorf("expected default page after unregistering, got:\n%s", got)
+	}
+}
+
+func TestInstall(t *testing.T) {
+	prev := h.HTTPErrorHandler
+	defer func() { h.HTTPErrorHandler = prev }()
+	Install()
+
+	handler := h.ErrorHandlerFunc(func(r *http.Request) (h.Builder, error) {
+		return nil, h.Error(http.StatusNotFound, errors.New("secret detail"))
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != h.ContentTypeHTML {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "secret detail") || !strings.Contains(body, "404 Not Found") {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Maintenance(5*time.Minute).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "300" {
+		t.Errorf("Retry-After = %q", got)
+	}
+}
+
+func TestWriteUsesRequestNonce(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(h.WithNonce(req.Context(), "abc123"))
+	rec := httptest.NewRecorder()
+	Write(rec, req, http.StatusNotFound, nil)
+	if got := rec.Body.String(); !strings.Contains(got, `<style nonce="abc123">`) {
+		t.Errorf("expected nonced <style> in:\n%s", got)
+	}
+}
+
+func TestWriteRenderError(t *testing.T) {
+	Register(http.StatusTeapot, func(r *http.Request, err error) h.Builder {
+		return h.P(h.Text("costs "), h.Money(100, "ZZZ"))
+	})
+	defer Register(http.StatusTeapot, nil)
+
+	rec := httptest.NewRecorder()
+	Write(rec, httptest.NewRequest("GET", "/", nil), http.StatusTeapot, nil)
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("status = %d", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain") {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Body.String(); got != "I'm a teapot\n" {
+		t.Errorf("body = %q", got)
+	}
+}
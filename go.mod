@@ -2,7 +2,10 @@ module github.com/jeffh/htmlgen
 
 go 1.25.5
 
-require github.com/jeffh/gocheck v0.2.0
+require (
+	github.com/jeffh/gocheck v0.2.0
+	golang.org/x/text v0.31.0
+)
 
 require (
 	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
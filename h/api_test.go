@@ -2,10 +2,15 @@ package h
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"slices"
 	"strings"
 	"testing"
+	"text/template"
+
+	"github.com/jeffh/htmlgen/config"
 )
 
 // errorWriter is a writer that always returns an error
@@ -24,7 +29,7 @@ func TestHtmlWriting(t *testing.T) {
 		{
 			"Basic HTML Writing",
 			`<!DOCTYPE html>
-<html lang="en"><head><link rel="stylesheet" href="style.css" rel="preload"/></head><body><script src="script.js" defer></script></body></html>`,
+<html lang="en"><head><link rel="stylesheet preload" href="style.css"/></head><body><script src="script.js" defer></script></body></html>`,
 			func(w *Writer) {
 				w.Doctype()
 				w.OpenTag("html", Attrs("lang", "en"))
@@ -45,7 +50,7 @@ func TestHtmlWriting(t *testing.T) {
 		{
 			"Tag-api Writing",
 			`<!DOCTYPE html>
-<html lang="en"><head><link rel="stylesheet" href="style.css" rel="preload"/></head><body><script src="script.js" defer></script></body></html>`,
+<html lang="en"><head><link rel="stylesheet preload" href="style.css"/></head><body><script src="script.js" defer></script></body></html>`,
 			func(w *Writer) {
 				b := Html(Attrs("lang", "en"),
 					Head(
@@ -63,7 +68,7 @@ func TestHtmlWriting(t *testing.T) {
 			`<!DOCTYPE html>
 <html lang="en">
   <head>
-    <link rel="stylesheet" href="style.css" rel="preload"/>
+    <link rel="stylesheet preload" href="style.css"/>
   </head>
   <body>
     <script src="script.js" defer>
@@ -348,6 +353,42 @@ func TestAttrIfMultiple(t *testing.T) {
 	}
 }
 
+type testProduct struct {
+	ProvidesAttrs
+	ID  string
+	SKU string
+}
+
+func (p testProduct) Attrs() Attributes {
+	return Attrs("data-product-id", p.ID, "data-sku", p.SKU)
+}
+
+func TestAttrProvider(t *testing.T) {
+	product := testProduct{ID: "7", SKU: "TEA-01"}
+	shared := Attrs("class", "card")
+
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"alone", Div(product), `<div data-product-id="7" data-sku="TEA-01"></div>`},
+		{"later attrs override", Div(product, Attr("data-sku", "X"), Text("Tea")), `<div data-product-id="7" data-sku="X">Tea</div>`},
+		{"merged into attributes", Div(shared, product), `<div class="card" data-product-id="7" data-sku="TEA-01"></div>`},
+		{"pointer", Span(&product), `<span data-product-id="7" data-sku="TEA-01"></span>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+	if len(shared) != 1 {
+		t.Errorf("shared attributes were mutated: %v", shared)
+	}
+}
+
 func TestCloseOneTagError(t *testing.T) {
 	buf := bytes.NewBuffer(nil)
 	w := NewWriter(buf)
@@ -376,6 +417,31 @@ func TestTextEscaping(t *testing.T) {
 	}
 }
 
+func TestWriteHTMLEscape(t *testing.T) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	inputs := []string{
+		"",
+		"plain text",
+		"<script>alert(\"x\")</script> & 'y'",
+		"a\x00b",
+		"trailing &",
+		"héllo <wörld>",
+		string(all),
+	}
+	for _, in := range inputs {
+		var buf bytes.Buffer
+		if err := writeEscapedString(&buf, in); err != nil {
+			t.Fatal(err)
+		}
+		if expected := template.HTMLEscapeString(in); buf.String() != expected {
+			t.Errorf("escape(%q) = %q, want %q", in, buf.String(), expected)
+		}
+	}
+}
+
 func TestRaw(t *testing.T) {
 	buf := bytes.NewBuffer(nil)
 	w := NewWriter(buf)
@@ -1645,3 +1711,321 @@ func TestFragmentBuilderError(t *testing.T) {
 		t.Error("expected error from fragment builder")
 	}
 }
+
+func TestGatedScript(t *testing.T) {
+	got := RenderString(GatedScript("analytics", "https://stats.example.com/s.js", Attrs("async", "")))
+	expected := `<script type="text/plain" data-consent="analytics" data-src="https://stats.example.com/s.js" async></script>`
+	if got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+
+	// Caller attributes cannot make the placeholder executable.
+	got = RenderString(GatedScript("analytics", "/s.js", Attrs("type", "module", "src", "/evil.js", "data-consent", "necessary")))
+	expected = `<script type="text/plain" data-consent="analytics" data-src="/s.js" data-type="module"></script>`
+	if got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+}
+
+func TestTagArgsInterleaved(t *testing.T) {
+	got := RenderString(Div(
+		Class("box"),
+		P(Text("hi")),
+		Attr("hx-get", "/x"),
+		Attrs("id", "main"),
+		Text("!"),
+	))
+	expected := `<div class="box" hx-get="/x" id="main"><p>hi</p>!</div>`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestTagArgsDoNotMutateSharedAttributes(t *testing.T) {
+	base := Attrs("class", "card", "role", "region")
+	first := RenderString(Div(base, Attrs("class", "card wide"), ID("a")))
+	second := RenderString(Div(base))
+	if first != `<div class="card wide" role="region" id="a"></div>` {
+		t.Errorf("unexpected first render: %q", first)
+	}
+	if second != `<div class="card" role="region"></div>` {
+		t.Errorf("shared attributes were mutated: %q", second)
+	}
+}
+
+// countingWriter records how many Write calls reach it.
+type countingWriter struct {
+	strings.Builder
+	writes int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.writes++
+	return c.Builder.Write(p)
+}
+
+func TestBufferedWriter(t *testing.T) {
+	page := Div(Class("list"), Ul(Li(Text("a")), Li(Text("b")), Li(Text("c"))))
+	expected := RenderString(page)
+
+	var out countingWriter
+	w := NewBufferedWriter(&out, 0)
+	if err := page.Build(w); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("expected output to be buffered, got %q", out.String())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if out.writes != 1 {
+		t.Errorf("expected 1 write to the underlying writer, got %d", out.writes)
+	}
+}
+
+func TestBufferedWriterFlushesAtThreshold(t *testing.T) {
+	var out countingWriter
+	w := NewBufferedWriter(&out, 16)
+	if err := w.Text(strings.Repeat("x", 40)); err != nil {
+		t.Fatal(err)
+	}
+	if out.writes == 0 {
+		t.Error("expected a write once the buffer filled")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != strings.Repeat("x", 40) {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestBufferedWriterCloseFlushesOpenTags(t *testing.T) {
+	var out strings.Builder
+	w := NewBufferedWriter(&out, 0)
+	if err := w.OpenTag("section", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "<section></section>" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestUnbufferedWriterFlushIsNoop(t *testing.T) {
+	if err := NewWriter(io.Discard).Flush(); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	defer config.Reset()
+	config.Set(
+		config.Indent("  "),
+		config.BaseURL("/app"),
+		config.Nonce(func(ctx context.Context) string {
+			nonce, _ := ctx.Value(nonceKey{}).(string)
+			return nonce
+		}),
+	)
+
+	page := Div(A(Href("/x")), Script(Raw("go()")), Style(Attr("nonce", "mine")))
+	ctx := context.WithValue(context.Background(), nonceKey{}, "abc")
+	tests := []struct {
+		name     string
+		render   func(io.Writer) error
+		expected string
+	}{
+		{
+			"defaults",
+			func(w io.Writer) error { return RenderContext(ctx, w, page) },
+			"<div>\n  <a href=\"/app/x\">\n  </a>\n  <script nonce=\"abc\">\ngo()\n  </script>\n  <style nonce=\"mine\">\n  </style>\n</div>\n",
+		},
+		{
+			"explicit indent wins",
+			func(w io.Writer) error { return RenderIndent(w, "", page) },
+			`<div><a href="/app/x"></a><script>go()</script><style nonce="mine"></style></div>`,
+		},
+		{
+			"context config",
+			func(w io.Writer) error {
+				return RenderContext(config.WithContext(ctx, config.New(config.Indent(""), config.Nonce(nil))), w, page)
+			},
+			`<div><a href="/app/x"></a><script>go()</script><style nonce="mine"></style></div>`,
+		},
+		{
+			"WithBaseURL wins",
+			func(w io.Writer) error {
+				return RenderContext(WithBaseURL(config.WithContext(ctx, config.New(config.Indent(""))), "/other"), w, A(Href("/x")))
+			},
+			`<a href="/other/x"></a>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.render(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("got %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewline(t *testing.T) {
+	doc := Html(Body(P(Text("hi"))))
+	tests := []struct {
+		name     string
+		opts     []config.Option
+		expected string
+	}{
+		{"compact", nil, `<!DOCTYPE html>` + "\n" + `<html lang="en"><body><p>hi</p></body></html>`},
+		{"crlf", []config.Option{config.Newline(config.CRLF)}, `<!DOCTYPE html>` + "\r\n" + `<html lang="en"><body><p>hi</p></body></html>`},
+		{
+			"crlf indented",
+			[]config.Option{config.Newline(config.CRLF), config.Indent(" ")},
+			"<!DOCTYPE html>\r\n<html lang=\"en\">\r\n <body>\r\n  <p>\r\n   hi\r\n  </p>\r\n </body>\r\n</html>\r\n",
+		},
+		{"trailing", []config.Option{config.TrailingNewline(true)}, `<!DOCTYPE html>` + "\n" + `<html lang="en"><body><p>hi</p></body></html>` + "\n"},
+		{
+			"trailing crlf indented",
+			[]config.Option{config.TrailingNewline(true), config.Newline(config.CRLF), config.Indent(" ")},
+			"<!DOCTYPE html>\r\n<html lang=\"en\">\r\n <body>\r\n  <p>\r\n   hi\r\n  </p>\r\n </body>\r\n</html>\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := config.WithContext(context.Background(), config.New(tt.opts...))
+			if err := RenderContext(ctx, &buf, doc); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("got %q, want %q", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// stateProbe records the Writer's structural state when built.
+type stateProbe struct {
+	depth      int
+	tags       []string
+	bytes      int64
+	components []string
+}
+
+func (p *stateProbe) isTagArg() {}
+
+func (p *stateProbe) Build(w *Writer) error {
+	p.depth = w.Depth()
+	p.tags = w.OpenTags()
+	p.bytes = w.BytesWritten()
+	p.components = w.Components()
+	return nil
+}
+
+func TestWriterState(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(io.Writer, Builder) error
+	}{
+		{"render", Render},
+		{"writer", func(out io.Writer, b Builder) error { return b.Build(NewWriter(out)) }},
+		{"buffered", func(out io.Writer, b Builder) error {
+			w := NewBufferedWriter(out, 0)
+			if err := b.Build(w); err != nil {
+				return err
+			}
+			return w.Flush()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := &stateProbe{}
+			var buf bytes.Buffer
+			page := Named("Page", Div(Named("List", Ul(Li(Text("ab"), probe))), Named("After", P())))
+			if err := tt.write(&buf, page); err != nil {
+				t.Fatal(err)
+			}
+			if probe.depth != 3 {
+				t.Errorf("Depth() = %d, want 3", probe.depth)
+			}
+			if !slices.Equal(probe.tags, []string{"div", "ul", "li"}) {
+				t.Errorf("OpenTags() = %q", probe.tags)
+			}
+			if want := int64(len("<div><ul><li>ab")); probe.bytes != want {
+				t.Errorf("BytesWritten() = %d, want %d", probe.bytes, want)
+			}
+			if !slices.Equal(probe.components, []string{"Page", "List"}) {
+				t.Errorf("Components() = %q", probe.components)
+			}
+		})
+	}
+}
+
+func TestCompileParamsDoctypeNewline(t *testing.T) {
+	tmpl := MustCompileParams(Html(Body()))
+	if got := RenderString(tmpl.With()); !strings.HasPrefix(got, "<!DOCTYPE html>\n<html") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestCompileAttrParams(t *testing.T) {
+	href := NewAttrParam("href")
+	id := NewAttrParam("id")
+	state := NewAttrParam("state")
+	body := NewParam("body")
+	tmpl := MustCompileParams(Div(
+		id.Attr("id"), Class("card"), state.Attr("class"),
+		A(href.Attr("href"), body),
+		Span(id.Attr("data-for")),
+	))
+
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{
+			"values",
+			tmpl.With(href.Value("/users/42"), id.Value("u42"), state.Value("active"), body.Value(Text("Ada"))),
+			`<div id="u42" class="card active"><a href="/users/42">Ada</a><span data-for="u42"></span></div>`,
+		},
+		{
+			"escapes",
+			tmpl.With(href.Value(`/q?a=1&b="2"`), id.Value("<x>")),
+			`<div id="&lt;x&gt;" class="card "><a href="/q?a=1&amp;b=&#34;2&#34;"></a><span data-for="&lt;x&gt;"></span></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	var sb strings.Builder
+	ctx := WithBaseURL(context.Background(), "/acme")
+	if err := RenderContext(ctx, &sb, tmpl.With(href.Value("/users/42"))); err != nil {
+		t.Fatal(err)
+	}
+	if want := `<div id="" class="card "><a href="/acme/users/42"></a><span data-for=""></span></div>`; sb.String() != want {
+		t.Errorf("with base URL expected %q, got %q", want, sb.String())
+	}
+
+	if got := RenderString(A(href.Attr("href"), Text("x"))); got != `<a href>x</a>` {
+		t.Errorf("outside CompileParams got %q", got)
+	}
+}
+
+type nonceKey struct{}
@@ -4,12 +4,16 @@ import (
 	"maps"
 	"slices"
 	"sort"
+	"strings"
 )
 
 // Attribute represents a single HTML attribute as a name-value pair.
 type Attribute struct {
 	Name  string
 	Value string
+
+	untrusted bool       // Value came from an Untrusted (see WithStrictUntrusted)
+	param     *AttrParam // Value is set per render (see NewAttrParam)
 }
 
 func (a Attribute) isTagArg() {}
@@ -45,9 +49,40 @@ type Attributes []Attribute
 
 func (a Attributes) isTagArg() {}
 
+// AttrProvider is implemented by domain types that carry their own
+// attributes, such as a model's id and tracking data-* attributes. Tag
+// functions merge them into the element like Attributes, so a component
+// only has to pass the model along. To be accepted as a TagArg, the type
+// also embeds ProvidesAttrs:
+//
+//	type Product struct {
+//	    h.ProvidesAttrs
+//	    ID  int
+//	    SKU string
+//	}
+//
+//	func (p Product) Attrs() h.Attributes {
+//	    return h.Attrs("data-product-id", strconv.Itoa(p.ID), "data-sku", p.SKU)
+//	}
+//
+//	h.Div(product, h.Class("product"), h.Text(product.Name))
+//	// <div data-product-id="7" data-sku="TEA-01" class="product">...</div>
+//
+// A type that is also a Builder is treated as a child, not an AttrProvider.
+type AttrProvider interface {
+	Attrs() Attributes
+}
+
+// ProvidesAttrs is embedded in an AttrProvider to make it a TagArg.
+type ProvidesAttrs struct{}
+
+func (ProvidesAttrs) isTagArg() {}
+
 // Attrs creates an Attributes slice from alternating key-value string pairs.
 // Panics if an odd number of arguments is provided or if any key is empty.
 //
+// A repeated name is combined as when passed to a tag function.
+//
 // Example: Attrs("href", "/home", "class", "nav-link")
 func Attrs(kv ...string) Attributes {
 	if len(kv)%2 != 0 {
@@ -61,7 +96,7 @@ func Attrs(kv ...string) Attributes {
 		if kv[i] == "" {
 			panic("attribute name cannot be empty")
 		}
-		results = append(results, Attribute{Name: kv[i], Value: kv[i+1]})
+		results.combineAttr(Attribute{Name: kv[i], Value: kv[i+1]})
 	}
 	return results
 }
@@ -73,7 +108,7 @@ func AttrsMap(m map[string]string) Attributes {
 	keys := slices.Collect(maps.Keys(m))
 	sort.Strings(keys)
 	for _, k := range keys {
-		result = append(result, Attribute{k, m[k]})
+		result = append(result, Attribute{Name: k, Value: m[k]})
 	}
 	return result
 }
@@ -104,11 +139,16 @@ func (a *Attributes) Index(key string) int {
 // If the attribute already exists, its value is updated; otherwise,
 // a new attribute is appended.
 func (a *Attributes) Set(key, value string) {
-	idx := a.Index(key)
+	a.setAttr(Attribute{Name: key, Value: value})
+}
+
+// setAttr is Set for a whole Attribute, keeping whether it is untrusted.
+func (a *Attributes) setAttr(attr Attribute) {
+	idx := a.Index(attr.Name)
 	if idx >= 0 {
-		(*a)[idx].Value = value
+		(*a)[idx] = attr
 	} else {
-		*a = append(*a, Attribute{Name: key, Value: value})
+		*a = append(*a, attr)
 	}
 }
 
@@ -138,7 +178,7 @@ func (a *Attributes) Merge(b Attributes) {
 	if len(b) <= 4 {
 		// For small b, use linear search
 		for _, attr := range b {
-			a.Set(attr.Name, attr.Value)
+			a.setAttr(attr)
 		}
 		return
 	}
@@ -150,10 +190,55 @@ func (a *Attributes) Merge(b Attributes) {
 	// Merge attributes from b
 	for _, attr := range b {
 		if idx, exists := index[attr.Name]; exists {
-			(*a)[idx].Value = attr.Value
+			(*a)[idx] = attr
 		} else {
 			*a = append(*a, attr)
 			index[attr.Name] = len(*a) - 1
 		}
 	}
 }
+
+// combine is like Merge, but joins the values of token-list attributes
+// (class and rel) instead of replacing them. Tag functions combine their
+// arguments, so h.Div(h.Class("a"), h.Class("b")) renders class="a b".
+func (a *Attributes) combine(b Attributes) {
+	for _, attr := range b {
+		a.combineAttr(attr)
+	}
+}
+
+// combineAttr adds attr like setAttr, joining token-list values.
+func (a *Attributes) combineAttr(attr Attribute) {
+	idx := a.Index(attr.Name)
+	if idx < 0 {
+		*a = append(*a, attr)
+		return
+	}
+	if isTokenList(attr.Name) {
+		attr.Value = joinTokens((*a)[idx].Value, attr.Value)
+		attr.untrusted = attr.untrusted || (*a)[idx].untrusted
+		if attr.param == nil {
+			attr.param = (*a)[idx].param
+		}
+	}
+	(*a)[idx] = attr
+}
+
+// isTokenList reports whether the attribute holds a space-separated set
+// of tokens whose values are joined when repeated.
+func isTokenList(name string) bool { return name == "class" || name == "rel" }
+
+// joinTokens returns the tokens of a followed by those of b not already
+// in a.
+func joinTokens(a, b string) string {
+	if a == "" {
+		return b
+	}
+	result := a
+	for tok := range strings.FieldsSeq(b) {
+		if !slices.Contains(strings.Fields(result), tok) {
+			result += " " + tok
+		}
+	}
+	return result
+}
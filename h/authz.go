@@ -0,0 +1,123 @@
+package h
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Authorizer decides whether the current render may show content gated
+// by a permission.
+type Authorizer interface {
+	Can(ctx context.Context, perm string) bool
+}
+
+// AuthorizerFunc adapts a function to an Authorizer.
+type AuthorizerFunc func(ctx context.Context, perm string) bool
+
+// Can implements Authorizer.
+func (f AuthorizerFunc) Can(ctx context.Context, perm string) bool { return f(ctx, perm) }
+
+type authorizerKey struct{}
+type authAuditKey struct{}
+
+// WithAuthorizer returns a context whose renders resolve Authorized through a.
+//
+//	ctx := h.WithAuthorizer(r.Context(), h.AuthorizerFunc(func(ctx context.Context, perm string) bool {
+//	    return currentUser(ctx).Can(perm)
+//	}))
+//	h.RenderContext(ctx, w, page)
+func WithAuthorizer(ctx context.Context, a Authorizer) context.Context {
+	return context.WithValue(ctx, authorizerKey{}, a)
+}
+
+// AuthDecision records one permission check made while rendering.
+type AuthDecision struct {
+	Perm    string
+	Granted bool
+	// Path is the element path of the gated content, e.g. "html/body/nav/a".
+	Path string
+}
+
+// AuthAudit collects the permission checks made by Authorized during a
+// render, showing which permissions gated which components on a page.
+// It is safe for concurrent use.
+type AuthAudit struct {
+	mu        sync.Mutex
+	decisions []AuthDecision
+}
+
+// Decisions returns the recorded checks in render order.
+func (a *AuthAudit) Decisions() []AuthDecision {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]AuthDecision(nil), a.decisions...)
+}
+
+func (a *AuthAudit) record(d AuthDecision) {
+	a.mu.Lock()
+	a.decisions = append(a.decisions, d)
+	a.mu.Unlock()
+}
+
+// WithAuthAudit returns a context whose renders record every Authorized
+// check in audit.
+func WithAuthAudit(ctx context.Context, audit *AuthAudit) context.Context {
+	return context.WithValue(ctx, authAuditKey{}, audit)
+}
+
+// Authorized renders b only if the Authorizer in the render context grants
+// perm. Without an Authorizer, nothing is rendered (fail closed). The check
+// happens at render time, so Authorized content must not be pre-rendered
+// with Compile.
+//
+//	h.Nav(
+//	    h.A(h.Href("/"), h.Text("Home")),
+//	    h.Authorized("admin:view", h.A(h.Href("/admin"), h.Text("Admin"))),
+//	)
+func Authorized(perm string, b Builder) Builder {
+	return &authorizedBuilder{perm: perm, ifGranted: b}
+}
+
+// AuthorizedElse renders b if perm is granted, otherwise fallback.
+func AuthorizedElse(perm string, b, fallback Builder) Builder {
+	return &authorizedBuilder{perm: perm, ifGranted: b, ifDenied: fallback}
+}
+
+type authorizedBuilder struct {
+	perm      string
+	ifGranted Builder
+	ifDenied  Builder
+}
+
+func (b *authorizedBuilder) isTagArg() {}
+
+func (b *authorizedBuilder) Build(w *Writer) error {
+	ctx := w.Context()
+	if err := checkShared(ctx, fmt.Sprintf("Authorized(%q)", b.perm)); err != nil {
+		return err
+	}
+	a, _ := ctx.Value(authorizerKey{}).(Authorizer)
+	granted := a != nil && a.Can(ctx, b.perm)
+
+	if audit, _ := ctx.Value(authAuditKey{}).(*AuthAudit); audit != nil {
+		path := strings.Join(w.openTags, "/")
+		if tb, ok := b.ifGranted.(*tagBuilder); ok {
+			if path != "" {
+				path += "/"
+			}
+			path += tb.Name
+		}
+		audit.record(AuthDecision{Perm: b.perm, Granted: granted, Path: path})
+	}
+
+	next := b.ifDenied
+	if granted {
+		next = b.ifGranted
+	}
+	if next == nil {
+		return nil
+	}
+	return next.Build(w)
+}
@@ -0,0 +1,64 @@
+package h
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAuthorized(t *testing.T) {
+	allow := AuthorizerFunc(func(_ context.Context, perm string) bool { return perm == "admin" })
+	page := Nav(
+		Authorized("admin", A(Href("/admin"), Text("Admin"))),
+		AuthorizedElse("billing", A(Href("/billing"), Text("Billing")), Span(Text("No billing"))),
+	)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"no authorizer fails closed", context.Background(), `<nav><span>No billing</span></nav>`},
+		{"authorizer", WithAuthorizer(context.Background(), allow), `<nav><a href="/admin">Admin</a><span>No billing</span></nav>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RenderContext(tt.ctx, &sb, page); err != nil {
+				t.Fatal(err)
+			}
+			if sb.String() != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, sb.String())
+			}
+		})
+	}
+}
+
+func TestAuthAudit(t *testing.T) {
+	var audit AuthAudit
+	ctx := WithAuthAudit(WithAuthorizer(context.Background(), AuthorizerFunc(func(_ context.Context, perm string) bool {
+		return perm == "read"
+	})), &audit)
+
+	page := Body(
+		Authorized("read", Div(Text("content"))),
+		Footer(Authorized("write", Text("edit"))),
+	)
+	if err := RenderContext(ctx, &strings.Builder{}, page); err != nil {
+		t.Fatal(err)
+	}
+	expected := []AuthDecision{
+		{Perm: "read", Granted: true, Path: "body/div"},
+		{Perm: "write", Granted: false, Path: "body/footer"},
+	}
+	if got := audit.Decisions(); !slices.Equal(got, expected) {
+		t.Errorf("Decisions() = %+v, want %+v", got, expected)
+	}
+}
+
+func TestWriterContextDefault(t *testing.T) {
+	if NewWriter(&strings.Builder{}).Context() == nil {
+		t.Error("expected non-nil default context")
+	}
+}
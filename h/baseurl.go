@@ -0,0 +1,97 @@
+package h
+
+import (
+	"context"
+	"strings"
+)
+
+type baseURLKey struct{}
+
+// WithBaseURL returns a context whose renders prefix root-relative URLs with
+// base, so the same Builder code can serve several tenants or locales from
+// path prefixes:
+//
+//	ctx := h.WithBaseURL(r.Context(), "/acme")
+//	h.RenderContext(ctx, w, h.A(h.Href("/settings"), h.Text("Settings")))
+//	// <a href="/acme/settings">Settings</a>
+//
+// See Writer.SetBaseURL for the attributes that are rewritten.
+func WithBaseURL(ctx context.Context, base string) context.Context {
+	return context.WithValue(ctx, baseURLKey{}, base)
+}
+
+// BaseURL returns the base set with WithBaseURL, or "" if none was set.
+func BaseURL(ctx context.Context) string {
+	base, _ := ctx.Value(baseURLKey{}).(string)
+	return base
+}
+
+// urlAttrs are attributes whose whole value is a URL.
+var urlAttrs = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true,
+	"poster": true, "cite": true, "data": true, "background": true,
+	"hx-get": true, "hx-post": true, "hx-put": true, "hx-patch": true,
+	"hx-delete": true, "hx-push-url": true, "hx-replace-url": true,
+}
+
+// rewriteURLAttr prefixes the root-relative URLs in an attribute value with
+// the Writer's base URL.
+func (w *Writer) rewriteURLAttr(name, value string) string {
+	switch {
+	case urlAttrs[name]:
+		return w.prefixURL(value)
+	case name == "srcset" || name == "imagesrcset":
+		candidates := strings.Split(value, ",")
+		for i, c := range candidates {
+			trimmed := strings.TrimLeft(c, " \t\n")
+			candidates[i] = c[:len(c)-len(trimmed)] + w.prefixURL(trimmed)
+		}
+		return strings.Join(candidates, ",")
+	case strings.HasPrefix(name, "data-") && strings.Contains(value, "@"):
+		return w.prefixActions(value)
+	}
+	return value
+}
+
+// prefixURL prefixes u if it is root-relative ("/path" but not "//host")
+// and not already under the base.
+func (w *Writer) prefixURL(u string) string {
+	if !strings.HasPrefix(u, "/") || strings.HasPrefix(u, "//") {
+		return u
+	}
+	if u == w.baseURL || strings.HasPrefix(u, w.baseURL+"/") {
+		return u
+	}
+	return w.baseURL + u
+}
+
+// prefixActions rewrites the URL argument of Datastar backend actions such
+// as @get('/path') in an expression.
+func (w *Writer) prefixActions(expr string) string {
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(expr, '@')
+		if i < 0 {
+			sb.WriteString(expr)
+			return sb.String()
+		}
+		sb.WriteString(expr[:i+1])
+		expr = expr[i+1:]
+		for _, action := range []string{"get(", "post(", "put(", "patch(", "delete("} {
+			if !strings.HasPrefix(expr, action) {
+				continue
+			}
+			sb.WriteString(action)
+			expr = expr[len(action):]
+			if len(expr) > 0 && (expr[0] == '\'' || expr[0] == '"' || expr[0] == '`') {
+				end := strings.IndexByte(expr[1:], expr[0])
+				if end >= 0 {
+					sb.WriteByte(expr[0])
+					sb.WriteString(w.prefixURL(expr[1 : end+1]))
+					expr = expr[end+1:]
+				}
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		b        Builder
+		expected string
+	}{
+		{"href", "/acme", A(Href("/settings")), `<a href="/acme/settings"></a>`},
+		{"trailing slash on base", "/acme/", A(Href("/")), `<a href="/acme/"></a>`},
+		{"absolute base", "https://cdn.example.com/acme", Img(Src("/logo.png")), `<img src="https://cdn.example.com/acme/logo.png"/>`},
+		{"already prefixed", "/acme", A(Href("/acme/settings")), `<a href="/acme/settings"></a>`},
+		{"base itself", "/acme", A(Href("/acme")), `<a href="/acme"></a>`},
+		{"similar prefix", "/acme", A(Href("/acmedia")), `<a href="/acme/acmedia"></a>`},
+		{"relative", "/acme", A(Href("settings")), `<a href="settings"></a>`},
+		{"protocol relative", "/acme", A(Href("//other.example.com/x")), `<a href="//other.example.com/x"></a>`},
+		{"absolute", "/acme", A(Href("https://example.com/x")), `<a href="https://example.com/x"></a>`},
+		{"fragment", "/acme", A(Href("#top")), `<a href="#top"></a>`},
+		{"form action", "/acme", Form(Attrs("action", "/save")), `<form action="/acme/save"></form>`},
+		{"non-url attribute", "/acme", Div(Attrs("title", "/path")), `<div title="/path"></div>`},
+		{"htmx", "/acme", Button(Attrs("hx-post", "/items", "hx-push-url", "true")), `<button hx-post="/acme/items" hx-push-url="true"></button>`},
+		{"srcset", "/acme", Img(Attrs("srcset", "/a.png 1x, /b.png 2x")), `<img srcset="/acme/a.png 1x, /acme/b.png 2x"/>`},
+		{"datastar action", "/acme", Button(Attrs("data-on-click", "@get('/items?page=2')")), `<button data-on-click="@get(&#39;/acme/items?page=2&#39;)"></button>`},
+		{"datastar multiple", "/acme", Div(Attrs("data-init", "@post(\"/a\"); @delete(`/b`)")), `<div data-init="@post(&#34;/acme/a&#34;); @delete(` + "`/acme/b`" + `)"></div>`},
+		{"datastar non-action", "/acme", Div(Attrs("data-text", "$user@'/x'")), `<div data-text="$user@&#39;/x&#39;"></div>`},
+		{"disabled", "", A(Href("/settings")), `<a href="/settings"></a>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := RenderContext(WithBaseURL(context.Background(), tt.base), &buf, tt.b); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBaseURLContext(t *testing.T) {
+	ctx := WithBaseURL(context.Background(), "/globex")
+	if got := BaseURL(ctx); got != "/globex" {
+		t.Errorf("BaseURL = %q, want /globex", got)
+	}
+	if got := BaseURL(context.Background()); got != "" {
+		t.Errorf("BaseURL without base = %q, want empty", got)
+	}
+}
+
+func TestBaseURLPooledWriterReset(t *testing.T) {
+	var buf bytes.Buffer
+	if err := RenderContext(WithBaseURL(context.Background(), "/acme"), &buf, A(Href("/x"))); err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderString(A(Href("/x"))); got != `<a href="/x"></a>` {
+		t.Errorf("base URL leaked into a later render: %s", got)
+	}
+}
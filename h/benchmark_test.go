@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"html/template"
 	"io"
+	"strings"
 	"testing"
 )
 
@@ -369,6 +370,56 @@ func BenchmarkEscaping_Template(b *testing.B) {
 	}
 }
 
+// Escape scan benchmarks: plain text is the common case and needs no
+// escaping; escape-heavy text exercises the replacement path.
+var (
+	escapeClean = strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	escapeDirty = strings.Repeat(`<script>alert("XSS")</script> & "quotes" 'apostrophe' `, 10)
+)
+
+func BenchmarkEscapeScan_Clean(b *testing.B) {
+	b.SetBytes(int64(len(escapeClean)))
+	for b.Loop() {
+		writeEscapedString(io.Discard, escapeClean)
+	}
+}
+
+func BenchmarkEscapeScan_Dirty(b *testing.B) {
+	b.SetBytes(int64(len(escapeDirty)))
+	for b.Loop() {
+		writeEscapedString(io.Discard, escapeDirty)
+	}
+}
+
+func BenchmarkEscaping_TextHeavy_HtmlGen(b *testing.B) {
+	paragraphs := make([]TagArg, 50)
+	for i := range paragraphs {
+		paragraphs[i] = P(Text("Readers & writers agree: \"plain\" text <em>mostly</em> needs no escaping at all, it's true."))
+	}
+	page := Article(paragraphs...)
+	var buf bytes.Buffer
+	for b.Loop() {
+		buf.Reset()
+		Render(&buf, page)
+	}
+}
+
+func BenchmarkEscaping_AttrHeavy_HtmlGen(b *testing.B) {
+	rows := make([]TagArg, 50)
+	for i := range rows {
+		rows[i] = Tr(
+			Attrs("class", "row row-striped", "data-id", "item-42", "data-title", `Tom's "favorite" <item>`, "title", "Details & more"),
+			Td(Attrs("class", "cell", "data-col", "name"), Text("Widget")),
+		)
+	}
+	table := Table(Tbody(rows...))
+	var buf bytes.Buffer
+	for b.Loop() {
+		buf.Reset()
+		Render(&buf, table)
+	}
+}
+
 // ============================================================================
 // Deep Nesting Benchmarks
 // ============================================================================
@@ -731,6 +782,20 @@ func BenchmarkCompiledParams_HtmlGen(b *testing.B) {
 	}
 }
 
+func BenchmarkCompiledAttrParams_HtmlGen(b *testing.B) {
+	href := NewAttrParam("href")
+	title := NewParam("title")
+	tmpl := MustCompileParams(Div(Class("card"), A(href.Attr("href"), title)))
+	hrefVal := href.Value("/users/42")
+	titleVal := title.Value(Text("Hello World"))
+
+	var buf bytes.Buffer
+	for b.Loop() {
+		buf.Reset()
+		tmpl.Render(&buf, hrefVal, titleVal)
+	}
+}
+
 func BenchmarkCompiledParams_Template(b *testing.B) {
 	tmpl := template.Must(template.New("params").Parse(
 		`<div><h1>{{.Title}}</h1><p>{{.Content}}</p></div>`))
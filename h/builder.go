@@ -1,7 +1,12 @@
 package h
 
+import "slices"
+
 // TagArg is a marker interface for types that can be passed to tag functions.
-// Valid types are: Attributes, Attribute, and Builder.
+// Valid types are: Attributes, Attribute, Builder, Mixin, and AttrProvider
+// (see ProvidesAttrs). They may be freely interleaved in any order:
+//
+//	Div(Class("box"), hx.Get("/x"), P(Text("hi")), Attrs("id", "main"))
 type TagArg interface {
 	isTagArg()
 }
@@ -29,7 +34,7 @@ func (b *htmlTagBuilder) Build(w *Writer) error {
 
 	for _, child := range b.Children {
 		if child != nil {
-			if err := child.Build(w); err != nil {
+			if err := w.buildChild(child); err != nil {
 				return err
 			}
 		}
@@ -39,11 +44,16 @@ func (b *htmlTagBuilder) Build(w *Writer) error {
 }
 
 // parseTagArgs separates attributes from children in a variadic argument list.
-// Multiple Attributes/Attribute are merged (later values override earlier ones).
-// All Builder arguments become children.
+// Multiple Attributes/Attribute/AttrProvider are combined: later values override
+// earlier ones, except class and rel, whose values are joined. All Builder
+// arguments become children, wrapped by any Mixin.
 func parseTagArgs(args []TagArg) (Attributes, []Builder) {
 	var attrs Attributes
 	var children []Builder
+	var mixins []Mixin
+	// shared is true while attrs aliases a caller's Attributes, which must
+	// be copied before merging so reused attribute values are not mutated.
+	shared := false
 
 	for _, arg := range args {
 		if arg == nil {
@@ -51,24 +61,45 @@ func parseTagArgs(args []TagArg) (Attributes, []Builder) {
 		}
 		switch v := arg.(type) {
 		case Attributes:
-			if attrs == nil {
-				attrs = v
-			} else {
-				attrs.Merge(v)
-			}
+			attrs, shared = mergeShared(attrs, shared, v)
 		case Attribute:
 			if attrs == nil {
 				attrs = Attributes{v}
 			} else {
-				attrs.Set(v.Name, v.Value)
+				if shared {
+					attrs = slices.Clone(attrs)
+					shared = false
+				}
+				attrs.combineAttr(v)
 			}
 		case Builder:
 			children = append(children, v)
+		case Mixin:
+			mixins = append(mixins, v)
+			attrs, shared = mergeShared(attrs, shared, v.Attrs)
+		case AttrProvider:
+			attrs, shared = mergeShared(attrs, shared, v.Attrs())
 		}
 	}
+	if mixins != nil {
+		children = applyMixins(mixins, children)
+	}
 	return attrs, children
 }
 
+// mergeShared combines v into attrs for parseTagArgs, aliasing v if attrs is
+// empty and copying attrs first if it is shared.
+func mergeShared(attrs Attributes, shared bool, v Attributes) (Attributes, bool) {
+	if attrs == nil {
+		return v, true
+	}
+	if shared {
+		attrs = slices.Clone(attrs)
+	}
+	attrs.combine(v)
+	return attrs, false
+}
+
 func tag(name string, args ...TagArg) Builder {
 	attrs, children := parseTagArgs(args)
 	return &tagBuilder{
@@ -107,7 +138,7 @@ func (b *tagBuilder) Build(w *Writer) error {
 
 	for _, child := range b.Children {
 		if child != nil {
-			if err := child.Build(w); err != nil {
+			if err := w.buildChild(child); err != nil {
 				return err
 			}
 		}
@@ -124,7 +155,7 @@ func (b *fragmentBuilder) isTagArg() {}
 func (b *fragmentBuilder) Build(w *Writer) error {
 	for _, child := range b.Children {
 		if child != nil {
-			if err := child.Build(w); err != nil {
+			if err := w.buildChild(child); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,134 @@
+package h
+
+import (
+	"bytes"
+	"sync"
+	"time"
+)
+
+// Cache stores the rendered output of expensive subtrees that are the same
+// for every request, such as navigation menus and footers, keyed by
+// component. Unlike MemoCache, each entry renders at most once at a time:
+// concurrent renders of a missing or expired key wait for the first one
+// instead of rendering it again. It is safe for concurrent use.
+//
+// A Cache is usually a package-level variable:
+//
+//	var fragments = h.NewCache()
+//
+//	func nav() h.Builder {
+//	    return fragments.Cached("nav", 10*time.Minute, func() h.Builder {
+//	        return h.Nav(menuItems()...)
+//	    })
+//	}
+type Cache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry
+}
+
+// cacheKey identifies a Cache entry: a component key rendered in a variant.
+type cacheKey struct {
+	key     string
+	variant sharedVariant
+}
+
+type cacheEntry struct {
+	mu      sync.Mutex // Held while rendering
+	html    []byte     // Nil until rendered
+	expires time.Time  // Zero if the entry does not expire
+}
+
+// NewCache creates an empty Cache.
+func NewCache() *Cache {
+	return &Cache{entries: map[cacheKey]*cacheEntry{}}
+}
+
+// Cached creates a Builder that renders fn once and re-emits the bytes
+// until ttl has passed, or until Invalidate or Clear. A ttl of 0 keeps
+// the output until then. key identifies the component and must not be
+// shared by fragments with different output.
+//
+// As with Memo, fn is rendered with a fresh context that carries over only
+// the base URL, locale, and Config, with a separate entry for each
+// combination of them. Authorized builders or elements that would get a
+// nonce fail with ErrSharedFragment.
+//
+// Like Compile, cached output ignores indentation settings. Element hooks
+// and strict mode apply only when the fragment is rendered, and failed
+// renders are not cached. fn must not render a Cached builder with the
+// same key.
+func (c *Cache) Cached(key string, ttl time.Duration, fn func() Builder) Builder {
+	return &cachedBuilder{cache: c, key: key, ttl: ttl, fn: fn}
+}
+
+// Invalidate removes the entries for key, so its next render calls fn
+// again.
+func (c *Cache) Invalidate(key string) {
+	c.mu.Lock()
+	for k := range c.entries {
+		if k.key == key {
+			delete(c.entries, k)
+		}
+	}
+	c.mu.Unlock()
+}
+
+// Clear removes all entries.
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+}
+
+func (c *Cache) entry(key cacheKey) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry{}
+		c.entries[key] = e
+	}
+	return e
+}
+
+// cachedBuilder renders fn once per key and Cache.
+type cachedBuilder struct {
+	cache *Cache
+	key   string
+	ttl   time.Duration
+	fn    func() Builder
+}
+
+func (b *cachedBuilder) isTagArg() {}
+
+func (b *cachedBuilder) Build(w *Writer) error {
+	html, err := b.html(w)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(html)
+	return err
+}
+
+// html returns the cached output, rendering it with w's shared context if
+// it is missing or expired.
+func (b *cachedBuilder) html(w *Writer) ([]byte, error) {
+	e := b.cache.entry(cacheKey{b.key, variantOf(w)})
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.html != nil && (e.expires.IsZero() || now().Before(e.expires)) {
+		return e.html, nil
+	}
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	buf.Reset()
+	if err := RenderContext(sharedContext(w), buf, b.fn()); err != nil {
+		return nil, err
+	}
+	e.html = append([]byte{}, buf.Bytes()...) // Non-nil even if empty
+	e.expires = time.Time{}
+	if b.ttl > 0 {
+		e.expires = now().Add(b.ttl)
+	}
+	return e.html, nil
+}
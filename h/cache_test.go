@@ -0,0 +1,151 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	ref := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := ref
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	cache := NewCache()
+	calls := 0
+	nav := cache.Cached("nav", time.Minute, func() Builder {
+		calls++
+		return Nav(A(Href("/"), Text("v"+strconv.Itoa(calls))))
+	})
+	footer := cache.Cached("footer", 0, func() Builder { return Footer(Text("(c)")) })
+
+	for _, tt := range []struct {
+		after    time.Duration
+		expected string
+	}{
+		{0, `<nav><a href="/">v1</a></nav>`},
+		{30 * time.Second, `<nav><a href="/">v1</a></nav>`},
+		{time.Minute, `<nav><a href="/">v2</a></nav>`},
+		{90 * time.Second, `<nav><a href="/">v2</a></nav>`},
+	} {
+		current = ref.Add(tt.after)
+		if got := RenderString(nav); got != tt.expected {
+			t.Errorf("after %v: got %s, want %s", tt.after, got, tt.expected)
+		}
+	}
+
+	cache.Invalidate("nav")
+	if got := RenderString(nav); got != `<nav><a href="/">v3</a></nav>` {
+		t.Errorf("after Invalidate: got %s", got)
+	}
+	if got := RenderString(Fragment(footer, footer)); got != "<footer>(c)</footer><footer>(c)</footer>" {
+		t.Errorf("footer: got %s", got)
+	}
+	cache.Clear()
+	if len(cache.entries) != 0 {
+		t.Error("Clear left entries")
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	cache := NewCache()
+	var calls atomic.Int32
+	b := cache.Cached("menu", 0, func() Builder {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return Ul(Li(Text("Home")))
+	})
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			if got := RenderString(b); got != "<ul><li>Home</li></ul>" {
+				t.Errorf("got %s", got)
+			}
+		})
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+}
+
+func TestCacheError(t *testing.T) {
+	cache := NewCache()
+	fail := true
+	b := cache.Cached("x", 0, func() Builder {
+		if fail {
+			return failingBuilder{errors.New("boom")}
+		}
+		return Text("ok")
+	})
+	if err := Render(io.Discard, b); err == nil {
+		t.Error("expected error")
+	}
+	fail = false
+	if got := RenderString(b); got != "ok" {
+		t.Errorf("failed render was cached: got %q", got)
+	}
+}
+
+func TestCacheRequestScoped(t *testing.T) {
+	admin := WithNonce(WithAuthorizer(context.Background(), AuthorizerFunc(func(context.Context, string) bool { return true })), "AAA")
+	guest := WithNonce(WithAuthorizer(context.Background(), AuthorizerFunc(func(context.Context, string) bool { return false })), "BBB")
+
+	cache := NewCache()
+	for _, b := range []Builder{
+		cache.Cached("actions", 0, func() Builder { return Div(Text("card"), Authorized("admin", Button(Text("Delete")))) }),
+		cache.Cached("script", 0, func() Builder { return Div(Text("card"), Script(Raw("x()"))) }),
+	} {
+		for _, ctx := range []context.Context{admin, guest} {
+			if err := RenderContext(ctx, io.Discard, b); !errors.Is(err, ErrSharedFragment) {
+				t.Errorf("RenderContext() = %v, want ErrSharedFragment", err)
+			}
+		}
+	}
+
+	// Request-scoped content around the fragment follows each context.
+	card := cache.Cached("card", 0, func() Builder { return Div(Text("card")) })
+	page := Fragment(card, Authorized("admin", Button(Text("Delete"))), Script(Raw("x()")))
+	for _, tt := range []struct {
+		ctx      context.Context
+		expected string
+	}{
+		{admin, `<div>card</div><button>Delete</button><script nonce="AAA">x()</script>`},
+		{guest, `<div>card</div><script nonce="BBB">x()</script>`},
+	} {
+		var sb strings.Builder
+		if err := RenderContext(tt.ctx, &sb, page); err != nil {
+			t.Fatal(err)
+		}
+		if got := sb.String(); got != tt.expected {
+			t.Errorf("got %s, want %s", got, tt.expected)
+		}
+	}
+}
+
+func TestCacheBaseURL(t *testing.T) {
+	cache := NewCache()
+	nav := cache.Cached("nav", 0, func() Builder { return Nav(A(Href("/home"))) })
+	for range 2 {
+		for _, base := range []string{"/acme", "/globex"} {
+			var sb strings.Builder
+			if err := RenderContext(WithBaseURL(context.Background(), base), &sb, nav); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := sb.String(), `<nav><a href="`+base+`/home"></a></nav>`; got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		}
+	}
+	cache.Invalidate("nav")
+	if len(cache.entries) != 0 {
+		t.Errorf("Invalidate left %d entries", len(cache.entries))
+	}
+}
@@ -85,7 +85,7 @@ func (p *Param) isTagArg() {}
 // Build signals to CompileParams that a parameter slot was encountered.
 // When used outside CompileParams, it renders nothing.
 func (p *Param) Build(w *Writer) error {
-	if cw, ok := w.w.(*compileWriter); ok {
+	if cw, ok := w.output().(*compileWriter); ok {
 		cw.recordParam(p)
 	}
 	return nil
@@ -96,10 +96,12 @@ func (p *Param) Value(b Builder) ParamValue {
 	return ParamValue{param: p, value: b}
 }
 
-// ParamValue binds a value to a parameter for rendering.
+// ParamValue binds a value to a Param or AttrParam for rendering.
 type ParamValue struct {
-	param *Param
-	value Builder
+	param     *Param
+	value     Builder
+	attr      *AttrParam
+	attrValue string
 }
 
 // NewParam creates a named placeholder for dynamic content in CompileParams.
@@ -114,18 +116,66 @@ func NewParam(name string) *Param {
 	return &Param{name: name}
 }
 
+// AttrParam is a placeholder for an attribute value in a parameterized
+// template, for values such as hrefs, ids, and data-* values that change
+// per render while the rest of the markup stays compiled.
+type AttrParam struct {
+	name string
+}
+
+// NewAttrParam creates a named placeholder for an attribute value in
+// CompileParams. Use Attr to place it on an element:
+//
+//	href := h.NewAttrParam("href")
+//	tmpl := h.MustCompileParams(h.A(href.Attr("href"), h.Text("Profile")))
+//	tmpl.Render(w, href.Value("/users/42"))
+//
+// Renders:
+//
+//	<a href="/users/42">Profile</a>
+func NewAttrParam(name string) *AttrParam {
+	return &AttrParam{name: name}
+}
+
+// Attr returns the attribute attrName with its value left to the render.
+// Combined with other class or rel values on the same element, the static
+// tokens are written first. Outside CompileParams, or when no value is
+// bound, the attribute is written with an empty value.
+func (p *AttrParam) Attr(attrName string) Attribute {
+	if attrName == "" {
+		panic("attribute name cannot be empty")
+	}
+	return Attribute{Name: attrName, param: p}
+}
+
+// Value binds an attribute value to this parameter for rendering. The
+// value is escaped, and root-relative URLs are prefixed with the Writer's
+// base URL as for any other attribute.
+func (p *AttrParam) Value(v string) ParamValue {
+	return ParamValue{attr: p, attrValue: v}
+}
+
 // CompiledTemplate is a pre-computed template with parameter placeholders.
 // Created by CompileParams, it stores static HTML segments and parameter positions.
 type CompiledTemplate struct {
 	segments [][]byte
-	params   []*Param
+	slots    []templateSlot
+}
+
+// templateSlot is a parameter position in a CompiledTemplate: a Param, or
+// the value of the attribute attrName for an AttrParam.
+type templateSlot struct {
+	param    *Param
+	attr     *AttrParam
+	attrName string
+	fallback Builder // Content for an unfilled DefineSlot
 }
 
 // compileWriter captures segments between parameters during CompileParams.
 type compileWriter struct {
 	buf      bytes.Buffer
 	segments [][]byte
-	params   []*Param
+	slots    []templateSlot
 }
 
 func (cw *compileWriter) Write(p []byte) (int, error) {
@@ -137,17 +187,22 @@ func (cw *compileWriter) WriteString(s string) (int, error) {
 }
 
 func (cw *compileWriter) recordParam(p *Param) {
+	cw.recordSlot(templateSlot{param: p})
+}
+
+func (cw *compileWriter) recordSlot(slot templateSlot) {
 	// Copy current buffer contents to a new segment
 	segment := make([]byte, cw.buf.Len())
 	copy(segment, cw.buf.Bytes())
 	cw.segments = append(cw.segments, segment)
 	cw.buf.Reset()
-	cw.params = append(cw.params, p)
+	cw.slots = append(cw.slots, slot)
 }
 
-// CompileParams pre-computes a Builder with Param placeholders for dynamic content.
-// Static HTML is pre-rendered into segments, with params marking where dynamic
-// content will be inserted at render time.
+// CompileParams pre-computes a Builder with Param placeholders for dynamic
+// content and AttrParam placeholders for attribute values. Static HTML is
+// pre-rendered into segments, with params marking where dynamic content
+// will be inserted at render time.
 //
 //	title := h.NewParam("title")
 //	content := h.NewParam("content")
@@ -170,7 +225,8 @@ func CompileParams(b Builder) (*CompiledTemplate, error) {
 		return &CompiledTemplate{}, nil
 	}
 	cw := &compileWriter{}
-	w := &Writer{w: cw, openTags: make([]string, 0, 32)}
+	w := &Writer{openTags: make([]string, 0, 32)}
+	w.setOutput(cw)
 	if err := b.Build(w); err != nil {
 		return nil, err
 	}
@@ -182,7 +238,7 @@ func CompileParams(b Builder) (*CompiledTemplate, error) {
 
 	return &CompiledTemplate{
 		segments: cw.segments,
-		params:   cw.params,
+		slots:    cw.slots,
 	}, nil
 }
 
@@ -226,7 +282,9 @@ func (b *boundTemplate) Build(w *Writer) error {
 	// Build value lookup map
 	valueMap := make(map[string]Builder, len(b.values))
 	for _, v := range b.values {
-		valueMap[v.param.name] = v.value
+		if v.param != nil {
+			valueMap[v.param.name] = v.value
+		}
 	}
 
 	// Write segments interleaved with parameter values
@@ -236,14 +294,37 @@ func (b *boundTemplate) Build(w *Writer) error {
 				return err
 			}
 		}
-		if i < len(b.template.params) {
-			param := b.template.params[i]
-			if value, ok := valueMap[param.name]; ok && value != nil {
-				if err := value.Build(w); err != nil {
-					return err
-				}
+		if i >= len(b.template.slots) {
+			continue
+		}
+		slot := b.template.slots[i]
+		if slot.attr != nil {
+			value := b.attrValue(slot.attr.name)
+			if w.baseURL != "" {
+				value = w.rewriteURLAttr(slot.attrName, value)
+			}
+			if err := w.writeEscaped(value, true); err != nil {
+				return err
+			}
+		} else if value, ok := valueMap[slot.param.name]; ok && value != nil {
+			if err := value.Build(w); err != nil {
+				return err
+			}
+		} else if slot.fallback != nil {
+			if err := slot.fallback.Build(w); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
+
+// attrValue returns the value bound to the AttrParam name, or "".
+func (b *boundTemplate) attrValue(name string) string {
+	for i := len(b.values) - 1; i >= 0; i-- {
+		if v := b.values[i]; v.attr != nil && v.attr.name == name {
+			return v.attrValue
+		}
+	}
+	return ""
+}
@@ -0,0 +1,261 @@
+// Package css builds CSS declarations for style attributes and <style>
+// elements:
+//
+//	h.Div(h.StyleAttr(css.Display("flex"), css.Gap(css.Rem(1)), css.Color("#333")))
+//	// <div style="display: flex; gap: 1rem; color: #333">
+//
+//	css.Sheet(
+//	    css.Rule(".card", css.Padding(css.Px(16)), css.BorderRadius(css.Px(8))),
+//	    css.Rule(".card h2", css.FontSize(css.Rem(1.25))),
+//	)
+//	// <style>.card{padding: 16px; border-radius: 8px}.card h2{font-size: 1.25rem}</style>
+//
+// Values are written as given, so they must be valid CSS; use Quote for
+// arbitrary text such as content or font names. A declaration whose
+// property is not a CSS identifier, or whose value contains ;, {, }, or <,
+// could end the declaration or the <style> element early and is omitted.
+// Likewise, a Rule with such a selector is omitted.
+package css
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Decl is a CSS declaration. It implements h.StyleDecl.
+type Decl struct {
+	Property string
+	Value    string
+}
+
+// Prop creates a declaration for any property.
+func Prop(property, value string) Decl { return Decl{property, value} }
+
+// Var creates a declaration for the custom property --name.
+func Var(name, value string) Decl { return Decl{"--" + name, value} }
+
+// CSSDecl returns "property: value", or "" if the declaration is unsafe.
+func (d Decl) CSSDecl() string {
+	if !validProperty(d.Property) || d.Value == "" || strings.ContainsAny(d.Value, ";{}<") {
+		return ""
+	}
+	return d.Property + ": " + d.Value
+}
+
+// String returns d.CSSDecl().
+func (d Decl) String() string { return d.CSSDecl() }
+
+func validProperty(p string) bool {
+	if p == "" {
+		return false
+	}
+	for _, c := range p {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Quote returns s as a CSS string literal, escaping quotes, backslashes,
+// newlines, and the characters that would otherwise make a declaration
+// unsafe:
+//
+//	css.Prop("content", css.Quote(label))
+func Quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\', '\n', '\r', '\f', ';', '{', '}', '<':
+			sb.WriteByte('\\')
+			sb.WriteString(strconv.FormatInt(int64(c), 16))
+			sb.WriteByte(' ')
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// Length units.
+
+// Px returns n pixels: 16px
+func Px(n int) string { return strconv.Itoa(n) + "px" }
+
+// Rem returns n root ems: 1.5rem
+func Rem(n float64) string { return formatFloat(n) + "rem" }
+
+// Em returns n ems: 2em
+func Em(n float64) string { return formatFloat(n) + "em" }
+
+// Percent returns n percent: 50%
+func Percent(n float64) string { return formatFloat(n) + "%" }
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+// Layout
+
+// Display creates a display declaration.
+func Display(v string) Decl { return Decl{"display", v} }
+
+// Position creates a position declaration.
+func Position(v string) Decl { return Decl{"position", v} }
+
+// Top creates a top declaration.
+func Top(v string) Decl { return Decl{"top", v} }
+
+// Right creates a right declaration.
+func Right(v string) Decl { return Decl{"right", v} }
+
+// Bottom creates a bottom declaration.
+func Bottom(v string) Decl { return Decl{"bottom", v} }
+
+// Left creates a left declaration.
+func Left(v string) Decl { return Decl{"left", v} }
+
+// ZIndex creates a z-index declaration.
+func ZIndex(n int) Decl { return Decl{"z-index", strconv.Itoa(n)} }
+
+// Width creates a width declaration.
+func Width(v string) Decl { return Decl{"width", v} }
+
+// Height creates a height declaration.
+func Height(v string) Decl { return Decl{"height", v} }
+
+// MaxWidth creates a max-width declaration.
+func MaxWidth(v string) Decl { return Decl{"max-width", v} }
+
+// MinHeight creates a min-height declaration.
+func MinHeight(v string) Decl { return Decl{"min-height", v} }
+
+// Margin creates a margin declaration.
+func Margin(v string) Decl { return Decl{"margin", v} }
+
+// Padding creates a padding declaration.
+func Padding(v string) Decl { return Decl{"padding", v} }
+
+// Overflow creates an overflow declaration.
+func Overflow(v string) Decl { return Decl{"overflow", v} }
+
+// Flexbox and grid
+
+// FlexDirection creates a flex-direction declaration.
+func FlexDirection(v string) Decl { return Decl{"flex-direction", v} }
+
+// FlexWrap creates a flex-wrap declaration.
+func FlexWrap(v string) Decl { return Decl{"flex-wrap", v} }
+
+// Flex creates a flex declaration.
+func Flex(v string) Decl { return Decl{"flex", v} }
+
+// JustifyContent creates a justify-content declaration.
+func JustifyContent(v string) Decl { return Decl{"justify-content", v} }
+
+// AlignItems creates an align-items declaration.
+func AlignItems(v string) Decl { return Decl{"align-items", v} }
+
+// Gap creates a gap declaration.
+func Gap(v string) Decl { return Decl{"gap", v} }
+
+// GridTemplateColumns creates a grid-template-columns declaration.
+func GridTemplateColumns(v string) Decl { return Decl{"grid-template-columns", v} }
+
+// Typography
+
+// Color creates a color declaration.
+func Color(v string) Decl { return Decl{"color", v} }
+
+// FontFamily creates a font-family declaration.
+func FontFamily(v string) Decl { return Decl{"font-family", v} }
+
+// FontSize creates a font-size declaration.
+func FontSize(v string) Decl { return Decl{"font-size", v} }
+
+// FontWeight creates a font-weight declaration.
+func FontWeight(v string) Decl { return Decl{"font-weight", v} }
+
+// LineHeight creates a line-height declaration.
+func LineHeight(v string) Decl { return Decl{"line-height", v} }
+
+// TextAlign creates a text-align declaration.
+func TextAlign(v string) Decl { return Decl{"text-align", v} }
+
+// Visuals
+
+// Background creates a background declaration.
+func Background(v string) Decl { return Decl{"background", v} }
+
+// BackgroundColor creates a background-color declaration.
+func BackgroundColor(v string) Decl { return Decl{"background-color", v} }
+
+// Border creates a border declaration.
+func Border(v string) Decl { return Decl{"border", v} }
+
+// BorderRadius creates a border-radius declaration.
+func BorderRadius(v string) Decl { return Decl{"border-radius", v} }
+
+// Opacity creates an opacity declaration.
+func Opacity(v float64) Decl { return Decl{"opacity", formatFloat(v)} }
+
+// Cursor creates a cursor declaration.
+func Cursor(v string) Decl { return Decl{"cursor", v} }
+
+// Ruleset is a selector and its declarations in a Sheet.
+type Ruleset struct {
+	Selector string
+	Decls    []Decl
+}
+
+// Rule creates a Ruleset.
+func Rule(selector string, decls ...Decl) Ruleset { return Ruleset{selector, decls} }
+
+// Scope prefixes the selector of each rule with scope, for styles that
+// apply only inside a component:
+//
+//	css.Sheet(css.Scope(".card", css.Rule("h2", ...), css.Rule("p", ...))...)
+//	// .card h2{...}.card p{...}
+func Scope(scope string, rules ...Ruleset) []Ruleset {
+	scoped := make([]Ruleset, len(rules))
+	for i, r := range rules {
+		scoped[i] = Ruleset{scope + " " + r.Selector, r.Decls}
+	}
+	return scoped
+}
+
+// String returns the rule as CSS, or "" if its selector is unsafe.
+func (r Ruleset) String() string {
+	if r.Selector == "" || strings.ContainsAny(r.Selector, ";{}<") {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(r.Selector)
+	sb.WriteByte('{')
+	first := true
+	for _, d := range r.Decls {
+		decl := d.CSSDecl()
+		if decl == "" {
+			continue
+		}
+		if !first {
+			sb.WriteString("; ")
+		}
+		first = false
+		sb.WriteString(decl)
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// Sheet renders rules as a <style> element. A CSP nonce configured with
+// config.Nonce is added by h.
+func Sheet(rules ...Ruleset) h.Builder {
+	var sb strings.Builder
+	for _, r := range rules {
+		sb.WriteString(r.String())
+	}
+	return h.Style(h.Raw(sb.String()))
+}
@@ -0,0 +1,75 @@
+package css
+
+import (
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestDecl(t *testing.T) {
+	tests := []struct {
+		name     string
+		decl     Decl
+		expected string
+	}{
+		{"display", Display("flex"), "display: flex"},
+		{"length", Gap(Rem(1.5)), "gap: 1.5rem"},
+		{"px", Padding(Px(16)), "padding: 16px"},
+		{"percent", Width(Percent(50)), "width: 50%"},
+		{"number", Opacity(0.5), "opacity: 0.5"},
+		{"z-index", ZIndex(-1), "z-index: -1"},
+		{"var", Var("accent", "#f00"), "--accent: #f00"},
+		{"quoted", Prop("content", Quote(`a "b";}</style>`)), `content: "a \22 b\22 \3b \7d \3c /style>"`},
+		{"injection omitted", Color("red; position: fixed"), ""},
+		{"brace omitted", Background("red}body{color:red"), ""},
+		{"tag omitted", FontFamily("</style><script>"), ""},
+		{"bad property omitted", Prop("color:red;x", "blue"), ""},
+		{"empty value omitted", Color(""), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.decl.CSSDecl(); got != tt.expected {
+				t.Errorf("CSSDecl() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStyleAttr(t *testing.T) {
+	got := h.RenderString(h.Div(h.StyleAttr(Display("flex"), Color("red;"), Gap("1rem"), Color("#333"))))
+	expected := `<div style="display: flex; gap: 1rem; color: #333"></div>`
+	if got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+}
+
+func TestSheet(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        h.Builder
+		expected string
+	}{
+		{
+			"rules",
+			Sheet(
+				Rule(".card", Padding(Px(16)), BorderRadius(Px(8))),
+				Rule(".card h2", FontSize(Rem(1.25)), Color("x;y")),
+			),
+			`<style>.card{padding: 16px; border-radius: 8px}.card h2{font-size: 1.25rem}</style>`,
+		},
+		{
+			"scope",
+			Sheet(Scope(".card", Rule("h2", FontWeight("bold")), Rule("p", Margin("0")))...),
+			`<style>.card h2{font-weight: bold}.card p{margin: 0}</style>`,
+		},
+		{"unsafe selector omitted", Sheet(Rule("</style><script>", Color("red")), Rule("p", Color("blue"))), `<style>p{color: blue}</style>`},
+		{"empty", Sheet(), `<style></style>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.RenderString(tt.b); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
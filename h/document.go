@@ -0,0 +1,132 @@
+package h
+
+import "cmp"
+
+// DocOptions describes the <head> written by Document. Empty fields are
+// omitted.
+type DocOptions struct {
+	Title       string
+	Lang        string // Defaults to "en"
+	Description string
+	Canonical   string // Absolute URL of the canonical page
+	OpenGraph   OpenGraph
+	TwitterCard TwitterCard
+	Favicons    []Favicon
+	Stylesheets []string  // URLs, in order
+	Scripts     []string  // URLs, loaded with defer in order
+	Head        []Builder // Extra head content, written last
+}
+
+// OpenGraph holds the og:* properties used for link previews. Title and
+// Description default to those of the DocOptions. Nothing is written if
+// all fields are empty.
+type OpenGraph struct {
+	Type        string // Such as "website" or "article"
+	Title       string
+	Description string
+	URL         string
+	Image       string // Absolute URL
+	ImageAlt    string
+	SiteName    string
+}
+
+// TwitterCard holds the twitter:* metadata. Nothing is written if Card is
+// empty; the other fields fall back to OpenGraph in clients that support it.
+type TwitterCard struct {
+	Card    string // "summary" or "summary_large_image"
+	Site    string // @handle of the site
+	Creator string // @handle of the author
+}
+
+// Favicon is a <link> to an icon. Rel defaults to "icon".
+type Favicon struct {
+	Href  string
+	Rel   string // Such as "icon" or "apple-touch-icon"
+	Type  string // Such as "image/svg+xml"
+	Sizes string // Such as "32x32" or "any"
+}
+
+// Document creates a complete HTML document: the doctype, <html lang>, a
+// <head> with the charset, viewport, and the metadata in opts, and a <body>
+// holding body:
+//
+//	h.Document(h.DocOptions{
+//	    Title:       "Pricing - Acme",
+//	    Description: "Plans for teams of every size.",
+//	    Canonical:   "https://acme.example/pricing",
+//	    OpenGraph:   h.OpenGraph{Type: "website", Image: "https://acme.example/og.png"},
+//	    Favicons:    []h.Favicon{{Href: "/favicon.svg", Type: "image/svg+xml"}},
+//	    Stylesheets: []string{"/app.css"},
+//	    Scripts:     []string{"/app.js"},
+//	}, header, h.Main(content))
+//
+// The head is written in this order: charset, viewport, title,
+// description, canonical link, Open Graph, Twitter card, favicons,
+// stylesheets, scripts, and Head.
+func Document(opts DocOptions, body ...Builder) Builder {
+	lang := opts.Lang
+	if lang == "" {
+		lang = "en"
+	}
+	head := []Builder{
+		Meta(Attrs("charset", "utf-8")),
+		Meta(Attrs("name", "viewport", "content", "width=device-width, initial-scale=1")),
+	}
+	if opts.Title != "" {
+		head = append(head, Title(Text(opts.Title)))
+	}
+	head = appendMeta(head, "name", "description", opts.Description)
+	if opts.Canonical != "" {
+		head = append(head, Link(Attrs("rel", "canonical", "href", opts.Canonical)))
+	}
+	if og := opts.OpenGraph; og != (OpenGraph{}) {
+		head = appendMeta(head, "property", "og:type", og.Type)
+		head = appendMeta(head, "property", "og:title", cmp.Or(og.Title, opts.Title))
+		head = appendMeta(head, "property", "og:description", cmp.Or(og.Description, opts.Description))
+		head = appendMeta(head, "property", "og:url", cmp.Or(og.URL, opts.Canonical))
+		head = appendMeta(head, "property", "og:image", og.Image)
+		head = appendMeta(head, "property", "og:image:alt", og.ImageAlt)
+		head = appendMeta(head, "property", "og:site_name", og.SiteName)
+	}
+	if tc := opts.TwitterCard; tc.Card != "" {
+		head = appendMeta(head, "name", "twitter:card", tc.Card)
+		head = appendMeta(head, "name", "twitter:site", tc.Site)
+		head = appendMeta(head, "name", "twitter:creator", tc.Creator)
+	}
+	for _, icon := range opts.Favicons {
+		attrs := Attrs("rel", cmp.Or(icon.Rel, "icon"), "href", icon.Href)
+		if icon.Type != "" {
+			attrs = append(attrs, Attribute{Name: "type", Value: icon.Type})
+		}
+		if icon.Sizes != "" {
+			attrs = append(attrs, Attribute{Name: "sizes", Value: icon.Sizes})
+		}
+		head = append(head, Link(attrs))
+	}
+	for _, href := range opts.Stylesheets {
+		head = append(head, Link(Attrs("rel", "stylesheet", "href", href)))
+	}
+	for _, src := range opts.Scripts {
+		head = append(head, Script(Attrs("src", src, "defer", "")))
+	}
+	head = append(head, opts.Head...)
+	return Html(Attrs("lang", lang), Head(builderArgs(head)...), Body(builderArgs(body)...))
+}
+
+// appendMeta appends <meta attr="key" content="value"> unless value is
+// empty.
+func appendMeta(head []Builder, attr, key, value string) []Builder {
+	if value == "" {
+		return head
+	}
+	return append(head, Meta(Attrs(attr, key, "content", value)))
+}
+
+// builderArgs converts builders to tag arguments.
+func builderArgs(bs []Builder) []TagArg {
+	args := make([]TagArg, len(bs))
+	for i, b := range bs {
+		args[i] = b
+	}
+	return args
+}
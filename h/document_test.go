@@ -0,0 +1,69 @@
+package h
+
+import "testing"
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     DocOptions
+		body     []Builder
+		expected string
+	}{
+		{
+			"minimal",
+			DocOptions{},
+			nil,
+			"<!DOCTYPE html>\n" + `<html lang="en"><head><meta charset="utf-8"/>` +
+				`<meta name="viewport" content="width=device-width, initial-scale=1"/></head><body></body></html>`,
+		},
+		{
+			"full",
+			DocOptions{
+				Title:       "Pricing & Plans",
+				Lang:        "fr",
+				Description: "Plans for teams.",
+				Canonical:   "https://acme.example/pricing",
+				OpenGraph:   OpenGraph{Type: "website", Image: "https://acme.example/og.png", SiteName: "Acme"},
+				TwitterCard: TwitterCard{Card: "summary_large_image", Site: "@acme"},
+				Favicons: []Favicon{
+					{Href: "/favicon.svg", Type: "image/svg+xml"},
+					{Href: "/apple.png", Rel: "apple-touch-icon", Sizes: "180x180"},
+				},
+				Stylesheets: []string{"/app.css"},
+				Scripts:     []string{"/app.js"},
+				Head:        []Builder{Meta(Attrs("name", "theme-color", "content", "#fff"))},
+			},
+			[]Builder{Main(Text("hi"))},
+			"<!DOCTYPE html>\n" + `<html lang="fr"><head><meta charset="utf-8"/>` +
+				`<meta name="viewport" content="width=device-width, initial-scale=1"/>` +
+				`<title>Pricing &amp; Plans</title>` +
+				`<meta name="description" content="Plans for teams."/>` +
+				`<link rel="canonical" href="https://acme.example/pricing"/>` +
+				`<meta property="og:type" content="website"/>` +
+				`<meta property="og:title" content="Pricing &amp; Plans"/>` +
+				`<meta property="og:description" content="Plans for teams."/>` +
+				`<meta property="og:url" content="https://acme.example/pricing"/>` +
+				`<meta property="og:image" content="https://acme.example/og.png"/>` +
+				`<meta property="og:site_name" content="Acme"/>` +
+				`<meta name="twitter:card" content="summary_large_image"/>` +
+				`<meta name="twitter:site" content="@acme"/>` +
+				`<link rel="icon" href="/favicon.svg" type="image/svg+xml"/>` +
+				`<link rel="apple-touch-icon" href="/apple.png" sizes="180x180"/>` +
+				`<link rel="stylesheet" href="/app.css"/>` +
+				`<script src="/app.js" defer></script>` +
+				`<meta name="theme-color" content="#fff"/>` +
+				`</head><body><main>hi</main></body></html>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderString(Document(tt.opts, tt.body...))
+			if got != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.expected)
+			}
+			if err := ValidateBuilder(Document(tt.opts, tt.body...)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package h
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Named labels b with a component name shown by Dump and, while b is
+// built, by Writer.Components. It renders exactly as b:
+//
+//	func UserCard(u User) h.Builder {
+//	    return h.Named("UserCard", h.Div(h.Class("card"), h.Text(u.Name)))
+//	}
+func Named(name string, b Builder) Builder { return &namedBuilder{name, b} }
+
+type namedBuilder struct {
+	name string
+	b    Builder
+}
+
+func (b *namedBuilder) isTagArg() {}
+
+func (b *namedBuilder) Build(w *Writer) error {
+	if b.b == nil {
+		return nil
+	}
+	w.components = append(w.components, b.name)
+	defer func() { w.components = w.components[:len(w.components)-1] }()
+	return b.b.Build(w)
+}
+
+// dumpTextLimit is the number of runes of text shown by Dump.
+const dumpTextLimit = 40
+
+// Dump returns an indented tree of b for logging and debugging: elements
+// with their attributes, text previews, and component names from Named.
+// Builders whose content is only known at render time, such as streams,
+// memoized and authorized content, are shown by kind without being
+// rendered, so Dump never consumes iterators or readers.
+//
+//	fmt.Println(h.Dump(page))
+//
+// Prints:
+//
+//	html lang="en"
+//	└─ body
+//	   └─ [UserCard]
+//	      └─ div class="card"
+//	         └─ "Ada Lovelace"
+func Dump(b Builder) string {
+	var sb strings.Builder
+	dump(&sb, b, "", "")
+	return sb.String()
+}
+
+// dump writes b's line with prefix, then its children, whose lines start
+// with childPrefix.
+func dump(sb *strings.Builder, b Builder, prefix, childPrefix string) {
+	sb.WriteString(prefix)
+	var children []Builder
+	switch b := b.(type) {
+	case nil:
+		sb.WriteString("<nil>")
+	case *tagBuilder:
+		dumpTag(sb, b.Name, b.Attrs)
+		children = b.Children
+	case *htmlTagBuilder:
+		dumpTag(sb, "html", b.Attrs)
+		children = b.Children
+	case *fragmentBuilder:
+		sb.WriteString("fragment")
+		children = b.Children
+	case *textBuilder:
+		if b.IsRaw {
+			sb.WriteString("raw ")
+		}
+		sb.WriteString(dumpText(b.Text))
+	case *namedBuilder:
+		sb.WriteString("[" + b.name + "]")
+		children = []Builder{b.b}
+	case *rootAttrsBuilder:
+		dumpTag(sb, "root attrs", b.attrs)
+		children = []Builder{b.b}
+	case *compiledBuilder:
+		fmt.Fprintf(sb, "compiled %s", dumpText(string(b.html)))
+	case *authorizedBuilder:
+		fmt.Fprintf(sb, "authorized %q", b.perm)
+		children = []Builder{b.ifGranted}
+		if b.ifDenied != nil {
+			children = append(children, Named("denied", b.ifDenied))
+		}
+	case *memoBuilder:
+		fmt.Fprintf(sb, "memo %v", b.key)
+	case *streamBuilder, *streamChanBuilder:
+		sb.WriteString("stream")
+	case *readerBuilder:
+		if b.isRaw {
+			sb.WriteString("raw reader")
+		} else {
+			sb.WriteString("text reader")
+		}
+	case *localizedBuilder:
+		sb.WriteString("localized")
+	case *uniqueIDBuilder:
+		fmt.Fprintf(sb, "unique id %q", b.prefix)
+	default:
+		fmt.Fprintf(sb, "%T", b)
+	}
+	sb.WriteByte('\n')
+
+	for i, child := range children {
+		if i == len(children)-1 {
+			dump(sb, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			dump(sb, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+func dumpTag(sb *strings.Builder, name string, as Attributes) {
+	sb.WriteString(name)
+	for _, attr := range as {
+		sb.WriteByte(' ')
+		sb.WriteString(attr.Name)
+		if attr.Value != "" {
+			sb.WriteByte('=')
+			sb.WriteString(dumpText(attr.Value))
+		}
+	}
+}
+
+// dumpText quotes s, truncated to dumpTextLimit runes.
+func dumpText(s string) string {
+	if utf8.RuneCountInString(s) <= dumpTextLimit {
+		return fmt.Sprintf("%q", s)
+	}
+	end := 0
+	for i := 0; i < dumpTextLimit; i++ {
+		_, size := utf8.DecodeRuneInString(s[end:])
+		end += size
+	}
+	return fmt.Sprintf("%q…", s[:end])
+}
@@ -0,0 +1,43 @@
+package h
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	card := Named("UserCard", Div(Class("card"), Text("Ada Lovelace"), Raw("<hr>")))
+	page := Html(Attr("lang", "en"), Body(card, Fragment(Input(Attrs("disabled", "")), Text(strings.Repeat("x", 50)))))
+
+	expected := `html lang="en"
+└─ body
+   ├─ [UserCard]
+   │  └─ div class="card"
+   │     ├─ "Ada Lovelace"
+   │     └─ raw "<hr>"
+   └─ fragment
+      ├─ input disabled
+      └─ "xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"…
+`
+	if got := Dump(page); got != expected {
+		t.Errorf("got:\n%s\nwant:\n%s", got, expected)
+	}
+
+	if got := RenderString(page); !strings.Contains(got, `<div class="card">Ada Lovelace<hr></div>`) {
+		t.Errorf("Named should render its builder unchanged, got %q", got)
+	}
+
+	tests := []struct {
+		b        Builder
+		expected string
+	}{
+		{nil, "<nil>\n"},
+		{TextReader(strings.NewReader("x")), "text reader\n"},
+		{UniqueID("field", func(string) Builder { return nil }), "unique id \"field\"\n"},
+	}
+	for _, tt := range tests {
+		if got := Dump(tt.b); got != tt.expected {
+			t.Errorf("Dump = %q, want %q", got, tt.expected)
+		}
+	}
+}
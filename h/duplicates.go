@@ -0,0 +1,106 @@
+package h
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+// ErrDuplicateAttribute is returned when the config.Error duplicate policy
+// (see Writer.SetDuplicateAttrs) finds an attribute repeated on an element.
+var ErrDuplicateAttribute = errors.New("duplicate attribute")
+
+// spaceSeparatedAttrs are the attributes, besides the token lists class and
+// rel, whose values are space-separated lists joined by config.Merge.
+var spaceSeparatedAttrs = map[string]bool{
+	"accesskey":        true,
+	"aria-controls":    true,
+	"aria-describedby": true,
+	"aria-flowto":      true,
+	"aria-labelledby":  true,
+	"aria-owns":        true,
+	"blocking":         true,
+	"headers":          true,
+	"itemprop":         true,
+	"itemref":          true,
+	"part":             true,
+	"ping":             true,
+	"sandbox":          true,
+}
+
+// resolveDuplicates applies policy to the attributes repeated in as,
+// returning as itself when none are.
+func resolveDuplicates(tag string, as Attributes, policy config.DuplicatePolicy) (Attributes, error) {
+	if !hasDuplicates(as) {
+		return as, nil
+	}
+	result := make(Attributes, 0, len(as))
+	for _, attr := range as {
+		if attr.Name == "" {
+			continue
+		}
+		idx := -1
+		for i := range result {
+			if strings.EqualFold(result[i].Name, attr.Name) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			result = append(result, attr)
+			continue
+		}
+		switch policy {
+		case config.KeepFirst:
+		case config.KeepLast:
+			result[idx].Value, result[idx].untrusted, result[idx].param = attr.Value, attr.untrusted, attr.param
+		case config.Merge:
+			prev := &result[idx]
+			prev.Value = mergeAttrValue(strings.ToLower(attr.Name), prev.Value, attr.Value)
+			prev.untrusted = prev.untrusted || attr.untrusted
+			if prev.param == nil {
+				prev.param = attr.param
+			}
+		case config.Error:
+			return nil, fmt.Errorf("%w: %s on <%s>", ErrDuplicateAttribute, attr.Name, tag)
+		default:
+			return as, nil
+		}
+	}
+	return result, nil
+}
+
+// hasDuplicates reports whether any attribute name appears twice in as.
+func hasDuplicates(as Attributes) bool {
+	for i := 1; i < len(as); i++ {
+		for j := range i {
+			if as[i].Name != "" && strings.EqualFold(as[i].Name, as[j].Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mergeAttrValue joins two values of the attribute name for config.Merge.
+func mergeAttrValue(name, a, b string) string {
+	switch {
+	case isTokenList(name), spaceSeparatedAttrs[name]:
+		return joinTokens(a, b)
+	case name == "style":
+		return joinStyle(a, b)
+	}
+	return b
+}
+
+// joinStyle joins two lists of CSS declarations.
+func joinStyle(a, b string) string {
+	a = strings.TrimRight(strings.TrimSpace(a), ";")
+	b = strings.TrimSpace(b)
+	if a == "" || b == "" {
+		return a + b
+	}
+	return a + "; " + b
+}
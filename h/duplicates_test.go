@@ -0,0 +1,62 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+func TestDuplicateAttrs(t *testing.T) {
+	link := Link(Attributes{
+		{Name: "rel", Value: "stylesheet"},
+		{Name: "href", Value: "style.css"},
+		{Name: "REL", Value: "preload stylesheet"},
+	})
+	styled := Div(Attributes{
+		{Name: "style", Value: "color: red;"},
+		{Name: "id", Value: "a"},
+		{Name: "style", Value: "margin: 0"},
+		{Name: "id", Value: "b"},
+	})
+
+	tests := []struct {
+		name     string
+		policy   config.DuplicatePolicy
+		b        Builder
+		expected string
+	}{
+		{"keep all", config.KeepAll, link, `<link rel="stylesheet" href="style.css" REL="preload stylesheet"/>`},
+		{"keep first", config.KeepFirst, link, `<link rel="stylesheet" href="style.css"/>`},
+		{"keep last", config.KeepLast, link, `<link rel="preload stylesheet" href="style.css"/>`},
+		{"merge tokens", config.Merge, link, `<link rel="stylesheet preload" href="style.css"/>`},
+		{"merge style and last", config.Merge, styled, `<div style="color: red; margin: 0" id="b"></div>`},
+		{"merge aria list", config.Merge, Span(Attributes{{Name: "aria-describedby", Value: "a"}, {Name: "aria-describedby", Value: "b"}}), `<span aria-describedby="a b"></span>`},
+		{"no duplicates", config.Error, Div(Class("x"), ID("y")), `<div class="x" id="y"></div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			ctx := config.WithContext(context.Background(), config.New(config.DuplicateAttrs(tt.policy)))
+			if err := RenderContext(ctx, &sb, tt.b); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	w.SetDuplicateAttrs(config.Error)
+	err := link.Build(w)
+	if !errors.Is(err, ErrDuplicateAttribute) || err.Error() != "duplicate attribute: REL on <link>" {
+		t.Errorf("Build() = %v, want ErrDuplicateAttribute", err)
+	}
+	if sb.Len() != 0 {
+		t.Errorf("wrote %q before failing", sb.String())
+	}
+}
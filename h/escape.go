@@ -0,0 +1,165 @@
+package h
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+// ErrInvalidUTF8 is returned when the config.RejectInvalidUTF8 escape rule
+// (see Writer.SetEscape) finds invalid UTF-8 in text or an attribute value.
+var ErrInvalidUTF8 = errors.New("invalid UTF-8")
+
+// writeEscaped writes s HTML-escaped under the Writer's escape policy. attr
+// reports whether s is an attribute value.
+func (w *Writer) writeEscaped(s string, attr bool) error {
+	if w.escape == 0 {
+		return writeEscapedString(w.w, s)
+	}
+	return writeEscapedPolicy(w.w, s, w.escape, attr)
+}
+
+// writeEscapedPolicy is writeEscapedString with the additional rules of
+// policy.
+func writeEscapedPolicy(w io.Writer, s string, policy config.EscapePolicy, attr bool) error {
+	var num [16]byte
+	last := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		size := 1
+		var repl []byte
+		switch {
+		case htmlEscapes[c] != 0:
+			repl = htmlReplacements[htmlEscapes[c]]
+		case c == '\n' || c == '\r':
+			if attr && policy&config.EscapeNewlines != 0 {
+				repl = appendCharRef(num[:0], rune(c))
+			}
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			if policy&config.EscapeControl != 0 {
+				repl = appendCharRef(num[:0], rune(c))
+			}
+		case c >= utf8.RuneSelf:
+			var r rune
+			r, size = utf8.DecodeRuneInString(s[i:])
+			switch {
+			case r == utf8.RuneError && size == 1 && policy&config.RejectInvalidUTF8 != 0:
+				return fmt.Errorf("%w: byte %#x at offset %d", ErrInvalidUTF8, c, i)
+			case (r == '\u2028' || r == '\u2029') && policy&config.EscapeLineSeparators != 0:
+				repl = appendCharRef(num[:0], r)
+			}
+		}
+		if repl != nil {
+			if _, err := io.WriteString(w, s[last:i]); err != nil {
+				return err
+			}
+			if _, err := w.Write(repl); err != nil {
+				return err
+			}
+			last = i + size
+		}
+		i += size
+	}
+	_, err := io.WriteString(w, s[last:])
+	return err
+}
+
+// appendCharRef appends the decimal character reference for r, like &#10;.
+func appendCharRef(b []byte, r rune) []byte {
+	b = append(b, "&#"...)
+	b = strconv.AppendInt(b, int64(r), 10)
+	return append(b, ';')
+}
+
+// writeRawText writes txt as the content of the raw text element tag, such
+// as <script>, under the Writer's escape policy. Character references are
+// not decoded there, so escaped characters use the element's backslash
+// escapes instead: \u2028 in scripts and \2028 in styles, which keep
+// their meaning inside string literals.
+func (w *Writer) writeRawText(tag, txt string) error {
+	txt = escapeRawText(tag, txt)
+	if w.escape != 0 {
+		var err error
+		if txt, err = rawTextPolicy(tag, txt, w.escape); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w.w, txt)
+	return err
+}
+
+// rawTextPolicy applies the rules of policy to the content of the raw text
+// element tag.
+func rawTextPolicy(tag, s string, policy config.EscapePolicy) (string, error) {
+	var sb strings.Builder
+	last := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		size := 1
+		r := rune(c)
+		escape := false
+		switch {
+		case c < 0x20 && c != '\t' && c != '\n' && c != '\r' || c == 0x7f:
+			escape = policy&config.EscapeControl != 0
+		case c >= utf8.RuneSelf:
+			r, size = utf8.DecodeRuneInString(s[i:])
+			switch {
+			case r == utf8.RuneError && size == 1 && policy&config.RejectInvalidUTF8 != 0:
+				return "", fmt.Errorf("%w: byte %#x at offset %d", ErrInvalidUTF8, c, i)
+			case r == '\u2028' || r == '\u2029':
+				escape = policy&config.EscapeLineSeparators != 0
+			}
+		}
+		if escape {
+			sb.WriteString(s[last:i])
+			if strings.EqualFold(tag, "style") {
+				fmt.Fprintf(&sb, "\\%x ", r)
+			} else {
+				fmt.Fprintf(&sb, "\\u%04x", r)
+			}
+			last = i + size
+		}
+		i += size
+	}
+	if last == 0 {
+		return s, nil
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), nil
+}
+
+// policyWriter writes chunks of text HTML-escaped under an escape policy,
+// holding back a UTF-8 sequence split across chunks until it is complete.
+type policyWriter struct {
+	w       io.Writer
+	policy  config.EscapePolicy
+	pending []byte
+}
+
+func (p *policyWriter) Write(b []byte) error {
+	data := append(p.pending, b...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	if err := writeEscapedPolicy(p.w, string(data[:cut]), p.policy, false); err != nil {
+		return err
+	}
+	p.pending = append(p.pending[:0], data[cut:]...)
+	return nil
+}
+
+// Flush writes any incomplete sequence left at the end of the text.
+func (p *policyWriter) Flush() error {
+	return writeEscapedPolicy(p.w, string(p.pending), p.policy, false)
+}
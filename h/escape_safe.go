@@ -2,17 +2,16 @@
 
 package h
 
-import (
-	"io"
-	"strings"
-)
+import "io"
 
 // writeEscapedString writes s to w with HTML escaping, avoiding allocations
 // when no escaping is needed.
 func writeEscapedString(w io.Writer, s string) error {
-	if strings.ContainsAny(s, "&<>\"'") {
-		// Slow path: use writeHTMLEscape which writes directly to w, allocating a byte slice
-		return writeHTMLEscape(w, []byte(s))
+	for i := 0; i < len(s); i++ {
+		if htmlEscapes[s[i]] != 0 {
+			// Slow path: use writeHTMLEscape which writes directly to w, allocating a byte slice
+			return writeHTMLEscape(w, []byte(s))
+		}
 	}
 	// Fast path: No escaping needed
 	_, err := io.WriteString(w, s)
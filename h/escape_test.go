@@ -0,0 +1,136 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+func TestEscapePolicy(t *testing.T) {
+	const value = "a\x1b[0m\nb\u2028c\x7f<"
+	b := Div(Attrs("title", value), Text(value))
+
+	tests := []struct {
+		name     string
+		policy   config.EscapePolicy
+		expected string
+	}{
+		{"default", 0, "<div title=\"a\x1b[0m\nb\u2028c\x7f&lt;\">a\x1b[0m\nb\u2028c\x7f&lt;</div>"},
+		{"control", config.EscapeControl, "<div title=\"a&#27;[0m\nb\u2028c&#127;&lt;\">a&#27;[0m\nb\u2028c&#127;&lt;</div>"},
+		{"newlines in attributes only", config.EscapeNewlines, "<div title=\"a\x1b[0m&#10;b\u2028c\x7f&lt;\">a\x1b[0m\nb\u2028c\x7f&lt;</div>"},
+		{"line separators", config.EscapeLineSeparators, "<div title=\"a\x1b[0m\nb&#8232;c\x7f&lt;\">a\x1b[0m\nb&#8232;c\x7f&lt;</div>"},
+		{"strict", config.EscapeStrict, `<div title="a&#27;[0m&#10;b&#8232;c&#127;&lt;">a&#27;[0m` + "\n" + `b&#8232;c&#127;&lt;</div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			ctx := config.WithContext(context.Background(), config.New(config.Escape(tt.policy)))
+			if err := RenderContext(ctx, &sb, b); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEscapeInvalidUTF8(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Builder
+	}{
+		{"text", P(Text("ok \xff"))},
+		{"attribute", P(Attrs("title", "caf\xc3"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			w := NewWriter(&sb)
+			w.SetEscape(config.RejectInvalidUTF8)
+			if err := tt.b.Build(w); !errors.Is(err, ErrInvalidUTF8) {
+				t.Errorf("Build() = %v, want ErrInvalidUTF8", err)
+			}
+		})
+	}
+
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	w.SetEscape(config.RejectInvalidUTF8)
+	err := Text("naïve \u2028 ok \xff").Build(w)
+	if want := "invalid UTF-8: byte 0xff at offset 14"; err == nil || err.Error() != want {
+		t.Errorf("Build() = %v, want %q", err, want)
+	}
+
+	if got := RenderString(P(Text("\xff"))); got != "<p>\xff</p>" {
+		t.Errorf("default policy should write invalid UTF-8 as-is, got %q", got)
+	}
+}
+
+func TestEscapePolicyRawText(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		policy   config.EscapePolicy
+		expected string
+	}{
+		{"default", Script(Text("a(\"\u2028\")")), 0, "<script>a(\"\u2028\")</script>"},
+		{"script", Script(Text("a(\"\u2028\x01\")</script>")), config.EscapeStrict, `<script>a("\u2028\u0001")<\/script></script>`},
+		{"style", Style(Text("a::after{content:\"\u2029\"}")), config.EscapeLineSeparators, `<style>a::after{content:"\2029 "}</style>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			w := NewWriter(&sb)
+			w.SetEscape(tt.policy)
+			if err := tt.b.Build(w); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	w := NewWriter(io.Discard)
+	w.SetEscape(config.RejectInvalidUTF8)
+	if err := Script(Text("x = '\xff'")).Build(w); !errors.Is(err, ErrInvalidUTF8) {
+		t.Errorf("Build() = %v, want ErrInvalidUTF8", err)
+	}
+}
+
+// byteReader returns one byte per Read, splitting every UTF-8 sequence.
+type byteReader struct{ s string }
+
+func (r *byteReader) Read(p []byte) (int, error) {
+	if r.s == "" {
+		return 0, io.EOF
+	}
+	p[0] = r.s[0]
+	r.s = r.s[1:]
+	return 1, nil
+}
+
+func TestEscapePolicyTextReader(t *testing.T) {
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	w.SetEscape(config.EscapeStrict)
+	if err := P(TextReader(&byteReader{"naïve\u2028<\x1b"})).Build(w); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sb.String(), "<p>naïve&#8232;&lt;&#27;</p>"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	for _, s := range []string{"ok \xff more", "cut \xe2\x80"} {
+		w := NewWriter(io.Discard)
+		w.SetEscape(config.RejectInvalidUTF8)
+		if err := P(TextReader(&byteReader{s})).Build(w); !errors.Is(err, ErrInvalidUTF8) {
+			t.Errorf("TextReader(%q) = %v, want ErrInvalidUTF8", s, err)
+		}
+	}
+}
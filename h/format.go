@@ -0,0 +1,233 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+type localeKey struct{}
+
+// WithLocale returns a context whose renders format Number, Money, and
+// DateTime for tag.
+func WithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// Locale returns the locale set with WithLocale, or language.AmericanEnglish.
+func Locale(ctx context.Context) language.Tag {
+	if tag, ok := ctx.Value(localeKey{}).(language.Tag); ok {
+		return tag
+	}
+	return language.AmericanEnglish
+}
+
+// LocaleFromRequest picks the best of supported for the request's
+// Accept-Language header. The first supported tag is the fallback.
+// Panics if supported is empty.
+func LocaleFromRequest(r *http.Request, supported ...language.Tag) language.Tag {
+	tags, _, _ := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	tag, _, _ := language.NewMatcher(supported).Match(tags...)
+	return tag
+}
+
+// Numeric is the set of types accepted by Number.
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Number renders v with the grouping and decimal separators of the render
+// locale (e.g., "1,234.5" in en-US and "1.234,5" in de-DE).
+func Number[T Numeric](v T) Builder {
+	return &localizedBuilder{format: func(w *Writer, p *message.Printer) error {
+		return w.Text(p.Sprint(number.Decimal(v)))
+	}}
+}
+
+// Money renders an amount of the ISO 4217 currency (e.g., "USD", "EUR")
+// given in its minor units, such as cents, so no precision is lost to
+// floating point. The amount is formatted with the render locale's
+// separators and the currency's precision and symbol, placed as the locale
+// writes it:
+//
+//	h.Money(123450, "USD") // en-US: $1,234.50
+//	h.Money(123450, "EUR") // de: 1.234,50 €
+//	h.Money(1234, "JPY")   // ja: ￥1,234
+//
+// Rendering fails with ErrUnknownCurrency if the code is not a known
+// currency.
+func Money(minorUnits int64, currencyCode string) Builder {
+	return &localizedBuilder{format: func(w *Writer, p *message.Printer) error {
+		unit, err := currency.ParseISO(currencyCode)
+		if err != nil {
+			return fmt.Errorf("%w: %q", ErrUnknownCurrency, currencyCode)
+		}
+		scale, _ := currency.Standard.Rounding(unit)
+		return w.Text(formatMoney(p, Locale(w.Context()), unit, minorUnits, scale))
+	}}
+}
+
+// ErrUnknownCurrency is returned when Money is given a currency code that
+// is not a known ISO 4217 currency.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// formatMoney formats minor units of unit, which has scale decimal
+// digits, for tag.
+func formatMoney(p *message.Printer, tag language.Tag, unit currency.Unit, minor int64, scale int) string {
+	sign := ""
+	// Negate through uint64 so the smallest int64 does not overflow.
+	abs := uint64(minor)
+	if minor < 0 {
+		sign, abs = "-", -abs
+	}
+	pow := uint64(1)
+	for range scale {
+		pow *= 10
+	}
+	amount := p.Sprint(number.Decimal(abs / pow))
+	if scale > 0 {
+		// The decimal separator is what the locale writes between 1 and 5.
+		sep := strings.TrimSuffix(strings.TrimPrefix(p.Sprint(number.Decimal(1.5, number.Scale(1))), "1"), "5")
+		frac := strconv.FormatUint(abs%pow, 10)
+		amount += sep + strings.Repeat("0", scale-len(frac)) + frac
+	}
+	symbol := p.Sprint(currency.Symbol(unit))
+	switch currencyPattern(tag) {
+	case symbolAfter:
+		return sign + amount + "\u00a0" + symbol
+	case symbolBeforeSpaced:
+		return sign + symbol + "\u00a0" + amount
+	default:
+		return sign + symbol + amount
+	}
+}
+
+// Placements of a currency symbol.
+const (
+	symbolBefore       = iota // $1.00
+	symbolBeforeSpaced        // € 1,00
+	symbolAfter               // 1,00 €
+)
+
+// currencyPattern returns where tag writes the currency symbol, following
+// the CLDR currency formats of the most common locales. Locales not listed
+// write it before the amount.
+func currencyPattern(tag language.Tag) int {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	switch base.String() {
+	case "de", "fr", "es", "it", "pl", "cs", "sk", "fi", "sv", "nb", "da", "ru", "uk", "lt", "hu", "ro", "bg", "el", "pt":
+		if base.String() == "pt" && region.String() == "BR" {
+			return symbolBeforeSpaced
+		}
+		if base.String() == "es" && region.String() != "ES" && region.String() != "ZZ" {
+			return symbolBefore // Latin American Spanish
+		}
+		return symbolAfter
+	case "nl":
+		return symbolBeforeSpaced
+	}
+	return symbolBefore
+}
+
+// DateStyle selects how DateTime formats a time.
+type DateStyle int
+
+const (
+	// ShortDate is a numeric date, e.g. "1/2/2006" (en-US) or "02.01.2006" (de).
+	ShortDate DateStyle = iota
+	// ShortDateTime is a numeric date and time, e.g. "1/2/2006, 3:04 PM".
+	ShortDateTime
+	// LongDate spells out the month in English locales, e.g. "January 2, 2006".
+	// Other locales use ShortDate, since month names are not localized.
+	LongDate
+	// LongDateTime is LongDate followed by the time.
+	LongDateTime
+	// TimeOnly is the time of day, e.g. "3:04 PM" or "15:04".
+	TimeOnly
+)
+
+// DateTime renders t as a <time> element with a machine-readable datetime
+// attribute and text formatted for the render locale:
+//
+//	h.DateTime(post.Published, h.LongDate)
+//	// <time datetime="2006-01-02">January 2, 2006</time>
+func DateTime(t time.Time, style DateStyle) Builder {
+	return &localizedBuilder{format: func(w *Writer, _ *message.Printer) error {
+		layout, machine := dateLayout(Locale(w.Context()), style)
+		return Time(Attrs("datetime", t.Format(machine)), Text(t.Format(layout))).Build(w)
+	}}
+}
+
+// dateLayout returns the display and datetime-attribute layouts for style in tag.
+func dateLayout(tag language.Tag, style DateStyle) (display, machine string) {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+
+	clock := "15:04"
+	switch region.String() {
+	case "US", "CA", "AU", "NZ", "IN", "PH":
+		if base.String() == "en" {
+			clock = "3:04 PM"
+		}
+	}
+
+	var date string
+	switch base.String() {
+	case "ja", "zh", "ko":
+		date = "2006/01/02"
+	case "sv", "lt":
+		date = "2006-01-02"
+	case "de", "ru", "pl", "fi", "cs", "sk", "nb", "da", "tr", "uk":
+		date = "02.01.2006"
+	case "nl":
+		date = "02-01-2006"
+	case "en":
+		if region.String() == "US" {
+			date = "1/2/2006"
+		} else {
+			date = "02/01/2006"
+		}
+	default:
+		date = "02/01/2006"
+	}
+	if base.String() == "en" && (style == LongDate || style == LongDateTime) {
+		if region.String() == "US" {
+			date = "January 2, 2006"
+		} else {
+			date = "2 January 2006"
+		}
+	}
+
+	switch style {
+	case ShortDateTime, LongDateTime:
+		return date + ", " + clock, time.RFC3339
+	case TimeOnly:
+		return clock, "15:04:05"
+	default:
+		return date, time.DateOnly
+	}
+}
+
+// localizedBuilder formats its content at render time with the locale from
+// the Writer's context.
+type localizedBuilder struct {
+	format func(w *Writer, p *message.Printer) error
+}
+
+func (b *localizedBuilder) isTagArg() {}
+
+func (b *localizedBuilder) Build(w *Writer) error {
+	return b.format(w, message.NewPrinter(Locale(w.Context())))
+}
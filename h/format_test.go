@@ -0,0 +1,93 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+func renderLocale(t *testing.T, tag language.Tag, b Builder) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := RenderContext(WithLocale(context.Background(), tag), &sb, b); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+func TestNumberAndMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      language.Tag
+		b        Builder
+		expected string
+	}{
+		{"number en", language.AmericanEnglish, Number(1234.5), "1,234.5"},
+		{"number de", language.German, Number(1234.5), "1.234,5"},
+		{"int", language.AmericanEnglish, Number(1000000), "1,000,000"},
+		{"money en", language.AmericanEnglish, Money(123450, "USD"), "$1,234.50"},
+		{"money de", language.German, Money(123450, "EUR"), "1.234,50\u00a0€"},
+		{"money nl", language.Dutch, Money(5, "EUR"), "€\u00a00,05"},
+		{"money pt-BR", language.BrazilianPortuguese, Money(990, "BRL"), "R$\u00a09,90"},
+		{"money yen", language.Japanese, Money(1234, "JPY"), "￥1,234"},
+		{"money negative", language.AmericanEnglish, Money(-1999, "USD"), "-$19.99"},
+		{"money cents exact", language.AmericanEnglish, Money(1_000_000_000_000_001, "USD"), "$10,000,000,000,000.01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderLocale(t, tt.tag, tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMoneyUnknownCurrency(t *testing.T) {
+	err := Render(io.Discard, Money(2, "XYZW"))
+	if !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Render() = %v, want ErrUnknownCurrency", err)
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name     string
+		tag      language.Tag
+		style    DateStyle
+		expected string
+	}{
+		{"en-US short", language.AmericanEnglish, ShortDate, `<time datetime="2006-01-02">1/2/2006</time>`},
+		{"en-US long", language.AmericanEnglish, LongDate, `<time datetime="2006-01-02">January 2, 2006</time>`},
+		{"en-GB long", language.BritishEnglish, LongDate, `<time datetime="2006-01-02">2 January 2006</time>`},
+		{"de short datetime", language.German, ShortDateTime, `<time datetime="2006-01-02T15:04:05Z">02.01.2006, 15:04</time>`},
+		{"de long falls back", language.German, LongDate, `<time datetime="2006-01-02">02.01.2006</time>`},
+		{"ja", language.Japanese, ShortDate, `<time datetime="2006-01-02">2006/01/02</time>`},
+		{"en-US time", language.AmericanEnglish, TimeOnly, `<time datetime="15:04:05">3:04 PM</time>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderLocale(t, tt.tag, DateTime(ts, tt.style)); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLocaleDefaultsAndRequest(t *testing.T) {
+	if got := RenderString(Number(1234)); got != "1,234" {
+		t.Errorf("default locale Number = %q", got)
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Language", "fr-CH, de;q=0.9, en;q=0.5")
+	tag := LocaleFromRequest(r, language.English, language.German)
+	if base, _ := tag.Base(); base.String() != "de" {
+		t.Errorf("LocaleFromRequest = %v, want de", tag)
+	}
+}
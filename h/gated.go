@@ -0,0 +1,36 @@
+package h
+
+import "strings"
+
+// GatedScript creates an inert <script> placeholder for a script that may only
+// run once the user has consented to category (e.g., "analytics").
+//
+// The element is rendered with type="text/plain" so browsers never execute it,
+// and carries the category and source in data attributes:
+//
+//	h.GatedScript("analytics", "https://stats.example.com/s.js")
+//	// <script type="text/plain" data-consent="analytics" data-src="https://stats.example.com/s.js"></script>
+//
+// Client-side consent code activates matching placeholders by replacing them
+// with real script elements. Additional args (attributes such as async or defer)
+// are copied onto the activated script by the consent package's activation code.
+// The gating attributes always win: a type attribute in args, such as
+// type="module", is kept in data-type for activation, and src is ignored.
+func GatedScript(category, src string, args ...TagArg) Builder {
+	attrs, children := parseTagArgs(args)
+	gated := Attributes{
+		{Name: "type", Value: "text/plain"},
+		{Name: "data-consent", Value: category},
+		{Name: "data-src", Value: src},
+	}
+	for _, attr := range attrs {
+		switch strings.ToLower(attr.Name) {
+		case "type":
+			attr.Name = "data-type"
+		case "src", "data-consent", "data-src":
+			continue
+		}
+		gated.setAttr(attr)
+	}
+	return &tagBuilder{Name: "script", Attrs: gated, Children: children}
+}
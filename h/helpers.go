@@ -1,6 +1,9 @@
 package h
 
-import "iter"
+import (
+	"iter"
+	"slices"
+)
 
 // If returns ifTrue if cond is true, otherwise returns ifElse.
 // This enables conditional rendering in builder expressions:
@@ -18,6 +21,14 @@ func If(cond bool, ifTrue, ifElse Builder) Builder {
 	return ifElse
 }
 
+// IfElse is an alias for If for readers who prefer the branch to be explicit
+// in the name:
+//
+//	h.IfElse(len(items) == 0, h.P(h.Text("No items")), itemList)
+func IfElse(cond bool, then, otherwise Builder) Builder {
+	return If(cond, then, otherwise)
+}
+
 // When returns ifTrue if cond is true, otherwise returns nil.
 // Nil builders are safely skipped during rendering.
 // This is a convenience wrapper around If for cases without an else branch:
@@ -130,3 +141,33 @@ func (m *forEach2Builder[X, Y]) Build(w *Writer) error {
 func ForEach2[X, Y any](s iter.Seq2[X, Y], fn func(X, Y) Builder) Builder {
 	return &forEach2Builder[X, Y]{seq: s, fn: fn}
 }
+
+// MapSlice creates a Builder that renders fn for each element of items.
+// It is ForEach over slices.Values, without the iterator boilerplate:
+//
+//	h.Ul(
+//	    h.MapSlice(users, func(u User) h.Builder {
+//	        return h.Li(h.Text(u.Name))
+//	    }),
+//	)
+//
+// (The name Map is taken by the <map> element.)
+func MapSlice[T any](items []T, fn func(T) Builder) Builder {
+	return ForEach(slices.Values(items), fn)
+}
+
+// Range creates a Builder that renders fn(i) for i from 0 to n-1.
+// Nothing is rendered when n <= 0.
+//
+//	h.Div(
+//	    h.Range(rating, func(int) h.Builder { return h.Span(h.Text("★")) }),
+//	)
+func Range(n int, fn func(i int) Builder) Builder {
+	return ForEach(func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}, fn)
+}
@@ -318,3 +318,68 @@ func TestForEachLazyEvaluation(t *testing.T) {
 		t.Errorf("expected 3 iterator calls, got %d", callCount)
 	}
 }
+
+func TestIfElse(t *testing.T) {
+	if got := RenderString(IfElse(false, Text("yes"), Text("no"))); got != "no" {
+		t.Errorf("expected %q, got %q", "no", got)
+	}
+}
+
+func TestMapSlice(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []string
+		expected string
+	}{
+		{"items", []string{"a", "b"}, "<ul><li>a</li><li>b</li></ul>"},
+		{"empty", nil, "<ul></ul>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderString(Ul(MapSlice(tt.items, func(s string) Builder { return Li(Text(s)) })))
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		expected string
+	}{
+		{"three", 3, "012"},
+		{"zero", 0, ""},
+		{"negative", -1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderString(Range(tt.n, func(i int) Builder { return Textf("%d", i) }))
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsland(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"default", Island("clock", P(Text("12:00"))), `<div data-island="clock"><p>12:00</p></div>`},
+		{"eager", Island("menu", IslandEager()), `<div data-island="menu" data-island-hydrate="eager"></div>`},
+		{"idle", Island("chat", Class("w"), IslandIdle()), `<div data-island="chat" class="w" data-island-hydrate="idle"></div>`},
+		{"visible", Island("map", IslandVisible()), `<div data-island="map" data-island-hydrate="visible"></div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package h
+
+import "context"
+
+// ElementHook extends rendering with per-element callbacks, for
+// instrumentation (timing or counting elements), analytics auto-tagging, or
+// custom validation, without wrapping every Builder. Register hooks with
+// WithElementHooks or Writer.AddElementHook.
+type ElementHook interface {
+	// BeforeOpen is called before an element's start tag is written and
+	// returns the attributes to write. Return attrs unchanged to observe
+	// only. attrs may be shared with the Builder, so copy it (for example
+	// with slices.Clone) before changing it. A non-nil error stops
+	// rendering before the tag is written.
+	BeforeOpen(w *Writer, tag string, attrs Attributes) (Attributes, error)
+	// AfterClose is called after an element's end tag is written, or
+	// right after the tag of a void element such as <img>, with the
+	// attributes returned by BeforeOpen.
+	AfterClose(w *Writer, tag string, attrs Attributes) error
+}
+
+type elementHooksKey struct{}
+
+// WithElementHooks returns a context whose renders call hooks for every
+// element, after any hooks already set on ctx:
+//
+//	ctx := h.WithElementHooks(r.Context(), tracer)
+//	h.RenderContext(ctx, w, page)
+func WithElementHooks(ctx context.Context, hooks ...ElementHook) context.Context {
+	existing, _ := ctx.Value(elementHooksKey{}).([]ElementHook)
+	all := make([]ElementHook, 0, len(existing)+len(hooks))
+	all = append(append(all, existing...), hooks...)
+	return context.WithValue(ctx, elementHooksKey{}, all)
+}
+
+// AddElementHook registers hook on w. BeforeOpen hooks run in the order
+// they were added, each receiving the attributes returned by the previous
+// one; AfterClose hooks run in reverse order.
+func (w *Writer) AddElementHook(hook ElementHook) {
+	w.hooks = append(w.hooks, hook)
+}
+
+// beforeOpen runs the BeforeOpen hooks for an element.
+func (w *Writer) beforeOpen(name string, as Attributes) (Attributes, error) {
+	for _, hook := range w.hooks {
+		var err error
+		if as, err = hook.BeforeOpen(w, name, as); err != nil {
+			return nil, err
+		}
+	}
+	return as, nil
+}
+
+// afterClose runs the AfterClose hooks for an element.
+func (w *Writer) afterClose(name string, as Attributes) error {
+	for i := len(w.hooks) - 1; i >= 0; i-- {
+		if err := w.hooks[i].AfterClose(w, name, as); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closedTag runs the AfterClose hooks for the open element at index i,
+// whose end tag has been written.
+func (w *Writer) closedTag(i int) error {
+	if len(w.hooks) == 0 {
+		return nil
+	}
+	var as Attributes
+	if i < len(w.openAttrs) {
+		as = w.openAttrs[i]
+	}
+	return w.afterClose(w.openTags[i], as)
+}
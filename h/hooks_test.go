@@ -0,0 +1,134 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// recordingHook logs each callback as "prefix+tag" or "prefix-tag".
+type recordingHook struct {
+	prefix string
+	log    *[]string
+}
+
+func (r recordingHook) BeforeOpen(w *Writer, tag string, as Attributes) (Attributes, error) {
+	*r.log = append(*r.log, r.prefix+"+"+tag)
+	return as, nil
+}
+
+func (r recordingHook) AfterClose(w *Writer, tag string, as Attributes) error {
+	id, _ := as.Get("id")
+	*r.log = append(*r.log, r.prefix+"-"+tag+id)
+	return nil
+}
+
+// trackingHook adds data-track to buttons.
+type trackingHook struct{}
+
+func (trackingHook) BeforeOpen(w *Writer, tag string, as Attributes) (Attributes, error) {
+	if tag != "button" {
+		return as, nil
+	}
+	as = slices.Clone(as)
+	as.Set("data-track", "click")
+	return as, nil
+}
+
+func (trackingHook) AfterClose(w *Writer, tag string, as Attributes) error { return nil }
+
+var errForbidden = errors.New("forbidden element")
+
+// denyHook rejects <marquee>.
+type denyHook struct{}
+
+func (denyHook) BeforeOpen(w *Writer, tag string, as Attributes) (Attributes, error) {
+	if tag == "marquee" {
+		return nil, errForbidden
+	}
+	return as, nil
+}
+
+func (denyHook) AfterClose(w *Writer, tag string, as Attributes) error { return nil }
+
+func TestElementHooks(t *testing.T) {
+	var log []string
+	ctx := WithElementHooks(context.Background(), recordingHook{"a", &log})
+	ctx = WithElementHooks(ctx, recordingHook{"b", &log})
+	var buf bytes.Buffer
+	err := RenderContext(ctx, &buf, Div(Attrs("id", "1"), P(Text("x")), Img(Src("/a.png"))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "a+div b+div a+p b+p b-p a-p a+img b+img b-img a-img b-div1 a-div1"
+	if got := strings.Join(log, " "); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
+
+func TestElementHookAttributes(t *testing.T) {
+	shared := Attrs("class", "btn")
+	var buf bytes.Buffer
+	ctx := WithElementHooks(context.Background(), trackingHook{})
+	if err := RenderContext(ctx, &buf, Div(Button(shared, Text("Go")))); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<div><button class="btn" data-track="click">Go</button></div>`
+	if got := buf.String(); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+	if len(shared) != 1 {
+		t.Errorf("hook modified the caller's attributes: %v", shared)
+	}
+}
+
+func TestElementHookError(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := WithElementHooks(context.Background(), denyHook{})
+	err := RenderContext(ctx, &buf, Div(CustomElement("marquee", Text("hi"))))
+	if !errors.Is(err, errForbidden) {
+		t.Errorf("err = %v, want errForbidden", err)
+	}
+	if got := buf.String(); got != "<div>" {
+		t.Errorf("got %s, want <div>", got)
+	}
+}
+
+func TestWriterElementHooks(t *testing.T) {
+	var log []string
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.OpenTag("main", nil) // Opened before the hook is added
+	w.AddElementHook(recordingHook{"", &log})
+	w.OpenTag("section", Attrs("id", "s"))
+	w.OpenTag("p", nil)
+	if err := w.CloseTag("section"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "+section +p -p -sections -main"
+	if got := strings.Join(log, " "); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+	if got := buf.String(); got != `<main><section id="s"><p></p></section></main>` {
+		t.Errorf("unexpected output %s", got)
+	}
+}
+
+func TestElementHooksPooledWriterReset(t *testing.T) {
+	var log []string
+	var buf bytes.Buffer
+	ctx := WithElementHooks(context.Background(), recordingHook{"", &log})
+	if err := RenderContext(ctx, &buf, Div()); err != nil {
+		t.Fatal(err)
+	}
+	RenderString(P())
+	if len(log) != 2 {
+		t.Errorf("hook leaked into a later render: %v", log)
+	}
+}
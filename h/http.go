@@ -0,0 +1,227 @@
+package h
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ContentTypeHTML is the Content-Type set by RenderHTTP when none is present.
+const ContentTypeHTML = "text/html; charset=utf-8"
+
+// HandlerFunc adapts a function returning a Builder to an http.Handler.
+// The Builder is streamed to the response with RenderHTTP. A nil Builder
+// produces an empty 200 response.
+//
+//	mux.Handle("/", h.HandlerFunc(func(r *http.Request) h.Builder {
+//	    return h.Html(h.Body(h.H1(h.Text("Hello"))))
+//	}))
+type HandlerFunc func(r *http.Request) Builder
+
+// ServeHTTP implements http.Handler.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveBuilder(w, r, f(r))
+}
+
+// Handler returns an http.Handler that renders the Builder returned by fn.
+// It is equivalent to HandlerFunc(fn).
+func Handler(fn func(r *http.Request) Builder) http.Handler {
+	return HandlerFunc(fn)
+}
+
+// ErrorHandlerFunc adapts a function returning a Builder or an error to an
+// http.Handler. Errors are passed to HTTPErrorHandler; return a *StatusError
+// (see Error) to choose the response status:
+//
+//	mux.Handle("/users/{id}", h.ErrorHandlerFunc(func(r *http.Request) (h.Builder, error) {
+//	    u, ok := users[r.PathValue("id")]
+//	    if !ok {
+//	        return nil, h.Error(http.StatusNotFound, nil)
+//	    }
+//	    return userPage(u), nil
+//	}))
+type ErrorHandlerFunc func(r *http.Request) (Builder, error)
+
+// ServeHTTP implements http.Handler.
+func (f ErrorHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, err := f(r)
+	if err != nil {
+		HTTPErrorHandler(w, r, err)
+		return
+	}
+	serveBuilder(w, r, b)
+}
+
+// Page returns an http.Handler that renders the same Builder for every request.
+func Page(b Builder) http.Handler {
+	return HandlerFunc(func(*http.Request) Builder { return b })
+}
+
+// StatusError is an error carrying the HTTP status code to respond with.
+type StatusError struct {
+	Code int
+	Err  error
+}
+
+// Error returns a *StatusError with the given status code wrapping err.
+// err may be nil.
+func Error(code int, err error) *StatusError {
+	return &StatusError{Code: code, Err: err}
+}
+
+func (e *StatusError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%d %s", e.Code, http.StatusText(e.Code))
+	}
+	return fmt.Sprintf("%d %s: %v", e.Code, http.StatusText(e.Code), e.Err)
+}
+
+func (e *StatusError) Unwrap() error { return e.Err }
+
+// StatusCode returns the HTTP status for err: the code of the first
+// *StatusError in its chain, or 500 Internal Server Error.
+func StatusCode(err error) int {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// HTTPErrorHandler writes the response for errors returned by an
+// ErrorHandlerFunc, or raised while rendering before any output was written.
+// The default writes the status text as plain text. Replace it to render
+// custom error pages.
+var HTTPErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+	code := StatusCode(err)
+	http.Error(w, http.StatusText(code), code)
+}
+
+func serveBuilder(w http.ResponseWriter, r *http.Request, b Builder) {
+	if err := RenderHTTP(w, r, b); err != nil {
+		var we *writeError
+		if !errors.As(err, &we) && !errors.Is(err, ErrPartialRender) {
+			HTTPErrorHandler(w, r, err)
+		}
+	}
+}
+
+var gzipPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// RenderHTTP streams b to w as HTML. It sets Content-Type to ContentTypeHTML
+// unless already set, and gzip-compresses the body when the request accepts
+// gzip and no Content-Encoding has been set (e.g., by a middleware).
+// HEAD requests receive headers only. The request context is available to
+// builders through Writer.Context.
+//
+// If rendering fails before any output is written, the error is returned and
+// nothing is sent, so the caller can still write an error response. Errors
+// after output has started are returned as-is; the response is truncated,
+// unless the request context has an error fallback (see WithErrorFallback),
+// in which case the response is complete and the error wraps
+// ErrPartialRender.
+func RenderHTTP(w http.ResponseWriter, r *http.Request, b Builder) error {
+	hdr := w.Header()
+	if hdr.Get("Content-Type") == "" {
+		hdr.Set("Content-Type", ContentTypeHTML)
+	}
+	if r.Method == http.MethodHead || b == nil {
+		return nil
+	}
+
+	lw := &lazyWriter{w: w}
+	if hdr.Get("Content-Encoding") != "" || !acceptsGzip(r) {
+		return lw.wrap(RenderContext(r.Context(), lw, b))
+	}
+
+	hdr.Add("Vary", "Accept-Encoding")
+	lw.gzip = true
+	gz := gzipPool.Get().(*gzip.Writer)
+	gz.Reset(lw)
+	defer gzipPool.Put(gz)
+	if err := RenderContext(r.Context(), &gzipWriter{gz, lw}, b); err != nil {
+		return lw.wrap(err)
+	}
+	return lw.wrap(gz.Close())
+}
+
+// lazyWriter defers committing response headers until the first write so
+// that rendering errors raised before any output can still be reported.
+type lazyWriter struct {
+	w       http.ResponseWriter
+	gzip    bool
+	started bool
+}
+
+func (lw *lazyWriter) Write(p []byte) (int, error) {
+	if !lw.started {
+		lw.started = true
+		if lw.gzip {
+			lw.w.Header().Set("Content-Encoding", "gzip")
+			lw.w.Header().Del("Content-Length")
+		}
+	}
+	n, err := lw.w.Write(p)
+	if err != nil {
+		return n, &writeError{err}
+	}
+	return n, nil
+}
+
+// Flush sends buffered response data to the client, for Stream.
+func (lw *lazyWriter) Flush() error {
+	if !lw.started {
+		return nil
+	}
+	err := http.NewResponseController(lw.w).Flush()
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+// gzipWriter flushes compressed data through to the response.
+type gzipWriter struct {
+	*gzip.Writer
+	lw *lazyWriter
+}
+
+func (g *gzipWriter) Flush() error {
+	if err := g.Writer.Flush(); err != nil {
+		return err
+	}
+	return g.lw.Flush()
+}
+
+// wrap marks err as unrecoverable once output has been sent.
+func (lw *lazyWriter) wrap(err error) error {
+	if err != nil && lw.started {
+		var we *writeError
+		if !errors.As(err, &we) && !errors.Is(err, ErrPartialRender) {
+			return &writeError{err}
+		}
+	}
+	return err
+}
+
+// writeError marks errors that occur after the response has started.
+type writeError struct{ err error }
+
+func (e *writeError) Error() string { return e.err.Error() }
+func (e *writeError) Unwrap() error { return e.err }
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		enc, params, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(enc) == "gzip" {
+			q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q=")
+			return !ok || strings.Trim(q, "0.") != ""
+		}
+	}
+	return false
+}
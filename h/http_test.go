@@ -0,0 +1,118 @@
+package h
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	handler := HandlerFunc(func(r *http.Request) Builder {
+		return P(Text(r.URL.Query().Get("name")))
+	})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/?name=Ann", nil))
+	if got := rec.Header().Get("Content-Type"); got != ContentTypeHTML {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Body.String(); got != "<p>Ann</p>" {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestPageHead(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Page(Text("hi")).ServeHTTP(rec, httptest.NewRequest("HEAD", "/", nil))
+	if rec.Body.Len() != 0 {
+		t.Errorf("HEAD body = %q", rec.Body.String())
+	}
+}
+
+func TestRenderHTTPGzip(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		gzipped        bool
+	}{
+		{"none", "", false},
+		{"gzip", "gzip, deflate, br", true},
+		{"gzip q", "br, gzip;q=0.5", true},
+		{"gzip refused", "gzip;q=0", false},
+		{"gzip refused decimal", "gzip;q=0.000", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			if err := RenderHTTP(rec, r, Div(Text("hello"))); err != nil {
+				t.Fatal(err)
+			}
+			var body io.Reader = rec.Body
+			if tt.gzipped {
+				if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+					t.Fatalf("Content-Encoding = %q", got)
+				}
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = zr
+			} else if got := rec.Header().Get("Content-Encoding"); got != "" {
+				t.Fatalf("Content-Encoding = %q", got)
+			}
+			got, _ := io.ReadAll(body)
+			if string(got) != "<div>hello</div>" {
+				t.Errorf("body = %q", got)
+			}
+		})
+	}
+}
+
+type failingBuilder struct{ err error }
+
+func (f failingBuilder) isTagArg()           {}
+func (f failingBuilder) Build(*Writer) error { return f.err }
+
+func TestErrorHandlerFunc(t *testing.T) {
+	tests := []struct {
+		name     string
+		fn       ErrorHandlerFunc
+		expected int
+	}{
+		{"ok", func(*http.Request) (Builder, error) { return Text("ok"), nil }, http.StatusOK},
+		{"status error", func(*http.Request) (Builder, error) { return nil, Error(http.StatusNotFound, nil) }, http.StatusNotFound},
+		{"plain error", func(*http.Request) (Builder, error) { return nil, errors.New("boom") }, http.StatusInternalServerError},
+		{"render error", func(*http.Request) (Builder, error) {
+			return failingBuilder{Error(http.StatusForbidden, nil)}, nil
+		}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.fn.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+			if rec.Code != tt.expected {
+				t.Errorf("status = %d, want %d", rec.Code, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	inner := errors.New("missing")
+	err := Error(http.StatusNotFound, inner)
+	if err.Error() != "404 Not Found: missing" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, inner) {
+		t.Error("expected StatusError to unwrap")
+	}
+	if StatusCode(errors.Join(errors.New("x"), err)) != http.StatusNotFound {
+		t.Error("expected StatusCode to find wrapped StatusError")
+	}
+}
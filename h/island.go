@@ -0,0 +1,28 @@
+package h
+
+// Island marks an interactive region of a server-rendered page whose client
+// code runs ("hydrates") independently of the rest of the page. The island's
+// JavaScript is looked up by name in a client-side registry; see
+// js.IslandScheduler, which also decides when each island runs:
+//
+//	h.Island("comments", h.IslandVisible(), h.Ul(...))
+//	// <div data-island="comments" data-island-hydrate="visible"><ul>...</ul></div>
+//
+// Islands without a priority hydrate immediately, like IslandEager.
+func Island(name string, args ...TagArg) Builder {
+	attrs, children := parseTagArgs(args)
+	island := Attributes{{Name: "data-island", Value: name}}
+	island.Merge(attrs)
+	return &tagBuilder{Name: "div", Attrs: island, Children: children}
+}
+
+// IslandEager hydrates an island as soon as the scheduler runs.
+func IslandEager() Attribute { return Attr("data-island-hydrate", "eager") }
+
+// IslandIdle hydrates an island when the browser is idle
+// (requestIdleCallback), for below-the-fold or low-priority widgets.
+func IslandIdle() Attribute { return Attr("data-island-hydrate", "idle") }
+
+// IslandVisible hydrates an island the first time it scrolls into view
+// (IntersectionObserver).
+func IslandVisible() Attribute { return Attr("data-island-hydrate", "visible") }
@@ -0,0 +1,55 @@
+package h
+
+// slotBuilder is a named insertion point in a layout.
+type slotBuilder struct {
+	param    *Param
+	fallback Builder
+}
+
+func (s *slotBuilder) isTagArg() {}
+
+func (s *slotBuilder) Build(w *Writer) error {
+	if cw, ok := w.output().(*compileWriter); ok {
+		cw.recordSlot(templateSlot{param: s.param, fallback: s.fallback})
+		return nil
+	}
+	if s.fallback != nil {
+		return s.fallback.Build(w)
+	}
+	return nil
+}
+
+// DefineSlot marks a named insertion point in a layout, a page shell
+// compiled once with CompileParams and filled per page with FillSlot:
+//
+//	var layout = h.MustCompileParams(h.Html(
+//	    h.Head(h.DefineSlot("head")),
+//	    h.Body(
+//	        siteHeader,
+//	        h.Main(h.DefineSlot("content")),
+//	        h.Aside(h.DefineSlot("sidebar", defaultSidebar)),
+//	        siteFooter,
+//	    ),
+//	))
+//
+//	layout.With(
+//	    h.FillSlot("head", h.Title(h.Text("Pricing"))),
+//	    h.FillSlot("content", pricingTable),
+//	)
+//
+// A slot that is not filled renders fallback, or nothing. Rendered without
+// CompileParams, the layout shows the fallbacks. Unlike Slot, which creates
+// a <slot> element for web components, DefineSlot writes no markup itself.
+func DefineSlot(name string, fallback ...Builder) Builder {
+	s := &slotBuilder{param: NewParam(name)}
+	if len(fallback) > 0 {
+		s.fallback = Fragment(fallback...)
+	}
+	return s
+}
+
+// FillSlot binds content to the slot name of a layout, for
+// CompiledTemplate.With and Render. See DefineSlot.
+func FillSlot(name string, content ...Builder) ParamValue {
+	return NewParam(name).Value(Fragment(content...))
+}
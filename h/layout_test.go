@@ -0,0 +1,48 @@
+package h
+
+import "testing"
+
+func TestLayoutSlots(t *testing.T) {
+	layout := MustCompileParams(Div(Class("shell"),
+		Header(DefineSlot("header", Text("Site"))),
+		Main(DefineSlot("content")),
+		Aside(DefineSlot("sidebar", P(Text("default")), P(Text("sidebar")))),
+	))
+	tests := []struct {
+		name     string
+		fills    []ParamValue
+		expected string
+	}{
+		{
+			"fallbacks",
+			nil,
+			`<div class="shell"><header>Site</header><main></main><aside><p>default</p><p>sidebar</p></aside></div>`,
+		},
+		{
+			"filled",
+			[]ParamValue{
+				FillSlot("content", H1(Text("Pricing")), P(Text("Plans"))),
+				FillSlot("sidebar", Nav(Text("links"))),
+			},
+			`<div class="shell"><header>Site</header><main><h1>Pricing</h1><p>Plans</p></main><aside><nav>links</nav></aside></div>`,
+		},
+		{
+			"unknown slot ignored",
+			[]ParamValue{FillSlot("footer", Text("x")), FillSlot("header", Text("Acme"))},
+			`<div class="shell"><header>Acme</header><main></main><aside><p>default</p><p>sidebar</p></aside></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(layout.With(tt.fills...)); got != tt.expected {
+				t.Errorf("got  %s\nwant %s", got, tt.expected)
+			}
+		})
+	}
+
+	// Rendered directly, a layout shows its fallbacks.
+	direct := Div(DefineSlot("a", Text("fallback")), DefineSlot("b"))
+	if got := RenderString(direct); got != "<div>fallback</div>" {
+		t.Errorf("direct: got %s", got)
+	}
+}
@@ -0,0 +1,249 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jeffh/htmlgen/config"
+	"golang.org/x/text/language"
+)
+
+// ErrSharedFragment is returned when a Memo or Cached fragment, whose
+// output is reused by later renders, contains request-scoped content: an
+// Authorized check, or a <script> or <style> that would get a CSP nonce.
+var ErrSharedFragment = errors.New("request-scoped content in a shared fragment")
+
+type sharedFragmentKey struct{}
+
+// sharedFragment marks the context of a shared fragment render.
+type sharedFragment struct {
+	nonce bool // The enclosing render adds nonces
+}
+
+// sharedVariant holds the settings of the enclosing render that shape a
+// shared fragment's output. Memo and Cached keep one entry per variant, so
+// renders with different base URLs, locales, or Configs never share bytes.
+type sharedVariant struct {
+	baseURL string
+	locale  string         // "" if no locale was set
+	config  *config.Config // nil for the process-wide defaults
+}
+
+// variantOf returns the sharedVariant of w's render.
+func variantOf(w *Writer) sharedVariant {
+	ctx := w.Context()
+	v := sharedVariant{baseURL: w.baseURL}
+	if tag, ok := ctx.Value(localeKey{}).(language.Tag); ok {
+		v.locale = tag.String()
+	}
+	if c, ok := config.FromContext(ctx); ok {
+		v.config = c
+	}
+	return v
+}
+
+// sharedContext returns the context for rendering a fragment within w
+// whose output is reused. It starts from an empty context and copies in
+// only the settings of w's render that are the same for every request
+// sharing the output: the sharedVariant, element hooks, strict mode, and
+// the error fallback. Request-scoped content fails with ErrSharedFragment
+// instead of being cached.
+func sharedContext(w *Writer) context.Context {
+	outer := w.Context()
+	ctx := context.Background()
+	if w.baseURL != "" {
+		ctx = WithBaseURL(ctx, w.baseURL)
+	}
+	if c, ok := config.FromContext(outer); ok {
+		ctx = config.WithContext(ctx, c)
+	}
+	for _, key := range []any{localeKey{}, elementHooksKey{}, strictUntrustedKey{}, errorFallbackKey{}, memoCacheKey{}} {
+		if v := outer.Value(key); v != nil {
+			ctx = context.WithValue(ctx, key, v)
+		}
+	}
+	sf := sharedFragment{nonce: w.nonce != nil && w.nonce(outer) != ""}
+	return context.WithValue(ctx, sharedFragmentKey{}, sf)
+}
+
+// checkShared returns an error wrapping ErrSharedFragment if ctx renders a
+// shared fragment.
+func checkShared(ctx context.Context, what string) error {
+	if _, ok := ctx.Value(sharedFragmentKey{}).(sharedFragment); ok {
+		return fmt.Errorf("%w: %s", ErrSharedFragment, what)
+	}
+	return nil
+}
+
+// MemoCache stores the rendered output of Memo builders. Its lifetime sets
+// the scope of the memoization: create one per request and attach it with
+// WithMemoCache, or share one across requests for process-wide caching,
+// optionally with a TTL. It is safe for concurrent use.
+type MemoCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[any]memoEntry
+	sweepAt int // Entry count that triggers removal of expired entries
+}
+
+type memoEntry struct {
+	html    []byte
+	expires time.Time // Zero if the entry does not expire
+}
+
+// minMemoSweep is the smallest entry count at which a MemoCache with a TTL
+// removes expired entries on insert.
+const minMemoSweep = 64
+
+// NewMemoCache creates a MemoCache whose entries expire ttl after they are
+// rendered. A ttl of 0 keeps entries until Clear.
+func NewMemoCache(ttl time.Duration) *MemoCache {
+	return &MemoCache{ttl: ttl, entries: map[any]memoEntry{}, sweepAt: minMemoSweep}
+}
+
+// Clear removes all entries.
+func (c *MemoCache) Clear() {
+	c.mu.Lock()
+	clear(c.entries)
+	c.mu.Unlock()
+}
+
+func (c *MemoCache) get(key any) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expires.IsZero() && !now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.html, true
+}
+
+func (c *MemoCache) put(key any, html []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var expires time.Time
+	if c.ttl > 0 {
+		t := now()
+		expires = t.Add(c.ttl)
+		if len(c.entries) >= c.sweepAt {
+			for k, e := range c.entries {
+				if !t.Before(e.expires) {
+					delete(c.entries, k)
+				}
+			}
+			c.sweepAt = max(minMemoSweep, 2*len(c.entries))
+		}
+	}
+	c.entries[key] = memoEntry{html: html, expires: expires}
+}
+
+// Memo is like the package-level Memo but always uses c.
+func (c *MemoCache) Memo(key any, fn func() Builder) Builder {
+	checkMemoKey(key)
+	return &memoBuilder{cache: c, key: key, fn: fn}
+}
+
+type memoCacheKey struct{}
+
+// WithMemoCache returns a context whose renders cache Memo output in c.
+// For request-scoped memoization, attach a new cache per request:
+//
+//	ctx := h.WithMemoCache(r.Context(), h.NewMemoCache(0))
+func WithMemoCache(ctx context.Context, c *MemoCache) context.Context {
+	return context.WithValue(ctx, memoCacheKey{}, c)
+}
+
+// memoKey identifies a MemoCache entry: a Memo key rendered in a variant.
+type memoKey struct {
+	key     any
+	variant sharedVariant
+}
+
+// memoBuilder renders fn once per key and cache.
+type memoBuilder struct {
+	cache *MemoCache // nil to use the context's cache
+	key   any
+	fn    func() Builder
+}
+
+func (b *memoBuilder) isTagArg() {}
+
+func (b *memoBuilder) Build(w *Writer) error {
+	c := b.cache
+	if c == nil {
+		c, _ = w.Context().Value(memoCacheKey{}).(*MemoCache)
+	}
+	if c == nil {
+		return b.render(w)
+	}
+	key := memoKey{b.key, variantOf(w)}
+	if html, ok := c.get(key); ok {
+		_, err := w.w.Write(html)
+		return err
+	}
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	buf.Reset()
+	if err := RenderContext(sharedContext(w), buf, b.fn()); err != nil {
+		return err
+	}
+	html := slices.Clone(buf.Bytes())
+	c.put(key, html)
+	_, err := w.w.Write(html)
+	return err
+}
+
+func (b *memoBuilder) render(w *Writer) error {
+	if child := b.fn(); child != nil {
+		return child.Build(w)
+	}
+	return nil
+}
+
+// Memo creates a Builder that reuses the rendered output of fn for
+// identical keys, for data-dependent fragments that repeat often, such as
+// product cards. The cache comes from the render context (see
+// WithMemoCache); without one, fn is rendered every time.
+//
+// key must be comparable and should hold every input fn depends on. Keys
+// are shared by all Memo calls using a cache, so use a distinct key type
+// per fragment:
+//
+//	type cardKey struct {
+//	    ID    int
+//	    Price int64
+//	}
+//	h.Memo(cardKey{p.ID, p.Price}, func() h.Builder { return productCard(p) })
+//
+// The output is shared by every render using the cache, so fn is rendered
+// with a fresh context rather than the render context. Only the base URL
+// (WithBaseURL or SetBaseURL), locale (WithLocale), and Config
+// (config.WithContext) are carried over, and each combination of them gets
+// its own cache entry. Other context values, including the Authorizer and
+// nonce, are not visible to fn. An Authorized builder, or a <script> or
+// <style> that would get a nonce, makes the render fail with
+// ErrSharedFragment; keep them outside the fragment.
+//
+// Like Compile, memoized output ignores indentation settings. Element
+// hooks and strict mode apply only when the fragment is first rendered.
+// Memo panics if key is not comparable.
+func Memo(key any, fn func() Builder) Builder {
+	checkMemoKey(key)
+	return &memoBuilder{key: key, fn: fn}
+}
+
+func checkMemoKey(key any) {
+	if key == nil || !reflect.ValueOf(key).Comparable() {
+		panic("htmlgen: Memo key must be a non-nil comparable value")
+	}
+}
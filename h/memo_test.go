@@ -0,0 +1,208 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+type cardKey struct {
+	ID    int
+	Price int
+}
+
+func TestMemo(t *testing.T) {
+	calls := 0
+	card := func(id, price int) Builder {
+		return Memo(cardKey{id, price}, func() Builder {
+			calls++
+			return Div(Class("card"), Text(strconv.Itoa(id)+":"+strconv.Itoa(price)))
+		})
+	}
+	page := Fragment(card(1, 100), card(2, 200), card(1, 100), card(1, 150))
+
+	var buf bytes.Buffer
+	ctx := WithMemoCache(context.Background(), NewMemoCache(0))
+	if err := RenderContext(ctx, &buf, page); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<div class="card">1:100</div><div class="card">2:200</div><div class="card">1:100</div><div class="card">1:150</div>`
+	if got := buf.String(); got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+	if calls != 3 {
+		t.Errorf("fn called %d times, want 3", calls)
+	}
+
+	// Without a cache, every Memo renders.
+	calls = 0
+	if got := RenderString(page); got != expected {
+		t.Errorf("uncached got %s", got)
+	}
+	if calls != 4 {
+		t.Errorf("uncached fn called %d times, want 4", calls)
+	}
+}
+
+func TestMemoCacheTTL(t *testing.T) {
+	ref := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := ref
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	cache := NewMemoCache(time.Minute)
+	calls := 0
+	b := cache.Memo("greeting", func() Builder {
+		calls++
+		return P(Text("v" + strconv.Itoa(calls)))
+	})
+	for _, tt := range []struct {
+		after    time.Duration
+		expected string
+	}{
+		{0, "<p>v1</p>"},
+		{30 * time.Second, "<p>v1</p>"},
+		{time.Minute, "<p>v2</p>"},
+		{90 * time.Second, "<p>v2</p>"},
+	} {
+		current = ref.Add(tt.after)
+		if got := RenderString(b); got != tt.expected {
+			t.Errorf("after %v: got %s, want %s", tt.after, got, tt.expected)
+		}
+	}
+
+	cache.Clear()
+	if got := RenderString(b); got != "<p>v3</p>" {
+		t.Errorf("after Clear: got %s", got)
+	}
+}
+
+func TestMemoCacheSweep(t *testing.T) {
+	ref := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := ref
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+
+	cache := NewMemoCache(time.Minute)
+	for i := range minMemoSweep {
+		RenderString(cache.Memo(i, func() Builder { return Text("x") }))
+	}
+	current = ref.Add(time.Hour)
+	RenderString(cache.Memo("new", func() Builder { return Text("y") }))
+	if n := len(cache.entries); n != 1 {
+		t.Errorf("%d entries after sweep, want 1", n)
+	}
+}
+
+func TestMemoKeyNotComparable(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for non-comparable key")
+		}
+	}()
+	Memo([]int{1}, func() Builder { return nil })
+}
+
+func TestMemoError(t *testing.T) {
+	cache := NewMemoCache(0)
+	b := cache.Memo(1, func() Builder { return failingBuilder{errors.New("boom")} })
+	if err := Render(&bytes.Buffer{}, b); err == nil {
+		t.Error("expected error")
+	}
+	if len(cache.entries) != 0 {
+		t.Error("failed render was cached")
+	}
+}
+
+func TestMemoRequestScoped(t *testing.T) {
+	cache := NewMemoCache(0)
+	admin := WithNonce(WithAuthorizer(context.Background(), AuthorizerFunc(func(context.Context, string) bool { return true })), "AAA")
+	guest := WithNonce(context.Background(), "BBB")
+
+	tests := []struct {
+		name string
+		fn   func() Builder
+	}{
+		{"authorized", func() Builder { return Div(Text("card"), Authorized("admin", Button(Text("Delete")))) }},
+		{"script", func() Builder { return Div(Text("card"), Script(Raw("x()"))) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := cache.Memo(tt.name, tt.fn)
+			for _, ctx := range []context.Context{admin, guest} {
+				var buf bytes.Buffer
+				if err := RenderContext(ctx, &buf, b); !errors.Is(err, ErrSharedFragment) {
+					t.Errorf("RenderContext() = %v, want ErrSharedFragment", err)
+				}
+			}
+		})
+	}
+
+	// A script without a nonce in the context, and request-scoped
+	// content outside the fragment, are fine.
+	b := cache.Memo("plain", func() Builder { return Div(Text("card"), Script(Raw("x()"))) })
+	page := Fragment(b, Authorized("admin", Button(Text("Delete"))))
+	if got, want := RenderString(page), `<div>card<script>x()</script></div>`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestMemoVariants(t *testing.T) {
+	cache := NewMemoCache(0)
+	b := cache.Memo("item", func() Builder {
+		return Div(A(Href("/item")), Money(1234, "EUR"), A(Href("/home")))
+	})
+	acme := WithLocale(WithBaseURL(context.Background(), "/acme"), language.German)
+	globex := WithBaseURL(context.Background(), "/globex")
+	for range 2 {
+		for _, tt := range []struct {
+			ctx      context.Context
+			expected string
+		}{
+			{acme, "<div><a href=\"/acme/item\"></a>12,34\u00a0€<a href=\"/acme/home\"></a></div>"},
+			{globex, `<div><a href="/globex/item"></a>€12.34<a href="/globex/home"></a></div>`},
+			{context.Background(), `<div><a href="/item"></a>€12.34<a href="/home"></a></div>`},
+		} {
+			var buf bytes.Buffer
+			if err := RenderContext(tt.ctx, &buf, b); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
+			}
+		}
+	}
+	if len(cache.entries) != 3 {
+		t.Errorf("got %d entries, want one per variant", len(cache.entries))
+	}
+}
+
+type tenantKey struct{}
+
+// tenantName writes the tenantKey value of the render context.
+type tenantName struct{}
+
+func (tenantName) isTagArg() {}
+
+func (tenantName) Build(w *Writer) error {
+	name, _ := w.Context().Value(tenantKey{}).(string)
+	return w.Text(name)
+}
+
+func TestMemoHidesContextValues(t *testing.T) {
+	cache := NewMemoCache(0)
+	b := cache.Memo("tenant", func() Builder { return tenantName{} })
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	var buf bytes.Buffer
+	if err := RenderContext(ctx, &buf, b); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("fragment saw request context value: %s", buf.String())
+	}
+}
@@ -0,0 +1,47 @@
+package h
+
+// Mixin bundles attributes and a wrapping of children into a reusable
+// styling convention. Pass it to any tag function like an attribute: Attrs
+// are merged at its position, and once all arguments are collected Wrap
+// replaces the element's children with its result:
+//
+//	func Card(title string) h.Mixin {
+//	    return h.Mixin{
+//	        Attrs: h.Attrs("class", "card"),
+//	        Wrap: func(children []h.Builder) h.Builder {
+//	            return h.Fragment(
+//	                h.Header(h.Class("card-header"), h.Text(title)),
+//	                h.Div(h.Class("card-body"), h.Fragment(children...)),
+//	            )
+//	        },
+//	    }
+//	}
+//
+//	h.Section(Card("Profile"), h.P(h.Text("...")))
+//	// <section class="card"><header class="card-header">Profile</header><div class="card-body"><p>...</p></div></section>
+//
+// A Class passed alongside the mixin is joined with the mixin's class. With
+// several mixins, each Wrap receives the result of the previous one, so the
+// last mixin's wrapping is outermost. Wrap may be nil to add attributes
+// only.
+type Mixin struct {
+	Attrs Attributes
+	Wrap  func(children []Builder) Builder
+}
+
+func (m Mixin) isTagArg() {}
+
+// applyMixins wraps children with each mixin's Wrap in order.
+func applyMixins(mixins []Mixin, children []Builder) []Builder {
+	for _, m := range mixins {
+		if m.Wrap == nil {
+			continue
+		}
+		if b := m.Wrap(children); b != nil {
+			children = []Builder{b}
+		} else {
+			children = nil
+		}
+	}
+	return children
+}
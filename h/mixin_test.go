@@ -0,0 +1,46 @@
+package h
+
+import "testing"
+
+func TestMixin(t *testing.T) {
+	card := func(title string) Mixin {
+		return Mixin{
+			Attrs: Attrs("class", "card"),
+			Wrap: func(children []Builder) Builder {
+				return Fragment(
+					Header(Class("card-header"), Text(title)),
+					Div(Class("card-body"), Fragment(children...)),
+				)
+			},
+		}
+	}
+	outlined := Mixin{Attrs: Attrs("data-outlined", "")}
+	framed := Mixin{Wrap: func(children []Builder) Builder { return Div(Class("frame"), Fragment(children...)) }}
+
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{
+			"attrs and wrap",
+			Section(card("Profile"), P(Text("Ada"))),
+			`<section class="card"><header class="card-header">Profile</header><div class="card-body"><p>Ada</p></div></section>`,
+		},
+		{"later attrs override", Div(outlined, Attr("data-outlined", "thin")), `<div data-outlined="thin"></div>`},
+		{"attrs only", Div(Attr("id", "x"), outlined, Text("hi")), `<div id="x" data-outlined>hi</div>`},
+		{
+			"last mixin is outermost",
+			Div(framed, card("T"), Text("body")),
+			`<div class="card"><header class="card-header">T</header><div class="card-body"><div class="frame">body</div></div></div>`,
+		},
+		{"nil wrap result", Div(Mixin{Wrap: func([]Builder) Builder { return nil }}, Text("dropped")), `<div></div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package h
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+type withNonceKey struct{}
+
+// WithNonce returns a context whose renders add nonce="..." to <script> and
+// <style> elements that do not have one, so they run under a
+// Content-Security-Policy with a matching 'nonce-...' source:
+//
+//	nonce, _ := httpsec.NewNonce(nil)
+//	ctx := h.WithNonce(r.Context(), nonce)
+//	h.RenderContext(ctx, w, h.Script(h.Raw("init()")))
+//	// <script nonce="...">init()</script>
+//
+// It takes precedence over config.Nonce. An empty nonce adds nothing.
+func WithNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, withNonceKey{}, nonce)
+}
+
+// Nonce returns the nonce set with WithNonce, or "" if none was set.
+func Nonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(withNonceKey{}).(string)
+	return nonce
+}
+
+// CSPHash returns the Content-Security-Policy source that allows an inline
+// script or style whose content is exactly content, for pages served
+// without a nonce:
+//
+//	const initJS = "document.documentElement.classList.add('js')"
+//	h.Script(h.Raw(initJS))
+//	// Content-Security-Policy: script-src 'self' <h.CSPHash(initJS)>
+//
+// The result has the form 'sha256-<base64>', quotes included. Whitespace
+// is significant: hash the exact text between the tags.
+func CSPHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
@@ -0,0 +1,52 @@
+package h
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+func TestWithNonce(t *testing.T) {
+	page := Fragment(Script(Raw("go()")), Style(Raw("p{}")), Style(Attr("nonce", "mine")), Div())
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected string
+	}{
+		{"none", context.Background(), `<script>go()</script><style>p{}</style><style nonce="mine"></style><div></div>`},
+		{"empty", WithNonce(context.Background(), ""), `<script>go()</script><style>p{}</style><style nonce="mine"></style><div></div>`},
+		{
+			"nonce",
+			WithNonce(context.Background(), "r4nd"),
+			`<script nonce="r4nd">go()</script><style nonce="r4nd">p{}</style><style nonce="mine"></style><div></div>`,
+		},
+		{
+			"overrides config",
+			WithNonce(config.WithContext(context.Background(), config.New(config.Indent(""), config.Nonce(func(context.Context) string { return "cfg" }))), "ctx"),
+			`<script nonce="ctx">go()</script><style nonce="ctx">p{}</style><style nonce="mine"></style><div></div>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RenderContext(tt.ctx, &sb, page); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("got %s\nwant %s", got, tt.expected)
+			}
+		})
+	}
+	if got := Nonce(WithNonce(context.Background(), "x")); got != "x" {
+		t.Errorf("Nonce() = %q", got)
+	}
+}
+
+func TestCSPHash(t *testing.T) {
+	// echo -n "alert('Hello, world.');" | openssl sha256 -binary | openssl base64
+	if got := CSPHash("alert('Hello, world.');"); got != "'sha256-qznLcsROx4GACP2dm0UCKCzCG+HiZ1guq6ZZDob/Tng='" {
+		t.Errorf("CSPHash() = %s", got)
+	}
+}
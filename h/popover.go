@@ -0,0 +1,137 @@
+package h
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PopoverState is a value of the popover attribute.
+type PopoverState string
+
+// Popover states.
+const (
+	PopoverAuto   PopoverState = "auto"   // Light dismiss; closes other auto popovers
+	PopoverManual PopoverState = "manual" // Only closed explicitly
+	PopoverHint   PopoverState = "hint"   // Closes other hints but not auto popovers
+)
+
+// PopoverAction is a value of the popovertargetaction attribute.
+type PopoverAction string
+
+// Popover target actions.
+const (
+	PopoverToggle PopoverAction = "toggle"
+	PopoverShow   PopoverAction = "show"
+	PopoverHide   PopoverAction = "hide"
+)
+
+// Command is a value of the command attribute of an invoker button.
+type Command string
+
+// Built-in invoker commands.
+const (
+	CommandShowModal     Command = "show-modal"     // Open a <dialog> modally
+	CommandClose         Command = "close"          // Close a <dialog>
+	CommandRequestClose  Command = "request-close"  // Ask a <dialog> to close, firing cancel
+	CommandShowPopover   Command = "show-popover"   // Show a popover
+	CommandHidePopover   Command = "hide-popover"   // Hide a popover
+	CommandTogglePopover Command = "toggle-popover" // Toggle a popover
+)
+
+// Popover makes the element a popover, shown and hidden by buttons with
+// PopoverTarget or Invoke, without JavaScript:
+//
+//	h.Button(h.PopoverTarget("menu"), h.Text("Menu"))
+//	h.Div(h.ID("menu"), h.Popover(h.PopoverAuto), menuItems)
+//
+// Panics if state is not one of the PopoverState constants.
+func Popover(state PopoverState) Attribute {
+	if !validEnum(popoverStates, string(state)) {
+		panic(fmt.Sprintf("Popover: invalid state %q", state))
+	}
+	return Attribute{Name: "popover", Value: string(state)}
+}
+
+// PopoverTarget creates the popovertarget attribute of a button, naming
+// the id of the popover it toggles. Panics if id is empty.
+func PopoverTarget(id string) Attribute {
+	if id == "" {
+		panic("PopoverTarget: empty id")
+	}
+	return Attribute{Name: "popovertarget", Value: id}
+}
+
+// PopoverTargetAction creates the popovertargetaction attribute, choosing
+// whether a PopoverTarget button toggles (the default), shows, or hides
+// its popover. Panics if action is not one of the PopoverAction constants.
+func PopoverTargetAction(action PopoverAction) Attribute {
+	if !validEnum(popoverActions, string(action)) {
+		panic(fmt.Sprintf("PopoverTargetAction: invalid action %q", action))
+	}
+	return Attribute{Name: "popovertargetaction", Value: string(action)}
+}
+
+// CustomCommand returns a custom invoker command, which dispatches a
+// command event to the target for a script to handle. Custom commands
+// must start with "--"; CustomCommand panics otherwise.
+func CustomCommand(name string) Command {
+	if !strings.HasPrefix(name, "--") || len(name) == 2 {
+		panic(fmt.Sprintf("CustomCommand: %q does not start with \"--\"", name))
+	}
+	return Command(name)
+}
+
+// Invoke creates the command and commandfor attributes of an invoker
+// button, which runs command on the element with id target without
+// JavaScript:
+//
+//	h.Button(h.Invoke(h.CommandShowModal, "confirm"), h.Text("Delete"))
+//	h.Dialog(h.ID("confirm"), ...)
+//
+// Panics if command is neither a built-in Command nor from CustomCommand,
+// or if target is empty.
+func Invoke(command Command, target string) Attributes {
+	if !validCommand(string(command)) {
+		panic(fmt.Sprintf("Invoke: invalid command %q", command))
+	}
+	if target == "" {
+		panic("Invoke: empty target")
+	}
+	return Attributes{{Name: "command", Value: string(command)}, {Name: "commandfor", Value: target}}
+}
+
+var (
+	popoverStates  = []string{"", "auto", "manual", "hint"}
+	popoverActions = []string{"toggle", "show", "hide"}
+	commands       = []string{"show-modal", "close", "request-close", "show-popover", "hide-popover", "toggle-popover"}
+)
+
+// validEnum reports whether value is one of allowed, ignoring case as
+// browsers do for enumerated attributes.
+func validEnum(allowed []string, value string) bool {
+	for _, v := range allowed {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// validCommand reports whether value is a built-in or custom command.
+func validCommand(value string) bool {
+	return validEnum(commands, value) || strings.HasPrefix(value, "--") && len(value) > 2
+}
+
+// validAttrValue reports whether the value of an enumerated attribute
+// checked by ValidateBuilder is allowed.
+func validAttrValue(a Attribute) bool {
+	switch strings.ToLower(a.Name) {
+	case "popover":
+		return validEnum(popoverStates, a.Value)
+	case "popovertargetaction":
+		return validEnum(popoverActions, a.Value)
+	case "command":
+		return validCommand(a.Value)
+	}
+	return true
+}
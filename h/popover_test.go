@@ -0,0 +1,53 @@
+package h
+
+import "testing"
+
+func TestPopoverAttrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"popover", Div(ID("menu"), Popover(PopoverAuto)), `<div id="menu" popover="auto"></div>`},
+		{"manual", Div(Popover(PopoverManual)), `<div popover="manual"></div>`},
+		{"target", Button(PopoverTarget("menu"), Text("Menu")), `<button popovertarget="menu">Menu</button>`},
+		{"target action", Button(PopoverTarget("menu"), PopoverTargetAction(PopoverHide)), `<button popovertarget="menu" popovertargetaction="hide"></button>`},
+		{"invoke", Button(Invoke(CommandShowModal, "confirm"), Text("Delete")), `<button command="show-modal" commandfor="confirm">Delete</button>`},
+		{"custom command", Button(Invoke(CustomCommand("--rotate"), "img")), `<button command="--rotate" commandfor="img"></button>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			if err := ValidateBuilder(tt.b); err != nil {
+				t.Errorf("ValidateBuilder() = %v", err)
+			}
+		})
+	}
+}
+
+func TestPopoverAttrsPanic(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"state", func() { Popover("sticky") }},
+		{"empty target", func() { PopoverTarget("") }},
+		{"action", func() { PopoverTargetAction("open") }},
+		{"custom without dashes", func() { CustomCommand("rotate") }},
+		{"custom only dashes", func() { CustomCommand("--") }},
+		{"command", func() { Invoke("open-modal", "d") }},
+		{"empty invoke target", func() { Invoke(CommandClose, "") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected panic")
+				}
+			}()
+			tt.fn()
+		})
+	}
+}
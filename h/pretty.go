@@ -0,0 +1,413 @@
+package h
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// RenderPretty writes b to w formatted for reading, without changing how
+// it displays. Unlike RenderIndent, which puts every element and text on
+// its own line, it only breaks lines around block-level elements such as
+// <div>, <p>, and <li>. Inline content keeps its whitespace exactly, and
+// the content of <pre>, <textarea>, <script>, and <style> is written as-is:
+//
+//	h.RenderPretty(w, "  ", h.Div(h.P(h.Text("Hello, "), h.Strong(h.Text("world")))))
+//
+// Output:
+//
+//	<div>
+//	  <p>Hello, <strong>world</strong></p>
+//	</div>
+//
+// Whitespace-only text next to a block boundary is replaced by the line
+// breaks and indentation. Returns nil if b is nil.
+func RenderPretty(w io.Writer, indent string, b Builder) error {
+	return renderFormatted(w, b, func(nodes []*htmlNode, sb *strings.Builder) {
+		p := &prettyPrinter{sb: sb, indent: indent}
+		p.blocks(nodes, 0)
+	})
+}
+
+// RenderMinified writes b to w without insignificant whitespace: runs of
+// whitespace in text are collapsed to one space, and whitespace next to a
+// block boundary is removed. The content of <pre>, <textarea>, <script>,
+// and <style> and any comments are kept as-is. It assumes the default CSS
+// white-space handling outside <pre> and <textarea>. Returns nil if b is
+// nil.
+func RenderMinified(w io.Writer, b Builder) error {
+	return renderFormatted(w, b, func(nodes []*htmlNode, sb *strings.Builder) {
+		minifyBlocks(sb, nodes)
+	})
+}
+
+// renderFormatted renders b, parses the output, and writes what format
+// produces from it.
+func renderFormatted(w io.Writer, b Builder, format func([]*htmlNode, *strings.Builder)) error {
+	if b == nil {
+		return nil
+	}
+	buf := bufPool.Get().(*bytes.Buffer)
+	defer bufPool.Put(buf)
+	buf.Reset()
+	if err := Render(buf, b); err != nil {
+		return err
+	}
+	var sb strings.Builder
+	sb.Grow(buf.Len() + buf.Len()/4)
+	format(parseHTML(buf.String()), &sb)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// htmlNode is an element, text, or other markup (a comment or doctype)
+// parsed from rendered output. Elements keep their exact start and end
+// tags.
+type htmlNode struct {
+	name     string // Lowercased element name, or "" for text and other markup
+	start    string // Start tag, text, or other markup
+	end      string // End tag, or "" for void or unclosed elements
+	text     bool
+	children []*htmlNode
+}
+
+// verbatim reports whether an element's content must be written unchanged.
+func (n *htmlNode) verbatim() bool {
+	switch n.name {
+	case "pre", "textarea", "script", "style":
+		return true
+	}
+	return false
+}
+
+// isBlock reports whether n starts on its own line. Unknown elements,
+// including custom elements, are treated as inline, so whitespace around
+// them is never changed.
+func (n *htmlNode) isBlock() bool {
+	if n.name == "" {
+		return !n.text && strings.HasPrefix(n.start, "<!") && !strings.HasPrefix(n.start, "<!--") // Doctype
+	}
+	return blockElements[n.name]
+}
+
+func (n *htmlNode) write(sb *strings.Builder) {
+	sb.WriteString(n.start)
+	for _, c := range n.children {
+		c.write(sb)
+	}
+	sb.WriteString(n.end)
+}
+
+// blockElements are the elements laid out as blocks by default, and the
+// document-level elements that are not displayed.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "base": true, "blockquote": true,
+	"body": true, "caption": true, "col": true, "colgroup": true, "dd": true,
+	"details": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true,
+	"form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "head": true, "header": true, "hgroup": true, "hr": true,
+	"html": true, "legend": true, "li": true, "link": true, "main": true,
+	"menu": true, "meta": true, "nav": true, "ol": true, "optgroup": true,
+	"option": true, "p": true, "pre": true, "script": true, "search": true,
+	"section": true, "style": true, "summary": true, "table": true, "tbody": true,
+	"td": true, "template": true, "tfoot": true, "th": true, "thead": true,
+	"title": true, "tr": true, "ul": true,
+}
+
+// voidElementNames are the HTML elements without end tags.
+var voidElementNames = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true,
+	"track": true, "wbr": true,
+}
+
+// parseHTML parses rendered output into a tree. It tolerates any input:
+// unmatched end tags are kept as text, and unclosed elements end with
+// their parent.
+func parseHTML(doc string) []*htmlNode {
+	root := &htmlNode{}
+	stack := []*htmlNode{root}
+	add := func(n *htmlNode) {
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, n)
+	}
+	addText := func(s string) {
+		parent := stack[len(stack)-1]
+		if k := len(parent.children); k > 0 && parent.children[k-1].text {
+			parent.children[k-1].start += s
+			return
+		}
+		add(&htmlNode{start: s, text: true})
+	}
+	for doc != "" {
+		i := strings.IndexByte(doc, '<')
+		if i < 0 {
+			addText(doc)
+			break
+		}
+		if i > 0 {
+			addText(doc[:i])
+			doc = doc[i:]
+		}
+		switch {
+		case strings.HasPrefix(doc, "<!--"):
+			end := strings.Index(doc, "-->")
+			if end < 0 {
+				end = len(doc) - 3
+			}
+			add(&htmlNode{start: doc[:end+3]})
+			doc = doc[end+3:]
+		case len(doc) > 1 && (doc[1] == '!' || doc[1] == '?'):
+			end := strings.IndexByte(doc, '>') + 1
+			if end == 0 {
+				end = len(doc)
+			}
+			add(&htmlNode{start: doc[:end]})
+			doc = doc[end:]
+		case len(doc) > 2 && doc[1] == '/' && isASCIILetter(doc[2]):
+			end := strings.IndexByte(doc, '>') + 1
+			if end == 0 {
+				end = len(doc)
+			}
+			tag := doc[:end]
+			doc = doc[end:]
+			name := strings.ToLower(strings.TrimSpace(strings.TrimSuffix(tag[2:], ">")))
+			closed := false
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].name == name {
+					stack[j].end = tag
+					stack = stack[:j]
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				addText(tag)
+			}
+		case len(doc) > 1 && isASCIILetter(doc[1]):
+			end := startTagEnd(doc)
+			n := &htmlNode{name: strings.ToLower(doc[1:tagNameEnd(doc)]), start: doc[:end]}
+			doc = doc[end:]
+			add(n)
+			switch {
+			case voidElementNames[n.name] || strings.HasSuffix(n.start, "/>"):
+			case n.name == "script" || n.name == "style":
+				body := indexFold(doc, "</"+n.name)
+				if body < 0 {
+					body = len(doc)
+				}
+				if body > 0 {
+					n.children = []*htmlNode{{start: doc[:body], text: true}}
+				}
+				doc = doc[body:]
+				stack = append(stack, n)
+			default:
+				stack = append(stack, n)
+			}
+		default:
+			addText("<")
+			doc = doc[1:]
+		}
+	}
+	return root.children
+}
+
+// tagNameEnd returns the index just past the element name of the start tag
+// at the beginning of doc.
+func tagNameEnd(doc string) int {
+	i := 1
+	for i < len(doc) && !strings.ContainsRune(" \t\n\r\f/>", rune(doc[i])) {
+		i++
+	}
+	return i
+}
+
+// startTagEnd returns the index just past the start tag at the beginning of
+// doc, skipping '>' in quoted attribute values.
+func startTagEnd(doc string) int {
+	var quote byte
+	for i := 1; i < len(doc); i++ {
+		switch c := doc[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+	return len(doc)
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// indexFold is strings.Index ignoring ASCII case.
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// prettyPrinter writes the tree for RenderPretty.
+type prettyPrinter struct {
+	sb     *strings.Builder
+	indent string
+}
+
+// blocks writes nodes in block context at depth: block elements on their
+// own lines, and runs of inline content on a line each.
+func (p *prettyPrinter) blocks(nodes []*htmlNode, depth int) {
+	for _, run := range splitRuns(nodes) {
+		if len(run) == 1 && run[0].isBlock() {
+			p.block(run[0], depth)
+			continue
+		}
+		p.line(depth, func() { writeRun(p.sb, run) })
+	}
+}
+
+// block writes a block element at depth. Elements with only inline content
+// stay on one line.
+func (p *prettyPrinter) block(n *htmlNode, depth int) {
+	if n.verbatim() || !hasBlockChild(n.children) {
+		p.line(depth, func() {
+			if n.verbatim() {
+				n.write(p.sb)
+				return
+			}
+			p.sb.WriteString(n.start)
+			writeRun(p.sb, trimRun(n.children))
+			p.sb.WriteString(n.end)
+		})
+		return
+	}
+	p.line(depth, func() { p.sb.WriteString(n.start) })
+	p.blocks(n.children, depth+1)
+	if n.end != "" {
+		p.line(depth, func() { p.sb.WriteString(n.end) })
+	}
+}
+
+func (p *prettyPrinter) line(depth int, fn func()) {
+	for range depth {
+		p.sb.WriteString(p.indent)
+	}
+	fn()
+	p.sb.WriteByte('\n')
+}
+
+// splitRuns splits nodes into single block nodes and runs of inline nodes,
+// dropping whitespace next to block boundaries.
+func splitRuns(nodes []*htmlNode) [][]*htmlNode {
+	var runs [][]*htmlNode
+	var run []*htmlNode
+	flush := func() {
+		if trimmed := trimRun(run); len(trimmed) > 0 {
+			runs = append(runs, trimmed)
+		}
+		run = nil
+	}
+	for _, n := range nodes {
+		if n.isBlock() {
+			flush()
+			runs = append(runs, []*htmlNode{n})
+			continue
+		}
+		run = append(run, n)
+	}
+	flush()
+	return runs
+}
+
+// trimRun returns run without leading and trailing whitespace, copying
+// the edge text nodes it changes.
+func trimRun(run []*htmlNode) []*htmlNode {
+	if len(run) > 0 && run[0].text {
+		first := *run[0]
+		first.start = strings.TrimLeft(first.start, " \t\n\r\f")
+		run = append([]*htmlNode{&first}, run[1:]...)
+		if first.start == "" {
+			run = run[1:]
+		}
+	}
+	if k := len(run); k > 0 && run[k-1].text {
+		last := *run[k-1]
+		last.start = strings.TrimRight(last.start, " \t\n\r\f")
+		run = append(run[:k-1:k-1], &last)
+		if last.start == "" {
+			run = run[:k-1]
+		}
+	}
+	return run
+}
+
+func hasBlockChild(nodes []*htmlNode) bool {
+	for _, n := range nodes {
+		if n.isBlock() {
+			return true
+		}
+	}
+	return false
+}
+
+// writeRun writes inline nodes unchanged.
+func writeRun(sb *strings.Builder, run []*htmlNode) {
+	for _, n := range run {
+		n.write(sb)
+	}
+}
+
+// minifyBlocks writes nodes in block context for RenderMinified.
+func minifyBlocks(sb *strings.Builder, nodes []*htmlNode) {
+	for _, run := range splitRuns(nodes) {
+		for _, n := range run {
+			minifyNode(sb, n)
+		}
+	}
+}
+
+func minifyNode(sb *strings.Builder, n *htmlNode) {
+	switch {
+	case n.text:
+		sb.WriteString(collapseSpace(n.start))
+	case n.verbatim():
+		n.write(sb)
+	case n.isBlock():
+		sb.WriteString(n.start)
+		minifyBlocks(sb, n.children)
+		sb.WriteString(n.end)
+	default:
+		sb.WriteString(n.start)
+		for _, c := range n.children {
+			minifyNode(sb, c)
+		}
+		sb.WriteString(n.end)
+	}
+}
+
+// collapseSpace replaces each run of whitespace in s with one space.
+func collapseSpace(s string) string {
+	var sb strings.Builder
+	space := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case ' ', '\t', '\n', '\r', '\f':
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+		default:
+			sb.WriteByte(c)
+			space = false
+		}
+	}
+	return sb.String()
+}
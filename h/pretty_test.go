@@ -0,0 +1,75 @@
+package h
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRenderPrettyBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"inline content", P(Text("hello "), Strong(Text("world")), Text("!")), "<p>hello <strong>world</strong>!</p>\n"},
+		{"edge whitespace", P(Text("  hi  ")), "<p>hi</p>\n"},
+		{"nested blocks", Div(Ul(Li(Text("a")), Li(A(Href("/"), Text("b"))))),
+			"<div>\n  <ul>\n    <li>a</li>\n    <li><a href=\"/\">b</a></li>\n  </ul>\n</div>\n"},
+		{"mixed content", Div(Text(" intro "), Em(Text("x")), P(Text("para")), Text("tail  text")),
+			"<div>\n  intro <em>x</em>\n  <p>para</p>\n  tail  text\n</div>\n"},
+		{"pre", Div(Pre(Text("a\n  b "))), "<div>\n  <pre>a\n  b </pre>\n</div>\n"},
+		{"textarea", Form(Textarea(Text(" x\n"))), "<form><textarea> x\n</textarea></form>\n"},
+		{"script", Head(Script(Text("if (a<b) {}")), Meta(Attrs("charset", "utf-8"))),
+			"<head>\n  <script>if (a<b) {}</script>\n  <meta charset=\"utf-8\"/>\n</head>\n"},
+		{"custom elements are inline", Div(CustomElement("x-card", Text(" a "))), "<div><x-card> a </x-card></div>\n"},
+		{"attribute with >", Div(Attrs("data-x", "a>b"), P()), "<div data-x=\"a&gt;b\">\n  <p></p>\n</div>\n"},
+		{"document", Html(Head(Title(Text("t"))), Body(Main(H1(Text("Hi"))))),
+			"<!DOCTYPE html>\n<html lang=\"en\">\n  <head>\n    <title>t</title>\n  </head>\n  <body>\n    <main>\n      <h1>Hi</h1>\n    </main>\n  </body>\n</html>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RenderPretty(&sb, "  ", tt.b); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderMinifiedWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"collapse", P(Text("  hello   "), Strong(Text(" big \n world ")), Text("!\n")), "<p>hello <strong> big world </strong>!</p>"},
+		{"block boundaries", Div(Text("\n  "), P(Text("a")), Text("\n  "), P(Text("b")), Text("\n")), "<div><p>a</p><p>b</p></div>"},
+		{"inline spaces kept", P(Em(Text("a")), Text(" "), Em(Text("b"))), "<p><em>a</em> <em>b</em></p>"},
+		{"verbatim", Div(Pre(Text("a\n   b")), Script(Text("x  =  1")), Raw("<!--  c  -->")), "<div><pre>a\n   b</pre><script>x  =  1</script><!--  c  --></div>"},
+		{"raw markup", Raw("<ul>\n  <li>one</li>\n  <li>two  words</li>\n</ul>\n"), "<ul><li>one</li><li>two words</li></ul>"},
+		{"unmatched end tag", Raw("a</span>  b"), "a</span> b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			if err := RenderMinified(&sb, tt.b); err != nil {
+				t.Fatal(err)
+			}
+			if got := sb.String(); got != tt.expected {
+				t.Errorf("got  %q\nwant %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRenderFormattedError(t *testing.T) {
+	var sb strings.Builder
+	if err := RenderPretty(&sb, "  ", Div(failingBuilder{errors.New("boom")})); err == nil || sb.Len() != 0 {
+		t.Errorf("err = %v, output %q", err, sb.String())
+	}
+}
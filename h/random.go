@@ -0,0 +1,61 @@
+package h
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// Rand returns the Writer's source of randomness: config.Rand from the
+// render's Config, or crypto/rand.Reader. Builders that need random values
+// read from it so a seeded source (config.Seeded) makes output reproducible.
+func (w *Writer) Rand() io.Reader {
+	if w.rand == nil {
+		return crand.Reader
+	}
+	return w.rand
+}
+
+// NewID returns a random element id starting with prefix, e.g.
+// "field-3f9a1c0b7d2e", read from Rand.
+func (w *Writer) NewID(prefix string) (string, error) {
+	var b [6]byte
+	if _, err := io.ReadFull(w.Rand(), b[:]); err != nil {
+		return "", fmt.Errorf("h: generating id: %w", err)
+	}
+	return prefix + "-" + hex.EncodeToString(b[:]), nil
+}
+
+// UniqueID calls fn with a new id from Writer.NewID at render time and
+// builds its result, for linking elements that need an id only to refer to
+// each other:
+//
+//	h.UniqueID("email", func(id string) h.Builder {
+//	    return h.Fragment(
+//	        h.Label(h.For(id), h.Text("Email")),
+//	        h.Input(h.ID(id), h.Type("email")),
+//	    )
+//	})
+func UniqueID(prefix string, fn func(id string) Builder) Builder {
+	return &uniqueIDBuilder{prefix, fn}
+}
+
+type uniqueIDBuilder struct {
+	prefix string
+	fn     func(id string) Builder
+}
+
+func (b *uniqueIDBuilder) isTagArg() {}
+
+func (b *uniqueIDBuilder) Build(w *Writer) error {
+	id, err := w.NewID(b.prefix)
+	if err != nil {
+		return err
+	}
+	child := b.fn(id)
+	if child == nil {
+		return nil
+	}
+	return child.Build(w)
+}
@@ -0,0 +1,48 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+func TestUniqueID(t *testing.T) {
+	field := UniqueID("email", func(id string) Builder {
+		return Fragment(Label(For(id), Text("Email")), Input(ID(id)))
+	})
+	page := Div(field, field)
+
+	got := RenderString(page)
+	m := regexp.MustCompile(`^<div><label for="(email-[0-9a-f]{12})">Email</label><input id="(email-[0-9a-f]{12})"/><label for="(email-[0-9a-f]{12})">Email</label><input id="(email-[0-9a-f]{12})"/></div>$`).FindStringSubmatch(got)
+	if m == nil {
+		t.Fatalf("unexpected output %q", got)
+	}
+	if m[1] != m[2] || m[3] != m[4] || m[1] == m[3] {
+		t.Errorf("ids not linked or not unique: %q", got)
+	}
+
+	// A seeded source makes renders byte-identical.
+	render := func() string {
+		var buf bytes.Buffer
+		ctx := config.WithContext(context.Background(), config.New(config.Rand(config.Seeded(42))))
+		if err := RenderContext(ctx, &buf, page); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	if a, b := render(), render(); a != b {
+		t.Errorf("seeded renders differ:\n%s\n%s", a, b)
+	}
+
+	errRand := errors.New("no entropy")
+	ctx := config.WithContext(context.Background(), config.New(config.Rand(iotest.ErrReader(errRand))))
+	if err := RenderContext(ctx, io.Discard, page); !errors.Is(err, errRand) {
+		t.Errorf("expected read error, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package h
+
+import "strings"
+
+// isRawTextElement reports whether text inside the element name is raw
+// text, which browsers do not decode entities in: <script> and <style>.
+// The escapable raw text elements, <textarea> and <title>, decode
+// entities like other elements, so text in them is escaped as usual.
+func isRawTextElement(name string) bool {
+	return strings.EqualFold(name, "script") || strings.EqualFold(name, "style")
+}
+
+// escapeRawText returns txt with the sequences that would end the raw text
+// element tag, or in a script start an HTML comment, backslash-escaped.
+// Inside JavaScript and CSS strings the backslash leaves the text
+// unchanged: "</script>" becomes "<\/script>".
+func escapeRawText(tag, txt string) string {
+	if !strings.Contains(txt, "<") {
+		return txt
+	}
+	var sb strings.Builder
+	for {
+		i := strings.IndexByte(txt, '<')
+		if i < 0 || i+1 == len(txt) {
+			sb.WriteString(txt)
+			return sb.String()
+		}
+		sb.WriteString(txt[:i+1])
+		txt = txt[i+1:]
+		if hasPrefixFold(txt, "/"+tag) || strings.EqualFold(tag, "script") && strings.HasPrefix(txt, "!--") {
+			sb.WriteByte('\\')
+		}
+	}
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
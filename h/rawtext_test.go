@@ -0,0 +1,43 @@
+package h
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRawTextElements(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"script", Script(Text(`if (a < b && c > "d") x = 'y';`)), `<script>if (a < b && c > "d") x = 'y';</script>`},
+		{"script end", Script(Text(`s = "</script><script>alert(1)</script>"`)), `<script>s = "<\/script><script>alert(1)<\/script>"</script>`},
+		{"script end case", Script(Text(`"</SCRIPT >"`)), `<script>"<\/SCRIPT >"</script>`},
+		{"script comment", Script(Text(`s = "<!--"`)), `<script>s = "<\!--"</script>`},
+		{"script trailing <", Script(Text(`a <`)), `<script>a <</script>`},
+		{"style", Style(Text(`a > b { content: "&"; }`)), `<style>a > b { content: "&"; }</style>`},
+		{"style end", Style(Text(`p::after { content: "</style>" }`)), `<style>p::after { content: "<\/style>" }</style>`},
+		{"style comment", Style(Text(`<!-- p {}`)), `<style><!-- p {}</style>`},
+		{"textarea", Textarea(Text(`<b>&</b>`)), `<textarea>&lt;b&gt;&amp;&lt;/b&gt;</textarea>`},
+		{"title", Title(Text(`a & b`)), `<title>a &amp; b</title>`},
+		{"nested element", Script(Attrs("type", "text/x-template"), Div(Text("<"))), `<script type="text/x-template"><div>&lt;</div></script>`},
+		{"after script", Fragment(Script(Text("<")), P(Text("<"))), `<script><</script><p>&lt;</p>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("got  %s\nwant %s", got, tt.expected)
+			}
+		})
+	}
+
+	var sb strings.Builder
+	w := NewWriter(&sb)
+	w.OpenTag("script", nil)
+	w.Text("a && b")
+	w.CloseTag("script")
+	if got := sb.String(); got != "<script>a && b</script>" {
+		t.Errorf("Writer: got %s", got)
+	}
+}
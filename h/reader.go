@@ -0,0 +1,105 @@
+package h
+
+import (
+	"io"
+	"sync"
+)
+
+// readerBufPool pools the copy buffers used by RawReader and TextReader.
+var readerBufPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 32*1024)
+		return &b
+	},
+}
+
+// RawReader creates a Builder that copies r to the output unescaped, in
+// chunks, without buffering the whole payload. Use it to embed large
+// pre-rendered fragments or files:
+//
+//	f, err := os.Open("fragments/footer.html")
+//	if err != nil {
+//		// handle error
+//	}
+//	defer f.Close()
+//	h.Render(w, h.Div(h.RawReader(f)))
+//
+// The reader is consumed by the first render and is not closed. Like Raw,
+// the content is trusted; never pass user-controlled input.
+func RawReader(r io.Reader) Builder { return &readerBuilder{r, true} }
+
+// TextReader creates a Builder that streams r to the output HTML-escaped,
+// as Text does for strings, including the Writer's escape policy. The reader is consumed by the first render and
+// is not closed.
+func TextReader(r io.Reader) Builder { return &readerBuilder{r, false} }
+
+type readerBuilder struct {
+	r     io.Reader
+	isRaw bool
+}
+
+func (b *readerBuilder) isTagArg() {}
+
+func (b *readerBuilder) Build(w *Writer) error {
+	if b.r == nil {
+		return nil
+	}
+	if !b.isRaw && w.isIndenting() && w.atLineStart {
+		if err := w.writeIndent(0); err != nil {
+			return err
+		}
+	}
+
+	bufp := readerBufPool.Get().(*[]byte)
+	defer readerBufPool.Put(bufp)
+	buf := *bufp
+	var policy *policyWriter
+	if !b.isRaw && w.escape != 0 {
+		policy = &policyWriter{w: w.w, policy: w.escape}
+	}
+	var last byte
+	wrote := false
+	for {
+		n, err := b.r.Read(buf)
+		if n > 0 {
+			var werr error
+			switch {
+			case b.isRaw:
+				_, werr = w.w.Write(buf[:n])
+			case policy != nil:
+				werr = policy.Write(buf[:n])
+			default:
+				// Escaping is per byte, so chunk boundaries that split a
+				// UTF-8 sequence are harmless.
+				werr = writeHTMLEscape(w.w, buf[:n])
+			}
+			if werr != nil {
+				return werr
+			}
+			last, wrote = buf[n-1], true
+		}
+		if err == io.EOF {
+			if policy != nil {
+				if err := policy.Flush(); err != nil {
+					return err
+				}
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !w.isIndenting() {
+		return nil
+	}
+	if b.isRaw {
+		if wrote {
+			w.atLineStart = last == '\n'
+		}
+		return nil
+	}
+	w.atLineStart = false
+	return w.writeIndentNewline()
+}
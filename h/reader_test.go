@@ -0,0 +1,45 @@
+package h
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderBuilders(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"raw", Div(RawReader(strings.NewReader("<b>bold</b>"))), "<div><b>bold</b></div>"},
+		{"text", P(TextReader(strings.NewReader(`a < b & "c"`))), "<p>a &lt; b &amp; &#34;c&#34;</p>"},
+		{"one byte reads", P(TextReader(iotest.OneByteReader(strings.NewReader("<é>")))), "<p>&lt;é&gt;</p>"},
+		{"nil reader", P(RawReader(nil)), "<p></p>"},
+		{"large", Pre(TextReader(strings.NewReader(strings.Repeat("<x>", 20000)))), "<pre>" + strings.Repeat("&lt;x&gt;", 20000) + "</pre>"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Render(&buf, tt.b); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("%s: got %.80q, want %.80q", tt.name, buf.String(), tt.expected)
+		}
+	}
+
+	// Indented output matches the equivalent Text and Raw strings.
+	var buf, expected bytes.Buffer
+	RenderIndent(&buf, "  ", Div(TextReader(strings.NewReader("hi")), RawReader(strings.NewReader("<i>x</i>\n")), Span()))
+	RenderIndent(&expected, "  ", Div(Text("hi"), Raw("<i>x</i>\n"), Span()))
+	if buf.String() != expected.String() {
+		t.Errorf("indented: got %q, want %q", buf.String(), expected.String())
+	}
+
+	boom := errors.New("boom")
+	if err := Render(&buf, Div(RawReader(iotest.ErrReader(boom)))); !errors.Is(err, boom) {
+		t.Errorf("read error = %v, want %v", err, boom)
+	}
+}
@@ -0,0 +1,140 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrPartialRender is returned by RenderContext and RenderHTTP when an
+// error fallback (see WithErrorFallback) replaced one or more failed
+// children. The output is complete, well-formed HTML; the error joins the
+// recovered errors for logging.
+var ErrPartialRender = errors.New("render completed with recovered errors")
+
+// ErrorPlaceholder is the comment written in place of a child that failed
+// to render while an error fallback is set.
+const ErrorPlaceholder = "<!-- render error -->"
+
+type errorFallbackKey struct{}
+
+type errorFallback struct {
+	fn func(err error) Builder
+}
+
+// WithErrorFallback returns a context whose renders recover from child
+// errors instead of aborting mid-tag. See Writer.SetErrorFallback.
+func WithErrorFallback(ctx context.Context, fallback func(err error) Builder) context.Context {
+	return context.WithValue(ctx, errorFallbackKey{}, errorFallback{fallback})
+}
+
+// SetErrorFallback makes the Writer recover when a child of an element or
+// Fragment fails: tags the child left open are closed, ErrorPlaceholder and
+// fallback(err) are written in its place, and rendering continues with the
+// next sibling. fallback may be nil to write only the placeholder:
+//
+//	ctx := h.WithErrorFallback(r.Context(), func(err error) h.Builder {
+//	    return h.Span(h.Class("unavailable"), h.Text("This section is unavailable."))
+//	})
+//	err := h.RenderContext(ctx, w, page) // errors.Is(err, h.ErrPartialRender)
+//
+// Errors writing to the underlying io.Writer are not recovered, since the
+// output can no longer be repaired.
+func (w *Writer) SetErrorFallback(fallback func(err error) Builder) {
+	w.resilient = true
+	w.fallback = fallback
+}
+
+// RecoveredErrors returns the child errors recovered since the Writer was
+// created or pooled, in order.
+func (w *Writer) RecoveredErrors() []error { return w.recovered }
+
+// partialRenderError returns an error wrapping ErrPartialRender and the
+// recovered errors, or nil if there are none.
+func (w *Writer) partialRenderError() error {
+	if len(w.recovered) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrPartialRender, errors.Join(w.recovered...))
+}
+
+// buildChild builds a child of an element or Fragment, recovering from its
+// error if an error fallback is set.
+func (w *Writer) buildChild(b Builder) error {
+	if !w.resilient {
+		return b.Build(w)
+	}
+	depth := len(w.openTags)
+	err := b.Build(w)
+	if err == nil || w.out.err != nil {
+		return err
+	}
+	return w.recoverChild(depth, err)
+}
+
+func (w *Writer) recoverChild(depth int, err error) error {
+	w.recovered = append(w.recovered, w.renderError(err))
+	if err := w.closeTo(depth); err != nil {
+		return err
+	}
+	if err := w.Raw(ErrorPlaceholder); err != nil {
+		return err
+	}
+	if w.fallback == nil {
+		return nil
+	}
+	fb := w.fallback(err)
+	if fb == nil {
+		return nil
+	}
+	// A failing fallback is dropped rather than recovered again.
+	w.resilient = false
+	ferr := fb.Build(w)
+	w.resilient = true
+	if ferr != nil {
+		if w.out.err != nil {
+			return ferr
+		}
+		w.recovered = append(w.recovered, w.renderError(ferr))
+		return w.closeTo(depth)
+	}
+	return nil
+}
+
+// closeTo closes open tags until depth remain.
+func (w *Writer) closeTo(depth int) error {
+	for len(w.openTags) > depth {
+		if err := w.CloseOneTag(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trackingWriter counts the bytes written for Writer.BytesWritten and
+// records the first write error so that recovery can tell builder errors
+// from output errors.
+type trackingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	n, err := t.w.Write(p)
+	t.n += int64(n)
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
+
+func (t *trackingWriter) WriteString(s string) (int, error) {
+	n, err := io.WriteString(t.w, s)
+	t.n += int64(n)
+	if err != nil && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
@@ -0,0 +1,101 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// failing is a Builder that opens tags and then fails, leaving them open.
+type failing struct{ err error }
+
+func (f failing) isTagArg() {}
+func (f failing) Build(w *Writer) error {
+	if err := w.OpenTag("section", nil); err != nil {
+		return err
+	}
+	if err := w.OpenTag("p", nil); err != nil {
+		return err
+	}
+	return f.err
+}
+
+func TestErrorFallback(t *testing.T) {
+	errBoom := errors.New("boom")
+	errFallback := errors.New("fallback failed")
+	page := Div(Span(Text("a")), failing{errBoom}, Fragment(Text("b"), failing{errBoom}), Span(Text("c")))
+
+	tests := []struct {
+		name     string
+		fallback func(error) Builder
+		expected string
+		errs     int
+	}{
+		{
+			"placeholder only",
+			nil,
+			"<div><span>a</span><section><p></p></section><!-- render error -->b<section><p></p></section><!-- render error --><span>c</span></div>",
+			2,
+		},
+		{
+			"fallback",
+			func(err error) Builder { return Em(Text(err.Error())) },
+			"<div><span>a</span><section><p></p></section><!-- render error --><em>boom</em>b<section><p></p></section><!-- render error --><em>boom</em><span>c</span></div>",
+			2,
+		},
+		{
+			"failing fallback",
+			func(error) Builder { return failing{errFallback} },
+			"<div><span>a</span><section><p></p></section><!-- render error --><section><p></p></section>b<section><p></p></section><!-- render error --><section><p></p></section><span>c</span></div>",
+			4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderContext(WithErrorFallback(context.Background(), tt.fallback), &buf, page)
+			if !errors.Is(err, ErrPartialRender) || !errors.Is(err, errBoom) {
+				t.Errorf("err = %v, want ErrPartialRender wrapping boom", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("got %q\nwant %q", buf.String(), tt.expected)
+			}
+			w := NewWriter(&bytes.Buffer{})
+			w.SetErrorFallback(tt.fallback)
+			if err := page.Build(w); err != nil {
+				t.Fatal(err)
+			}
+			if got := len(w.RecoveredErrors()); got != tt.errs {
+				t.Errorf("RecoveredErrors() has %d errors, want %d", got, tt.errs)
+			}
+		})
+	}
+
+	// Without a fallback the first error aborts the render.
+	var buf bytes.Buffer
+	if err := RenderContext(context.Background(), &buf, page); !errors.Is(err, errBoom) || errors.Is(err, ErrPartialRender) {
+		t.Errorf("err = %v, want boom", err)
+	}
+
+	// Output errors are not recovered.
+	ctx := WithErrorFallback(context.Background(), nil)
+	if err := RenderContext(ctx, &errorWriter{}, page); err == nil || errors.Is(err, ErrPartialRender) {
+		t.Errorf("err = %v, want the write error", err)
+	}
+}
+
+func TestErrorFallbackHTTP(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r = r.WithContext(WithErrorFallback(r.Context(), nil))
+	rec := httptest.NewRecorder()
+	Handler(func(*http.Request) Builder { return Div(failing{errors.New("boom")}, Text("after")) }).ServeHTTP(rec, r)
+	if got := rec.Body.String(); got != "<div><section><p></p></section><!-- render error -->after</div>" {
+		t.Errorf("body = %q", got)
+	}
+	if rec.Code != 200 {
+		t.Errorf("status = %d", rec.Code)
+	}
+}
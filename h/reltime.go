@@ -0,0 +1,98 @@
+package h
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// now is the clock used by RelativeTime; tests replace it.
+var now = time.Now
+
+// relativeUnits are the units RelativeTime and RelativeTimeUpdater pick from,
+// largest first, with their length in seconds. The two must agree so the
+// client-side refresh doesn't change the text it was given.
+var relativeUnits = []struct {
+	name    string
+	seconds int64
+}{
+	{"year", 365 * 86400},
+	{"month", 30 * 86400},
+	{"day", 86400},
+	{"hour", 3600},
+	{"minute", 60},
+	{"second", 1},
+}
+
+// RelativeTime renders t relative to the current time inside a <time>
+// element, such as "3 minutes ago" or "in 2 days":
+//
+//	h.RelativeTime(comment.Created)
+//	// <time datetime="2006-01-02T15:04:05Z" data-relative-time>3 minutes ago</time>
+//
+// The text is rendered in English. Include RelativeTimeUpdater once on the
+// page to keep it current and localized to the document's lang without
+// re-requesting the page. Additional args are applied to the <time> element.
+func RelativeTime(t time.Time, args ...TagArg) Builder {
+	attrs, children := parseTagArgs(args)
+	rel := Attributes{
+		{Name: "datetime", Value: t.Format(time.RFC3339)},
+		{Name: "data-relative-time"},
+	}
+	rel.Merge(attrs)
+	children = append([]Builder{Text(relativeText(t.Sub(now())))}, children...)
+	return &tagBuilder{Name: "time", Attrs: rel, Children: children}
+}
+
+// relativeText formats d like Intl.RelativeTimeFormat("en", {numeric: "always"}).
+func relativeText(d time.Duration) string {
+	secs := int64(math.Round(d.Seconds()))
+	abs := secs
+	if abs < 0 {
+		abs = -abs
+	}
+	unit := relativeUnits[len(relativeUnits)-1]
+	for _, u := range relativeUnits {
+		if abs >= u.seconds {
+			unit = u
+			break
+		}
+	}
+	n := abs / unit.seconds
+	text := strconv.FormatInt(n, 10) + " " + unit.name
+	if n != 1 {
+		text += "s"
+	}
+	if secs > 0 {
+		return "in " + text
+	}
+	return text + " ago"
+}
+
+// RelativeTimeUpdater renders a <script> that re-renders every RelativeTime
+// on the page each interval (a minute if interval <= 0), using
+// Intl.RelativeTimeFormat with the document's lang. Include it once per page;
+// elements added later (e.g., by HTMX swaps) are picked up on the next tick.
+func RelativeTimeUpdater(interval time.Duration) Builder {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	units := ""
+	for i, u := range relativeUnits {
+		if i > 0 {
+			units += ","
+		}
+		units += `["` + u.name + `",` + strconv.FormatInt(u.seconds, 10) + `]`
+	}
+	// A zero difference is formatted as -0 so it reads "0 seconds ago",
+	// matching relativeText, rather than "in 0 seconds".
+	return Script(Raw(`(()=>{` +
+		`const f=new Intl.RelativeTimeFormat(document.documentElement.lang||undefined,{numeric:"always"});` +
+		`const u=[` + units + `];` +
+		`const r=()=>{const n=Date.now();` +
+		`for(const e of document.querySelectorAll("time[data-relative-time]")){` +
+		`const s=Math.round((Date.parse(e.dateTime)-n)/1000);const a=Math.abs(s);` +
+		`const[k,v]=u.find(([,v])=>a>=v)||u[u.length-1];` +
+		`e.textContent=f.format(Math.trunc(s/v)||-0,k)}};` +
+		`r();setInterval(r,` + strconv.FormatInt(interval.Milliseconds(), 10) + `)})()`))
+}
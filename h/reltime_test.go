@@ -0,0 +1,60 @@
+package h
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelativeText(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{0, "0 seconds ago"},
+		{-time.Second, "1 second ago"},
+		{-45 * time.Second, "45 seconds ago"},
+		{-90 * time.Second, "1 minute ago"},
+		{-3 * time.Minute, "3 minutes ago"},
+		{-5 * time.Hour, "5 hours ago"},
+		{-49 * time.Hour, "2 days ago"},
+		{-61 * 24 * time.Hour, "2 months ago"},
+		{-800 * 24 * time.Hour, "2 years ago"},
+		{10 * time.Second, "in 10 seconds"},
+		{2 * time.Hour, "in 2 hours"},
+	}
+	for _, tt := range tests {
+		if got := relativeText(tt.d); got != tt.expected {
+			t.Errorf("relativeText(%v) = %q, want %q", tt.d, got, tt.expected)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	ref := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return ref }
+	defer func() { now = time.Now }()
+
+	got := RenderString(RelativeTime(ref.Add(-3*time.Minute), Class("muted")))
+	expected := `<time datetime="2024-03-01T11:57:00Z" data-relative-time class="muted">3 minutes ago</time>`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestRelativeTimeUpdater(t *testing.T) {
+	got := RenderString(RelativeTimeUpdater(30 * time.Second))
+	for _, want := range []string{
+		"<script>",
+		`querySelectorAll("time[data-relative-time]")`,
+		`["minute",60]`,
+		"setInterval(r,30000)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+	if got := RenderString(RelativeTimeUpdater(0)); !strings.Contains(got, "setInterval(r,60000)") {
+		t.Errorf("expected default one minute interval, got %s", got)
+	}
+}
@@ -2,6 +2,7 @@ package h
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"strings"
 )
@@ -13,7 +14,25 @@ func Render(w io.Writer, b Builder) error {
 		return nil
 	}
 	writer := getPooledWriter(w)
-	err := b.Build(writer)
+	err := writer.buildDocument(b)
+	putPooledWriter(writer)
+	return err
+}
+
+// RenderContext is like Render, but makes ctx available to builders
+// through Writer.Context. If ctx has an error fallback (see
+// WithErrorFallback) that recovered from child errors, the output is
+// complete and the returned error wraps ErrPartialRender.
+func RenderContext(ctx context.Context, w io.Writer, b Builder) error {
+	if b == nil {
+		return nil
+	}
+	writer := getPooledWriter(w)
+	writer.SetContext(ctx)
+	err := writer.buildDocument(b)
+	if err == nil {
+		err = writer.partialRenderError()
+	}
 	putPooledWriter(writer)
 	return err
 }
@@ -28,7 +47,7 @@ func RenderIndent(w io.Writer, indent string, b Builder) error {
 	}
 	writer := getPooledWriter(w)
 	writer.SetIndent(indent)
-	err := b.Build(writer)
+	err := writer.buildDocument(b)
 	putPooledWriter(writer)
 	return err
 }
@@ -43,7 +62,7 @@ func RenderString(b Builder) string {
 	}
 	var sb strings.Builder
 	writer := getPooledWriter(&sb)
-	err := b.Build(writer)
+	err := writer.buildDocument(b)
 	putPooledWriter(writer)
 	if err != nil {
 		panic(err)
@@ -62,7 +81,7 @@ func RenderBytes(b Builder) []byte {
 	buf := bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 	writer := getPooledWriter(buf)
-	err := b.Build(writer)
+	err := writer.buildDocument(b)
 	putPooledWriter(writer)
 	if err != nil {
 		bufPool.Put(buf)
@@ -73,3 +92,16 @@ func RenderBytes(b Builder) []byte {
 	bufPool.Put(buf)
 	return result
 }
+
+// buildDocument builds b, wrapping its error in a RenderError, then ends
+// the output with a newline if config.TrailingNewline is set and it does
+// not already end with one.
+func (w *Writer) buildDocument(b Builder) error {
+	if err := b.Build(w); err != nil {
+		return w.renderError(err)
+	}
+	if w.trailingNL && !(w.isIndenting() && w.atLineStart) {
+		return w.write(w.nl())
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package h
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// RenderError locates an error returned by a builder: Render and the other
+// Render functions wrap errors raised inside an element in a RenderError,
+// and errors recovered by an error fallback (see WithErrorFallback) are
+// recorded as RenderErrors. Use errors.Is and errors.As on the Render
+// result as before; Unwrap returns the builder's error.
+type RenderError struct {
+	// Path lists the elements open when the error occurred, outermost
+	// first, e.g. ["html", "body", "main", "ul"].
+	Path []string
+	// Tag is the innermost open element, the last entry of Path.
+	Tag string
+	// Err is the error returned by the builder.
+	Err error
+}
+
+func (e *RenderError) Error() string {
+	return strings.Join(e.Path, "/") + ": " + e.Err.Error()
+}
+
+func (e *RenderError) Unwrap() error { return e.Err }
+
+// renderError wraps err with the elements currently open, or returns it
+// as-is if none are. A RenderError from a nested render keeps its own
+// path, prefixed with the open elements.
+func (w *Writer) renderError(err error) error {
+	if len(w.openTags) == 0 {
+		return err
+	}
+	path := make([]string, len(w.openTags), len(w.openTags)+2)
+	copy(path, w.openTags)
+	if inner, ok := err.(*RenderError); ok {
+		path = append(path, inner.Path...)
+		err = inner.Err
+	}
+	return &RenderError{Path: path, Tag: path[len(path)-1], Err: err}
+}
+
+// RenderErrors is the list of failures found by DryRun.
+type RenderErrors []*RenderError
+
+func (e RenderErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual errors for errors.Is and errors.As.
+func (e RenderErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// DryRun renders b with ctx without output, continuing past failing
+// children as an error fallback would, and returns nil or a RenderErrors
+// listing every failure with its location. Render stops at the first
+// failure; DryRun reports them all, for tests and pre-deploy checks:
+//
+//	if err := h.DryRun(ctx, page); err != nil {
+//	    t.Error(err)
+//	}
+//
+// Failures outside any element, which cannot be recovered, end the run
+// and are listed last. Combine with ValidateBuilder to check attributes.
+func DryRun(ctx context.Context, b Builder) error {
+	if b == nil {
+		return nil
+	}
+	writer := getPooledWriter(io.Discard)
+	writer.SetContext(ctx)
+	writer.SetErrorFallback(nil)
+	err := writer.buildDocument(b)
+	var errs RenderErrors
+	for _, rerr := range writer.recovered {
+		errs = append(errs, asRenderError(rerr))
+	}
+	putPooledWriter(writer)
+	if err != nil {
+		errs = append(errs, asRenderError(err))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// asRenderError returns err as a *RenderError, with an empty path if it
+// does not have one.
+func asRenderError(err error) *RenderError {
+	if re, ok := err.(*RenderError); ok {
+		return re
+	}
+	return &RenderError{Err: err}
+}
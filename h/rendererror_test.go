@@ -0,0 +1,85 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderError(t *testing.T) {
+	errBoom := errors.New("boom")
+	tests := []struct {
+		name    string
+		b       Builder
+		path    []string
+		message string
+	}{
+		{"nested", Html(Body(Ul(Li(Text("a")), Li(failingBuilder{errBoom})))), []string{"html", "body", "ul", "li"}, "html/body/ul/li: boom"},
+		{"fragment", Div(Fragment(Span(), failingBuilder{errBoom})), []string{"div"}, "div: boom"},
+		{"nested render", Main(NewCache().Cached("k", time.Minute, func() Builder { return Section(failingBuilder{errBoom}) })), []string{"main", "section"}, "main/section: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Render(io.Discard, tt.b)
+			if !errors.Is(err, errBoom) {
+				t.Fatalf("Render() = %v, want errBoom", err)
+			}
+			var re *RenderError
+			if !errors.As(err, &re) {
+				t.Fatalf("Render() = %T, want *RenderError", err)
+			}
+			if !slices.Equal(re.Path, tt.path) || re.Tag != tt.path[len(tt.path)-1] {
+				t.Errorf("Path = %q, Tag = %q, want %q", re.Path, re.Tag, tt.path)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.message)
+			}
+		})
+	}
+
+	if err := Render(io.Discard, failingBuilder{errBoom}); err != errBoom {
+		t.Errorf("error outside any element = %v, want it returned as-is", err)
+	}
+}
+
+func TestRenderErrorRecovered(t *testing.T) {
+	ctx := WithErrorFallback(context.Background(), nil)
+	err := RenderContext(ctx, io.Discard, Div(P(failingBuilder{errors.New("boom")})))
+	var re *RenderError
+	if !errors.Is(err, ErrPartialRender) || !errors.As(err, &re) || re.Tag != "p" {
+		t.Errorf("RenderContext() = %v, want a recovered RenderError at p", err)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	if err := DryRun(context.Background(), Div(Text("ok"))); err != nil {
+		t.Errorf("DryRun() = %v, want nil", err)
+	}
+
+	errA, errB := errors.New("a failed"), errors.New("b failed")
+	page := Html(Body(
+		Header(failingBuilder{errA}),
+		Main(Ul(Li(Text("ok")), Li(failingBuilder{errB}))),
+	))
+	err := DryRun(context.Background(), page)
+	var errs RenderErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("DryRun() = %v, want two RenderErrors", err)
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("DryRun() = %v, want both errors", err)
+	}
+	want := "html/body/header: a failed\nhtml/body/main/ul/li: b failed"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	err = DryRun(context.Background(), Fragment(Div(failingBuilder{errA}), failingBuilder{errB}))
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[1].Path != nil || !strings.HasSuffix(err.Error(), ": b failed") {
+		t.Errorf("DryRun() = %v, want the top-level failure last", err)
+	}
+}
@@ -0,0 +1,43 @@
+package h
+
+import "slices"
+
+// RootAttrs adds attrs to the root element b renders, as if they were
+// passed last to its tag function, so wrappers can decorate a component
+// without it accepting extra arguments:
+//
+//	h.RootAttrs(UserCard(u), h.Attr("data-testid", "user-card"))
+//
+// Only the first element b opens is changed. If b renders no element, attrs
+// are dropped. Nested RootAttrs apply to the same element, with the outer
+// attrs taking precedence.
+func RootAttrs(b Builder, attrs ...Attribute) Builder {
+	return &rootAttrsBuilder{b, attrs}
+}
+
+type rootAttrsBuilder struct {
+	b     Builder
+	attrs Attributes
+}
+
+func (b *rootAttrsBuilder) isTagArg() {}
+
+func (b *rootAttrsBuilder) Build(w *Writer) error {
+	if b.b == nil {
+		return nil
+	}
+	pending := slices.Clone(b.attrs)
+	pending.combine(w.rootAttrs)
+	w.rootAttrs = pending
+	err := b.b.Build(w)
+	w.rootAttrs = nil
+	return err
+}
+
+// withRootAttrs merges the pending RootAttrs into as and clears them.
+func (w *Writer) withRootAttrs(as Attributes) Attributes {
+	as = slices.Clone(as)
+	as.combine(w.rootAttrs)
+	w.rootAttrs = nil
+	return as
+}
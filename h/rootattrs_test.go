@@ -0,0 +1,33 @@
+package h
+
+import "testing"
+
+func TestRootAttrs(t *testing.T) {
+	card := Div(Class("card"), Span(Text("Ada")))
+
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"adds to root only", RootAttrs(card, Attr("data-testid", "card")), `<div class="card" data-testid="card"><span>Ada</span></div>`},
+		{"joins class", RootAttrs(card, Class("highlight")), `<div class="card highlight"><span>Ada</span></div>`},
+		{"overrides", RootAttrs(Div(ID("a")), ID("b")), `<div id="b"></div>`},
+		{"void element", RootAttrs(Input(Type("text")), Attr("id", "q")), `<input type="text" id="q"/>`},
+		{"first element of fragment", RootAttrs(Fragment(Text("x"), Br(), Hr()), Attr("id", "a")), `x<br id="a"/><hr/>`},
+		{"nested outer wins", RootAttrs(RootAttrs(card, Attr("id", "inner"), Attr("title", "t")), Attr("id", "outer")), `<div class="card" id="outer" title="t"><span>Ada</span></div>`},
+		{"no element drops attrs", Fragment(RootAttrs(Text("x"), Attr("id", "a")), P()), `x<p></p>`},
+		{"nil", RootAttrs(nil, Attr("id", "a")), ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if got := RenderString(card); got != `<div class="card"><span>Ada</span></div>` {
+		t.Errorf("wrapped builder was mutated: %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+package h
+
+import (
+	"iter"
+	"net/http"
+)
+
+// streamBuilder renders Builders from an iterator, flushing after each.
+type streamBuilder struct {
+	seq iter.Seq[Builder]
+}
+
+func (s *streamBuilder) isTagArg() {}
+
+func (s *streamBuilder) Build(w *Writer) error {
+	ctx := w.Context()
+	for b := range s.seq {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if b == nil {
+			continue
+		}
+		if err := b.Build(w); err != nil {
+			return err
+		}
+		if err := w.flushOutput(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stream creates a Builder that renders each Builder from seq as it is
+// produced, flushing the output after each one so clients receive rows
+// while later ones are still being fetched. Nil builders are skipped, and
+// rendering stops with the context's error if the Writer's context is
+// canceled, such as when an HTTP client disconnects.
+//
+//	h.Table(h.Tbody(h.Stream(func(yield func(h.Builder) bool) {
+//	    for rows.Next() {
+//	        var u User
+//	        rows.Scan(&u.Name, &u.Email)
+//	        if !yield(h.Tr(h.Td(h.Text(u.Name)), h.Td(h.Text(u.Email)))) {
+//	            return
+//	        }
+//	    }
+//	})))
+//
+// Output is flushed through buffered Writers, gzip compression in
+// RenderHTTP, and any underlying writer with a Flush method, including
+// http.ResponseWriter.
+func Stream(seq iter.Seq[Builder]) Builder {
+	return &streamBuilder{seq: seq}
+}
+
+// streamChanBuilder renders Builders received from a channel.
+type streamChanBuilder struct {
+	ch <-chan Builder
+}
+
+func (s *streamChanBuilder) isTagArg() {}
+
+func (s *streamChanBuilder) Build(w *Writer) error {
+	ctx := w.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case b, ok := <-s.ch:
+			if !ok {
+				return nil
+			}
+			if b == nil {
+				continue
+			}
+			if err := b.Build(w); err != nil {
+				return err
+			}
+			if err := w.flushOutput(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamChan is like Stream but renders Builders received from ch until it
+// is closed. If the Writer's context is canceled first, rendering stops
+// with the context's error; the sender should also watch the context so it
+// does not block forever.
+func StreamChan(ch <-chan Builder) Builder {
+	return &streamChanBuilder{ch: ch}
+}
+
+// flushPointBuilder flushes the output when reached.
+type flushPointBuilder struct{}
+
+func (flushPointBuilder) isTagArg() {}
+
+func (flushPointBuilder) Build(w *Writer) error {
+	if err := w.Context().Err(); err != nil {
+		return err
+	}
+	return w.flushOutput()
+}
+
+// FlushPoint creates a Builder that flushes the output written so far, so
+// the client can start on the head and above-the-fold content while the
+// rest of the page is rendered:
+//
+//	h.Html(
+//	    h.Head(styles),
+//	    h.Body(header, h.FlushPoint(), h.Defer(func() h.Builder {
+//	        return results(search(q)) // Slow
+//	    })),
+//	)
+//
+// Like Stream, it flushes through buffered Writers, gzip compression in
+// RenderHTTP, and any underlying writer with a Flush method, including
+// http.ResponseWriter; elsewhere it writes nothing. If the Writer's context
+// is canceled, rendering stops with the context's error.
+func FlushPoint() Builder {
+	return flushPointBuilder{}
+}
+
+// deferBuilder calls fn when it is built.
+type deferBuilder struct {
+	fn func() Builder
+}
+
+func (d *deferBuilder) isTagArg() {}
+
+func (d *deferBuilder) Build(w *Writer) error {
+	if err := w.Context().Err(); err != nil {
+		return err
+	}
+	if b := d.fn(); b != nil {
+		return b.Build(w)
+	}
+	return nil
+}
+
+// Defer creates a Builder whose content is computed by fn only when the
+// render reaches it, after everything before it has been written. After a
+// FlushPoint, slow queries in fn no longer delay the content above it. fn
+// is called on every render and is not called if the Writer's context is
+// canceled first; a nil result renders nothing.
+func Defer(fn func() Builder) Builder {
+	return &deferBuilder{fn: fn}
+}
+
+// flushOutput flushes the Writer's buffer and then the underlying writer,
+// if it supports flushing, so written output reaches the client.
+func (w *Writer) flushOutput() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	dst := w.output()
+	if w.buf != nil {
+		dst = w.bufDst
+	}
+	switch f := dst.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package h
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// flushRecorder records the output present at each Flush.
+type flushRecorder struct {
+	bytes.Buffer
+	flushes []string
+}
+
+func (f *flushRecorder) Flush() error {
+	f.flushes = append(f.flushes, f.String())
+	return nil
+}
+
+func TestStream(t *testing.T) {
+	var out flushRecorder
+	rows := slices.Values([]Builder{Li(Text("a")), nil, Li(Text("b"))})
+	if err := Render(&out, Ul(Stream(rows))); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "<ul><li>a</li><li>b</li></ul>" {
+		t.Errorf("got %s", got)
+	}
+	expected := []string{"<ul><li>a</li>", "<ul><li>a</li><li>b</li>"}
+	if !slices.Equal(out.flushes, expected) {
+		t.Errorf("flushes = %q, want %q", out.flushes, expected)
+	}
+}
+
+func TestStreamWithErrorFallback(t *testing.T) {
+	var out flushRecorder
+	ctx := WithErrorFallback(context.Background(), nil)
+	if err := RenderContext(ctx, &out, Ul(Stream(slices.Values([]Builder{Li(Text("a"))})))); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.flushes) != 1 {
+		t.Errorf("flushes = %q, want one flush", out.flushes)
+	}
+}
+
+func TestStreamBuffered(t *testing.T) {
+	var out flushRecorder
+	w := NewBufferedWriter(&out, 0)
+	if err := Div(Stream(slices.Values([]Builder{P(Text("a"))}))).Build(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.flushes) != 1 || out.flushes[0] != "<div><p>a</p>" {
+		t.Errorf("flushes = %q", out.flushes)
+	}
+}
+
+func TestStreamChan(t *testing.T) {
+	ch := make(chan Builder)
+	go func() {
+		defer close(ch)
+		for _, s := range []string{"a", "b", "c"} {
+			ch <- Li(Text(s))
+		}
+	}()
+	var out flushRecorder
+	if err := Render(&out, Ul(StreamChan(ch))); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "<ul><li>a</li><li>b</li><li>c</li></ul>" {
+		t.Errorf("got %s", got)
+	}
+	if len(out.flushes) != 3 {
+		t.Errorf("flushes = %q, want 3", out.flushes)
+	}
+}
+
+func TestStreamCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	seq := func(yield func(Builder) bool) {
+		for i := 0; ; i++ {
+			if i == 2 {
+				cancel()
+			}
+			if !yield(P()) {
+				return
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := RenderContext(ctx, &buf, Stream(seq)); !errors.Is(err, context.Canceled) {
+		t.Errorf("Stream err = %v, want context.Canceled", err)
+	}
+	if got := buf.String(); got != "<p></p><p></p>" {
+		t.Errorf("got %s", got)
+	}
+
+	// A channel that never sends must not block a canceled render.
+	if err := RenderContext(ctx, &buf, StreamChan(make(chan Builder))); !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamChan err = %v, want context.Canceled", err)
+	}
+}
+
+func TestStreamHTTP(t *testing.T) {
+	for _, encoding := range []string{"", "gzip"} {
+		t.Run("encoding="+encoding, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			req.Header.Set("Accept-Encoding", encoding)
+			rows := slices.Values([]Builder{Li(Text("a")), Li(Text("b"))})
+			if err := RenderHTTP(rec, req, Ul(Stream(rows))); err != nil {
+				t.Fatal(err)
+			}
+			if !rec.Flushed {
+				t.Error("response was not flushed")
+			}
+			var body io.Reader = rec.Body
+			if encoding == "gzip" {
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body = gz
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), "<li>a</li><li>b</li>") {
+				t.Errorf("body = %s", got)
+			}
+		})
+	}
+}
+
+func TestFlushPointAndDefer(t *testing.T) {
+	var out flushRecorder
+	var atDefer string
+	page := Body(
+		Header(Text("top")),
+		FlushPoint(),
+		Defer(func() Builder {
+			atDefer = out.String()
+			return Main(Text("slow"))
+		}),
+		Defer(func() Builder { return nil }),
+	)
+	if err := Render(&out, page); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "<body><header>top</header><main>slow</main></body>" {
+		t.Errorf("got %s", got)
+	}
+	if !slices.Equal(out.flushes, []string{"<body><header>top</header>"}) {
+		t.Errorf("flushes = %q", out.flushes)
+	}
+	if atDefer != "<body><header>top</header>" {
+		t.Errorf("output when Defer ran = %q", atDefer)
+	}
+
+	// Without a flusher, FlushPoint writes nothing.
+	if got := RenderString(Div(FlushPoint(), Text("x"))); got != "<div>x</div>" {
+		t.Errorf("got %s", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	err := RenderContext(ctx, io.Discard, Div(FlushPoint(), Defer(func() Builder { called = true; return nil })))
+	if !errors.Is(err, context.Canceled) || called {
+		t.Errorf("canceled: err = %v, called = %v", err, called)
+	}
+}
+
+func TestFlushPointHTTP(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if err := RenderHTTP(rec, httptest.NewRequest("GET", "/", nil), Div(Text("a"), FlushPoint(), Text("b"))); err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Flushed || rec.Body.String() != "<div>ab</div>" {
+		t.Errorf("flushed = %v, body = %s", rec.Flushed, rec.Body)
+	}
+}
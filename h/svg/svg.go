@@ -0,0 +1,400 @@
+// Package svg builds inline SVG elements and attributes:
+//
+//	svg.Svg(svg.ViewBox(0, 0, 24, 24), svg.Width(24), svg.Height(24),
+//	    svg.Fill("none"), svg.Stroke("currentColor"), svg.StrokeWidth(2),
+//	    svg.Circle(svg.Cx(12), svg.Cy(12), svg.R(10)),
+//	    svg.Path(svg.D("M12 6v6l4 2")),
+//	)
+//	// <svg viewBox="0 0 24 24" width="24" height="24" fill="none" stroke="currentColor"
+//	//   stroke-width="2"><circle cx="12" cy="12" r="10"></circle><path d="M12 6v6l4 2"></path></svg>
+//
+// SVG names are case-sensitive: elements such as linearGradient and
+// attributes such as viewBox are camelCase, while presentation attributes
+// such as stroke-width are hyphenated. The helpers spell them correctly,
+// and Element and Attr correct names given in other forms ("viewbox",
+// "strokeWidth"). Numbers are written in their shortest form.
+package svg
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Element creates the SVG element name, correcting its case:
+// Element("lineargradient") renders <linearGradient>.
+func Element(name string, args ...h.TagArg) h.Builder {
+	if fixed, ok := elementNames[strings.ToLower(name)]; ok {
+		name = fixed
+	}
+	return h.CustomElement(name, args...)
+}
+
+// Attr creates the SVG attribute name, correcting its case:
+// Attr("viewbox", ...) and Attr("strokeWidth", ...) render viewBox and
+// stroke-width. Unknown names are written as given.
+func Attr(name, value string) h.Attribute {
+	return h.Attr(AttrName(name), value)
+}
+
+// AttrName returns the SVG spelling of an attribute name given in any case
+// or in camelCase, such as "strokeWidth" for stroke-width.
+func AttrName(name string) string {
+	key := strings.ToLower(name)
+	if fixed, ok := camelAttrs[key]; ok {
+		return fixed
+	}
+	if hyphenated, ok := hyphenatedAttrs[key]; ok {
+		return hyphenated
+	}
+	return name
+}
+
+// Elements
+
+// Svg creates an <svg> root element.
+func Svg(args ...h.TagArg) h.Builder { return h.Svg(args...) }
+
+// G creates a <g> group element.
+func G(args ...h.TagArg) h.Builder { return h.CustomElement("g", args...) }
+
+// Defs creates a <defs> element for definitions referenced elsewhere.
+func Defs(args ...h.TagArg) h.Builder { return h.CustomElement("defs", args...) }
+
+// Symbol creates a <symbol> element, a template instantiated by Use.
+func Symbol(args ...h.TagArg) h.Builder { return h.CustomElement("symbol", args...) }
+
+// Use creates a <use> element that renders a copy of the element Href
+// refers to, such as an icon from a sprite: svg.Use(svg.Href("#icon-x")).
+func Use(args ...h.TagArg) h.Builder { return h.CustomElement("use", args...) }
+
+// Title creates a <title> element, the accessible name of its parent.
+func Title(args ...h.TagArg) h.Builder { return h.CustomElement("title", args...) }
+
+// Desc creates a <desc> element, the accessible description of its parent.
+func Desc(args ...h.TagArg) h.Builder { return h.CustomElement("desc", args...) }
+
+// Path creates a <path> element.
+func Path(args ...h.TagArg) h.Builder { return h.CustomElement("path", args...) }
+
+// Circle creates a <circle> element.
+func Circle(args ...h.TagArg) h.Builder { return h.CustomElement("circle", args...) }
+
+// Ellipse creates an <ellipse> element.
+func Ellipse(args ...h.TagArg) h.Builder { return h.CustomElement("ellipse", args...) }
+
+// Line creates a <line> element.
+func Line(args ...h.TagArg) h.Builder { return h.CustomElement("line", args...) }
+
+// Polyline creates a <polyline> element.
+func Polyline(args ...h.TagArg) h.Builder { return h.CustomElement("polyline", args...) }
+
+// Polygon creates a <polygon> element.
+func Polygon(args ...h.TagArg) h.Builder { return h.CustomElement("polygon", args...) }
+
+// Rect creates a <rect> element.
+func Rect(args ...h.TagArg) h.Builder { return h.CustomElement("rect", args...) }
+
+// Text creates a <text> element. Use h.Text for its content.
+func Text(args ...h.TagArg) h.Builder { return h.CustomElement("text", args...) }
+
+// TSpan creates a <tspan> element.
+func TSpan(args ...h.TagArg) h.Builder { return h.CustomElement("tspan", args...) }
+
+// TextPath creates a <textPath> element.
+func TextPath(args ...h.TagArg) h.Builder { return h.CustomElement("textPath", args...) }
+
+// Image creates an <image> element.
+func Image(args ...h.TagArg) h.Builder { return h.CustomElement("image", args...) }
+
+// ForeignObject creates a <foreignObject> element for embedding HTML.
+func ForeignObject(args ...h.TagArg) h.Builder { return h.CustomElement("foreignObject", args...) }
+
+// LinearGradient creates a <linearGradient> element.
+func LinearGradient(args ...h.TagArg) h.Builder {
+	return h.CustomElement("linearGradient", args...)
+}
+
+// RadialGradient creates a <radialGradient> element.
+func RadialGradient(args ...h.TagArg) h.Builder {
+	return h.CustomElement("radialGradient", args...)
+}
+
+// Stop creates a <stop> element, a gradient color stop.
+func Stop(args ...h.TagArg) h.Builder { return h.CustomElement("stop", args...) }
+
+// ClipPath creates a <clipPath> element.
+func ClipPath(args ...h.TagArg) h.Builder { return h.CustomElement("clipPath", args...) }
+
+// Mask creates a <mask> element.
+func Mask(args ...h.TagArg) h.Builder { return h.CustomElement("mask", args...) }
+
+// Pattern creates a <pattern> element.
+func Pattern(args ...h.TagArg) h.Builder { return h.CustomElement("pattern", args...) }
+
+// Marker creates a <marker> element, such as an arrowhead.
+func Marker(args ...h.TagArg) h.Builder { return h.CustomElement("marker", args...) }
+
+// Filter creates a <filter> element. Add filter primitives with Element,
+// e.g. Element("feGaussianBlur", StdDeviation(2)).
+func Filter(args ...h.TagArg) h.Builder { return h.CustomElement("filter", args...) }
+
+// Animate creates an <animate> element.
+func Animate(args ...h.TagArg) h.Builder { return h.CustomElement("animate", args...) }
+
+// AnimateTransform creates an <animateTransform> element.
+func AnimateTransform(args ...h.TagArg) h.Builder {
+	return h.CustomElement("animateTransform", args...)
+}
+
+// Attributes
+
+// ViewBox creates a viewBox attribute: viewBox="0 0 24 24".
+func ViewBox(minX, minY, width, height float64) h.Attribute {
+	return h.Attr("viewBox", num(minX)+" "+num(minY)+" "+num(width)+" "+num(height))
+}
+
+// PreserveAspectRatio creates a preserveAspectRatio attribute, such as
+// "xMidYMid meet" or "none".
+func PreserveAspectRatio(v string) h.Attribute { return h.Attr("preserveAspectRatio", v) }
+
+// Width creates a width attribute in user units.
+func Width(n float64) h.Attribute { return h.Attr("width", num(n)) }
+
+// Height creates a height attribute in user units.
+func Height(n float64) h.Attribute { return h.Attr("height", num(n)) }
+
+// X creates an x attribute.
+func X(n float64) h.Attribute { return h.Attr("x", num(n)) }
+
+// Y creates a y attribute.
+func Y(n float64) h.Attribute { return h.Attr("y", num(n)) }
+
+// X1 creates an x1 attribute.
+func X1(n float64) h.Attribute { return h.Attr("x1", num(n)) }
+
+// Y1 creates a y1 attribute.
+func Y1(n float64) h.Attribute { return h.Attr("y1", num(n)) }
+
+// X2 creates an x2 attribute.
+func X2(n float64) h.Attribute { return h.Attr("x2", num(n)) }
+
+// Y2 creates a y2 attribute.
+func Y2(n float64) h.Attribute { return h.Attr("y2", num(n)) }
+
+// Cx creates a cx attribute, the x coordinate of a center.
+func Cx(n float64) h.Attribute { return h.Attr("cx", num(n)) }
+
+// Cy creates a cy attribute, the y coordinate of a center.
+func Cy(n float64) h.Attribute { return h.Attr("cy", num(n)) }
+
+// R creates an r attribute, a radius.
+func R(n float64) h.Attribute { return h.Attr("r", num(n)) }
+
+// Rx creates an rx attribute, a horizontal or corner radius.
+func Rx(n float64) h.Attribute { return h.Attr("rx", num(n)) }
+
+// Ry creates an ry attribute, a vertical or corner radius.
+func Ry(n float64) h.Attribute { return h.Attr("ry", num(n)) }
+
+// D creates a d attribute, the path data of a Path.
+func D(path string) h.Attribute { return h.Attr("d", path) }
+
+// Points creates the points attribute of a Polyline or Polygon from x, y
+// pairs: Points(0, 0, 10, 0, 5, 8) renders points="0,0 10,0 5,8". Panics
+// if given an odd number of coordinates.
+func Points(coords ...float64) h.Attribute {
+	if len(coords)%2 != 0 {
+		panic("svg: Points: odd number of coordinates")
+	}
+	pairs := make([]string, 0, len(coords)/2)
+	for i := 0; i < len(coords); i += 2 {
+		pairs = append(pairs, num(coords[i])+","+num(coords[i+1]))
+	}
+	return h.Attr("points", strings.Join(pairs, " "))
+}
+
+// Href creates an href attribute, for Use, Image, TextPath, and gradients.
+func Href(url string) h.Attribute { return h.Attr("href", url) }
+
+// Fill creates a fill attribute: a color, "none", or "url(#id)".
+func Fill(paint string) h.Attribute { return h.Attr("fill", paint) }
+
+// FillOpacity creates a fill-opacity attribute.
+func FillOpacity(n float64) h.Attribute { return h.Attr("fill-opacity", num(n)) }
+
+// FillRule creates a fill-rule attribute: "nonzero" or "evenodd".
+func FillRule(v string) h.Attribute { return h.Attr("fill-rule", v) }
+
+// Stroke creates a stroke attribute: a color, "none", or "url(#id)".
+func Stroke(paint string) h.Attribute { return h.Attr("stroke", paint) }
+
+// StrokeWidth creates a stroke-width attribute.
+func StrokeWidth(n float64) h.Attribute { return h.Attr("stroke-width", num(n)) }
+
+// StrokeOpacity creates a stroke-opacity attribute.
+func StrokeOpacity(n float64) h.Attribute { return h.Attr("stroke-opacity", num(n)) }
+
+// StrokeLinecap creates a stroke-linecap attribute: "butt", "round", or
+// "square".
+func StrokeLinecap(v string) h.Attribute { return h.Attr("stroke-linecap", v) }
+
+// StrokeLinejoin creates a stroke-linejoin attribute: "miter", "round", or
+// "bevel".
+func StrokeLinejoin(v string) h.Attribute { return h.Attr("stroke-linejoin", v) }
+
+// StrokeDasharray creates a stroke-dasharray attribute from dash and gap
+// lengths.
+func StrokeDasharray(lengths ...float64) h.Attribute {
+	parts := make([]string, len(lengths))
+	for i, n := range lengths {
+		parts[i] = num(n)
+	}
+	return h.Attr("stroke-dasharray", strings.Join(parts, " "))
+}
+
+// Opacity creates an opacity attribute.
+func Opacity(n float64) h.Attribute { return h.Attr("opacity", num(n)) }
+
+// Transform creates a transform attribute, such as "rotate(45 12 12)".
+func Transform(v string) h.Attribute { return h.Attr("transform", v) }
+
+// ClipPathAttr creates a clip-path attribute referring to a ClipPath:
+// ClipPathAttr("url(#clip)").
+func ClipPathAttr(v string) h.Attribute { return h.Attr("clip-path", v) }
+
+// MaskAttr creates a mask attribute referring to a Mask: MaskAttr("url(#m)").
+func MaskAttr(v string) h.Attribute { return h.Attr("mask", v) }
+
+// FilterAttr creates a filter attribute referring to a Filter.
+func FilterAttr(v string) h.Attribute { return h.Attr("filter", v) }
+
+// Offset creates the offset attribute of a Stop, from 0 to 1.
+func Offset(n float64) h.Attribute { return h.Attr("offset", num(n)) }
+
+// StopColor creates a stop-color attribute.
+func StopColor(color string) h.Attribute { return h.Attr("stop-color", color) }
+
+// StopOpacity creates a stop-opacity attribute.
+func StopOpacity(n float64) h.Attribute { return h.Attr("stop-opacity", num(n)) }
+
+// GradientUnits creates a gradientUnits attribute: "userSpaceOnUse" or
+// "objectBoundingBox".
+func GradientUnits(v string) h.Attribute { return h.Attr("gradientUnits", v) }
+
+// GradientTransform creates a gradientTransform attribute.
+func GradientTransform(v string) h.Attribute { return h.Attr("gradientTransform", v) }
+
+// PatternUnits creates a patternUnits attribute.
+func PatternUnits(v string) h.Attribute { return h.Attr("patternUnits", v) }
+
+// ClipPathUnits creates a clipPathUnits attribute.
+func ClipPathUnits(v string) h.Attribute { return h.Attr("clipPathUnits", v) }
+
+// MarkerWidth creates a markerWidth attribute.
+func MarkerWidth(n float64) h.Attribute { return h.Attr("markerWidth", num(n)) }
+
+// MarkerHeight creates a markerHeight attribute.
+func MarkerHeight(n float64) h.Attribute { return h.Attr("markerHeight", num(n)) }
+
+// RefX creates a refX attribute.
+func RefX(n float64) h.Attribute { return h.Attr("refX", num(n)) }
+
+// RefY creates a refY attribute.
+func RefY(n float64) h.Attribute { return h.Attr("refY", num(n)) }
+
+// Orient creates the orient attribute of a Marker: "auto" or an angle.
+func Orient(v string) h.Attribute { return h.Attr("orient", v) }
+
+// MarkerEnd creates a marker-end attribute referring to a Marker.
+func MarkerEnd(v string) h.Attribute { return h.Attr("marker-end", v) }
+
+// StdDeviation creates the stdDeviation attribute of feGaussianBlur.
+func StdDeviation(n float64) h.Attribute { return h.Attr("stdDeviation", num(n)) }
+
+// PathLength creates a pathLength attribute.
+func PathLength(n float64) h.Attribute { return h.Attr("pathLength", num(n)) }
+
+// TextAnchor creates a text-anchor attribute: "start", "middle", or "end".
+func TextAnchor(v string) h.Attribute { return h.Attr("text-anchor", v) }
+
+// DominantBaseline creates a dominant-baseline attribute, such as
+// "central".
+func DominantBaseline(v string) h.Attribute { return h.Attr("dominant-baseline", v) }
+
+// FontSize creates a font-size attribute in user units.
+func FontSize(n float64) h.Attribute { return h.Attr("font-size", num(n)) }
+
+// AttributeName creates the attributeName attribute of an animation.
+func AttributeName(name string) h.Attribute { return h.Attr("attributeName", AttrName(name)) }
+
+// RepeatCount creates a repeatCount attribute: a number or "indefinite".
+func RepeatCount(v string) h.Attribute { return h.Attr("repeatCount", v) }
+
+func num(n float64) string { return strconv.FormatFloat(n, 'f', -1, 64) }
+
+// elementNames maps lowercased names of camelCase SVG elements to their
+// spelling.
+var elementNames = lowerKeys(
+	"altGlyph", "altGlyphDef", "altGlyphItem", "animateColor", "animateMotion",
+	"animateTransform", "clipPath", "feBlend", "feColorMatrix",
+	"feComponentTransfer", "feComposite", "feConvolveMatrix",
+	"feDiffuseLighting", "feDisplacementMap", "feDistantLight", "feDropShadow",
+	"feFlood", "feFuncA", "feFuncB", "feFuncG", "feFuncR", "feGaussianBlur",
+	"feImage", "feMerge", "feMergeNode", "feMorphology", "feOffset",
+	"fePointLight", "feSpecularLighting", "feSpotLight", "feTile",
+	"feTurbulence", "foreignObject", "glyphRef", "linearGradient",
+	"radialGradient", "textPath",
+)
+
+// camelAttrs maps lowercased names of camelCase SVG attributes to their
+// spelling.
+var camelAttrs = lowerKeys(
+	"attributeName", "attributeType", "baseFrequency", "calcMode",
+	"clipPathUnits", "diffuseConstant", "edgeMode", "filterUnits",
+	"gradientTransform", "gradientUnits", "kernelMatrix", "kernelUnitLength",
+	"keyPoints", "keySplines", "keyTimes", "lengthAdjust", "limitingConeAngle",
+	"markerHeight", "markerUnits", "markerWidth", "maskContentUnits",
+	"maskUnits", "numOctaves", "pathLength", "patternContentUnits",
+	"patternTransform", "patternUnits", "pointsAtX", "pointsAtY", "pointsAtZ",
+	"preserveAlpha", "preserveAspectRatio", "primitiveUnits", "refX", "refY",
+	"repeatCount", "repeatDur", "requiredExtensions", "specularConstant",
+	"specularExponent", "spreadMethod", "startOffset", "stdDeviation",
+	"stitchTiles", "surfaceScale", "systemLanguage", "tableValues", "targetX",
+	"targetY", "textLength", "viewBox", "xChannelSelector", "yChannelSelector",
+	"zoomAndPan",
+)
+
+// hyphenatedAttrs maps the camelCase forms of hyphenated presentation
+// attributes, lowercased, to their spelling: "strokewidth" to
+// "stroke-width".
+var hyphenatedAttrs = func() map[string]string {
+	m := map[string]string{}
+	for _, name := range []string{
+		"alignment-baseline", "baseline-shift", "clip-path", "clip-rule",
+		"color-interpolation", "color-interpolation-filters", "dominant-baseline",
+		"fill-opacity", "fill-rule", "flood-color", "flood-opacity", "font-family",
+		"font-size", "font-size-adjust", "font-stretch", "font-style",
+		"font-variant", "font-weight", "image-rendering", "letter-spacing",
+		"lighting-color", "marker-end", "marker-mid", "marker-start",
+		"paint-order", "pointer-events", "shape-rendering", "stop-color",
+		"stop-opacity", "stroke-dasharray", "stroke-dashoffset",
+		"stroke-linecap", "stroke-linejoin", "stroke-miterlimit",
+		"stroke-opacity", "stroke-width", "text-anchor", "text-decoration",
+		"text-rendering", "transform-origin", "unicode-bidi", "vector-effect",
+		"word-spacing", "writing-mode",
+	} {
+		m[strings.ReplaceAll(name, "-", "")] = name
+		m[name] = name
+	}
+	return m
+}()
+
+func lowerKeys(names ...string) map[string]string {
+	m := make(map[string]string, len(names))
+	for _, name := range names {
+		m[strings.ToLower(name)] = name
+	}
+	return m
+}
@@ -0,0 +1,75 @@
+package svg
+
+import (
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestIcon(t *testing.T) {
+	icon := Svg(ViewBox(0, 0, 24, 24), Width(24), Height(24),
+		Fill("none"), Stroke("currentColor"), StrokeWidth(1.5), StrokeLinecap("round"),
+		Title(h.Text("Clock")),
+		Circle(Cx(12), Cy(12), R(10)),
+		Path(D("M12 6v6l4 2")),
+		Polyline(Points(0, 0, 10, 0, 5, 8.5)),
+		Use(Href("#tick"), StrokeDasharray(2, 1)),
+	)
+	expected := `<svg viewBox="0 0 24 24" width="24" height="24" fill="none" stroke="currentColor" stroke-width="1.5" stroke-linecap="round">` +
+		`<title>Clock</title><circle cx="12" cy="12" r="10"></circle><path d="M12 6v6l4 2"></path>` +
+		`<polyline points="0,0 10,0 5,8.5"></polyline><use href="#tick" stroke-dasharray="2 1"></use></svg>`
+	if got := h.RenderString(icon); got != expected {
+		t.Errorf("got  %s\nwant %s", got, expected)
+	}
+}
+
+func TestGradient(t *testing.T) {
+	g := Defs(LinearGradient(h.ID("fade"), GradientUnits("userSpaceOnUse"),
+		Stop(Offset(0), StopColor("#fff")),
+		Stop(Offset(1), StopColor("#000"), StopOpacity(0.5)),
+	))
+	expected := `<defs><linearGradient id="fade" gradientUnits="userSpaceOnUse">` +
+		`<stop offset="0" stop-color="#fff"></stop><stop offset="1" stop-color="#000" stop-opacity="0.5"></stop>` +
+		`</linearGradient></defs>`
+	if got := h.RenderString(g); got != expected {
+		t.Errorf("got  %s\nwant %s", got, expected)
+	}
+}
+
+func TestCaseCorrection(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"viewbox", "viewBox"},
+		{"VIEWBOX", "viewBox"},
+		{"preserveaspectratio", "preserveAspectRatio"},
+		{"strokeWidth", "stroke-width"},
+		{"stroke-width", "stroke-width"},
+		{"fillOpacity", "fill-opacity"},
+		{"data-icon", "data-icon"},
+		{"cx", "cx"},
+	}
+	for _, tt := range tests {
+		if got := AttrName(tt.name); got != tt.expected {
+			t.Errorf("AttrName(%q) = %q, want %q", tt.name, got, tt.expected)
+		}
+	}
+
+	b := Filter(h.ID("blur"), Element("fegaussianblur", Attr("stddeviation", "2")))
+	if got := h.RenderString(b); got != `<filter id="blur"><feGaussianBlur stdDeviation="2"></feGaussianBlur></filter>` {
+		t.Errorf("got %s", got)
+	}
+	if got := h.RenderString(Animate(AttributeName("strokeDashoffset"), RepeatCount("indefinite"))); got != `<animate attributeName="stroke-dashoffset" repeatCount="indefinite"></animate>` {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestPointsOdd(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for odd coordinates")
+		}
+	}()
+	Points(1, 2, 3)
+}
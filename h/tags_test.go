@@ -0,0 +1,47 @@
+package h
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestVoidElements checks that every void element in the HTML spec renders
+// as a single self-closing tag, with no end tag, and that non-void
+// elements always get one.
+func TestVoidElements(t *testing.T) {
+	void := []struct {
+		name string
+		fn   func(...TagArg) Builder
+	}{
+		{"area", Area}, {"base", Base}, {"br", Br}, {"col", Col},
+		{"embed", Embed}, {"hr", Hr}, {"img", Img}, {"input", Input},
+		{"link", Link}, {"meta", Meta}, {"source", Source}, {"track", Track},
+		{"wbr", Wbr},
+	}
+	for _, tt := range void {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.fn()); got != "<"+tt.name+"/>" {
+				t.Errorf("empty: got %s", got)
+			}
+			if got := RenderString(tt.fn(Class("x"), Attrs("title", `a"b`))); got != "<"+tt.name+` class="x" title="a&#34;b"/>` {
+				t.Errorf("with attributes: got %s", got)
+			}
+			var sb strings.Builder
+			if err := RenderIndent(&sb, "  ", Div(tt.fn(), tt.fn())); err != nil {
+				t.Fatal(err)
+			}
+			if expected := "<div>\n  <" + tt.name + "/>\n  <" + tt.name + "/>\n</div>\n"; sb.String() != expected {
+				t.Errorf("indented: got %q, want %q", sb.String(), expected)
+			}
+		})
+	}
+
+	for _, fn := range []func(...TagArg) Builder{Div, P, Span, Script, Textarea, Video, Canvas, Iframe} {
+		got := RenderString(fn())
+		name := strings.TrimSuffix(strings.TrimPrefix(got, "<"), ">")
+		name, _, _ = strings.Cut(name, ">")
+		if got != "<"+name+"></"+name+">" {
+			t.Errorf("non-void element: got %s", got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package h
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"fits", Truncate("short", 10, nil), "short"},
+		{"exact", Truncate("exactly", 7, nil), "exactly"},
+		{"word boundary", Truncate("The quick brown fox", 12, nil), "The quick…"},
+		{"cut before space", Truncate("The quick brown fox", 9, nil), "The quick…"},
+		{"trailing punctuation", Truncate("Hello, world again", 8, nil), "Hello…"},
+		{"long word", Truncate("Supercalifragilistic", 5, nil), "Super…"},
+		{"multibyte", Truncate("héllo wörld ünïcode", 11, nil), "héllo wörld…"},
+		{"emoji", Truncate("🙂🙂🙂🙂", 2, nil), "🙂🙂…"},
+		{"combining mark", Truncate("cafés", 4, nil), "café…"},
+		{"escapes", Truncate("a < b && c > d", 8, nil), "a &lt; b &amp;&amp;…"},
+		{"custom ellipsis", Truncate("one two three", 7, A(Href("/more"), Text("more"))), `one two<a href="/more">more</a>`},
+		{"zero", Truncate("text", 0, nil), "…"},
+		{"empty", Truncate("", 0, nil), ""},
+		{"trailing space only", Truncate("word   ", 4, nil), "word   "},
+		{"read more fits", ReadMore("short", 10), "short"},
+		{"read more", ReadMore("The quick brown <fox>", 9, Class("review")),
+			`<details class="review"><summary>The quick… <span class="read-more">Read more</span></summary>brown &lt;fox&gt;</details>`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := Render(&buf, tt.b); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if buf.String() != tt.expected {
+			t.Errorf("%s: got %q, want %q", tt.name, buf.String(), tt.expected)
+		}
+	}
+}
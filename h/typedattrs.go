@@ -0,0 +1,195 @@
+package h
+
+import (
+	"slices"
+	"strings"
+)
+
+// Typed attribute helpers return a single Attribute for common HTML attributes.
+// They can be mixed freely with Attrs and children in any tag function:
+//
+//	h.A(h.Class("nav-link", "active"), h.Href("/home"), h.Text("Home"))
+//	h.Button(h.Type("submit"), h.Disabled(!ready), h.Text("Save"))
+//
+// Boolean attribute helpers return a zero Attribute when false, which is
+// skipped during rendering.
+
+// Class creates a class attribute from one or more class names.
+// Empty names are ignored, so optional classes can be passed as "":
+//
+//	h.Class("btn", variantClass)
+//
+// Class attributes given to the same tag are joined, so
+// h.Div(h.Class("card"), h.Class("wide")) renders class="card wide".
+func Class(names ...string) Attribute {
+	n := 0
+	for _, name := range names {
+		if name != "" {
+			n++
+		}
+	}
+	if n == len(names) {
+		return Attribute{Name: "class", Value: strings.Join(names, " ")}
+	}
+	parts := make([]string, 0, n)
+	for _, name := range names {
+		if name != "" {
+			parts = append(parts, name)
+		}
+	}
+	return Attribute{Name: "class", Value: strings.Join(parts, " ")}
+}
+
+// ClassNames creates a class attribute from class lists, splitting each on
+// whitespace and dropping duplicates. Combine it with ClassIf and ClassMap
+// for conditional classes:
+//
+//	h.ClassNames("btn", h.ClassIf(isPrimary, "btn-primary"), h.ClassMap(map[string]bool{
+//	    "active":   isActive,
+//	    "disabled": !enabled,
+//	}))
+func ClassNames(lists ...string) Attribute {
+	var value string
+	for _, list := range lists {
+		value = joinTokens(value, strings.Join(strings.Fields(list), " "))
+	}
+	return Attribute{Name: "class", Value: value}
+}
+
+// ClassIf returns names if cond is true, and "" otherwise.
+func ClassIf(cond bool, names string) string {
+	if cond {
+		return names
+	}
+	return ""
+}
+
+// ClassMap returns the keys of classes whose value is true, sorted and
+// separated by spaces.
+func ClassMap(classes map[string]bool) string {
+	names := make([]string, 0, len(classes))
+	for name, on := range classes {
+		if on {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return strings.Join(names, " ")
+}
+
+// ID creates an id attribute.
+func ID(id string) Attribute { return Attribute{Name: "id", Value: id} }
+
+// Href creates an href attribute.
+func Href(url string) Attribute { return Attribute{Name: "href", Value: url} }
+
+// Src creates a src attribute.
+func Src(url string) Attribute { return Attribute{Name: "src", Value: url} }
+
+// Type creates a type attribute (e.g., "submit", "email", "text/javascript").
+func Type(typ string) Attribute { return Attribute{Name: "type", Value: typ} }
+
+// Name creates a name attribute.
+func Name(name string) Attribute { return Attribute{Name: "name", Value: name} }
+
+// Value creates a value attribute.
+func Value(value string) Attribute { return Attribute{Name: "value", Value: value} }
+
+// Rel creates a rel attribute.
+func Rel(rel string) Attribute { return Attribute{Name: "rel", Value: rel} }
+
+// Alt creates an alt attribute.
+func Alt(text string) Attribute { return Attribute{Name: "alt", Value: text} }
+
+// For creates a for attribute, associating a label with a form control.
+func For(id string) Attribute { return Attribute{Name: "for", Value: id} }
+
+// Placeholder creates a placeholder attribute.
+func Placeholder(text string) Attribute { return Attribute{Name: "placeholder", Value: text} }
+
+// Role creates an ARIA role attribute.
+func Role(role string) Attribute { return Attribute{Name: "role", Value: role} }
+
+// DataAttr creates a data-* attribute. The "data-" prefix is added automatically.
+// Panics if name is empty.
+//
+//	h.DataAttr("user-id", "42") // data-user-id="42"
+func DataAttr(name, value string) Attribute {
+	if name == "" {
+		panic("data attribute name cannot be empty")
+	}
+	return Attribute{Name: "data-" + name, Value: value}
+}
+
+// Aria creates an aria-* attribute. The "aria-" prefix is added automatically.
+// Panics if name is empty.
+//
+//	h.Aria("label", "Close") // aria-label="Close"
+func Aria(name, value string) Attribute {
+	if name == "" {
+		panic("aria attribute name cannot be empty")
+	}
+	return Attribute{Name: "aria-" + name, Value: value}
+}
+
+// boolAttr returns a valueless attribute when on is true, or a zero Attribute otherwise.
+func boolAttr(name string, on bool) Attribute {
+	if on {
+		return Attribute{Name: name}
+	}
+	return Attribute{}
+}
+
+// Disabled creates a disabled boolean attribute when disabled is true.
+func Disabled(disabled bool) Attribute { return boolAttr("disabled", disabled) }
+
+// Checked creates a checked boolean attribute when checked is true.
+func Checked(checked bool) Attribute { return boolAttr("checked", checked) }
+
+// Selected creates a selected boolean attribute when selected is true.
+func Selected(selected bool) Attribute { return boolAttr("selected", selected) }
+
+// Required creates a required boolean attribute when required is true.
+func Required(required bool) Attribute { return boolAttr("required", required) }
+
+// ReadOnly creates a readonly boolean attribute when readOnly is true.
+func ReadOnly(readOnly bool) Attribute { return boolAttr("readonly", readOnly) }
+
+// Hidden creates a hidden boolean attribute when hidden is true.
+func Hidden(hidden bool) Attribute { return boolAttr("hidden", hidden) }
+
+// Multiple creates a multiple boolean attribute when multiple is true.
+func Multiple(multiple bool) Attribute { return boolAttr("multiple", multiple) }
+
+// Autofocus creates an autofocus boolean attribute when autofocus is true.
+func Autofocus(autofocus bool) Attribute { return boolAttr("autofocus", autofocus) }
+
+// StyleDecl is a CSS declaration for StyleAttr. The css package provides
+// typed implementations.
+type StyleDecl interface {
+	// CSSDecl returns the declaration as "property: value", or "" to omit
+	// it.
+	CSSDecl() string
+}
+
+// StyleAttr creates a style attribute from CSS declarations, joined with
+// "; ". Omitted declarations are skipped:
+//
+//	h.Div(h.StyleAttr(css.Display("flex"), css.Gap("1rem")))  // style="display: flex; gap: 1rem"
+func StyleAttr(decls ...StyleDecl) Attribute {
+	var sb strings.Builder
+	for _, d := range decls {
+		if d == nil {
+			continue
+		}
+		decl := d.CSSDecl()
+		if decl == "" {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(decl)
+	}
+	return Attribute{Name: "style", Value: sb.String()}
+}
@@ -0,0 +1,99 @@
+package h
+
+import "testing"
+
+type testDecl string
+
+func (d testDecl) CSSDecl() string { return string(d) }
+
+func TestTypedAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     Attribute
+		expected Attribute
+	}{
+		{"Class", Class("a", "b"), Attribute{Name: "class", Value: "a b"}},
+		{"Class skips empty", Class("a", "", "c"), Attribute{Name: "class", Value: "a c"}},
+		{"Class empty", Class(), Attribute{Name: "class", Value: ""}},
+		{"ID", ID("main"), Attribute{Name: "id", Value: "main"}},
+		{"Href", Href("/home"), Attribute{Name: "href", Value: "/home"}},
+		{"Src", Src("/a.png"), Attribute{Name: "src", Value: "/a.png"}},
+		{"Type", Type("submit"), Attribute{Name: "type", Value: "submit"}},
+		{"Name", Name("email"), Attribute{Name: "name", Value: "email"}},
+		{"Value", Value("x"), Attribute{Name: "value", Value: "x"}},
+		{"Rel", Rel("stylesheet"), Attribute{Name: "rel", Value: "stylesheet"}},
+		{"Alt", Alt("logo"), Attribute{Name: "alt", Value: "logo"}},
+		{"For", For("email"), Attribute{Name: "for", Value: "email"}},
+		{"Placeholder", Placeholder("Search"), Attribute{Name: "placeholder", Value: "Search"}},
+		{"Role", Role("button"), Attribute{Name: "role", Value: "button"}},
+		{"DataAttr", DataAttr("user-id", "42"), Attribute{Name: "data-user-id", Value: "42"}},
+		{"Aria", Aria("label", "Close"), Attribute{Name: "aria-label", Value: "Close"}},
+		{"Disabled true", Disabled(true), Attribute{Name: "disabled", Value: ""}},
+		{"Disabled false", Disabled(false), Attribute{}},
+		{"Checked", Checked(true), Attribute{Name: "checked", Value: ""}},
+		{"Selected", Selected(true), Attribute{Name: "selected", Value: ""}},
+		{"Required", Required(true), Attribute{Name: "required", Value: ""}},
+		{"ReadOnly", ReadOnly(true), Attribute{Name: "readonly", Value: ""}},
+		{"Hidden", Hidden(true), Attribute{Name: "hidden", Value: ""}},
+		{"Multiple", Multiple(true), Attribute{Name: "multiple", Value: ""}},
+		{"Autofocus", Autofocus(false), Attribute{}},
+		{"StyleAttr", StyleAttr(testDecl("display: flex"), nil, testDecl(""), testDecl("gap: 1rem")), Attribute{Name: "style", Value: "display: flex; gap: 1rem"}},
+		{"ClassNames", ClassNames("btn  btn-lg", ClassIf(true, "primary"), ClassIf(false, "hidden"), "btn", ""), Attribute{Name: "class", Value: "btn btn-lg primary"}},
+		{"ClassMap", ClassNames(ClassMap(map[string]bool{"b": true, "a": true, "c": false})), Attribute{Name: "class", Value: "a b"}},
+		{"StyleAttr empty", StyleAttr(), Attribute{Name: "style", Value: ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr != tt.expected {
+				t.Errorf("got %+v, want %+v", tt.attr, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepeatedTokenListAttrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string
+	}{
+		{"class args", Div(Class("card"), Class("wide", "card")), `<div class="card wide"></div>`},
+		{"class in Attrs", Div(Attrs("class", "a", "id", "x", "class", "b")), `<div class="a b" id="x"></div>`},
+		{"rel", Link(Attrs("rel", "stylesheet"), Rel("preload")), `<link rel="stylesheet preload"/>`},
+		{"other attrs replace", Div(ID("a"), ID("b")), `<div id="b"></div>`},
+		{"shared attrs untouched", Fragment(Div(sharedCardClass, Class("x")), Div(sharedCardClass)), `<div class="card x"></div><div class="card"></div>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderString(tt.b); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+var sharedCardClass = Attrs("class", "card")
+
+func TestTypedAttributesInTags(t *testing.T) {
+	got := RenderString(Button(
+		Class("btn", "btn-primary"),
+		Type("submit"),
+		Disabled(true),
+		Hidden(false),
+		DataAttr("action", "save"),
+		Text("Save"),
+	))
+	expected := `<button class="btn btn-primary" type="submit" disabled data-action="save">Save</button>`
+	if got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+}
+
+func TestDataAttrPanicOnEmptyName(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty data attribute name")
+		}
+	}()
+	DataAttr("", "value")
+}
@@ -0,0 +1,71 @@
+package h
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUntrustedAttribute is returned when strict mode finds an Untrusted
+// value in an attribute where escaping alone does not make it safe.
+var ErrUntrustedAttribute = errors.New("untrusted value in unsafe attribute")
+
+// Untrusted marks a string as user-controlled. UntrustedAttr writes it as
+// an attribute value:
+//
+//	h.Input(h.Name("q"), h.UntrustedAttr("value", h.Untrusted(r.FormValue("q"))))
+//
+// Untrusted values are escaped like any other attribute value. In strict
+// mode (see WithStrictUntrusted), rendering fails if one appears in a URL
+// attribute, an event handler, or style, where escaped input can still run
+// script, e.g. href="javascript:...".
+type Untrusted string
+
+// UntrustedAttr creates the attribute name with the user-controlled value
+// v. Panics if name is empty.
+func UntrustedAttr(name string, v Untrusted) Attribute {
+	if name == "" {
+		panic("attribute name cannot be empty")
+	}
+	return Attribute{Name: name, Value: string(v), untrusted: true}
+}
+
+type strictUntrustedKey struct{}
+
+// WithStrictUntrusted returns a context whose renders fail with
+// ErrUntrustedAttribute when an Untrusted value is used in an unsafe
+// attribute. See Writer.SetStrictUntrusted.
+func WithStrictUntrusted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, strictUntrustedKey{}, true)
+}
+
+// StrictUntrusted reports whether ctx was created with WithStrictUntrusted.
+func StrictUntrusted(ctx context.Context) bool {
+	strict, _ := ctx.Value(strictUntrustedKey{}).(bool)
+	return strict
+}
+
+// checkUntrusted returns an error wrapping ErrUntrustedAttribute for the
+// first Untrusted value in as that is in an unsafe attribute.
+func checkUntrusted(tag string, as Attributes) error {
+	for _, attr := range as {
+		if attr.untrusted && unsafeForUntrusted(attr.Name) {
+			return fmt.Errorf("%w: %s on <%s>", ErrUntrustedAttribute, attr.Name, tag)
+		}
+	}
+	return nil
+}
+
+// unsafeForUntrusted reports whether an attribute's value is interpreted as
+// a URL, script, or CSS.
+func unsafeForUntrusted(name string) bool {
+	name = strings.ToLower(name)
+	switch {
+	case urlAttrs[name], name == "srcset", name == "imagesrcset", name == "srcdoc", name == "style":
+		return true
+	case strings.HasPrefix(name, "on"), strings.HasPrefix(name, "hx-on"), strings.HasPrefix(name, "data-on"):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package h
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUntrusted(t *testing.T) {
+	input := Untrusted(`javascript:alert("x")`)
+	tests := []struct {
+		name     string
+		b        Builder
+		expected string // Output in strict mode; "" if it must fail
+	}{
+		{"value", Input(UntrustedAttr("value", `<b>"hi"</b>`)), `<input value="&lt;b&gt;&#34;hi&#34;&lt;/b&gt;"/>`},
+		{"alt", Img(UntrustedAttr("alt", "cat")), `<img alt="cat"/>`},
+		{"data", Div(UntrustedAttr("data-name", "ada")), `<div data-name="ada"></div>`},
+		{"aria", Div(UntrustedAttr("aria-label", "ada")), `<div aria-label="ada"></div>`},
+		{"trusted href", A(Href(`/profile`)), `<a href="/profile"></a>`},
+		{"href", A(UntrustedAttr("href", input)), ""},
+		{"src", Img(UntrustedAttr("src", input)), ""},
+		{"action", Form(UntrustedAttr("action", input)), ""},
+		{"htmx url", Button(UntrustedAttr("hx-get", input)), ""},
+		{"event handler", Button(UntrustedAttr("onclick", input)), ""},
+		{"event handler case", Button(UntrustedAttr("onClick", input)), ""},
+		{"htmx handler", Button(UntrustedAttr("hx-on:click", input)), ""},
+		{"datastar handler", Button(UntrustedAttr("data-on-click", input)), ""},
+		{"style", Div(UntrustedAttr("style", input)), ""},
+		{"nested", Div(P(A(UntrustedAttr("href", input)))), ""},
+		{"overridden by trusted", A(UntrustedAttr("href", input), Href("/safe")), `<a href="/safe"></a>`},
+		{"overridden by untrusted", A(Href("/safe"), Attrs("title", "x"), UntrustedAttr("href", input)), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := RenderContext(WithStrictUntrusted(context.Background()), &buf, tt.b)
+			if tt.expected == "" {
+				if !errors.Is(err, ErrUntrustedAttribute) {
+					t.Errorf("err = %v, want ErrUntrustedAttribute", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUntrustedAttrPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty name")
+		}
+	}()
+	UntrustedAttr("", "x")
+}
+
+func TestUntrustedNotStrict(t *testing.T) {
+	got := RenderString(A(UntrustedAttr("href", `javascript:alert("x")`)))
+	expected := `<a href="javascript:alert(&#34;x&#34;)"></a>`
+	if got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
+
+func TestUntrustedError(t *testing.T) {
+	var buf bytes.Buffer
+	err := RenderContext(WithStrictUntrusted(context.Background()), &buf, Div(P(Text("ok")), A(UntrustedAttr("href", "x"))))
+	if !errors.Is(err, ErrUntrustedAttribute) || err.Error() != "div: untrusted value in unsafe attribute: href on <a>" {
+		t.Errorf("err = %v", err)
+	}
+	if got := buf.String(); got != "<div><p>ok</p>" {
+		t.Errorf("unsafe tag was written: %s", got)
+	}
+}
@@ -0,0 +1,107 @@
+package h
+
+import "strings"
+
+// SafeURL is a URL checked by URL, or trusted as-is with UnsafeURL, for
+// use with URLAttr.
+type SafeURL string
+
+// BlockedURL replaces URLs rejected by URL. It navigates nowhere.
+const BlockedURL SafeURL = "about:invalid#blocked"
+
+// safeSchemes are the URL schemes allowed by URL.
+var safeSchemes = []string{"http", "https", "mailto", "tel"}
+
+// URL checks u for use in an href, src, action, or formaction attribute.
+// Relative URLs and http, https, mailto, and tel URLs are allowed; any
+// other scheme, such as javascript: or data:, is replaced by BlockedURL,
+// since escaping alone does not stop those from running script. Characters
+// that are not valid in URLs, such as spaces and quotes, are
+// percent-encoded; existing %XX escapes are kept.
+//
+//	h.A(h.URLAttr("href", h.URL(r.FormValue("next"))), h.Text("Continue"))
+//
+// Browsers ignore leading spaces and control characters and any tabs or
+// newlines when reading a scheme, so "\tjava\nscript:..." is blocked too.
+func URL(u string) SafeURL {
+	if scheme, ok := urlScheme(u); ok && !validEnum(safeSchemes, scheme) {
+		return BlockedURL
+	}
+	return SafeURL(normalizeURL(u))
+}
+
+// UnsafeURL marks u as safe without checking it, for URLs with other
+// schemes that come from trusted code, such as data: images generated by
+// the server. Never pass user input.
+func UnsafeURL(u string) SafeURL {
+	return SafeURL(u)
+}
+
+// URLAttr creates the attribute name with the URL u:
+//
+//	h.Img(h.URLAttr("src", h.UnsafeURL(thumbnailDataURI)))
+//	h.Form(h.URLAttr("action", h.URL(next)), ...)
+//	h.Button(h.URLAttr("formaction", h.URL("/drafts")), h.Text("Save draft"))
+func URLAttr(name string, u SafeURL) Attribute {
+	if name == "" {
+		panic("attribute name cannot be empty")
+	}
+	return Attribute{Name: name, Value: string(u)}
+}
+
+// urlScheme returns the scheme of u, lowercased, as a browser reads it.
+// ok is false for relative URLs.
+func urlScheme(u string) (scheme string, ok bool) {
+	u = strings.TrimLeftFunc(u, func(r rune) bool { return r <= ' ' })
+	i := strings.IndexAny(u, ":/?#")
+	if i <= 0 || u[i] != ':' {
+		return "", false
+	}
+	scheme = strings.NewReplacer("\t", "", "\n", "", "\r", "").Replace(u[:i])
+	for j := 0; j < len(scheme); j++ {
+		c := scheme[j] | 0x20 // Lowercase letters
+		switch {
+		case 'a' <= c && c <= 'z':
+		case j > 0 && ('0' <= scheme[j] && scheme[j] <= '9' || scheme[j] == '+' || scheme[j] == '-' || scheme[j] == '.'):
+		default:
+			return "", false
+		}
+	}
+	return strings.ToLower(scheme), scheme != ""
+}
+
+// normalizeURL percent-encodes the bytes of u that are not valid in a URL.
+func normalizeURL(u string) string {
+	const hex = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(u); i++ {
+		c := u[i]
+		if validURLByte(c) {
+			if sb.Len() > 0 {
+				sb.WriteByte(c)
+			}
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.Grow(len(u) + 8)
+			sb.WriteString(u[:i])
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hex[c>>4])
+		sb.WriteByte(hex[c&0xf])
+	}
+	if sb.Len() == 0 {
+		return u
+	}
+	return sb.String()
+}
+
+// validURLByte reports whether c is an unreserved or reserved URL
+// character, or '%'.
+func validURLByte(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("-._~:/?#[]@!$&'()*+,;=%", c) >= 0
+}
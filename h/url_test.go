@@ -0,0 +1,64 @@
+package h
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected SafeURL
+	}{
+		{"/users/42?tab=posts#top", "/users/42?tab=posts#top"},
+		{"https://example.com/a b", "https://example.com/a%20b"},
+		{"HTTP://example.com", "HTTP://example.com"},
+		{"mailto:ada@example.com", "mailto:ada@example.com"},
+		{"tel:+15551234", "tel:+15551234"},
+		{"search?q=café&x=\"<>\"", "search?q=caf%C3%A9&x=%22%3C%3E%22"},
+		{"already%20encoded", "already%20encoded"},
+		{"docs/page:1", "docs/page:1"},
+		{"./javascript:alert(1)", "./javascript:alert(1)"},
+		{"javascript:alert(1)", BlockedURL},
+		{"JavaScript:alert(1)", BlockedURL},
+		{" \x01javascript:alert(1)", BlockedURL},
+		{"java\tscr\nipt:alert(1)", BlockedURL},
+		{"data:text/html,<script>alert(1)</script>", BlockedURL},
+		{"vbscript:msgbox", BlockedURL},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := URL(tt.input); got != tt.expected {
+			t.Errorf("URL(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestURLAttrs(t *testing.T) {
+	page := Fragment(
+		A(URLAttr("href", URL("javascript:alert(1)")), Text("x")),
+		Form(URLAttr("action", URL("/save")), Button(URLAttr("formaction", URL("/draft")))),
+		Img(URLAttr("src", UnsafeURL("data:image/png;base64,AAAA"))),
+	)
+	expected := `<a href="about:invalid#blocked">x</a>` +
+		`<form action="/save"><button formaction="/draft"></button></form>` +
+		`<img src="data:image/png;base64,AAAA"/>`
+	if got := RenderString(page); got != expected {
+		t.Errorf("got %s\nwant %s", got, expected)
+	}
+
+	// A checked URL is not untrusted, so strict mode allows it.
+	var sb strings.Builder
+	ctx := WithStrictUntrusted(context.Background())
+	if err := RenderContext(ctx, &sb, A(URLAttr("href", URL(string(Untrusted("/next")))))); err != nil {
+		t.Errorf("strict mode rejected a checked URL: %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for empty name")
+		}
+	}()
+	URLAttr("", URL("/"))
+}
@@ -0,0 +1,330 @@
+package h
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors wrapped by AttrError.
+var (
+	// ErrUnknownAttribute is returned for attribute names not defined by the
+	// HTML spec for the element they appear on.
+	ErrUnknownAttribute = errors.New("unknown attribute")
+	// ErrInvalidAttributeName is returned for attribute names containing
+	// characters that are not allowed in HTML.
+	ErrInvalidAttributeName = errors.New("invalid attribute name")
+	// ErrInvalidAttributeValue is returned for values not allowed for an
+	// enumerated attribute: popover, popovertargetaction, or command.
+	ErrInvalidAttributeValue = errors.New("invalid attribute value")
+)
+
+// AttrError describes an invalid attribute found by ValidateBuilder.
+type AttrError struct {
+	// Path locates the element, e.g. "html/body/div[2]/button".
+	// Indexes are 1-based and only shown for repeated sibling elements.
+	Path string
+	// Element is the element name.
+	Element string
+	// Attr is the offending attribute name.
+	Attr string
+	// Value is the offending value, for ErrInvalidAttributeValue.
+	Value string
+	// Suggestion is the closest known attribute name, if the attribute
+	// looks like a typo.
+	Suggestion string
+	// Err is ErrUnknownAttribute, ErrInvalidAttributeName, or
+	// ErrInvalidAttributeValue.
+	Err error
+}
+
+func (e *AttrError) Error() string {
+	msg := fmt.Sprintf("%s: %v %q", e.Path, e.Err, e.Attr)
+	if errors.Is(e.Err, ErrInvalidAttributeValue) {
+		msg += fmt.Sprintf(" = %q", e.Value)
+	}
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", e.Suggestion)
+	}
+	return msg
+}
+
+func (e *AttrError) Unwrap() error { return e.Err }
+
+// ValidationError is the list of attribute problems found by ValidateBuilder.
+type ValidationError []*AttrError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual errors for errors.Is and errors.As.
+func (e ValidationError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ValidAttributePrefixes lists attribute name prefixes accepted on any
+// element: custom data attributes and common framework attributes.
+var ValidAttributePrefixes = []string{"data-", "aria-", "hx-", "x-", ":", "@"}
+
+// ValidateBuilder renders b without output and checks every attribute name
+// against the HTML spec, flagging likely typos such as "clas" or "onlcick",
+// and the values of the popover, popovertargetaction, and command
+// attributes.
+// It returns nil or a ValidationError listing each problem with its element
+// path. Rendering errors are returned as-is.
+//
+// Custom elements, SVG and MathML subtrees, and attributes with a prefix in
+// ValidAttributePrefixes are not checked. Content written with Raw is not
+// inspected. Intended for test suites:
+//
+//	if err := h.ValidateBuilder(page); err != nil {
+//	    t.Error(err)
+//	}
+func ValidateBuilder(b Builder) error {
+	if b == nil {
+		return nil
+	}
+	var (
+		errs     ValidationError
+		segments []string         // path segment for each open element
+		counts   []map[string]int // sibling counts per depth
+		foreign  = -1             // depth of the enclosing svg/math element
+	)
+	writer := getPooledWriter(io.Discard)
+	writer.onTag = func(w *Writer, name string, as Attributes, void bool) {
+		depth := len(w.openTags)
+		if foreign >= depth {
+			foreign = -1
+		}
+		segments = segments[:depth]
+		if len(counts) > depth+1 {
+			counts = counts[:depth+1]
+		}
+		for len(counts) <= depth {
+			counts = append(counts, map[string]int{})
+		}
+		counts[depth][name]++
+		seg := name
+		if n := counts[depth][name]; n > 1 {
+			seg += "[" + strconv.Itoa(n) + "]"
+		}
+		path := strings.Join(append(segments, seg), "/")
+		if !void {
+			segments = append(segments, seg)
+			// Children start with fresh sibling counts.
+			if len(counts) > depth+1 {
+				counts[depth+1] = map[string]int{}
+			}
+		}
+
+		if foreign < 0 && (name == "svg" || name == "math") {
+			foreign = depth
+			return
+		}
+		if foreign >= 0 || strings.Contains(name, "-") {
+			return
+		}
+		for _, a := range as {
+			if a.Name == "" {
+				continue
+			}
+			err := validateAttr(name, a.Name)
+			if err == nil && !validAttrValue(a) {
+				err = &AttrError{Element: name, Attr: a.Name, Value: a.Value, Err: ErrInvalidAttributeValue}
+			}
+			if err != nil {
+				err.Path = path
+				errs = append(errs, err)
+			}
+		}
+	}
+	err := b.Build(writer)
+	putPooledWriter(writer)
+	if err != nil {
+		return err
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func validateAttr(element, name string) *AttrError {
+	if strings.ContainsAny(name, " \t\n\f\r\"'>/=<`") {
+		return &AttrError{Element: element, Attr: name, Err: ErrInvalidAttributeName}
+	}
+	lower := strings.ToLower(name)
+	if globalAttrs[lower] || eventAttrs[lower] || elementAttrs[element][lower] {
+		return nil
+	}
+	if strings.HasPrefix(lower, "aria-") {
+		if ariaAttrs[lower] {
+			return nil
+		}
+	} else {
+		for _, prefix := range ValidAttributePrefixes {
+			if strings.HasPrefix(lower, prefix) {
+				return nil
+			}
+		}
+	}
+	return &AttrError{Element: element, Attr: name, Suggestion: suggestAttr(element, lower), Err: ErrUnknownAttribute}
+}
+
+// suggestAttr returns the known attribute closest to name within an edit
+// distance of 2, or "" if none is close enough.
+func suggestAttr(element, name string) string {
+	best, bestDist := "", 3
+	consider := func(set map[string]bool) {
+		for candidate := range set {
+			if d := editDistance(name, candidate); d < bestDist || d == bestDist && candidate < best {
+				best, bestDist = candidate, d
+			}
+		}
+	}
+	consider(globalAttrs)
+	consider(elementAttrs[element])
+	consider(eventAttrs)
+	consider(ariaAttrs)
+	return best
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b, so transpositions like "onlcick" count as one edit.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func attrSet(names string) map[string]bool {
+	set := map[string]bool{}
+	for _, n := range strings.Fields(names) {
+		set[n] = true
+	}
+	return set
+}
+
+var globalAttrs = attrSet(`accesskey autocapitalize autocorrect autofocus class contenteditable dir
+	draggable enterkeyhint exportparts hidden id inert inputmode is itemid itemprop itemref
+	itemscope itemtype lang nonce part popover role slot spellcheck style tabindex title
+	translate writingsuggestions xmlns`)
+
+var eventAttrs = attrSet(`onabort onafterprint onanimationcancel onanimationend onanimationiteration
+	onanimationstart onauxclick onbeforeinput onbeforematch onbeforeprint onbeforetoggle
+	onbeforeunload onblur oncancel oncanplay oncanplaythrough onchange onclick onclose
+	oncontextlost oncontextmenu oncontextrestored oncopy oncuechange oncut ondblclick ondrag
+	ondragend ondragenter ondragleave ondragover ondragstart ondrop ondurationchange onemptied
+	onended onerror onfocus onfocusin onfocusout onformdata onhashchange oninput oninvalid
+	onkeydown onkeypress onkeyup onlanguagechange onload onloadeddata onloadedmetadata
+	onloadstart onmessage onmessageerror onmousedown onmouseenter onmouseleave onmousemove
+	onmouseout onmouseover onmouseup onoffline ononline onpagehide onpagereveal onpageshow
+	onpageswap onpaste onpause onplay onplaying onpointercancel onpointerdown onpointerenter
+	onpointerleave onpointermove onpointerout onpointerover onpointerup onpopstate onprogress
+	onratechange onrejectionhandled onreset onresize onscroll onscrollend
+	onsecuritypolicyviolation onseeked onseeking onselect onselectionchange onselectstart
+	onslotchange onstalled onstorage onsubmit onsuspend ontimeupdate ontoggle ontouchcancel
+	ontouchend ontouchmove ontouchstart ontransitioncancel ontransitionend ontransitionrun
+	ontransitionstart onunhandledrejection onunload onvolumechange onwaiting onwheel`)
+
+var ariaAttrs = attrSet(`aria-activedescendant aria-atomic aria-autocomplete aria-braillelabel
+	aria-brailleroledescription aria-busy aria-checked aria-colcount aria-colindex
+	aria-colindextext aria-colspan aria-controls aria-current aria-describedby
+	aria-description aria-details aria-disabled aria-dropeffect aria-errormessage
+	aria-expanded aria-flowto aria-grabbed aria-haspopup aria-hidden aria-invalid
+	aria-keyshortcuts aria-label aria-labelledby aria-level aria-live aria-modal
+	aria-multiline aria-multiselectable aria-orientation aria-owns aria-placeholder
+	aria-posinset aria-pressed aria-readonly aria-relevant aria-required
+	aria-roledescription aria-rowcount aria-rowindex aria-rowindextext aria-rowspan
+	aria-selected aria-setsize aria-sort aria-valuemax aria-valuemin aria-valuenow
+	aria-valuetext`)
+
+const mediaAttrs = `autoplay controls controlslist crossorigin disableremoteplayback loop muted preload src`
+
+var elementAttrs = map[string]map[string]bool{
+	"a":          attrSet(`attributionsrc download href hreflang ping referrerpolicy rel target type`),
+	"area":       attrSet(`alt coords download href ping referrerpolicy rel shape target`),
+	"audio":      attrSet(mediaAttrs),
+	"base":       attrSet(`href target`),
+	"blockquote": attrSet(`cite`),
+	"button": attrSet(`command commandfor disabled form formaction formenctype formmethod
+		formnovalidate formtarget name popovertarget popovertargetaction type value`),
+	"canvas":   attrSet(`height width`),
+	"col":      attrSet(`span`),
+	"colgroup": attrSet(`span`),
+	"data":     attrSet(`value`),
+	"del":      attrSet(`cite datetime`),
+	"details":  attrSet(`name open`),
+	"dialog":   attrSet(`closedby open`),
+	"embed":    attrSet(`height src type width`),
+	"fieldset": attrSet(`disabled form name`),
+	"form":     attrSet(`accept-charset action autocomplete enctype method name novalidate rel target`),
+	"html":     attrSet(`manifest version`),
+	"iframe": attrSet(`allow allowfullscreen credentialless height loading name referrerpolicy
+		sandbox src srcdoc width`),
+	"img": attrSet(`alt attributionsrc crossorigin decoding elementtiming fetchpriority height
+		ismap loading referrerpolicy sizes src srcset usemap width`),
+	"input": attrSet(`accept alt autocomplete capture checked dirname disabled form formaction
+		formenctype formmethod formnovalidate formtarget height list max maxlength min minlength
+		multiple name pattern placeholder popovertarget popovertargetaction readonly required
+		size src step type value width`),
+	"ins":   attrSet(`cite datetime`),
+	"label": attrSet(`for`),
+	"li":    attrSet(`value`),
+	"link": attrSet(`as blocking crossorigin disabled fetchpriority href hreflang imagesizes
+		imagesrcset integrity media referrerpolicy rel sizes type`),
+	"map":      attrSet(`name`),
+	"meta":     attrSet(`charset content http-equiv media name property`),
+	"meter":    attrSet(`high low max min optimum value`),
+	"object":   attrSet(`data form height name type width`),
+	"ol":       attrSet(`reversed start type`),
+	"optgroup": attrSet(`disabled label`),
+	"option":   attrSet(`disabled label selected value`),
+	"output":   attrSet(`for form name`),
+	"progress": attrSet(`max value`),
+	"q":        attrSet(`cite`),
+	"script": attrSet(`async attributionsrc blocking crossorigin defer fetchpriority integrity
+		nomodule referrerpolicy src type`),
+	"select": attrSet(`autocomplete disabled form multiple name required size`),
+	"slot":   attrSet(`name`),
+	"source": attrSet(`height media sizes src srcset type width`),
+	"style":  attrSet(`blocking media`),
+	"td":     attrSet(`colspan headers rowspan`),
+	"template": attrSet(`shadowrootclonable shadowrootdelegatesfocus shadowrootmode
+		shadowrootserializable`),
+	"textarea": attrSet(`autocomplete cols dirname disabled form maxlength minlength name
+		placeholder readonly required rows wrap`),
+	"th":    attrSet(`abbr colspan headers rowspan scope`),
+	"time":  attrSet(`datetime`),
+	"track": attrSet(`default kind label src srclang`),
+	"video": attrSet(mediaAttrs + ` disablepictureinpicture height playsinline poster width`),
+}
@@ -0,0 +1,99 @@
+package h
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		b        Builder
+		expected []string
+	}{
+		{"valid", Div(Attrs("class", "a", "id", "b", "data-x", "1", "aria-label", "l", "hx-get", "/x", "onclick", "go()"),
+			A(Attrs("href", "/", "rel", "nofollow")),
+			Input(Attrs("type", "text", "name", "q", "required", "")),
+		), nil},
+		{"typo", Div(Attrs("clas", "a")), []string{`div: unknown attribute "clas" (did you mean "class"?)`}},
+		{"event typo", Button(Attrs("onlcick", "go()")), []string{`button: unknown attribute "onlcick" (did you mean "onclick"?)`}},
+		{"element specific", Div(Attrs("href", "/")), []string{`div: unknown attribute "href"`}},
+		{"no suggestion", Span(Attrs("frobnicate", "1")), []string{`span: unknown attribute "frobnicate"`}},
+		{"aria typo", Div(Attrs("aria-lable", "x")), []string{`div: unknown attribute "aria-lable" (did you mean "aria-label"?)`}},
+		{"invalid name", P(Attrs("a b", "1")), []string{`p: invalid attribute name "a b"`}},
+		{"paths", Html(Body(
+			Div(),
+			Div(Ul(Li(), Li(Attrs("hreff", "x")))),
+			Br(Attrs("clas", "x")),
+		)), []string{
+			`html/body/div[2]/ul/li[2]: unknown attribute "hreff"`,
+			`html/body/br: unknown attribute "clas" (did you mean "class"?)`,
+		}},
+		{"popover values", Div(
+			Button(Attrs("popovertarget", "m", "popovertargetaction", "open")),
+			Div(Attrs("id", "m", "popover", "Manual")),
+			Div(Attrs("popover", "sticky")),
+			Button(Attrs("command", "open-modal", "commandfor", "d")),
+			Button(Attrs("command", "--refresh", "commandfor", "d")),
+		), []string{
+			`div/button: invalid attribute value "popovertargetaction" = "open"`,
+			`div/div[2]: invalid attribute value "popover" = "sticky"`,
+			`div/button[2]: invalid attribute value "command" = "open-modal"`,
+		}},
+		{"skips foreign and custom elements", Div(
+			Svg(Attrs("viewBox", "0 0 1 1"), CustomElement("path", Attrs("d", "M0"))),
+			CustomElement("my-widget", Attrs("anything", "1")),
+		), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBuilder(tt.b)
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var verr ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("expected ValidationError, got %v", err)
+			}
+			if len(verr) != len(tt.expected) {
+				t.Fatalf("got %d errors, want %d: %v", len(verr), len(tt.expected), err)
+			}
+			for i, want := range tt.expected {
+				if got := verr[i].Error(); got != want {
+					t.Errorf("error %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateBuilderSentinels(t *testing.T) {
+	err := ValidateBuilder(Div(Attrs("clas", "a")))
+	if !errors.Is(err, ErrUnknownAttribute) {
+		t.Errorf("expected ErrUnknownAttribute, got %v", err)
+	}
+	if ValidateBuilder(nil) != nil {
+		t.Error("expected nil for nil builder")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"class", "class", 0},
+		{"clas", "class", 1},
+		{"onlcick", "onclick", 1},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
@@ -3,10 +3,16 @@
 package h
 
 import (
+	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 	"sync"
+
+	"github.com/jeffh/htmlgen/config"
 )
 
 // ErrUnknownTagToClose is returned when attempting to close a tag that was not opened.
@@ -22,8 +28,9 @@ var writerPool = sync.Pool{
 // getPooledWriter returns a Writer from the pool, configured to write to w.
 func getPooledWriter(w io.Writer) *Writer {
 	writer := writerPool.Get().(*Writer)
-	writer.w = w
+	writer.setOutput(w)
 	writer.atLineStart = true
+	writer.applyConfig(config.Default())
 	return writer
 }
 
@@ -35,13 +42,72 @@ func putPooledWriter(w *Writer) {
 	w.openTags = w.openTags[:0]
 	w.atLineStart = false
 	w.maxLineLen = 0
+	w.onTag = nil
+	w.ctx = nil
+	w.buf = nil
+	w.bufDst = nil
+	w.baseURL = ""
+	w.strict = false
+	w.nonce = nil
+	w.rand = nil
+	w.resilient = false
+	w.fallback = nil
+	w.out = trackingWriter{}
+	w.recovered = nil
+	w.hooks = nil
+	w.openAttrs = w.openAttrs[:0]
+	w.rootAttrs = nil
+	w.newline = ""
+	w.trailingNL = false
+	w.duplicates = config.KeepAll
+	w.escape = 0
+	w.components = w.components[:0]
 	writerPool.Put(w)
 }
 
 // NewWriter creates a new Writer that wraps the provided io.Writer.
 // The Writer tracks open tags and provides methods for writing HTML elements.
+// It starts with the settings of config.Default.
 func NewWriter(w io.Writer) *Writer {
-	return &Writer{w: w, openTags: make([]string, 0, 32), atLineStart: true}
+	writer := &Writer{openTags: make([]string, 0, 32), atLineStart: true}
+	writer.setOutput(w)
+	writer.applyConfig(config.Default())
+	return writer
+}
+
+// DefaultBufferSize is the buffer size used by NewBufferedWriter when size <= 0.
+const DefaultBufferSize = 4096
+
+// NewBufferedWriter creates a Writer that coalesces its many small writes
+// (one per tag delimiter, name, and attribute piece) in an internal buffer of
+// size bytes, writing to w only when the buffer fills or on Flush. This cuts
+// system calls when writing directly to a net.Conn or unbuffered file.
+//
+// Callers must call Flush (or Close) after writing to send buffered output:
+//
+//	w := h.NewBufferedWriter(conn, 0)
+//	if err := page.Build(w); err != nil {
+//	    return err
+//	}
+//	return w.Flush()
+func NewBufferedWriter(w io.Writer, size int) *Writer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	buf := bufio.NewWriterSize(w, size)
+	writer := &Writer{buf: buf, bufDst: w, openTags: make([]string, 0, 32), atLineStart: true}
+	writer.setOutput(buf)
+	writer.applyConfig(config.Default())
+	return writer
+}
+
+// Flush writes any buffered output to the underlying io.Writer.
+// It is a no-op for unbuffered Writers.
+func (w *Writer) Flush() error {
+	if w.buf == nil {
+		return nil
+	}
+	return w.buf.Flush()
 }
 
 // Writer is a low-level streaming HTML writer that wraps an io.Writer.
@@ -54,6 +120,28 @@ type Writer struct {
 	openTags    []string
 	atLineStart bool // Tracks if we're at the beginning of a line
 	maxLineLen  int  // Max line length before wrapping attributes (0 = disabled)
+	ctx         context.Context
+	buf         *bufio.Writer                // Non-nil for buffered Writers (see NewBufferedWriter)
+	bufDst      io.Writer                    // Writer that buf flushes to
+	baseURL     string                       // Prefix for root-relative URLs (see SetBaseURL)
+	strict      bool                         // Reject unsafe Untrusted values (see SetStrictUntrusted)
+	nonce       func(context.Context) string // Nonce for <script> and <style> (see config.Nonce)
+	rand        io.Reader                    // Source for NewID (see config.Rand)
+	resilient   bool                         // Recover from child errors (see SetErrorFallback)
+	fallback    func(error) Builder
+	out         trackingWriter // w when set with setOutput; counts bytes and records write errors
+	recovered   []error
+	hooks       []ElementHook          // See AddElementHook
+	openAttrs   []Attributes           // Attributes of openTags, kept while hooks are set
+	rootAttrs   Attributes             // Added to the next element opened (see RootAttrs)
+	newline     string                 // Line ending (see SetNewline); empty means LF
+	trailingNL  bool                   // End documents with a newline (see config.TrailingNewline)
+	duplicates  config.DuplicatePolicy // See SetDuplicateAttrs
+	escape      config.EscapePolicy    // See SetEscape
+	components  []string               // Names of the Named builders being built
+
+	// onTag, if set, is called before each tag is written (see ValidateBuilder).
+	onTag func(w *Writer, name string, as Attributes, void bool)
 }
 
 // SetIndent sets the indentation prefix used for pretty-printing.
@@ -63,6 +151,40 @@ func (w *Writer) SetIndent(prefix string) {
 	w.indent = prefix
 }
 
+// SetNewline sets the line ending written after the doctype and, when
+// pretty-printing, between tags: config.LF (the default) or config.CRLF.
+// An empty nl selects LF.
+func (w *Writer) SetNewline(nl string) {
+	w.newline = nl
+}
+
+// nl returns the line ending to write.
+func (w *Writer) nl() string {
+	if w.newline == "" {
+		return config.LF
+	}
+	return w.newline
+}
+
+// SetDuplicateAttrs sets how OpenTag and SelfClosingTag write an attribute
+// that appears more than once in their Attributes, after element hooks
+// run. Tag functions already combine repeated Attribute arguments, so
+// duplicates come from Attributes literals, appends, and hooks. The
+// default, config.KeepAll, writes every occurrence.
+func (w *Writer) SetDuplicateAttrs(policy config.DuplicatePolicy) {
+	w.duplicates = policy
+}
+
+// SetEscape sets the escaping Text, TextReader, and attribute values get
+// beyond &, <, >, ", and ', such as config.EscapeControl, or
+// config.EscapeStrict for every rule. Text inside <script> and <style>
+// uses backslash escapes instead of character references. Raw content and
+// the static parts of compiled templates are not affected; compiled
+// parameter values are.
+func (w *Writer) SetEscape(policy config.EscapePolicy) {
+	w.escape = policy
+}
+
 // SetMaxLineLength sets the maximum line length before wrapping attributes
 // to new lines. When set to 0 (default), attributes are never wrapped.
 // When the combined tag + attributes would exceed this length, additional
@@ -71,6 +193,134 @@ func (w *Writer) SetMaxLineLength(maxLen int) {
 	w.maxLineLen = maxLen
 }
 
+// SetContext sets the context available to builders through Context.
+// A Config attached with config.WithContext replaces the Writer's settings,
+// a base URL set on ctx with WithBaseURL is applied as if by SetBaseURL, and
+// a nonce set with WithNonce replaces config.Nonce.
+func (w *Writer) SetContext(ctx context.Context) {
+	w.ctx = ctx
+	if c, ok := config.FromContext(ctx); ok {
+		w.applyConfig(c)
+	}
+	if fb, ok := ctx.Value(errorFallbackKey{}).(errorFallback); ok {
+		w.SetErrorFallback(fb.fn)
+	}
+	if base := BaseURL(ctx); base != "" {
+		w.SetBaseURL(base)
+	}
+	if StrictUntrusted(ctx) {
+		w.SetStrictUntrusted(true)
+	}
+	if Nonce(ctx) != "" {
+		w.nonce = Nonce
+	}
+	if sf, _ := ctx.Value(sharedFragmentKey{}).(sharedFragment); sf.nonce {
+		w.nonce = Nonce // Reached by withNonce, which rejects the element
+	}
+	if hooks, _ := ctx.Value(elementHooksKey{}).([]ElementHook); len(hooks) > 0 {
+		w.hooks = append(w.hooks, hooks...)
+	}
+}
+
+// SetStrictUntrusted makes OpenTag and SelfClosingTag return an error
+// wrapping ErrUntrustedAttribute, without writing the tag, when an
+// Untrusted value is used in a URL attribute (href, src, action, hx-get,
+// ...), an event handler (on*, hx-on*, data-on*), srcdoc, or style.
+func (w *Writer) SetStrictUntrusted(strict bool) {
+	w.strict = strict
+}
+
+// SetBaseURL prefixes root-relative URLs ("/path", but not "//host/path")
+// written in URL attributes with base, which may be a path ("/acme") or an
+// absolute URL ("https://cdn.example.com/acme"). It rewrites href, src,
+// action, formaction, poster, cite, data, srcset, the hx-get family,
+// hx-push-url, hx-replace-url, and the URLs of Datastar actions such as
+// @get('/path') in data-* attributes. URLs already under base are left
+// unchanged, so prefixing is idempotent. An empty base disables rewriting.
+func (w *Writer) SetBaseURL(base string) {
+	w.baseURL = strings.TrimSuffix(base, "/")
+}
+
+// Context returns the render context set with SetContext or RenderContext,
+// or context.Background() if none was set. Builders use it to access
+// request-scoped values such as the current user or locale.
+func (w *Writer) Context() context.Context {
+	if w.ctx == nil {
+		return context.Background()
+	}
+	return w.ctx
+}
+
+// setOutput makes the Writer write to dst through w.out.
+func (w *Writer) setOutput(dst io.Writer) {
+	w.out = trackingWriter{w: dst}
+	w.w = &w.out
+}
+
+// output returns the io.Writer the Writer writes to, or its buffer for
+// buffered Writers.
+func (w *Writer) output() io.Writer {
+	if w.w == &w.out {
+		return w.out.w
+	}
+	return w.w
+}
+
+// Depth returns the number of elements currently open.
+func (w *Writer) Depth() int { return len(w.openTags) }
+
+// OpenTags returns the names of the open elements, outermost first. The
+// result is a copy.
+func (w *Writer) OpenTags() []string { return slices.Clone(w.openTags) }
+
+// Components returns the names given with Named to the builders being
+// built, outermost first, for element hooks that attribute elements to
+// components. The result is a copy.
+func (w *Writer) Components() []string { return slices.Clone(w.components) }
+
+// BytesWritten returns the number of bytes written so far, including
+// bytes still held by a buffered Writer.
+func (w *Writer) BytesWritten() int64 { return w.out.n }
+
+// applyConfig replaces the Writer's settings with those of c.
+func (w *Writer) applyConfig(c *config.Config) {
+	if w.indent != c.Indent {
+		w.indent = c.Indent
+		w.indentCache = nil
+	}
+	w.maxLineLen = c.MaxLineLength
+	w.strict = c.StrictUntrusted
+	w.SetBaseURL(c.BaseURL)
+	w.nonce = c.Nonce
+	w.rand = c.Rand
+	w.SetNewline(c.Newline)
+	w.trailingNL = c.TrailingNewline
+	w.duplicates = c.DuplicateAttrs
+	w.escape = c.Escape
+}
+
+// withNonce adds the configured nonce to the attributes of a <script> or
+// <style> element that has none. It fails inside shared fragments, whose
+// output would carry the nonce to other renders.
+func (w *Writer) withNonce(name string, as Attributes) (Attributes, error) {
+	if name != "script" && name != "style" {
+		return as, nil
+	}
+	for _, attr := range as {
+		if attr.Name == "nonce" {
+			return as, nil
+		}
+	}
+	if sf, _ := w.Context().Value(sharedFragmentKey{}).(sharedFragment); sf.nonce {
+		return nil, fmt.Errorf("%w: nonce for <%s>", ErrSharedFragment, name)
+	}
+	nonce := w.nonce(w.Context())
+	if nonce == "" {
+		return as, nil
+	}
+	return append(as[:len(as):len(as)], Attribute{Name: "nonce", Value: nonce}), nil
+}
+
 func (w *Writer) isIndenting() bool { return len(w.indent) != 0 }
 
 func (w *Writer) write(values ...string) error {
@@ -84,11 +334,11 @@ func (w *Writer) write(values ...string) error {
 }
 
 // Doctype writes the HTML5 doctype declaration (<!DOCTYPE html>).
-func (w *Writer) Doctype() error { return w.write("<!DOCTYPE html>\n") }
+func (w *Writer) Doctype() error { return w.write("<!DOCTYPE html>", w.nl()) }
 
 func (w *Writer) writeIndentNewline() error {
 	if w.isIndenting() {
-		if err := w.write("\n"); err != nil {
+		if err := w.write(w.nl()); err != nil {
 			return err
 		}
 		w.atLineStart = true
@@ -146,6 +396,17 @@ func (w *Writer) writeAttrs(as Attributes, lineLen int) (int, error) {
 		if attr.Name == "" {
 			continue
 		}
+		if attr.param != nil {
+			if cw, ok := w.output().(*compileWriter); ok {
+				if err := w.writeAttrParam(cw, attr); err != nil {
+					return lineLen, err
+				}
+				continue
+			}
+		}
+		if w.baseURL != "" {
+			attr.Value = w.rewriteURLAttr(attr.Name, attr.Value)
+		}
 
 		aLen := attrLen(attr)
 		wrapped := false
@@ -153,7 +414,7 @@ func (w *Writer) writeAttrs(as Attributes, lineLen int) (int, error) {
 		// Check if we need to wrap
 		if w.maxLineLen > 0 && lineLen+aLen > w.maxLineLen {
 			// Wrap: newline + extra indent (one deeper than current tag)
-			if _, err := io.WriteString(w.w, "\n"); err != nil {
+			if _, err := io.WriteString(w.w, w.nl()); err != nil {
 				return lineLen, err
 			}
 			// Write indent at depth+1 (inside the tag for attributes)
@@ -182,7 +443,7 @@ func (w *Writer) writeAttrs(as Attributes, lineLen int) (int, error) {
 			if _, err := io.WriteString(w.w, "=\""); err != nil {
 				return lineLen, err
 			}
-			if err := writeEscapedString(w.w, attr.Value); err != nil {
+			if err := w.writeEscaped(attr.Value, true); err != nil {
 				return lineLen, err
 			}
 			if _, err := io.WriteString(w.w, "\""); err != nil {
@@ -199,9 +460,48 @@ func (w *Writer) writeAttrs(as Attributes, lineLen int) (int, error) {
 	return lineLen, nil
 }
 
+// writeAttrParam writes attr, whose value is an AttrParam, for
+// CompileParams: the static value, if any, followed by the slot.
+func (w *Writer) writeAttrParam(cw *compileWriter, attr Attribute) error {
+	if _, err := io.WriteString(w.w, " "+attr.Name+"=\""); err != nil {
+		return err
+	}
+	if attr.Value != "" {
+		if err := w.writeEscaped(attr.Value+" ", true); err != nil {
+			return err
+		}
+	}
+	cw.recordSlot(templateSlot{attr: attr.param, attrName: attr.Name})
+	_, err := io.WriteString(w.w, "\"")
+	return err
+}
+
 // SelfClosingTag writes a self-closing HTML tag with the given name and attributes.
 // For example, SelfClosingTag("br", nil) writes "<br/>".
 func (w *Writer) SelfClosingTag(name string, as Attributes) error {
+	if w.rootAttrs != nil {
+		as = w.withRootAttrs(as)
+	}
+	if len(w.hooks) > 0 {
+		var err error
+		if as, err = w.beforeOpen(name, as); err != nil {
+			return err
+		}
+	}
+	if w.duplicates != config.KeepAll {
+		var err error
+		if as, err = resolveDuplicates(name, as, w.duplicates); err != nil {
+			return err
+		}
+	}
+	if w.onTag != nil {
+		w.onTag(w, name, as, true)
+	}
+	if w.strict {
+		if err := checkUntrusted(name, as); err != nil {
+			return err
+		}
+	}
 	if err := w.writeIndent(0); err != nil {
 		return err
 	}
@@ -228,13 +528,48 @@ func (w *Writer) SelfClosingTag(name string, as Attributes) error {
 	if _, err := io.WriteString(w.w, "/>"); err != nil {
 		return err
 	}
-	return w.writeIndentNewline()
+	if err := w.writeIndentNewline(); err != nil {
+		return err
+	}
+	if len(w.hooks) > 0 {
+		return w.afterClose(name, as)
+	}
+	return nil
 }
 
 // OpenTag writes an opening HTML tag with the given name and attributes.
 // The tag is added to the stack of open tags and must be closed with CloseTag,
 // CloseOneTag, or Close. Attribute values are automatically HTML-escaped.
 func (w *Writer) OpenTag(name string, as Attributes) error {
+	if w.rootAttrs != nil {
+		as = w.withRootAttrs(as)
+	}
+	if w.nonce != nil {
+		var err error
+		if as, err = w.withNonce(name, as); err != nil {
+			return err
+		}
+	}
+	if len(w.hooks) > 0 {
+		var err error
+		if as, err = w.beforeOpen(name, as); err != nil {
+			return err
+		}
+	}
+	if w.duplicates != config.KeepAll {
+		var err error
+		if as, err = resolveDuplicates(name, as, w.duplicates); err != nil {
+			return err
+		}
+	}
+	if w.onTag != nil {
+		w.onTag(w, name, as, false)
+	}
+	if w.strict {
+		if err := checkUntrusted(name, as); err != nil {
+			return err
+		}
+	}
 	if err := w.writeIndent(0); err != nil {
 		return err
 	}
@@ -266,10 +601,21 @@ func (w *Writer) OpenTag(name string, as Attributes) error {
 		return err
 	}
 	w.openTags = append(w.openTags, name)
+	if len(w.hooks) > 0 {
+		// Pad for tags opened before the first hook was added.
+		for len(w.openAttrs) < len(w.openTags)-1 {
+			w.openAttrs = append(w.openAttrs, nil)
+		}
+		w.openAttrs = append(w.openAttrs[:len(w.openTags)-1], as)
+	}
 	return nil
 }
 
 // Text writes HTML-escaped text content.
+// Inside <script> and <style>, whose content browsers do not decode, the
+// text is written as-is instead, except that a closing </script> or
+// </style> and, in scripts, <!-- are escaped with a backslash so the text
+// cannot end the element: h.Script(h.Text(js)) yields valid JavaScript.
 // When indentation is enabled, text is indented at the current content depth
 // and followed by a newline.
 func (w *Writer) Text(txt string) error {
@@ -278,7 +624,11 @@ func (w *Writer) Text(txt string) error {
 			return err
 		}
 	}
-	if err := writeEscapedString(w.w, txt); err != nil {
+	if n := len(w.openTags); n > 0 && isRawTextElement(w.openTags[n-1]) {
+		if err := w.writeRawText(w.openTags[n-1], txt); err != nil {
+			return err
+		}
+	} else if err := w.writeEscaped(txt, false); err != nil {
 		return err
 	}
 	if w.isIndenting() {
@@ -310,7 +660,7 @@ func (w *Writer) CloseTag(name string) error {
 	}
 	// Ensure we're on a new line before closing tag
 	if w.isIndenting() && !w.atLineStart {
-		if err := w.write("\n"); err != nil {
+		if err := w.write(w.nl()); err != nil {
 			return err
 		}
 		w.atLineStart = true
@@ -334,6 +684,11 @@ func (w *Writer) CloseTag(name string) error {
 			if err := w.writeIndentNewline(); err != nil {
 				return err
 			}
+			for j := size - 1; j >= i; j-- {
+				if err := w.closedTag(j); err != nil {
+					return err
+				}
+			}
 			w.openTags = w.openTags[:i]
 			break
 		}
@@ -350,7 +705,7 @@ func (w *Writer) CloseOneTag() error {
 	}
 	// Ensure we're on a new line before closing tag
 	if w.isIndenting() && !w.atLineStart {
-		if err := w.write("\n"); err != nil {
+		if err := w.write(w.nl()); err != nil {
 			return err
 		}
 		w.atLineStart = true
@@ -370,16 +725,20 @@ func (w *Writer) CloseOneTag() error {
 	if err := w.writeIndentNewline(); err != nil {
 		return err
 	}
+	if err := w.closedTag(size - 1); err != nil {
+		return err
+	}
 	w.openTags = w.openTags[:size-1]
 	return nil
 }
 
 // Close closes all remaining open tags in reverse order (most recent first).
+// Buffered Writers are flushed afterwards.
 func (w *Writer) Close() error {
 	for i := len(w.openTags) - 1; i >= 0; i-- {
 		// Ensure we're on a new line before closing tag
 		if w.isIndenting() && !w.atLineStart {
-			if err := w.write("\n"); err != nil {
+			if err := w.write(w.nl()); err != nil {
 				return err
 			}
 			w.atLineStart = true
@@ -399,9 +758,13 @@ func (w *Writer) Close() error {
 		if err := w.writeIndentNewline(); err != nil {
 			return err
 		}
+		if err := w.closedTag(i); err != nil {
+			return err
+		}
 	}
 	w.openTags = nil
-	return nil
+	w.openAttrs = w.openAttrs[:0]
+	return w.Flush()
 }
 
 // copied from text/template.HTMLEscape so we can return errors
@@ -414,31 +777,25 @@ var (
 	htmlNull = []byte("\uFFFD")
 )
 
+// htmlEscapes maps each byte to its index in htmlReplacements, or 0 if it
+// is written as is. A table lookup is cheaper than a switch in the scan
+// loop, which dominates escaping since most text needs no escaping.
+var htmlEscapes = [256]uint8{0: 1, '"': 2, '\'': 3, '&': 4, '<': 5, '>': 6}
+
+var htmlReplacements = [...][]byte{nil, htmlNull, htmlQuot, htmlApos, htmlAmp, htmlLt, htmlGt}
+
 // writeHTMLEscape writes to w the escaped HTML equivalent of the plain text data b.
 func writeHTMLEscape(w io.Writer, b []byte) error {
 	last := 0
 	for i, c := range b {
-		var html []byte
-		switch c {
-		case '\000':
-			html = htmlNull
-		case '"':
-			html = htmlQuot
-		case '\'':
-			html = htmlApos
-		case '&':
-			html = htmlAmp
-		case '<':
-			html = htmlLt
-		case '>':
-			html = htmlGt
-		default:
+		r := htmlEscapes[c]
+		if r == 0 {
 			continue
 		}
 		if _, err := w.Write(b[last:i]); err != nil {
 			return err
 		}
-		if _, err := w.Write(html); err != nil {
+		if _, err := w.Write(htmlReplacements[r]); err != nil {
 			return err
 		}
 		last = i + 1
@@ -0,0 +1,28 @@
+// Package httpsec derives HTTP security headers from the HTML a page actually renders.
+//
+// Rather than hand-maintaining a Content-Security-Policy that drifts out of sync
+// with the markup, httpsec inspects rendered output and computes the minimal set
+// of sources the page needs:
+//   - External script, style, image, frame, and form hosts become CSP sources
+//   - Inline event handlers (onclick, ...) and style attributes are allowed by hash
+//   - Inline <script> and <style> blocks are allowed by hash or by the policy nonce
+//   - Datastar and HTMX expression attributes enable 'unsafe-eval' only when present
+//
+// It also emits Referrer-Policy, X-Content-Type-Options, and Permissions-Policy.
+//
+// Basic usage:
+//
+//	nonce, err := httpsec.NewNonce(nil)
+//	if err != nil {
+//	    // handle error
+//	}
+//	policy := httpsec.Policy{Nonce: nonce}
+//	if err := policy.Render(w, page); err != nil {
+//	    // handle error
+//	}
+//
+// Or analyze pre-rendered bytes:
+//
+//	report := httpsec.Analyze(html)
+//	policy.Apply(w.Header(), report)
+package httpsec
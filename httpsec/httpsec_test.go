@@ -0,0 +1,190 @@
+package httpsec
+
+import (
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/config"
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestAnalyzeHosts(t *testing.T) {
+	page := h.Html(
+		h.Head(
+			h.Script(h.Attrs("src", "https://cdn.example.com/app.js")),
+			h.Script(h.Attrs("src", "/local.js")),
+			h.Link(h.Attrs("rel", "stylesheet", "href", "https://fonts.example.com/a.css")),
+			h.Link(h.Attrs("rel", "icon", "href", "https://icons.example.com/i.png")),
+		),
+		h.Body(
+			h.Img(h.Attrs("src", "https://img.example.com/a.png")),
+			h.Img(h.Attrs("src", "//img2.example.com/b.png")),
+			h.Iframe(h.Attrs("src", "https://www.youtube.com/embed/x")),
+			h.Form(h.Attrs("action", "https://pay.example.com/submit")),
+			h.Button(h.Attrs("hx-post", "https://api.example.com/items")),
+		),
+	)
+	r := Analyze([]byte(h.RenderString(page)))
+
+	tests := []struct {
+		name     string
+		got      []string
+		expected []string
+	}{
+		{"ScriptHosts", r.ScriptHosts, []string{"https://cdn.example.com"}},
+		{"StyleHosts", r.StyleHosts, []string{"https://fonts.example.com"}},
+		{"ImageHosts", r.ImageHosts, []string{"https://img.example.com", "img2.example.com"}},
+		{"FrameHosts", r.FrameHosts, []string{"https://www.youtube.com"}},
+		{"FormHosts", r.FormHosts, []string{"https://pay.example.com"}},
+		{"ConnectHosts", r.ConnectHosts, []string{"https://api.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !slices.Equal(tt.got, tt.expected) {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.expected)
+			}
+		})
+	}
+	if r.HasInlineHandlers() || r.UsesEval || len(r.InlineScriptHashes) > 0 {
+		t.Errorf("unexpected inline usage: %+v", r)
+	}
+}
+
+func TestAnalyzeInline(t *testing.T) {
+	page := h.Div(
+		h.Button(h.Attrs("onclick", `alert("hi")`)),
+		h.Script(h.Raw("console.log(1)")),
+		h.Script(h.Attrs("nonce", "abc"), h.Raw("console.log(2)")),
+		h.Script(h.Attrs("type", "application/json"), h.Raw(`{"a":1}`)),
+		h.Style(h.Raw("p{color:red}")),
+		h.P(h.Attrs("style", "color: blue")),
+	)
+	r := Analyze([]byte(h.RenderString(page)))
+
+	if got := r.InlineHandlerHashes; !slices.Equal(got, []string{hashSource([]byte(`alert("hi")`))}) {
+		t.Errorf("InlineHandlerHashes = %v", got)
+	}
+	if got := r.InlineScriptHashes; !slices.Equal(got, []string{hashSource([]byte("console.log(1)"))}) {
+		t.Errorf("InlineScriptHashes = %v", got)
+	}
+	if got := r.InlineStyleHashes; !slices.Equal(got, []string{hashSource([]byte("p{color:red}"))}) {
+		t.Errorf("InlineStyleHashes = %v", got)
+	}
+	if got := r.StyleAttrHashes; !slices.Equal(got, []string{hashSource([]byte("color: blue"))}) {
+		t.Errorf("StyleAttrHashes = %v", got)
+	}
+}
+
+func TestAnalyzeEval(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    h.Attributes
+		expected bool
+	}{
+		{"plain", h.Attrs("class", "x"), false},
+		{"datastar on", h.Attrs("data-on:click", "$x++"), true},
+		{"datastar signals", h.Attrs("data-signals:x", "1"), true},
+		{"htmx on", h.Attrs("hx-on:click", "go()"), true},
+		{"hx-vals js", h.Attrs("hx-vals", "js:{a: 1}"), true},
+		{"hx-vals json", h.Attrs("hx-vals", `{"a": 1}`), false},
+		{"data attribute", h.Attrs("data-id", "1"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Analyze([]byte(h.RenderString(h.Div(tt.attrs))))
+			if r.UsesEval != tt.expected {
+				t.Errorf("UsesEval = %v, want %v", r.UsesEval, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPolicyCSP(t *testing.T) {
+	p := Policy{Nonce: "n0nce"}
+	r := Report{
+		ScriptHosts:         []string{"https://cdn.example.com"},
+		InlineHandlerHashes: []string{"'sha256-abc'"},
+		UsesEval:            true,
+	}
+	csp := p.CSP(r)
+	for _, want := range []string{
+		"default-src 'self'",
+		"script-src 'self' 'nonce-n0nce' https://cdn.example.com 'unsafe-hashes' 'sha256-abc' 'unsafe-eval'",
+		"style-src 'self' 'nonce-n0nce'",
+		"img-src 'self' data:",
+		"object-src 'none'",
+	} {
+		if !strings.Contains(csp, want) {
+			t.Errorf("CSP missing %q:\n%s", want, csp)
+		}
+	}
+	if strings.Contains(csp, "frame-src") {
+		t.Errorf("CSP should not include frame-src without frames:\n%s", csp)
+	}
+}
+
+func TestPolicyCSPStrictByDefault(t *testing.T) {
+	var p Policy
+	csp := p.CSP(Report{})
+	if strings.Contains(csp, "unsafe") {
+		t.Errorf("empty report should produce no unsafe sources: %s", csp)
+	}
+}
+
+func TestPolicyPermissionsPolicy(t *testing.T) {
+	p := Policy{Permissions: map[string]string{"camera": "(self)", "usb": "", "fullscreen": "*"}}
+	got := p.PermissionsPolicy()
+	expected := "camera=(self), fullscreen=*, geolocation=(), interest-cohort=(), microphone=(), payment=()"
+	if got != expected {
+		t.Errorf("PermissionsPolicy() = %q, want %q", got, expected)
+	}
+}
+
+func TestPolicyRender(t *testing.T) {
+	p := Policy{ReportOnly: true, ReferrerPolicy: "no-referrer"}
+	rec := httptest.NewRecorder()
+	page := h.Button(h.Attrs("onclick", "go()"), h.Text("Go"))
+	if err := p.Render(rec, page); err != nil {
+		t.Fatal(err)
+	}
+	hdr := rec.Header()
+	if hdr.Get(HeaderCSP) != "" {
+		t.Error("expected CSP only in report-only header")
+	}
+	if !strings.Contains(hdr.Get(HeaderCSPReportOnly), hashSource([]byte("go()"))) {
+		t.Errorf("report-only CSP missing handler hash: %s", hdr.Get(HeaderCSPReportOnly))
+	}
+	if got := hdr.Get(HeaderReferrerPolicy); got != "no-referrer" {
+		t.Errorf("Referrer-Policy = %q", got)
+	}
+	if got := hdr.Get(HeaderContentTypeOptions); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q", got)
+	}
+	if got := hdr.Get("Content-Type"); got != "text/html; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := rec.Body.String(); got != `<button onclick="go()">Go</button>` {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	a, err := NewNonce(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := NewNonce(nil)
+	if len(a) != 24 || a == b {
+		t.Errorf("NewNonce(nil) = %q, %q; want distinct 24-character nonces", a, b)
+	}
+	s1, _ := NewNonce(config.Seeded(7))
+	s2, _ := NewNonce(config.Seeded(7))
+	if s1 != s2 {
+		t.Errorf("seeded nonces differ: %q, %q", s1, s2)
+	}
+	if _, err := NewNonce(strings.NewReader("short")); err == nil {
+		t.Error("expected error from a short reader")
+	}
+}
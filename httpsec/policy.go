@@ -0,0 +1,208 @@
+package httpsec
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Header names set by Policy.
+const (
+	HeaderCSP                = "Content-Security-Policy"
+	HeaderCSPReportOnly      = "Content-Security-Policy-Report-Only"
+	HeaderReferrerPolicy     = "Referrer-Policy"
+	HeaderContentTypeOptions = "X-Content-Type-Options"
+	HeaderPermissionsPolicy  = "Permissions-Policy"
+)
+
+// DefaultReferrerPolicy is used when Policy.ReferrerPolicy is empty.
+const DefaultReferrerPolicy = "strict-origin-when-cross-origin"
+
+// DefaultPermissions denies powerful browser features that server-rendered
+// pages rarely need. Override individual features with Policy.Permissions.
+var DefaultPermissions = map[string]string{
+	"camera":          "()",
+	"geolocation":     "()",
+	"microphone":      "()",
+	"payment":         "()",
+	"usb":             "()",
+	"interest-cohort": "()",
+}
+
+// Policy configures how security headers are derived from a Report.
+// The zero value produces a strict same-origin policy.
+type Policy struct {
+	// Nonce is added to script-src and style-src as 'nonce-<value>'.
+	// Inline <script> and <style> elements carrying a matching nonce
+	// attribute are then allowed without hashing.
+	Nonce string
+
+	// Extra sources appended to the derived directives, for resources loaded
+	// dynamically that cannot be discovered from markup.
+	ScriptHosts  []string
+	StyleHosts   []string
+	ImageHosts   []string
+	ConnectHosts []string
+	FontHosts    []string
+	FrameHosts   []string
+
+	// ReferrerPolicy overrides DefaultReferrerPolicy when non-empty.
+	ReferrerPolicy string
+
+	// Permissions overrides or extends DefaultPermissions. A value of ""
+	// removes the feature from the header.
+	Permissions map[string]string
+
+	// ReportURI adds a report-uri directive when non-empty.
+	ReportURI string
+
+	// ReportOnly sends the CSP as Content-Security-Policy-Report-Only.
+	ReportOnly bool
+}
+
+// CSP returns the Content-Security-Policy value for a page described by r.
+func (p *Policy) CSP(r Report) string {
+	var nonce string
+	if p.Nonce != "" {
+		nonce = "'nonce-" + p.Nonce + "'"
+	}
+
+	script := sources([]string{"'self'", nonce}, r.ScriptHosts, p.ScriptHosts, r.InlineScriptHashes)
+	if r.HasInlineHandlers() {
+		script = append(script, "'unsafe-hashes'")
+		script = append(script, r.InlineHandlerHashes...)
+	}
+	if r.UsesEval {
+		script = append(script, "'unsafe-eval'")
+	}
+
+	style := sources([]string{"'self'", nonce}, r.StyleHosts, p.StyleHosts, r.InlineStyleHashes)
+	if len(r.StyleAttrHashes) > 0 {
+		style = append(style, "'unsafe-hashes'")
+		style = append(style, r.StyleAttrHashes...)
+	}
+
+	directives := [][]string{
+		{"default-src", "'self'"},
+		append([]string{"script-src"}, script...),
+		append([]string{"style-src"}, style...),
+		append([]string{"img-src"}, sources([]string{"'self'", "data:"}, r.ImageHosts, p.ImageHosts)...),
+		append([]string{"connect-src"}, sources([]string{"'self'"}, r.ConnectHosts, p.ConnectHosts)...),
+		append([]string{"font-src"}, sources([]string{"'self'"}, p.FontHosts)...),
+		append([]string{"form-action"}, sources([]string{"'self'"}, r.FormHosts)...),
+		{"object-src", "'none'"},
+		{"base-uri", "'self'"},
+	}
+	if frames := sources(nil, r.FrameHosts, p.FrameHosts); len(frames) > 0 {
+		directives = append(directives, append([]string{"frame-src", "'self'"}, frames...))
+	}
+	if p.ReportURI != "" {
+		directives = append(directives, []string{"report-uri", p.ReportURI})
+	}
+
+	parts := make([]string, len(directives))
+	for i, d := range directives {
+		parts[i] = strings.Join(d, " ")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// PermissionsPolicy returns the Permissions-Policy header value.
+// Features are sorted for deterministic output.
+func (p *Policy) PermissionsPolicy() string {
+	perms := maps.Clone(DefaultPermissions)
+	for k, v := range p.Permissions {
+		if v == "" {
+			delete(perms, k)
+		} else {
+			perms[k] = v
+		}
+	}
+	keys := slices.Sorted(maps.Keys(perms))
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + perms[k]
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Apply sets the security headers for a page described by r on hdr.
+func (p *Policy) Apply(hdr http.Header, r Report) {
+	cspHeader := HeaderCSP
+	if p.ReportOnly {
+		cspHeader = HeaderCSPReportOnly
+	}
+	hdr.Set(cspHeader, p.CSP(r))
+
+	referrer := p.ReferrerPolicy
+	if referrer == "" {
+		referrer = DefaultReferrerPolicy
+	}
+	hdr.Set(HeaderReferrerPolicy, referrer)
+	hdr.Set(HeaderContentTypeOptions, "nosniff")
+	if pp := p.PermissionsPolicy(); pp != "" {
+		hdr.Set(HeaderPermissionsPolicy, pp)
+	}
+}
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// Render renders b, derives security headers from the output, and writes
+// both the headers and the body to w. Headers must be computed from the
+// exact bytes sent to the browser for hashes to match, so the page is
+// buffered before writing. Returns nil if b is nil.
+func (p *Policy) Render(w http.ResponseWriter, b h.Builder) error {
+	if b == nil {
+		return nil
+	}
+	buf := bufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer bufPool.Put(buf)
+	if err := h.Render(buf, b); err != nil {
+		return err
+	}
+	p.Apply(w.Header(), Analyze(buf.Bytes()))
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// sources concatenates source lists, dropping empty entries and duplicates
+// while preserving first-seen order.
+func sources(lists ...[]string) []string {
+	var out []string
+	for _, list := range lists {
+		for _, s := range list {
+			if s != "" && !slices.Contains(out, s) {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
+
+// NewNonce returns a base64-encoded 128-bit nonce for Policy.Nonce, read
+// from rnd, or from crypto/rand when rnd is nil. Servers should pass nil;
+// static builds that need reproducible output can pass config.Seeded.
+func NewNonce(rnd io.Reader) (string, error) {
+	if rnd == nil {
+		rnd = rand.Reader
+	}
+	var b [16]byte
+	if _, err := io.ReadFull(rnd, b[:]); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b[:]), nil
+}
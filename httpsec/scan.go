@@ -0,0 +1,199 @@
+package httpsec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/jeffh/htmlgen/internal/htmlscan"
+)
+
+// Report summarizes the security-relevant features used by a rendered page.
+// Host lists contain origins (scheme://host[:port]) and are sorted and deduplicated.
+type Report struct {
+	// ScriptHosts are origins referenced by <script src>.
+	ScriptHosts []string
+	// StyleHosts are origins referenced by <link rel="stylesheet" href>.
+	StyleHosts []string
+	// ImageHosts are origins referenced by <img src>, <source src>, and similar.
+	ImageHosts []string
+	// FrameHosts are origins referenced by <iframe src>.
+	FrameHosts []string
+	// FormHosts are origins referenced by <form action> and formaction attributes.
+	FormHosts []string
+	// ConnectHosts are origins referenced by hx-get/hx-post/... attributes.
+	ConnectHosts []string
+
+	// InlineScriptHashes are CSP hash sources for inline <script> bodies
+	// that do not carry a nonce attribute.
+	InlineScriptHashes []string
+	// InlineHandlerHashes are CSP hash sources for inline event handler attributes.
+	InlineHandlerHashes []string
+	// InlineStyleHashes are CSP hash sources for <style> bodies without a nonce.
+	InlineStyleHashes []string
+	// StyleAttrHashes are CSP hash sources for style="..." attributes.
+	StyleAttrHashes []string
+
+	// UsesEval is true when the page contains Datastar or HTMX expression
+	// attributes, which are evaluated with the Function constructor.
+	UsesEval bool
+}
+
+// HasInlineHandlers reports whether the page contains inline event handler attributes.
+func (r *Report) HasInlineHandlers() bool { return len(r.InlineHandlerHashes) > 0 }
+
+// imageTags lists elements whose src attribute loads an image-like resource.
+var imageTags = map[string]bool{"img": true, "source": true, "input": true, "video": true, "audio": true, "track": true}
+
+// hxRequestAttrs lists HTMX attributes that issue requests to a URL.
+var hxRequestAttrs = map[string]bool{"hx-get": true, "hx-post": true, "hx-put": true, "hx-patch": true, "hx-delete": true}
+
+// evalAttrPrefixes lists attribute prefixes whose values are evaluated as JavaScript.
+var evalAttrPrefixes = []string{
+	"hx-on",
+	"data-on",
+	"data-text",
+	"data-show",
+	"data-class",
+	"data-style",
+	"data-attr",
+	"data-computed",
+	"data-effect",
+	"data-init",
+	"data-signals",
+}
+
+// Analyze scans rendered HTML and reports the resources and inline code it uses.
+// The input is expected to be well-formed output from the h package, but the
+// scanner tolerates arbitrary HTML.
+func Analyze(doc []byte) Report {
+	var (
+		r    Report
+		sets = map[*[]string]map[string]bool{}
+	)
+	add := func(dst *[]string, v string) {
+		if v == "" {
+			return
+		}
+		set := sets[dst]
+		if set == nil {
+			set = map[string]bool{}
+			sets[dst] = set
+		}
+		if !set[v] {
+			set[v] = true
+			*dst = append(*dst, v)
+		}
+	}
+
+	htmlscan.Scan(doc, func(t htmlscan.Tag) {
+		if t.End {
+			return
+		}
+		src := t.Value("src")
+		hasNonce := t.Value("nonce") != ""
+		switch t.Name {
+		case "script":
+			if src != "" {
+				add(&r.ScriptHosts, origin(src))
+			} else if !hasNonce && len(bytes.TrimSpace(t.Body)) > 0 && isJavaScript(t.Value("type")) {
+				add(&r.InlineScriptHashes, hashSource(t.Body))
+			}
+		case "style":
+			if !hasNonce && len(t.Body) > 0 {
+				add(&r.InlineStyleHashes, hashSource(t.Body))
+			}
+		case "link":
+			if hasToken(t.Value("rel"), "stylesheet") {
+				add(&r.StyleHosts, origin(t.Value("href")))
+			}
+		case "iframe":
+			add(&r.FrameHosts, origin(src))
+		case "form":
+			add(&r.FormHosts, origin(t.Value("action")))
+		default:
+			if imageTags[t.Name] {
+				add(&r.ImageHosts, origin(src))
+			}
+		}
+
+		for _, a := range t.Attrs {
+			switch {
+			case a.Name == "style":
+				add(&r.StyleAttrHashes, hashSource([]byte(a.Value)))
+			case a.Name == "formaction":
+				add(&r.FormHosts, origin(a.Value))
+			case hxRequestAttrs[a.Name]:
+				add(&r.ConnectHosts, origin(a.Value))
+			case len(a.Name) > 2 && strings.HasPrefix(a.Name, "on"):
+				add(&r.InlineHandlerHashes, hashSource([]byte(a.Value)))
+			}
+			if !r.UsesEval {
+				for _, prefix := range evalAttrPrefixes {
+					if strings.HasPrefix(a.Name, prefix) {
+						r.UsesEval = true
+						break
+					}
+				}
+				if a.Name == "hx-vals" && strings.HasPrefix(a.Value, "js:") {
+					r.UsesEval = true
+				}
+			}
+		}
+	})
+
+	for _, s := range []*[]string{&r.ScriptHosts, &r.StyleHosts, &r.ImageHosts, &r.FrameHosts, &r.FormHosts, &r.ConnectHosts} {
+		slices.Sort(*s)
+	}
+	return r
+}
+
+// hashSource returns the CSP 'sha256-...' source expression for content.
+func hashSource(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+}
+
+// origin returns the scheme://host origin of an absolute or protocol-relative URL,
+// or an empty string for relative URLs (which are covered by 'self').
+func origin(raw string) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Scheme == "" {
+		return u.Host
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ws" && u.Scheme != "wss" {
+		return ""
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// isJavaScript reports whether a <script type> value denotes executable script.
+// Data blocks such as application/json and speculationrules are not executed
+// and therefore do not need CSP sources.
+func isJavaScript(typ string) bool {
+	switch strings.ToLower(strings.TrimSpace(typ)) {
+	case "", "text/javascript", "application/javascript", "module":
+		return true
+	}
+	return false
+}
+
+// hasToken reports whether the space-separated list contains tok (case-insensitive).
+func hasToken(list, tok string) bool {
+	for _, f := range strings.Fields(list) {
+		if strings.EqualFold(f, tok) {
+			return true
+		}
+	}
+	return false
+}
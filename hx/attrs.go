@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"strings"
 
+	"github.com/jeffh/htmlgen/csrf"
 	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
 )
 
 // Get creates an hx-get attribute that issues a GET request to the specified URL.
@@ -113,6 +115,21 @@ func HeadersJS(jsExpr string) h.Attribute {
 	return h.Attr("hx-headers", "js:"+jsExpr)
 }
 
+// CSRFHeaders creates an hx-headers attribute sending token in the
+// csrf.HeaderName header. hx-headers is inherited, so on <body> it covers
+// every htmx request on the page:
+//
+//	h.Body(hx.CSRFHeaders(csrf.Token(r.Context())), ...)
+func CSRFHeaders(token string) h.Attribute {
+	return Headers(map[string]string{csrf.HeaderName: token})
+}
+
+// CSRFHeadersFromMeta is like CSRFHeaders but reads the token from the
+// csrf.Meta tag when each request is made, for cached markup.
+func CSRFHeadersFromMeta() h.Attribute {
+	return HeadersJS(js.ToJS(js.Object(js.Pair(csrf.HeaderName, csrf.MetaValue()))))
+}
+
 // ParamsFilter specifies how parameters should be filtered.
 type ParamsFilter string
 
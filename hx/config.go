@@ -0,0 +1,144 @@
+package hx
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Config is the global htmx configuration (htmx.config), rendered as a
+// <meta name="htmx-config"> tag by Meta. Zero fields are left at htmx's
+// defaults; options whose htmx default is true are *bool so they can be
+// turned off:
+//
+//	off := false
+//	hx.Config{
+//	    DefaultSwapStyle: hx.OuterHTML,
+//	    Timeout:          10 * time.Second,
+//	    HistoryCacheSize: 20,
+//	    AllowEval:        &off,
+//	}.Meta()
+//
+// Renders:
+//
+//	<meta name="htmx-config" content="{&#34;allowEval&#34;:false,&#34;defaultSwapStyle&#34;:&#34;outerHTML&#34;,&#34;historyCacheSize&#34;:20,&#34;timeout&#34;:10000}"/>
+type Config struct {
+	HistoryEnabled          *bool
+	HistoryCacheSize        int
+	RefreshOnHistoryMiss    bool
+	DefaultSwapStyle        SwapStrategy
+	DefaultSwapDelay        time.Duration
+	DefaultSettleDelay      time.Duration
+	IncludeIndicatorStyles  *bool
+	IndicatorClass          string
+	RequestClass            string
+	AddedClass              string
+	SettlingClass           string
+	SwappingClass           string
+	AllowEval               *bool
+	AllowScriptTags         *bool
+	InlineScriptNonce       string
+	InlineStyleNonce        string
+	AttributesToSettle      []string
+	WSReconnectDelay        string // "full-jitter" or a custom JS function name
+	WSBinaryType            string // "blob" or "arraybuffer"
+	DisableSelector         string
+	WithCredentials         bool
+	Timeout                 time.Duration
+	ScrollBehavior          string // "instant", "smooth", or "auto"
+	DefaultFocusScroll      bool
+	GetCacheBusterParam     bool
+	GlobalViewTransitions   bool
+	MethodsThatUseURLParams []string
+	SelfRequestsOnly        *bool
+	IgnoreTitle             bool
+	ScrollIntoViewOnBoost   *bool
+	AllowNestedOOBSwaps     *bool
+	DisableInheritance      bool // Only inherit attributes listed in hx-inherit
+
+	// Extra holds options not covered above, such as those added by
+	// extensions. Its keys override the fields above.
+	Extra map[string]any
+}
+
+// Meta returns the <meta name="htmx-config"> tag for c. Place it in the
+// document head before the htmx script. Panics if an Extra value is not
+// JSON-encodable.
+func (c Config) Meta() h.Builder {
+	return h.Meta(h.Attrs("name", "htmx-config", "content", c.JSON()))
+}
+
+// JSON returns c as the JSON object htmx reads from the meta tag, with
+// durations in milliseconds.
+func (c Config) JSON() string {
+	m := map[string]any{}
+	setBool := func(key string, v *bool) {
+		if v != nil {
+			m[key] = *v
+		}
+	}
+	setTrue := func(key string, v bool) {
+		if v {
+			m[key] = true
+		}
+	}
+	setString := func(key, v string) {
+		if v != "" {
+			m[key] = v
+		}
+	}
+	setMillis := func(key string, d time.Duration) {
+		if d != 0 {
+			m[key] = d.Milliseconds()
+		}
+	}
+
+	setBool("historyEnabled", c.HistoryEnabled)
+	if c.HistoryCacheSize != 0 {
+		m["historyCacheSize"] = c.HistoryCacheSize
+	}
+	setTrue("refreshOnHistoryMiss", c.RefreshOnHistoryMiss)
+	setString("defaultSwapStyle", string(c.DefaultSwapStyle))
+	setMillis("defaultSwapDelay", c.DefaultSwapDelay)
+	setMillis("defaultSettleDelay", c.DefaultSettleDelay)
+	setBool("includeIndicatorStyles", c.IncludeIndicatorStyles)
+	setString("indicatorClass", c.IndicatorClass)
+	setString("requestClass", c.RequestClass)
+	setString("addedClass", c.AddedClass)
+	setString("settlingClass", c.SettlingClass)
+	setString("swappingClass", c.SwappingClass)
+	setBool("allowEval", c.AllowEval)
+	setBool("allowScriptTags", c.AllowScriptTags)
+	setString("inlineScriptNonce", c.InlineScriptNonce)
+	setString("inlineStyleNonce", c.InlineStyleNonce)
+	if c.AttributesToSettle != nil {
+		m["attributesToSettle"] = c.AttributesToSettle
+	}
+	setString("wsReconnectDelay", c.WSReconnectDelay)
+	setString("wsBinaryType", c.WSBinaryType)
+	setString("disableSelector", c.DisableSelector)
+	setTrue("withCredentials", c.WithCredentials)
+	setMillis("timeout", c.Timeout)
+	setString("scrollBehavior", c.ScrollBehavior)
+	setTrue("defaultFocusScroll", c.DefaultFocusScroll)
+	setTrue("getCacheBusterParam", c.GetCacheBusterParam)
+	setTrue("globalViewTransitions", c.GlobalViewTransitions)
+	if c.MethodsThatUseURLParams != nil {
+		m["methodsThatUseUrlParams"] = c.MethodsThatUseURLParams
+	}
+	setBool("selfRequestsOnly", c.SelfRequestsOnly)
+	setTrue("ignoreTitle", c.IgnoreTitle)
+	setBool("scrollIntoViewOnBoost", c.ScrollIntoViewOnBoost)
+	setBool("allowNestedOobSwaps", c.AllowNestedOOBSwaps)
+	setTrue("disableInheritance", c.DisableInheritance)
+	for k, v := range c.Extra {
+		m[k] = v
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		panic("hx.Config: " + err.Error())
+	}
+	return string(data)
+}
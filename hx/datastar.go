@@ -0,0 +1,196 @@
+package hx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// ErrDatastarConflict is wrapped by ConflictError.
+var ErrDatastarConflict = errors.New("conflicting htmx and Datastar attributes")
+
+// ConflictError describes an element whose HTMX and Datastar attributes
+// interfere with each other, found by CheckDatastar.
+type ConflictError struct {
+	Element  string // Element name
+	HTMX     string // The conflicting hx-* attribute
+	Datastar string // The conflicting data-* attribute
+	Problem  string // What goes wrong at runtime
+	Hint     string // How to fix it
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("<%s>: %v: %s and %s: %s; %s", e.Element, ErrDatastarConflict, e.HTMX, e.Datastar, e.Problem, e.Hint)
+}
+
+func (e *ConflictError) Unwrap() error { return ErrDatastarConflict }
+
+// requestAttrs are the attributes that make HTMX issue requests.
+var requestAttrs = []string{"hx-get", "hx-post", "hx-put", "hx-patch", "hx-delete"}
+
+// datastarActions are the Datastar backend actions that issue requests.
+var datastarActions = []string{"@get(", "@post(", "@put(", "@patch(", "@delete("}
+
+// CheckDatastar returns a ConflictError for each known conflict between the
+// HTMX and Datastar attributes of one element, for teams migrating from one
+// library to the other a page at a time. It reports:
+//
+//   - An HTMX request and a Datastar backend action (@get, @post, ...)
+//     triggered by the same event, which sends two requests.
+//   - An HTMX request that swaps the element's content while data-text
+//     also sets it, so the two overwrite each other.
+//   - An HTMX outerHTML swap of an element carrying data-signals, which
+//     resets the signals on every response.
+func CheckDatastar(element string, as h.Attributes) []*ConflictError {
+	var (
+		request     string // hx-get, hx-post, ...
+		swap        = "innerHTML"
+		hasTarget   bool
+		conflicts   []*ConflictError
+		htmxEvents  = map[string]bool{}
+		triggerSeen bool
+	)
+	for _, a := range as {
+		switch name := strings.ToLower(a.Name); {
+		case slices.Contains(requestAttrs, name):
+			request = name
+		case name == "hx-trigger":
+			triggerSeen = true
+			for _, spec := range strings.Split(a.Value, ",") {
+				if fields := strings.Fields(spec); len(fields) > 0 {
+					event, _, _ := strings.Cut(fields[0], "[") // Drop a filter such as [ctrlKey]
+					htmxEvents[event] = true
+				}
+			}
+		case name == "hx-swap":
+			if fields := strings.Fields(a.Value); len(fields) > 0 {
+				swap = fields[0]
+			}
+		case name == "hx-target":
+			hasTarget = a.Value != "" && a.Value != "this"
+		}
+	}
+	if request == "" {
+		return nil
+	}
+	if !triggerSeen {
+		htmxEvents[defaultTrigger(element)] = true
+	}
+
+	for _, a := range as {
+		name := strings.ToLower(a.Name)
+		if event, ok := datastarEvent(name); ok && htmxEvents[event] && hasAction(a.Value) {
+			conflicts = append(conflicts, &ConflictError{
+				Element: element, HTMX: request, Datastar: a.Name,
+				Problem: "both send a request on " + event,
+				Hint:    "remove " + request + " or the backend action in " + a.Name,
+			})
+		}
+		if name == "data-text" && !hasTarget && (swap == "innerHTML" || swap == "outerHTML") {
+			conflicts = append(conflicts, &ConflictError{
+				Element: element, HTMX: request, Datastar: a.Name,
+				Problem: "the htmx swap and data-text both replace the element's content",
+				Hint:    "set hx-target to another element or move data-text to a child",
+			})
+		}
+		if strings.HasPrefix(name, "data-signals") && !hasTarget && swap == "outerHTML" {
+			conflicts = append(conflicts, &ConflictError{
+				Element: element, HTMX: "hx-swap", Datastar: a.Name,
+				Problem: "the outerHTML swap replaces the element and resets its signals",
+				Hint:    "move " + a.Name + " to an ancestor outside the swap target",
+			})
+		}
+	}
+	return conflicts
+}
+
+// defaultTrigger returns the event HTMX uses for element without hx-trigger.
+func defaultTrigger(element string) string {
+	switch element {
+	case "form":
+		return "submit"
+	case "input", "select", "textarea":
+		return "change"
+	}
+	return "click"
+}
+
+// datastarEvent returns the event of a data-on:event, data-on-event, or
+// data-init attribute, without modifiers.
+func datastarEvent(name string) (string, bool) {
+	if name == "data-init" || strings.HasPrefix(name, "data-init__") {
+		return "load", true
+	}
+	event, ok := strings.CutPrefix(name, "data-on:")
+	if !ok {
+		if event, ok = strings.CutPrefix(name, "data-on-"); !ok {
+			return "", false
+		}
+	}
+	event, _, _ = strings.Cut(event, "__")
+	return event, event != ""
+}
+
+func hasAction(expr string) bool {
+	for _, action := range datastarActions {
+		if strings.Contains(expr, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// datastarGuard is the ElementHook returned by DatastarGuard.
+type datastarGuard struct {
+	collect *[]error // Collects conflicts instead of failing when non-nil
+}
+
+func (g datastarGuard) BeforeOpen(w *h.Writer, tag string, as h.Attributes) (h.Attributes, error) {
+	conflicts := CheckDatastar(tag, as)
+	if len(conflicts) == 0 {
+		return as, nil
+	}
+	errs := make([]error, len(conflicts))
+	for i, c := range conflicts {
+		errs[i] = c
+	}
+	if g.collect != nil {
+		*g.collect = append(*g.collect, errs...)
+		return as, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (g datastarGuard) AfterClose(w *h.Writer, tag string, as h.Attributes) error { return nil }
+
+// DatastarGuard returns an ElementHook that fails rendering at the first
+// element with conflicting HTMX and Datastar attributes (see CheckDatastar).
+// Enable it in development:
+//
+//	if devMode {
+//	    ctx = h.WithElementHooks(ctx, hx.DatastarGuard())
+//	}
+func DatastarGuard() h.ElementHook {
+	return datastarGuard{}
+}
+
+// ValidateDatastar renders b without output and returns every conflict
+// found by CheckDatastar, joined, or nil. Rendering errors are returned
+// as-is. Intended for test suites:
+//
+//	if err := hx.ValidateDatastar(page); err != nil {
+//	    t.Error(err)
+//	}
+func ValidateDatastar(b h.Builder) error {
+	var errs []error
+	ctx := h.WithElementHooks(context.Background(), datastarGuard{collect: &errs})
+	if err := h.RenderContext(ctx, io.Discard, b); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
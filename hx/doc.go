@@ -2,12 +2,26 @@
 //
 // This package includes:
 //   - HTTP methods: Get, Post, Put, Patch, Delete
-//   - Targeting: Target, Select, SelectOOB, SwapOOB
+//   - Targeting: Target, Select, SelectOOB, SwapOOB, and OOB/OOBGroup for
+//     out-of-band fragments in responses
 //   - Swap strategies: Swap with modifiers (Transition, SwapDelay, SettleDelay, etc.)
-//   - Triggers: Trigger with modifiers (Once, Changed, Delay, Throttle, From, etc.)
+//   - Triggers: Trigger with modifiers (Once, Changed, Delay, Throttle, From, etc.),
+//     and typed Event names (EventClick, ..., CustomEvent) shared with Response
 //   - Request config: Include, Vals, ValsJS, Headers, Params, Encoding, Ext
+//   - Extensions: SSEConnect, SSESwap, SSEClose (sse) and WSConnect, WSSend (ws)
 //   - Behavior: Boost, PushURL, ReplaceURL, Confirm, Prompt, Indicator, Sync, etc.
-//   - Events: On, OnBeforeRequest, OnAfterSwap, and other HTMX event handlers
+//   - Global configuration: Config renders the htmx-config meta tag
+//   - Events: On, OnBeforeRequest, OnAfterSwap, and other HTMX event handlers;
+//     OnStmts and OnHTMXStmts take js statements instead of raw script
+//   - Server side: Response (HX-Trigger, HX-Redirect, HX-Push-Url, ...) and
+//     request inspection (IsHTMX, RequestTarget, TriggerName, ...), and
+//     status helpers (StopPolling, NoContent, NoSwap, SwapError)
+//   - Migration checks: CheckDatastar, DatastarGuard, and ValidateDatastar
+//     flag HTMX and Datastar attributes that conflict on one element
+//   - Inheritance: ResolveInheritance reports each element's effective
+//     attributes after hx-disinherit, hx-inherit, and hx-disable, and
+//     ValidateInheritance flags surprising inheritance such as a child
+//     picking up its requesting parent's hx-confirm
 //
 // Basic usage:
 //
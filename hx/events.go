@@ -2,6 +2,7 @@ package hx
 
 import (
 	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
 )
 
 // On creates an hx-on attribute for handling DOM events with inline scripts.
@@ -26,6 +27,25 @@ func OnHTMX(event string, script string) h.Attribute {
 	return h.Attr("hx-on::"+event, script)
 }
 
+// OnStmts is On with a handler built from js statements, so it gets the
+// same escaping as js.OnClick.
+//
+// Example:
+//
+//	hx.OnStmts("click", js.ExprStmt(js.Call(js.Ident("toggle"), js.This())))
+func OnStmts(event string, stmts ...js.Stmt) h.Attribute {
+	return On(event, js.Handler(stmts...))
+}
+
+// OnHTMXStmts is OnHTMX with a handler built from js statements.
+//
+// Example:
+//
+//	hx.OnHTMXStmts("afterSwap", js.ExprStmt(js.Call(js.Ident("initializeComponents"))))
+func OnHTMXStmts(event string, stmts ...js.Stmt) h.Attribute {
+	return OnHTMX(event, js.Handler(stmts...))
+}
+
 // Standard DOM event handlers
 
 // OnClick creates an hx-on:click attribute.
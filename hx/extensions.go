@@ -0,0 +1,45 @@
+package hx
+
+import (
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// SSEConnect creates an sse-connect attribute that opens a Server-Sent
+// Events connection to url. The sse extension must be enabled with
+// Ext("sse") on the element or an ancestor:
+//
+//	h.Div(
+//	    hx.Ext("sse"),
+//	    hx.SSEConnect("/events"),
+//	    h.Div(hx.SSESwap("message")),
+//	)
+func SSEConnect(url string) h.Attribute {
+	return h.Attr("sse-connect", url)
+}
+
+// SSESwap creates an sse-swap attribute that swaps the data of the named
+// events into the element. Multiple events are comma-separated.
+func SSESwap(events ...string) h.Attribute {
+	return h.Attr("sse-swap", strings.Join(events, ","))
+}
+
+// SSEClose creates an sse-close attribute that closes the connection when
+// the named event is received.
+func SSEClose(event string) h.Attribute {
+	return h.Attr("sse-close", event)
+}
+
+// WSConnect creates a ws-connect attribute that opens a WebSocket
+// connection to url. The ws extension must be enabled with Ext("ws") on
+// the element or an ancestor.
+func WSConnect(url string) h.Attribute {
+	return h.Attr("ws-connect", url)
+}
+
+// WSSend creates a ws-send attribute that sends the element's form values
+// over the nearest WebSocket when the element is triggered.
+func WSSend() h.Attribute {
+	return h.Attr("ws-send", "")
+}
@@ -1,10 +1,17 @@
 package hx
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
 )
 
 // ============ attrs.go tests ============
@@ -164,6 +171,21 @@ func TestHeadersJS(t *testing.T) {
 	}
 }
 
+func TestCSRFHeaders(t *testing.T) {
+	attr := CSRFHeaders(`tok"en`)
+	if attr.Name != "hx-headers" {
+		t.Errorf("Name = %q, want %q", attr.Name, "hx-headers")
+	}
+	if want := `{"X-CSRF-Token":"tok\"en"}`; attr.Value != want {
+		t.Errorf("Value = %q, want %q", attr.Value, want)
+	}
+
+	attr = CSRFHeadersFromMeta()
+	if want := `js:{"X-CSRF-Token": document.querySelector("meta[name=\"csrf-token\"]")?.content}`; attr.Value != want {
+		t.Errorf("Value = %q, want %q", attr.Value, want)
+	}
+}
+
 func TestParams(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -324,6 +346,39 @@ func TestTriggerAttr(t *testing.T) {
 	}
 }
 
+func TestTriggerCustom(t *testing.T) {
+	orderCreated := CustomEvent("order:created")
+	tests := []struct {
+		name     string
+		trigger  *TriggerBuilder
+		expected string
+	}{
+		{"standard", TriggerCustom(EventKeyUp, Changed()), "keyup changed"},
+		{"custom", TriggerCustom(orderCreated, FromDocument()), "order:created from:document"},
+		{"and custom", TriggerLoad().AndCustom(orderCreated, FromWindow()), "load, order:created from:window"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trigger.Attr().Value; got != tt.expected {
+				t.Errorf("Value = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCustomEventInvalid(t *testing.T) {
+	for _, name := range []string{"", "order created", "a,b", "click[ctrlKey]"} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("CustomEvent(%q) did not panic", name)
+				}
+			}()
+			CustomEvent(name)
+		})
+	}
+}
+
 func TestTriggerWithModifiers(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -711,6 +766,32 @@ func TestOnHTMX(t *testing.T) {
 	}
 }
 
+func TestOnStmts(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     h.Attribute
+		expected h.Attribute
+	}{
+		{
+			"dom event",
+			OnStmts("click", js.ExprStmt(js.Call(js.Ident("toggle"), js.String(`it's "on"`)))),
+			h.Attr("hx-on:click", `toggle("it's \"on\"")`),
+		},
+		{
+			"htmx event",
+			OnHTMXStmts("afterSwap", js.ExprStmt(js.Call(js.Ident("init"))), js.ExprStmt(js.Call(js.Ident("focus")))),
+			h.Attr("hx-on::afterSwap", "init(); focus()"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr != tt.expected {
+				t.Errorf("got %+v, want %+v", tt.attr, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDOMEventHandlers(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -772,6 +853,428 @@ func TestHTMXEventHandlers(t *testing.T) {
 	}
 }
 
+// ============ response.go tests ============
+
+func TestRequestHeaders(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if IsHTMX(r) || IsBoosted(r) || IsHistoryRestore(r) {
+		t.Error("plain request reported as HTMX")
+	}
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set("HX-Boosted", "true")
+	r.Header.Set("HX-Target", "list")
+	r.Header.Set("HX-Trigger", "save-btn")
+	r.Header.Set("HX-Trigger-Name", "save")
+	r.Header.Set("HX-Current-URL", "https://example.com/items")
+	r.Header.Set("HX-Prompt", "yes")
+
+	tests := []struct {
+		name     string
+		got      any
+		expected any
+	}{
+		{"IsHTMX", IsHTMX(r), true},
+		{"IsBoosted", IsBoosted(r), true},
+		{"RequestTarget", RequestTarget(r), "list"},
+		{"TriggerID", TriggerID(r), "save-btn"},
+		{"TriggerName", TriggerName(r), "save"},
+		{"CurrentURL", CurrentURL(r), "https://example.com/items"},
+		{"PromptResponse", PromptResponse(r), "yes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewResponse(rec).
+		Redirect("/login").
+		Location("/next").
+		PushURL("/items/1").
+		ReplaceURL("/items").
+		Refresh().
+		Retarget("#items").
+		Reselect(".row").
+		Reswap(OuterHTML, Transition())
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"HX-Redirect", "/login"},
+		{"HX-Location", "/next"},
+		{"HX-Push-Url", "/items/1"},
+		{"HX-Replace-Url", "/items"},
+		{"HX-Refresh", "true"},
+		{"HX-Retarget", "#items"},
+		{"HX-Reselect", ".row"},
+		{"HX-Reswap", "outerHTML transition:true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := rec.Header().Get(tt.header); got != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResponseTrigger(t *testing.T) {
+	tests := []struct {
+		name     string
+		apply    func(*Response)
+		header   string
+		expected string
+	}{
+		{"names", func(r *Response) { r.Trigger("a", "b").Trigger("c") }, "HX-Trigger", "a, b, c"},
+		{"after swap", func(r *Response) { r.TriggerAfterSwap("swapped") }, "HX-Trigger-After-Swap", "swapped"},
+		{"after settle", func(r *Response) { r.TriggerAfterSettle("settled") }, "HX-Trigger-After-Settle", "settled"},
+		{"custom", func(r *Response) { r.Trigger("a").TriggerCustom(CustomEvent("order:created"), EventChange) }, "HX-Trigger", "a, order:created, change"},
+		{
+			"detail",
+			func(r *Response) { r.Trigger("plain").TriggerDetail("saved", map[string]int{"id": 1}) },
+			"HX-Trigger", `{"plain":null,"saved":{"id":1}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.apply(NewResponse(rec))
+			if got := rec.Header().Get(tt.header); got != tt.expected {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStatusHelpers(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(http.ResponseWriter)
+		status  int
+		headers map[string]string
+	}{
+		{"stop polling", StopPolling, 286, nil},
+		{"no content", NoContent, http.StatusNoContent, nil},
+		{"no swap", NoSwap, http.StatusOK, map[string]string{"HX-Reswap": "none"}},
+		{
+			"swap error",
+			func(w http.ResponseWriter) { SwapError(w, http.StatusUnprocessableEntity, "#errors", InnerHTML) },
+			http.StatusUnprocessableEntity,
+			map[string]string{"HX-Retarget": "#errors", "HX-Reswap": "innerHTML"},
+		},
+		{"through Response", func(w http.ResponseWriter) { StopPolling(NewResponse(w).Trigger("done")) }, 286, map[string]string{"HX-Trigger": "done"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			tt.respond(rec)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			for k, v := range tt.headers {
+				if got := rec.Header().Get(k); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
+
+// ============ extensions.go tests ============
+
+func TestExtensionAttrs(t *testing.T) {
+	tests := []struct {
+		name     string
+		attr     h.Attribute
+		expected h.Attribute
+	}{
+		{"sse connect", SSEConnect("/events"), h.Attr("sse-connect", "/events")},
+		{"sse swap", SSESwap("message"), h.Attr("sse-swap", "message")},
+		{"sse swap multiple", SSESwap("created", "updated"), h.Attr("sse-swap", "created,updated")},
+		{"sse close", SSEClose("done"), h.Attr("sse-close", "done")},
+		{"ws connect", WSConnect("/chat"), h.Attr("ws-connect", "/chat")},
+		{"ws send", WSSend(), h.Attr("ws-send", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.attr != tt.expected {
+				t.Errorf("got %+v, want %+v", tt.attr, tt.expected)
+			}
+		})
+	}
+}
+
+// ============ oob.go tests ============
+
+func TestOOB(t *testing.T) {
+	badge := h.Span(h.Class("badge"), h.Text("3"))
+
+	tests := []struct {
+		name     string
+		b        h.Builder
+		expected string
+	}{
+		{"by id", OOB(badge, "", ""), `<span class="badge" hx-swap-oob="true">3</span>`},
+		{"strategy", OOB(badge, InnerHTML, ""), `<span class="badge" hx-swap-oob="innerHTML">3</span>`},
+		{"strategy and target", OOB(badge, InnerHTML, "#cart-count"), `<span class="badge" hx-swap-oob="innerHTML:#cart-count">3</span>`},
+		{"target only", OOB(badge, "", "#cart"), `<span class="badge" hx-swap-oob="outerHTML:#cart">3</span>`},
+		{
+			"group",
+			OOBGroup(h.Div(h.Attr("id", "a")), nil, OOB(h.P(h.Text("hi")), AfterBegin, "#messages")),
+			`<div id="a" hx-swap-oob="true"></div><p hx-swap-oob="afterbegin:#messages">hi</p>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.RenderString(tt.b); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// ============ config.go tests ============
+
+func TestConfigJSON(t *testing.T) {
+	off := false
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{"empty", Config{}, `{}`},
+		{
+			"fields",
+			Config{
+				DefaultSwapStyle:        OuterHTML,
+				Timeout:                 10 * time.Second,
+				DefaultSettleDelay:      20 * time.Millisecond,
+				HistoryCacheSize:        20,
+				AllowEval:               &off,
+				GlobalViewTransitions:   true,
+				MethodsThatUseURLParams: []string{"get"},
+			},
+			`{"allowEval":false,"defaultSettleDelay":20,"defaultSwapStyle":"outerHTML","globalViewTransitions":true,"historyCacheSize":20,"methodsThatUseUrlParams":["get"],"timeout":10000}`,
+		},
+		{"extra overrides", Config{IndicatorClass: "busy", Extra: map[string]any{"indicatorClass": "loading", "custom": 1}}, `{"custom":1,"indicatorClass":"loading"}`},
+		{"escapes html", Config{DisableSelector: `[data-x="</meta>"]`}, `{"disableSelector":"[data-x=\"\u003c/meta\u003e\"]"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.JSON(); got != tt.expected {
+				t.Errorf("JSON() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfigMeta(t *testing.T) {
+	got := h.RenderString(Config{Timeout: time.Second}.Meta())
+	expected := `<meta name="htmx-config" content="{&#34;timeout&#34;:1000}"/>`
+	if got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+}
+
+// ============ inherit.go tests ============
+
+func TestResolveInheritance(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		page     h.Builder
+		expected map[string][]string // Element label to inherited "name=from"
+	}{
+		{
+			"closest ancestor wins",
+			Config{},
+			h.Div(h.Attr("id", "outer"), Target("#a"), Confirm("Sure?"),
+				h.Div(h.Class("inner"), Target("#b"),
+					h.Button(Delete("/x")))),
+			map[string][]string{
+				"div#outer": nil,
+				"div.inner": {"hx-confirm=div#outer"},
+				"button":    {"hx-confirm=div#outer", "hx-target=div.inner"},
+			},
+		},
+		{
+			"own attribute wins",
+			Config{},
+			h.Div(h.Attr("id", "list"), Target("#a"), h.Button(Delete("/x"), Target("this"))),
+			map[string][]string{"div#list": nil, "button": nil},
+		},
+		{
+			"disinherit all",
+			Config{},
+			h.Div(Confirm("Sure?"), h.Div(h.Attr("id", "mid"), Target("#a"), Disinherit(), h.Button(Get("/x")))),
+			map[string][]string{"div": nil, "div#mid": {"hx-confirm=div"}, "button": nil},
+		},
+		{
+			"disinherit one",
+			Config{},
+			h.Div(Confirm("Sure?"), Target("#a"), Disinherit("hx-target"), h.Button(Get("/x"))),
+			map[string][]string{"div": nil, "button": {"hx-confirm=div"}},
+		},
+		{
+			"unset stops lookup",
+			Config{},
+			h.Div(Confirm("Sure?"), h.Div(h.Attr("id", "mid"), Confirm("unset"), h.Button(Get("/x")))),
+			map[string][]string{"div": nil, "div#mid": nil, "button": nil},
+		},
+		{
+			"data prefix",
+			Config{},
+			h.Div(h.Attr("data-hx-confirm", "Sure?"), h.Button(h.Attr("data-hx-get", "/x"))),
+			map[string][]string{"div": nil, "button": {"hx-confirm=div"}},
+		},
+		{
+			"disabled subtree",
+			Config{},
+			h.Div(Confirm("Sure?"), h.Div(h.Attr("id", "off"), Disable(), h.Button(Get("/x")))),
+			map[string][]string{"div": nil, "div#off": nil, "button": nil},
+		},
+		{
+			"disable inheritance",
+			Config{DisableInheritance: true},
+			h.Div(Confirm("Sure?"), h.Div(h.Attr("id", "mid"), Target("#a"), Inherit("hx-target"), h.Button(Get("/x")))),
+			map[string][]string{"div": nil, "div#mid": nil, "button": {"hx-target=div#mid"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := tt.config.ResolveInheritance(tt.page)
+			if err != nil {
+				t.Fatalf("ResolveInheritance() error = %v", err)
+			}
+			got := map[string][]string{}
+			for _, el := range in {
+				var inherited []string
+				for _, a := range el.Inherited {
+					inherited = append(inherited, a.Name+"="+a.From)
+				}
+				got[el.Element] = inherited
+			}
+			if len(got) != len(tt.expected) {
+				t.Errorf("resolved %v, want %v", got, tt.expected)
+			}
+			for label, want := range tt.expected {
+				if inherited, ok := got[label]; !ok || !slices.Equal(inherited, want) {
+					t.Errorf("%s inherits %v, want %v", label, inherited, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInheritanceWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     h.Builder
+		expected []string // "element:attribute" of each warning
+	}{
+		{
+			"confirm for a wrapper is intended",
+			h.Div(Confirm("Sure?"), h.Button(Delete("/x"))),
+			nil,
+		},
+		{
+			"confirm meant for requesting ancestor",
+			h.Form(h.Attr("id", "bulk"), Post("/bulk"), Confirm("Delete all?"),
+				h.Button(h.Attr("id", "one"), Delete("/items/1"))),
+			[]string{"button#one:hx-confirm"},
+		},
+		{
+			"non-requesting child is fine",
+			h.Form(Post("/bulk"), Confirm("Delete all?"), h.Input(h.Attr("name", "q"))),
+			nil,
+		},
+		{
+			"disinherited",
+			h.Form(Post("/bulk"), Confirm("Delete all?"), Disinherit("hx-confirm"), h.Button(Delete("/items/1"))),
+			nil,
+		},
+		{
+			"confirm on load",
+			h.Div(Confirm("Sure?"), h.Div(h.Class("feed"), Get("/feed"), TriggerLoad().Attr())),
+			[]string{"div.feed:hx-confirm"},
+		},
+		{
+			"prompt when polling",
+			h.Div(Prompt("Name?"), h.Div(h.Class("poll"), Get("/poll"), TriggerEvery(time.Second).Attr())),
+			[]string{"div.poll:hx-prompt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := ResolveInheritance(tt.page)
+			if err != nil {
+				t.Fatalf("ResolveInheritance() error = %v", err)
+			}
+			var got []string
+			for _, el := range in {
+				for _, warn := range el.Warnings {
+					got = append(got, warn.Element+":"+warn.Attr)
+					if warn.Problem == "" || warn.Hint == "" {
+						t.Errorf("warning without explanation: %v", warn)
+					}
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("warnings %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateInheritance(t *testing.T) {
+	page := h.Form(h.Attr("id", "bulk"), Post("/bulk"), Confirm("Delete all?"),
+		h.Button(h.Attr("id", "one"), Delete("/items/1")))
+
+	err := ValidateInheritance(page)
+	var warn *InheritanceError
+	if !errors.Is(err, ErrSurprisingInheritance) || !errors.As(err, &warn) {
+		t.Fatalf("ValidateInheritance() = %v, want InheritanceError", err)
+	}
+	expected := `<button#one>: surprising htmx inheritance: hx-confirm from <form#bulk>: it is set for the ancestor's own request but also applies to this one; add hx-disinherit="hx-confirm" to <form#bulk> or set hx-confirm on this element`
+	if warn.Error() != expected {
+		t.Errorf("Error() = %q, want %q", warn.Error(), expected)
+	}
+
+	if err := ValidateInheritance(h.Div(Confirm("Sure?"), h.Button(Delete("/x")))); err != nil {
+		t.Errorf("ValidateInheritance() = %v for a valid page", err)
+	}
+}
+
+func TestInheritanceString(t *testing.T) {
+	page := h.Div(
+		h.Form(h.Attr("id", "bulk"), Post("/bulk"), Confirm("Delete all?"),
+			h.P(h.Text("Items")),
+			h.Button(h.Attr("id", "one"), Delete("/items/1"))),
+		h.Div(Disable(), h.Button(Get("/x"))),
+	)
+	in, err := ResolveInheritance(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `  form#bulk hx-post="/bulk" hx-confirm="Delete all?"
+    button#one hx-delete="/items/1"
+      inherits hx-confirm="Delete all?" from form#bulk
+      warning: hx-confirm from <form#bulk>: it is set for the ancestor's own request but also applies to this one; add hx-disinherit="hx-confirm" to <form#bulk> or set hx-confirm on this element
+  div hx-disable="" (disabled)
+    button hx-get="/x" (disabled)
+`
+	if got := in.String(); got != expected {
+		t.Errorf("String() =\n%s\nwant\n%s", got, expected)
+	}
+}
+
 // ============ Helper functions ============
 
 func containsString(s, substr string) bool {
@@ -787,3 +1290,80 @@ func searchString(s, substr string) bool {
 	}
 	return false
 }
+
+// ============ datastar.go tests ============
+
+func TestCheckDatastar(t *testing.T) {
+	tests := []struct {
+		name     string
+		element  string
+		attrs    h.Attributes
+		expected []string // Datastar attribute of each conflict
+	}{
+		{"htmx only", "button", h.Attrs("hx-post", "/save"), nil},
+		{"datastar only", "button", h.Attrs("data-on:click", "@post('/save')"), nil},
+		{"double click request", "button", h.Attrs("hx-post", "/save", "data-on:click", "@post('/save')"), []string{"data-on:click"}},
+		{"dash syntax with modifier", "button", h.Attrs("hx-get", "/x", "data-on-click__debounce.300ms", "@get('/x')"), []string{"data-on-click__debounce.300ms"}},
+		{"different event", "button", h.Attrs("hx-post", "/save", "data-on:mouseenter", "@get('/preview')"), nil},
+		{"client-only handler", "button", h.Attrs("hx-post", "/save", "data-on:click", "$open = false"), nil},
+		{"form submit", "form", h.Attrs("hx-post", "/save", "data-on:submit", "@post('/save')"), []string{"data-on:submit"}},
+		{"form click is fine", "form", h.Attrs("hx-post", "/save", "data-on:click", "@get('/x')"), nil},
+		{"input change", "input", h.Attrs("hx-get", "/search", "data-on:change", "@get('/search')"), []string{"data-on:change"}},
+		{"explicit trigger", "input", h.Attrs("hx-get", "/search", "hx-trigger", "keyup changed delay:500ms, search", "data-on:keyup", "@get('/search')"), []string{"data-on:keyup"}},
+		{"trigger filter", "button", h.Attrs("hx-get", "/x", "hx-trigger", "click[ctrlKey]", "data-on:click", "@get('/x')"), []string{"data-on:click"}},
+		{"trigger replaces default", "button", h.Attrs("hx-get", "/x", "hx-trigger", "mouseenter", "data-on:click", "@get('/x')"), nil},
+		{"load", "div", h.Attrs("hx-get", "/feed", "hx-trigger", "load", "data-init", "@get('/feed')"), []string{"data-init"}},
+		{"text swap", "div", h.Attrs("hx-get", "/count", "data-text", "$count"), []string{"data-text"}},
+		{"text with target", "div", h.Attrs("hx-get", "/count", "hx-target", "#other", "data-text", "$count"), nil},
+		{"text with attribute swap", "div", h.Attrs("hx-get", "/count", "hx-swap", "beforeend", "data-text", "$count"), nil},
+		{"signals outer swap", "div", h.Attrs("hx-get", "/row", "hx-swap", "outerHTML swap:1s", "data-signals", "{open: false}"), []string{"data-signals"}},
+		{"signals inner swap", "div", h.Attrs("hx-get", "/row", "data-signals:open", "false"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range CheckDatastar(tt.element, tt.attrs) {
+				got = append(got, c.Datastar)
+				if c.Hint == "" || c.Problem == "" {
+					t.Errorf("conflict without explanation: %v", c)
+				}
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("conflicts on %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDatastarGuard(t *testing.T) {
+	page := h.Div(
+		h.Button(Post("/save"), h.Attr("data-on:click", "@post('/save')"), h.Text("Save")),
+		h.Div(Get("/count"), h.Attr("data-text", "$count")),
+	)
+
+	err := ValidateDatastar(page)
+	var conflict *ConflictError
+	if !errors.Is(err, ErrDatastarConflict) || !errors.As(err, &conflict) {
+		t.Fatalf("ValidateDatastar() = %v, want ConflictError", err)
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 2 {
+		t.Errorf("ValidateDatastar() found %d conflicts, want 2", n)
+	}
+	expected := "<button>: conflicting htmx and Datastar attributes: hx-post and data-on:click: both send a request on click; remove hx-post or the backend action in data-on:click"
+	if conflict.Error() != expected {
+		t.Errorf("Error() = %q, want %q", conflict.Error(), expected)
+	}
+
+	var buf bytes.Buffer
+	ctx := h.WithElementHooks(context.Background(), DatastarGuard())
+	if err := h.RenderContext(ctx, &buf, page); !errors.Is(err, ErrDatastarConflict) {
+		t.Errorf("DatastarGuard render error = %v", err)
+	}
+	if got := buf.String(); got != "<div>" {
+		t.Errorf("rendered past the conflict: %s", got)
+	}
+
+	if err := ValidateDatastar(h.Button(Post("/save"), h.Text("Save"))); err != nil {
+		t.Errorf("ValidateDatastar() = %v for a valid page", err)
+	}
+}
@@ -0,0 +1,291 @@
+package hx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// ErrSurprisingInheritance is wrapped by InheritanceError.
+var ErrSurprisingInheritance = errors.New("surprising htmx inheritance")
+
+// InheritanceError describes an element that inherits an htmx attribute it
+// most likely was not meant to, found by ResolveInheritance.
+type InheritanceError struct {
+	Element string // Label of the inheriting element, such as button#save
+	Attr    string // The inherited hx-* attribute
+	From    string // Label of the ancestor that sets it
+	Problem string // What goes wrong at runtime
+	Hint    string // How to fix it
+}
+
+func (e *InheritanceError) Error() string {
+	return fmt.Sprintf("<%s>: %v: %s from <%s>: %s; %s", e.Element, ErrSurprisingInheritance, e.Attr, e.From, e.Problem, e.Hint)
+}
+
+func (e *InheritanceError) Unwrap() error { return ErrSurprisingInheritance }
+
+// inheritableAttrs are the attributes htmx looks up on ancestors when an
+// element does not set them itself.
+var inheritableAttrs = []string{
+	"hx-boost", "hx-confirm", "hx-disabled-elt", "hx-encoding", "hx-headers",
+	"hx-include", "hx-indicator", "hx-params", "hx-prompt", "hx-push-url",
+	"hx-replace-url", "hx-request", "hx-select", "hx-select-oob", "hx-swap",
+	"hx-sync", "hx-target", "hx-vals",
+}
+
+// InheritedAttr is an htmx attribute an element inherits from an ancestor.
+type InheritedAttr struct {
+	h.Attribute
+	From string // Label of the ancestor that sets it
+}
+
+// ResolvedElement is the effective htmx configuration of one element.
+type ResolvedElement struct {
+	Element   string          // Label such as button#save or div.card
+	Depth     int             // Number of open ancestor elements
+	Own       h.Attributes    // hx-* attributes set on the element itself
+	Inherited []InheritedAttr // Attributes inherited from ancestors, by name
+	Disabled  bool            // Set by hx-disable here or on an ancestor
+	Warnings  []*InheritanceError
+}
+
+// Inheritance lists, in document order, every element of a tree that sets
+// htmx attributes, with those it inherits. Its String method draws it as an indented tree.
+type Inheritance []ResolvedElement
+
+// String draws the report, one element per line indented by depth, followed
+// by its inherited attributes and warnings:
+//
+//	div#items hx-post="/bulk" hx-confirm="Delete all?"
+//	  button#one hx-delete="/items/1"
+//	    inherits hx-confirm="Delete all?" from div#items
+//	    warning: hx-confirm from <div#items>: ...
+func (in Inheritance) String() string {
+	var sb strings.Builder
+	for _, el := range in {
+		indent := strings.Repeat("  ", el.Depth)
+		sb.WriteString(indent)
+		sb.WriteString(el.Element)
+		for _, a := range el.Own {
+			fmt.Fprintf(&sb, " %s=%q", a.Name, a.Value)
+		}
+		if el.Disabled {
+			sb.WriteString(" (disabled)")
+		}
+		sb.WriteByte('\n')
+		for _, a := range el.Inherited {
+			fmt.Fprintf(&sb, "%s  inherits %s=%q from %s\n", indent, a.Name, a.Value, a.From)
+		}
+		for _, warn := range el.Warnings {
+			fmt.Fprintf(&sb, "%s  warning: %s from <%s>: %s; %s\n", indent, warn.Attr, warn.From, warn.Problem, warn.Hint)
+		}
+	}
+	return sb.String()
+}
+
+// ResolveInheritance renders b without output and returns the effective htmx
+// attributes of each element that sets any, applying htmx's inheritance
+// rules: the closest ancestor setting an attribute wins, hx-disinherit on
+// that ancestor or the value "unset" stops the lookup, and hx-disable turns
+// htmx off for a subtree. hx-vals and hx-headers, which htmx merges across
+// ancestors, are reported from the closest ancestor only. Rendering errors
+// are returned as-is.
+//
+// Each element's Warnings flag inheritance that is a frequent source of
+// bugs:
+//
+//   - A requesting element (hx-get, hx-post, ...) inherits an attribute from
+//     an ancestor that issues its own request, so a setting meant for the
+//     ancestor, such as its hx-confirm, also applies to the child.
+//   - A requesting element triggered without user action (load, revealed,
+//     intersect, or polling) inherits hx-confirm or hx-prompt, so a dialog
+//     opens by itself.
+//
+// Use Config.ResolveInheritance when htmx runs with disableInheritance.
+func ResolveInheritance(b h.Builder) (Inheritance, error) {
+	return Config{}.ResolveInheritance(b)
+}
+
+// ResolveInheritance is like the package-level ResolveInheritance, but
+// honors c.DisableInheritance, under which elements only inherit the
+// attributes their ancestors list in hx-inherit.
+func (c Config) ResolveInheritance(b h.Builder) (Inheritance, error) {
+	tracker := &inheritanceTracker{disableInheritance: c.DisableInheritance}
+	ctx := h.WithElementHooks(context.Background(), tracker)
+	if err := h.RenderContext(ctx, io.Discard, b); err != nil {
+		return nil, err
+	}
+	return tracker.resolved, nil
+}
+
+// ValidateInheritance returns every warning found by ResolveInheritance,
+// joined, or nil. Rendering errors are returned as-is. Intended for test
+// suites:
+//
+//	if err := hx.ValidateInheritance(page); err != nil {
+//	    t.Error(err)
+//	}
+func ValidateInheritance(b h.Builder) error {
+	in, err := ResolveInheritance(b)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, el := range in {
+		for _, warn := range el.Warnings {
+			errs = append(errs, warn)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// inheritanceFrame is an open element seen by inheritanceTracker.
+type inheritanceFrame struct {
+	label    string
+	attrs    map[string]string // hx-* attributes, without any data- prefix
+	request  bool
+	disabled bool
+}
+
+// inheritanceTracker is the ElementHook used by ResolveInheritance.
+type inheritanceTracker struct {
+	disableInheritance bool
+	stack              []inheritanceFrame
+	resolved           Inheritance
+}
+
+func (t *inheritanceTracker) BeforeOpen(w *h.Writer, tag string, as h.Attributes) (h.Attributes, error) {
+	frame := inheritanceFrame{label: elementLabel(tag, as), attrs: map[string]string{}}
+	var own h.Attributes
+	for _, a := range as {
+		name := strings.TrimPrefix(strings.ToLower(a.Name), "data-")
+		if !strings.HasPrefix(name, "hx-") {
+			continue
+		}
+		own = append(own, a)
+		frame.attrs[name] = a.Value
+		frame.request = frame.request || slices.Contains(requestAttrs, name)
+	}
+	_, frame.disabled = frame.attrs["hx-disable"]
+	if n := len(t.stack); n > 0 && t.stack[n-1].disabled {
+		frame.disabled = true
+	}
+
+	el := ResolvedElement{Element: frame.label, Depth: len(t.stack), Own: own, Disabled: frame.disabled}
+	if !frame.disabled {
+		for _, name := range inheritableAttrs {
+			if _, ok := frame.attrs[name]; ok {
+				continue
+			}
+			if i := t.closest(name); i >= 0 {
+				from := t.stack[i]
+				el.Inherited = append(el.Inherited, InheritedAttr{h.Attr(name, from.attrs[name]), from.label})
+				if frame.request {
+					if warn := inheritanceWarning(frame, from, name); warn != nil {
+						el.Warnings = append(el.Warnings, warn)
+					}
+				}
+			}
+		}
+	}
+	if len(own) > 0 {
+		t.resolved = append(t.resolved, el)
+	}
+	t.stack = append(t.stack, frame)
+	return as, nil
+}
+
+func (t *inheritanceTracker) AfterClose(w *h.Writer, tag string, as h.Attributes) error {
+	if n := len(t.stack); n > 0 {
+		t.stack = t.stack[:n-1]
+	}
+	return nil
+}
+
+// closest returns the index in t.stack of the ancestor the open element
+// inherits name from, or -1, following htmx's getClosestAttributeValue.
+func (t *inheritanceTracker) closest(name string) int {
+	for i := len(t.stack) - 1; i >= 0; i-- {
+		attrs := t.stack[i].attrs
+		if t.disableInheritance {
+			if !listsAttr(attrs["hx-inherit"], name) {
+				continue
+			}
+		} else if listsAttr(attrs["hx-disinherit"], name) {
+			return -1
+		}
+		if v := attrs[name]; v != "" {
+			if v == "unset" {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// listsAttr reports whether an hx-inherit or hx-disinherit value covers name.
+func listsAttr(list, name string) bool {
+	if list == "*" {
+		return true
+	}
+	for field := range strings.FieldsSeq(list) {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritanceWarning returns the warning for a requesting element inheriting
+// name from ancestor, or nil.
+func inheritanceWarning(el, ancestor inheritanceFrame, name string) *InheritanceError {
+	if ancestor.request {
+		return &InheritanceError{
+			Element: el.label, Attr: name, From: ancestor.label,
+			Problem: "it is set for the ancestor's own request but also applies to this one",
+			Hint:    fmt.Sprintf("add hx-disinherit=%q to <%s> or set %s on this element", name, ancestor.label, name),
+		}
+	}
+	if (name == "hx-confirm" || name == "hx-prompt") && automaticTrigger(el.attrs["hx-trigger"]) {
+		return &InheritanceError{
+			Element: el.label, Attr: name, From: ancestor.label,
+			Problem: "the request is triggered without user action, so the dialog opens by itself",
+			Hint:    fmt.Sprintf("set %s=\"unset\" on this element", name),
+		}
+	}
+	return nil
+}
+
+// automaticTrigger reports whether an hx-trigger value fires without user
+// action.
+func automaticTrigger(trigger string) bool {
+	for spec := range strings.SplitSeq(trigger, ",") {
+		if fields := strings.Fields(spec); len(fields) > 0 {
+			switch fields[0] {
+			case "load", "revealed", "intersect", "every":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// elementLabel returns tag#id, tag.class, or tag for reports.
+func elementLabel(tag string, as h.Attributes) string {
+	if id, ok := as.Get("id"); ok && id != "" {
+		return tag + "#" + id
+	}
+	if class, ok := as.Get("class"); ok {
+		if fields := strings.Fields(class); len(fields) > 0 {
+			return tag + "." + fields[0]
+		}
+	}
+	return tag
+}
@@ -0,0 +1,56 @@
+package hx
+
+import "github.com/jeffh/htmlgen/h"
+
+// OOB wraps b for an out-of-band swap: hx-swap-oob is added to the root
+// element b renders, so a component can be reused in a response without
+// threading the attribute through it. target is a CSS selector for the
+// element to replace; empty swaps the element with the same id. swap
+// defaults to OuterHTML:
+//
+//	hx.OOB(CartBadge(cart), hx.InnerHTML, "#cart-count")
+//	// <span class="badge" hx-swap-oob="innerHTML:#cart-count">3</span>
+//
+// b should render a single root element; if it renders none, OOB adds
+// nothing.
+func OOB(b h.Builder, swap SwapStrategy, target string) h.Builder {
+	value := string(swap)
+	switch {
+	case target != "" && swap == "":
+		value = string(OuterHTML) + ":" + target
+	case target != "":
+		value += ":" + target
+	case swap == "":
+		value = "true"
+	}
+	return oobBuilder{h.RootAttrs(b, SwapOOB(value))}
+}
+
+// OOBGroup batches out-of-band fragments into one response body. Fragments
+// not already wrapped with OOB are swapped by id (hx-swap-oob="true"):
+//
+//	h.Fragment(
+//	    updatedRow,
+//	    hx.OOBGroup(
+//	        CartBadge(cart),
+//	        hx.OOB(flash, hx.AfterBegin, "#messages"),
+//	    ),
+//	)
+func OOBGroup(fragments ...h.Builder) h.Builder {
+	children := make([]h.Builder, 0, len(fragments))
+	for _, b := range fragments {
+		if b == nil {
+			continue
+		}
+		if _, ok := b.(oobBuilder); !ok {
+			b = OOB(b, "", "")
+		}
+		children = append(children, b)
+	}
+	return h.Fragment(children...)
+}
+
+// oobBuilder marks a builder wrapped by OOB so OOBGroup leaves it as is.
+type oobBuilder struct {
+	h.Builder
+}
@@ -0,0 +1,249 @@
+package hx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Request headers sent by HTMX.
+const (
+	HeaderRequest        = "HX-Request"
+	HeaderBoosted        = "HX-Boosted"
+	HeaderCurrentURL     = "HX-Current-URL"
+	HeaderHistoryRestore = "HX-History-Restore-Request"
+	HeaderPrompt         = "HX-Prompt"
+	HeaderTarget         = "HX-Target"
+	HeaderTriggerName    = "HX-Trigger-Name"
+	HeaderTrigger        = "HX-Trigger"
+)
+
+// Response headers understood by HTMX.
+const (
+	HeaderLocation           = "HX-Location"
+	HeaderPushURL            = "HX-Push-Url"
+	HeaderRedirect           = "HX-Redirect"
+	HeaderRefresh            = "HX-Refresh"
+	HeaderReplaceURL         = "HX-Replace-Url"
+	HeaderReswap             = "HX-Reswap"
+	HeaderRetarget           = "HX-Retarget"
+	HeaderReselect           = "HX-Reselect"
+	HeaderTriggerAfterSettle = "HX-Trigger-After-Settle"
+	HeaderTriggerAfterSwap   = "HX-Trigger-After-Swap"
+)
+
+// IsHTMX reports whether r was issued by HTMX.
+func IsHTMX(r *http.Request) bool { return r.Header.Get(HeaderRequest) == "true" }
+
+// IsBoosted reports whether r was issued by an element using hx-boost.
+func IsBoosted(r *http.Request) bool { return r.Header.Get(HeaderBoosted) == "true" }
+
+// IsHistoryRestore reports whether r restores history after a cache miss.
+func IsHistoryRestore(r *http.Request) bool { return r.Header.Get(HeaderHistoryRestore) == "true" }
+
+// CurrentURL returns the browser's current URL when r was issued.
+func CurrentURL(r *http.Request) string { return r.Header.Get(HeaderCurrentURL) }
+
+// PromptResponse returns the user's response to an hx-prompt.
+func PromptResponse(r *http.Request) string { return r.Header.Get(HeaderPrompt) }
+
+// RequestTarget returns the id of the target element, if it has one.
+// (Target is the hx-target attribute constructor.)
+func RequestTarget(r *http.Request) string { return r.Header.Get(HeaderTarget) }
+
+// TriggerName returns the name of the element that triggered r, if it has one.
+func TriggerName(r *http.Request) string { return r.Header.Get(HeaderTriggerName) }
+
+// TriggerID returns the id of the element that triggered r, if it has one.
+func TriggerID(r *http.Request) string { return r.Header.Get(HeaderTrigger) }
+
+// Response wraps an http.ResponseWriter with methods that set HTMX response
+// headers. Headers must be set before the response body is written:
+//
+//	res := hx.NewResponse(w)
+//	res.Trigger("itemSaved").Retarget("#items").Reswap(hx.BeforeEnd)
+//	h.Render(res, itemRow(item))
+type Response struct {
+	http.ResponseWriter
+	triggers map[string][]triggerEvent
+}
+
+type triggerEvent struct {
+	name   string
+	detail any
+}
+
+// NewResponse wraps w.
+func NewResponse(w http.ResponseWriter) *Response {
+	return &Response{ResponseWriter: w}
+}
+
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (r *Response) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// Trigger triggers client-side events as soon as the response is received.
+func (r *Response) Trigger(events ...string) *Response {
+	return r.addTriggers(HeaderTrigger, events)
+}
+
+// TriggerCustom triggers events declared with CustomEvent, like Trigger.
+func (r *Response) TriggerCustom(events ...Event) *Response {
+	names := make([]string, len(events))
+	for i, e := range events {
+		names[i] = string(e)
+	}
+	return r.addTriggers(HeaderTrigger, names)
+}
+
+// TriggerAfterSwap triggers client-side events after the swap step.
+func (r *Response) TriggerAfterSwap(events ...string) *Response {
+	return r.addTriggers(HeaderTriggerAfterSwap, events)
+}
+
+// TriggerAfterSettle triggers client-side events after the settle step.
+func (r *Response) TriggerAfterSettle(events ...string) *Response {
+	return r.addTriggers(HeaderTriggerAfterSettle, events)
+}
+
+// TriggerDetail triggers a client-side event carrying detail, which must be
+// JSON-encodable. The event's detail is available as evt.detail.
+func (r *Response) TriggerDetail(event string, detail any) *Response {
+	return r.setTriggers(HeaderTrigger, triggerEvent{event, detail})
+}
+
+func (r *Response) addTriggers(header string, events []string) *Response {
+	evts := make([]triggerEvent, len(events))
+	for i, e := range events {
+		evts[i] = triggerEvent{name: e}
+	}
+	return r.setTriggers(header, evts...)
+}
+
+// setTriggers records events for header and rewrites it. Plain event names
+// are sent as a comma-separated list; if any event has a detail, the header
+// is sent as a JSON object instead.
+func (r *Response) setTriggers(header string, events ...triggerEvent) *Response {
+	if r.triggers == nil {
+		r.triggers = map[string][]triggerEvent{}
+	}
+	all := append(r.triggers[header], events...)
+	r.triggers[header] = all
+
+	hasDetail := false
+	for _, e := range all {
+		if e.detail != nil {
+			hasDetail = true
+			break
+		}
+	}
+	if !hasDetail {
+		names := make([]string, len(all))
+		for i, e := range all {
+			names[i] = e.name
+		}
+		r.Header().Set(header, strings.Join(names, ", "))
+		return r
+	}
+
+	obj := make(map[string]any, len(all))
+	for _, e := range all {
+		obj[e.name] = e.detail
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		panic("hx: trigger detail is not JSON-encodable: " + err.Error())
+	}
+	r.Header().Set(header, string(data))
+	return r
+}
+
+// Redirect performs a full-page client-side redirect to url.
+func (r *Response) Redirect(url string) *Response {
+	r.Header().Set(HeaderRedirect, url)
+	return r
+}
+
+// Location performs a client-side redirect to url without a full page reload,
+// as if an hx-boosted link was followed.
+func (r *Response) Location(url string) *Response {
+	r.Header().Set(HeaderLocation, url)
+	return r
+}
+
+// PushURL pushes url into the browser history. Pass "false" to prevent
+// the history from being updated.
+func (r *Response) PushURL(url string) *Response {
+	r.Header().Set(HeaderPushURL, url)
+	return r
+}
+
+// ReplaceURL replaces the current URL in the browser location bar.
+func (r *Response) ReplaceURL(url string) *Response {
+	r.Header().Set(HeaderReplaceURL, url)
+	return r
+}
+
+// Refresh makes the client perform a full page refresh.
+func (r *Response) Refresh() *Response {
+	r.Header().Set(HeaderRefresh, "true")
+	return r
+}
+
+// Retarget overrides the hx-target of the triggering element with a CSS selector.
+func (r *Response) Retarget(selector string) *Response {
+	r.Header().Set(HeaderRetarget, selector)
+	return r
+}
+
+// Reselect overrides the hx-select of the triggering element with a CSS selector.
+func (r *Response) Reselect(selector string) *Response {
+	r.Header().Set(HeaderReselect, selector)
+	return r
+}
+
+// Reswap overrides the hx-swap of the triggering element.
+func (r *Response) Reswap(strategy SwapStrategy, mods ...SwapMod) *Response {
+	b := &swapBuilder{strategy: strategy}
+	for _, mod := range mods {
+		mod.applySwap(b)
+	}
+	r.Header().Set(HeaderReswap, b.String())
+	return r
+}
+
+// Status codes with HTMX-specific meaning.
+const (
+	// StatusStopPolling stops an element polling with hx-trigger="every ...".
+	StatusStopPolling = 286
+)
+
+// StopPolling responds with status 286, which makes HTMX cancel the polling
+// trigger of the requesting element. Any body is swapped as usual.
+func StopPolling(w http.ResponseWriter) { w.WriteHeader(StatusStopPolling) }
+
+// NoContent responds with status 204, which HTMX treats as "nothing to
+// swap": the target is left unchanged but response headers such as
+// HX-Trigger are still processed.
+func NoContent(w http.ResponseWriter) { w.WriteHeader(http.StatusNoContent) }
+
+// NoSwap responds with status 200 and HX-Reswap: none, leaving the target
+// unchanged while still allowing a body for out-of-band swaps.
+func NoSwap(w http.ResponseWriter) {
+	w.Header().Set(HeaderReswap, string(None))
+	w.WriteHeader(http.StatusOK)
+}
+
+// SwapError responds with an error status and redirects the swap of the
+// following body into selector with strategy, so validation messages can be
+// shown somewhere other than the original target:
+//
+//	hx.SwapError(w, http.StatusUnprocessableEntity, "#errors", hx.InnerHTML)
+//	h.Render(w, errorList(errs))
+//
+// HTMX only swaps 4xx and 5xx responses when its responseHandling config
+// allows it, e.g. {code:"422", swap:true}.
+func SwapError(w http.ResponseWriter, status int, selector string, strategy SwapStrategy) {
+	w.Header().Set(HeaderRetarget, selector)
+	w.Header().Set(HeaderReswap, string(strategy))
+	w.WriteHeader(status)
+}
@@ -87,6 +87,62 @@ func TriggerAttr(event string) h.Attribute {
 	return h.Attr("hx-trigger", event)
 }
 
+// Event is an event name for hx-trigger and the HX-Trigger response
+// headers. Declare application events once with CustomEvent and use them on
+// both sides so the names cannot drift apart:
+//
+//	var OrderCreated = hx.CustomEvent("order:created")
+//
+//	h.Div(hx.Get("/orders"), hx.TriggerCustom(OrderCreated, hx.FromDocument()).Attr())
+//	hx.NewResponse(w).TriggerCustom(OrderCreated)
+type Event string
+
+// Standard DOM and HTMX events.
+const (
+	EventClick      Event = "click"
+	EventDblClick   Event = "dblclick"
+	EventChange     Event = "change"
+	EventInput      Event = "input"
+	EventSubmit     Event = "submit"
+	EventKeyDown    Event = "keydown"
+	EventKeyUp      Event = "keyup"
+	EventFocus      Event = "focus"
+	EventBlur       Event = "blur"
+	EventMouseEnter Event = "mouseenter"
+	EventMouseLeave Event = "mouseleave"
+	EventSearch     Event = "search"
+	EventLoad       Event = "load"      // Fires when the element is loaded
+	EventRevealed   Event = "revealed"  // Fires when the element is scrolled into view
+	EventIntersect  Event = "intersect" // Fires when the element intersects the viewport
+)
+
+// CustomEvent returns the Event named name, such as "order:created". It
+// panics if name is empty or contains whitespace, a comma, or a bracket,
+// which would be misread in an hx-trigger list or HX-Trigger header.
+func CustomEvent(name string) Event {
+	if name == "" || strings.ContainsAny(name, " \t\n\r,[]") {
+		panic("hx.CustomEvent: invalid event name " + strconv.Quote(name))
+	}
+	return Event(name)
+}
+
+// String returns the event name.
+func (e Event) String() string { return string(e) }
+
+// TriggerCustom creates a trigger for event, like Trigger.
+func TriggerCustom(event Event, mods ...TriggerMod) *TriggerBuilder {
+	return Trigger(string(event), mods...)
+}
+
+// AndCustom adds a trigger for event to the builder, like And.
+//
+// Example:
+//
+//	hx.TriggerLoad().AndCustom(OrderCreated, hx.FromDocument())
+func (t *TriggerBuilder) AndCustom(event Event, mods ...TriggerMod) *TriggerBuilder {
+	return t.And(string(event), mods...)
+}
+
 // Once makes the trigger fire only once.
 func Once() TriggerMod {
 	return triggerModFunc(func(s *triggerSpec) {
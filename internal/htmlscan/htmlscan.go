@@ -0,0 +1,203 @@
+// Package htmlscan is a minimal, allocation-light scanner for the HTML
+// produced by the h package. It reports start and end tags with their
+// attributes, skipping comments and doctypes. It is not a conforming HTML
+// parser, but tolerates arbitrary input.
+package htmlscan
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+// Attr is a scanned attribute with its value unescaped.
+type Attr struct {
+	Name  string
+	Value string
+}
+
+// Tag is a scanned start or end tag.
+type Tag struct {
+	// Name is the lowercased element name.
+	Name string
+	// End is true for end tags (</name>), which carry no attributes.
+	End bool
+	// SelfClosing is true for start tags ending in "/>".
+	SelfClosing bool
+	Attrs       []Attr
+	// Body holds the raw contents of <script> and <style> elements.
+	Body []byte
+}
+
+// Attr returns the value of the named attribute and whether it is present.
+func (t *Tag) Attr(name string) (string, bool) {
+	for _, a := range t.Attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// Value returns the value of the named attribute, or "" if absent.
+func (t *Tag) Value(name string) string {
+	v, _ := t.Attr(name)
+	return v
+}
+
+// voidElements never have end tags.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true,
+	"img": true, "input": true, "link": true, "meta": true, "source": true,
+	"track": true, "wbr": true,
+}
+
+// IsVoid reports whether the element has no end tag.
+func (t *Tag) IsVoid() bool { return t.SelfClosing || voidElements[t.Name] }
+
+// Scan walks the tags in doc, calling fn for each start and end tag.
+// Comments, doctypes, and processing instructions are skipped. For <script>
+// and <style> the raw element contents are captured in Body and the matching
+// end tag is reported afterwards.
+func Scan(doc []byte, fn func(Tag)) {
+	i := 0
+	for i < len(doc) {
+		lt := bytes.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			return
+		}
+		i += lt + 1
+		if i >= len(doc) {
+			return
+		}
+		switch {
+		case bytes.HasPrefix(doc[i:], []byte("!--")):
+			end := bytes.Index(doc[i:], []byte("-->"))
+			if end < 0 {
+				return
+			}
+			i += end + 3
+			continue
+		case doc[i] == '/':
+			end := bytes.IndexByte(doc[i:], '>')
+			if end < 0 {
+				return
+			}
+			name := bytes.TrimSpace(doc[i+1 : i+end])
+			if len(name) > 0 {
+				fn(Tag{Name: strings.ToLower(string(name)), End: true})
+			}
+			i += end + 1
+			continue
+		case doc[i] == '!' || doc[i] == '?':
+			end := bytes.IndexByte(doc[i:], '>')
+			if end < 0 {
+				return
+			}
+			i += end + 1
+			continue
+		}
+
+		start := i
+		for i < len(doc) && isNameByte(doc[i]) {
+			i++
+		}
+		if i == start {
+			continue
+		}
+		t := Tag{Name: strings.ToLower(string(doc[start:i]))}
+		i = scanAttrs(doc, i, &t)
+
+		if t.Name == "script" || t.Name == "style" {
+			closing := []byte("</" + t.Name)
+			end := IndexFold(doc[i:], closing)
+			if end < 0 {
+				t.Body = doc[i:]
+				i = len(doc)
+			} else {
+				t.Body = doc[i : i+end]
+				i += end
+			}
+		}
+		fn(t)
+	}
+}
+
+// scanAttrs parses attributes starting at i until the end of the tag,
+// returning the index just past the closing '>'.
+func scanAttrs(doc []byte, i int, t *Tag) int {
+	for i < len(doc) {
+		for i < len(doc) && isSpace(doc[i]) {
+			i++
+		}
+		if i >= len(doc) {
+			return i
+		}
+		if doc[i] == '>' {
+			return i + 1
+		}
+		if doc[i] == '/' {
+			i++
+			if i < len(doc) && doc[i] == '>' {
+				t.SelfClosing = true
+			}
+			continue
+		}
+		start := i
+		for i < len(doc) && !isSpace(doc[i]) && doc[i] != '=' && doc[i] != '>' && doc[i] != '/' {
+			i++
+		}
+		name := strings.ToLower(string(doc[start:i]))
+		value := ""
+		for i < len(doc) && isSpace(doc[i]) {
+			i++
+		}
+		if i < len(doc) && doc[i] == '=' {
+			i++
+			for i < len(doc) && isSpace(doc[i]) {
+				i++
+			}
+			if i < len(doc) && (doc[i] == '"' || doc[i] == '\'') {
+				q := doc[i]
+				i++
+				vstart := i
+				for i < len(doc) && doc[i] != q {
+					i++
+				}
+				value = string(doc[vstart:i])
+				if i < len(doc) {
+					i++
+				}
+			} else {
+				vstart := i
+				for i < len(doc) && !isSpace(doc[i]) && doc[i] != '>' {
+					i++
+				}
+				value = string(doc[vstart:i])
+			}
+		}
+		if name != "" {
+			t.Attrs = append(t.Attrs, Attr{Name: name, Value: html.UnescapeString(value)})
+		}
+	}
+	return i
+}
+
+func isNameByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == ':'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// IndexFold is bytes.Index with ASCII case-insensitive matching.
+func IndexFold(s, sep []byte) int {
+	n := len(sep)
+	for i := 0; i+n <= len(s); i++ {
+		if bytes.EqualFold(s[i:i+n], sep) {
+			return i
+		}
+	}
+	return -1
+}
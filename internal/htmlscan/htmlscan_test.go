@@ -0,0 +1,46 @@
+package htmlscan
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	var names []string
+	Scan([]byte(`<!DOCTYPE html><!-- <img src=x> --><p a=1 b='2' c>t</p><br/><script>if (a<b) {}</script>`), func(tag Tag) {
+		name := tag.Name
+		if tag.End {
+			name = "/" + name
+		}
+		names = append(names, name)
+		switch tag.Name {
+		case "p":
+			if !tag.End && (len(tag.Attrs) != 3 || tag.Value("a") != "1" || tag.Value("b") != "2" || tag.Attrs[2].Name != "c") {
+				t.Errorf("unexpected attrs: %+v", tag.Attrs)
+			}
+		case "br":
+			if !tag.IsVoid() || !tag.SelfClosing {
+				t.Errorf("expected self-closing br: %+v", tag)
+			}
+		case "script":
+			if !tag.End && string(tag.Body) != "if (a<b) {}" {
+				t.Errorf("script body = %q", tag.Body)
+			}
+		}
+	})
+	expected := []string{"p", "/p", "br", "script", "/script"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("tags = %v, want %v", names, expected)
+	}
+}
+
+func TestAttrUnescapes(t *testing.T) {
+	Scan([]byte(`<a href="/x?a=1&amp;b=2">`), func(tag Tag) {
+		if v, ok := tag.Attr("href"); !ok || v != "/x?a=1&b=2" {
+			t.Errorf("href = %q, %v", v, ok)
+		}
+		if _, ok := tag.Attr("title"); ok {
+			t.Error("unexpected title attribute")
+		}
+	})
+}
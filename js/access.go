@@ -1,7 +1,5 @@
 package js
 
-import "strings"
-
 // Prop accesses a property on a callable expression.
 // Example: Prop(Ident("document"), "body") => document.body
 func Prop(obj Callable, name string) Callable {
@@ -13,8 +11,15 @@ type propAccess struct {
 	prop string
 }
 
-func (p propAccess) js(sb *strings.Builder) {
-	p.obj.js(sb)
+func (p propAccess) js(sb *printer) {
+	if l, ok := p.obj.(literal); ok && l.value != "" && l.value[0] >= '0' && l.value[0] <= '9' {
+		// 1.toFixed would read the dot as a decimal point
+		sb.WriteString("(")
+		l.js(sb)
+		sb.WriteString(")")
+	} else {
+		writeOperand(sb, p.obj, precMember)
+	}
 	sb.WriteString(".")
 	sb.WriteString(p.prop)
 }
@@ -32,8 +37,8 @@ type indexAccess struct {
 	index Expr
 }
 
-func (i indexAccess) js(sb *strings.Builder) {
-	i.obj.js(sb)
+func (i indexAccess) js(sb *printer) {
+	writeOperand(sb, i.obj, precMember)
 	sb.WriteString("[")
 	i.index.js(sb)
 	sb.WriteString("]")
@@ -51,8 +56,8 @@ type funcCall struct {
 	args []Expr
 }
 
-func (f funcCall) js(sb *strings.Builder) {
-	f.fn.js(sb)
+func (f funcCall) js(sb *printer) {
+	writeOperand(sb, f.fn, precMember)
 	sb.WriteString("(")
 	for i, arg := range f.args {
 		if i > 0 {
@@ -84,9 +89,9 @@ type newExpr struct {
 	args        []Expr
 }
 
-func (n newExpr) js(sb *strings.Builder) {
+func (n newExpr) js(sb *printer) {
 	sb.WriteString("new ")
-	n.constructor.js(sb)
+	writeOperand(sb, n.constructor, precMember)
 	sb.WriteString("(")
 	for i, arg := range n.args {
 		if i > 0 {
@@ -109,8 +114,8 @@ type optionalChain struct {
 	prop string
 }
 
-func (o optionalChain) js(sb *strings.Builder) {
-	o.obj.js(sb)
+func (o optionalChain) js(sb *printer) {
+	writeOperand(sb, o.obj, precMember)
 	sb.WriteString("?.")
 	sb.WriteString(o.prop)
 }
@@ -128,8 +133,8 @@ type optionalMethodCall struct {
 	args   []Expr
 }
 
-func (o optionalMethodCall) js(sb *strings.Builder) {
-	o.obj.js(sb)
+func (o optionalMethodCall) js(sb *printer) {
+	writeOperand(sb, o.obj, precMember)
 	sb.WriteString("?.")
 	sb.WriteString(o.method)
 	sb.WriteString("(")
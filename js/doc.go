@@ -37,12 +37,16 @@
 // Use these functions to create JavaScript literals safely:
 //
 //	js.String("hello")     // "hello" (JSON-escaped, prevents XSS)
+//	js.StringForAttr("it's") // "it\u0027s" (also escapes ' and ` for foreign templates)
 //	js.Int(42)             // 42
 //	js.Float(3.14)         // 3.14
 //	js.Bool(true)          // true
 //	js.Null()              // null
 //	js.Undefined()         // undefined
 //
+// [FuzzEscape] is a fuzz target for this escaping that applications can
+// run from their own fuzz tests.
+//
 // For complex values, use [JSON], [Array], or [Object]:
 //
 //	js.JSON(map[string]int{"a": 1})           // {"a":1}
@@ -86,29 +90,37 @@
 // All standard JavaScript operators are available:
 //
 //	// Arithmetic
-//	js.Add(js.Int(1), js.Int(2))    // (1 + 2)
-//	js.Sub(js.Int(5), js.Int(3))    // (5 - 3)
-//	js.Mul(js.Int(4), js.Int(2))    // (4 * 2)
-//	js.Div(js.Int(10), js.Int(2))   // (10 / 2)
+//	js.Add(js.Int(1), js.Int(2))    // 1 + 2
+//	js.Sub(js.Int(5), js.Int(3))    // 5 - 3
+//	js.Mul(js.Int(4), js.Int(2))    // 4 * 2
+//	js.Div(js.Int(10), js.Int(2))   // 10 / 2
 //
 //	// Comparison (strict by default)
-//	js.Eq(js.Ident("x"), js.Int(5))       // (x === 5)
-//	js.NotEq(js.Ident("x"), js.Null())    // (x !== null)
-//	js.Lt(js.Ident("x"), js.Int(10))      // (x < 10)
-//	js.Gt(js.Ident("x"), js.Int(0))       // (x > 0)
+//	js.Eq(js.Ident("x"), js.Int(5))       // x === 5
+//	js.NotEq(js.Ident("x"), js.Null())    // x !== null
+//	js.Lt(js.Ident("x"), js.Int(10))      // x < 10
+//	js.Gt(js.Ident("x"), js.Int(0))       // x > 0
 //
 //	// Logical
-//	js.And(js.Ident("a"), js.Ident("b"))  // (a && b)
-//	js.Or(js.Ident("a"), js.Ident("b"))   // (a || b)
+//	js.And(js.Ident("a"), js.Ident("b"))  // a && b
+//	js.Or(js.Ident("a"), js.Ident("b"))   // a || b
 //	js.Not(js.Ident("x"))                 // !x
 //
 //	// Ternary
 //	js.Ternary(js.Ident("cond"), js.String("yes"), js.String("no"))
-//	// (cond ? "yes" : "no")
+//	// cond ? "yes" : "no"
 //
 //	// Nullish coalescing
 //	js.NullishCoalesce(js.Ident("x"), js.String("default"))
-//	// (x ?? "default")
+//	// x ?? "default"
+//
+// Parentheses are added only where precedence requires them:
+//
+//	js.Mul(js.Add(js.Ident("a"), js.Ident("b")), js.Ident("c"))  // (a + b) * c
+//	js.Add(js.Ident("a"), js.Mul(js.Ident("b"), js.Ident("c")))  // a + b * c
+//
+// Set [AlwaysParenthesize] to keep the fully parenthesized output of earlier
+// versions.
 //
 // # Statements
 //
@@ -118,6 +130,10 @@
 //	js.Let("x", js.Int(5))           // let x = 5
 //	js.Const("PI", js.Float(3.14))   // const PI = 3.14
 //
+//	// Destructuring
+//	js.LetDestructure([]string{"a", "b"}, js.Ident("pair"))         // let [a, b] = pair
+//	js.LetDestructureObj(map[string]string{"id": ""}, js.Ident("d")) // let {id} = d
+//
 //	// Assignment
 //	js.Assign(js.Ident("x"), js.Int(10))  // x = 10
 //	js.AddAssign(js.Ident("x"), js.Int(1)) // x += 1
@@ -132,11 +148,43 @@
 //	)
 //	// if (x === 0) { return null }
 //
+//	// Loops: For, ForOf, ForIn, While, DoWhile
+//	js.ForOf("el", js.QuerySelectorAll(js.String(".item")),
+//	    js.ExprStmt(js.ClassListAdd(js.Ident("el"), js.String("active"))),
+//	)
+//	// for (const el of document.querySelectorAll(".item")) { el.classList.add("active") }
+//
+//	// Error handling: Try, TryFinally, TryCatchFinally
+//	js.Try(
+//	    []js.Stmt{js.Const("data", js.Method(js.Ident("JSON"), "parse", js.Ident("text")))},
+//	    "err",
+//	    []js.Stmt{js.ExprStmt(js.ConsoleError(js.Ident("err")))},
+//	)
+//	// try { const data = JSON.parse(text) } catch (err) { console.error(err) }
+//
+//	// Multi-branch: Switch with CaseClause and DefaultClause
+//	js.Switch(js.EventKey(),
+//	    js.CaseClause(js.String("Escape"), js.ExprStmt(js.Call(js.Ident("close"))), js.Break()),
+//	    js.DefaultClause(js.ReturnVoid()),
+//	)
+//	// switch (event.key) { case "Escape": close(); break; default: return; }
+//
 // To use an expression as a statement, wrap it with [ExprStmt]:
 //
 //	js.ExprStmt(js.ConsoleLog(js.String("hello")))
 //	// console.log("hello")
 //
+// For <script> blocks or debugging, [Format] renders statements as indented
+// multi-line JavaScript instead of the single line produced by [Handler]:
+//
+//	js.Format(js.If(js.Ident("ok"), js.ExprStmt(js.Call(js.Ident("save")))))
+//	// if (ok) {
+//	//   save();
+//	// }
+//
+// [ScriptTag] and [ScriptModule] wrap formatted statements in a <script>
+// element for page-level code.
+//
 // # Event Handlers
 //
 // The [Handler] function combines statements into a handler string:
@@ -157,11 +205,30 @@
 //	js.OnKeyDown(...)    // onkeydown="..."
 //	js.OnLoad(...)       // onload="..."
 //
+// These functions escape every string, template, and JSON literal in the
+// handler as [StringForAttr] does, so the value stays safe even when it is
+// copied into a single-quoted attribute by another template system. [Raw]
+// code is written as given.
+//
 // For custom events, use [On]:
 //
 //	js.On("touchstart", js.ExprStmt(js.ConsoleLog(js.String("touched"))))
 //	// ontouchstart="console.log(\"touched\")"
 //
+// In page scripts, register listeners with [AddEventListener] (options:
+// [Capture], [Passive], [Once], [Signal]) and defer setup with [DocumentReady]:
+//
+//	js.DocumentReady(
+//	    js.ExprStmt(js.AddEventListener(js.Window, "scroll", js.Ident("onScroll"), js.Passive())),
+//	)
+//
+// Emit custom events for HTMX (hx-trigger="... from:body") or Datastar
+// (data-on-*) listeners with [DispatchCustomEvent] (options: [Bubbles],
+// [Cancelable], [Composed]):
+//
+//	js.DispatchCustomEvent(js.EventThis, "item-saved", js.Ident("item"), js.Bubbles())
+//	// this.dispatchEvent(new CustomEvent("item-saved", {"detail": item, "bubbles": true}))
+//
 // # Built-in Helpers
 //
 // The package provides helpers for common JavaScript patterns:
@@ -177,6 +244,13 @@
 //	js.GetElementById(js.String("myId"))     // document.getElementById("myId")
 //	js.QuerySelector(js.String(".myClass"))  // document.querySelector(".myClass")
 //
+// Elements, wrapped with [El] for fluent access:
+//
+//	form := js.El(js.EventThis).Closest("form")
+//	form.QuerySelector("[name=email]").Value()  // this.closest("form").querySelector("[name=email]").value
+//	form.DatasetGet("user-id")                  // this.closest("form").dataset.userId
+//	form.QuerySelector(".error").Hidden(true)   // this.closest("form").querySelector(".error").hidden = true
+//
 // Event handling:
 //
 //	js.PreventDefault()      // event.preventDefault()
@@ -209,14 +283,14 @@
 //
 //	// Expression body
 //	js.ArrowFunc([]string{"x"}, js.Mul(js.Ident("x"), js.Int(2)))
-//	// x => (x * 2)
+//	// x => x * 2
 //
 //	// Statement body
 //	js.ArrowFuncStmts([]string{"x"},
 //	    js.Let("result", js.Mul(js.Ident("x"), js.Int(2))),
 //	    js.Return(js.Ident("result")),
 //	)
-//	// x => { let result = (x * 2); return result }
+//	// x => { let result = x * 2; return result }
 //
 //	// Async arrow functions
 //	js.AsyncArrowFunc([]string{}, js.Await(js.Fetch(js.String("/api"))))
@@ -304,7 +378,16 @@
 //	    ),
 //	)
 //
-// Fetch with error handling:
+// Requests with options, and JSON responses with error handling, using
+// [FetchRequest] and [FetchJSON]:
+//
+//	js.FetchRequest(js.String("/api/items")).
+//	    Method("POST").
+//	    Header("X-CSRF-Token", js.String(token)).
+//	    JSONBody(js.Object(js.Pair("name", js.Ident("name")))).
+//	    JSON(js.Ident("render"), js.Ident("showError"))
+//
+// Fetch with error handling, written out:
 //
 //	h.Button(
 //	    js.OnClick(
@@ -0,0 +1,65 @@
+package js
+
+import "strings"
+
+// Element wraps an expression that evaluates to a DOM element, with methods
+// for common property reads, traversals, and updates. It is a Callable, so
+// it can be used anywhere an element expression is expected.
+//
+//	js.El(js.EventThis).Closest("form").QuerySelector("[name=email]").Value()
+//	// this.closest("form").querySelector("[name=email]").value
+type Element struct {
+	Callable
+}
+
+// El wraps el for fluent element access.
+func El(el Callable) Element { return Element{el} }
+
+// Value creates el.value
+func (e Element) Value() Callable { return Prop(e.Callable, "value") }
+
+// Checked creates el.checked
+func (e Element) Checked() Callable { return Prop(e.Callable, "checked") }
+
+// DatasetGet creates el.dataset.name for the data attribute name, which may
+// be given as it appears in HTML: DatasetGet("data-user-id") and
+// DatasetGet("user-id") both create el.dataset.userId.
+func (e Element) DatasetGet(name string) Callable {
+	return Prop(Prop(e.Callable, "dataset"), datasetKey(name))
+}
+
+// Closest creates el.closest(selector)
+func (e Element) Closest(selector string) Element {
+	return El(Method(e.Callable, "closest", String(selector)))
+}
+
+// QuerySelector creates el.querySelector(selector)
+func (e Element) QuerySelector(selector string) Element {
+	return El(Method(e.Callable, "querySelector", String(selector)))
+}
+
+// SetText creates el.textContent = text
+func (e Element) SetText(text Expr) Stmt { return Assign(Prop(e.Callable, "textContent"), text) }
+
+// SetHTML creates el.innerHTML = html. The markup is not sanitized; prefer
+// SetText for user-supplied content.
+func (e Element) SetHTML(html Expr) Stmt { return Assign(Prop(e.Callable, "innerHTML"), html) }
+
+// Hidden creates el.hidden = hidden
+func (e Element) Hidden(hidden bool) Stmt { return Assign(Prop(e.Callable, "hidden"), Bool(hidden)) }
+
+// datasetKey converts a data attribute name to its dataset property name,
+// following the HTML rule that "-x" becomes "X" for lowercase letters.
+func datasetKey(name string) string {
+	name = strings.TrimPrefix(name, "data-")
+	var sb strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '-' && i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z' {
+			sb.WriteByte(name[i+1] - 'a' + 'A')
+			i++
+			continue
+		}
+		sb.WriteByte(name[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,34 @@
+package js
+
+import "testing"
+
+func TestElement(t *testing.T) {
+	el := El(GetElementById(String("f")))
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"value", ToJS(el.Value()), `document.getElementById("f").value`},
+		{"checked", ToJS(El(EventThis).Checked()), `this.checked`},
+		{"dataset", ToJS(el.DatasetGet("user-id")), `document.getElementById("f").dataset.userId`},
+		{"dataset with prefix", ToJS(el.DatasetGet("data-island-ready")), `document.getElementById("f").dataset.islandReady`},
+		{"dataset camel", ToJS(el.DatasetGet("userId")), `document.getElementById("f").dataset.userId`},
+		{
+			"chain",
+			ToJS(El(EventThis).Closest("form").QuerySelector("[name=email]").Value()),
+			`this.closest("form").querySelector("[name=email]").value`,
+		},
+		{"set text", ToJSStmt(el.SetText(String("Saved"))), `document.getElementById("f").textContent = "Saved"`},
+		{"set html", ToJSStmt(el.SetHTML(Ident("markup"))), `document.getElementById("f").innerHTML = markup`},
+		{"hidden", ToJSStmt(el.QuerySelector(".error").Hidden(true)), `document.getElementById("f").querySelector(".error").hidden = true`},
+		{"as callable", ToJS(Method(el, "focus")), `document.getElementById("f").focus()`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("got %q, want %q", tt.got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package js
+
+// Render size estimates. Handler, ToJS, and the other string renderers grow
+// their output buffer once from an estimate of the rendered length instead
+// of growing it repeatedly while writing. Estimates err on the high side,
+// allowing for parentheses and string escapes.
+
+// estimator is implemented by expressions and statements that can estimate
+// their rendered length.
+type estimator interface {
+	estimateLen() int
+}
+
+// defaultEstimate is used for expressions and statements defined outside
+// this package's estimators, such as Raw code wrapped by other packages.
+const defaultEstimate = 16
+
+// estimate returns the estimated rendered length of an Expr or Stmt.
+func estimate(v any) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case estimator:
+		return v.estimateLen()
+	}
+	return defaultEstimate
+}
+
+// quotedLen returns an upper bound on the length of s as a quoted,
+// escaped string literal.
+func quotedLen(s string) int {
+	n := len(s) + 2
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\' || c == '`' || c == '$':
+			n++
+		case c == '<' || c == '>' || c == '&' || c < 0x20:
+			n += 5 // \u003c
+		}
+	}
+	return n
+}
+
+func estimateExprs(exprs []Expr) int {
+	n := 2 // Delimiters
+	for _, e := range exprs {
+		n += estimate(e) + 2
+	}
+	return n
+}
+
+func estimateStmts(stmts []Stmt) int {
+	n := 4 // "{ " and " }"
+	for _, s := range stmts {
+		n += estimate(s) + 2
+	}
+	return n
+}
+
+func estimateParams(params []string) int {
+	n := 2
+	for _, p := range params {
+		n += len(p) + 2
+	}
+	return n
+}
+
+// Expressions
+
+func (i identifier) estimateLen() int    { return len(i) }
+func (r rawExpr) estimateLen() int       { return len(r) }
+func (l literal) estimateLen() int       { return len(l.value) }
+func (l jsonLiteral) estimateLen() int   { return len(l.value) }
+func (s stringLiteral) estimateLen() int { return quotedLen(s.value) }
+func (a arrayLiteral) estimateLen() int  { return estimateExprs(a.elements) }
+func (e Element) estimateLen() int       { return estimate(e.Callable) }
+
+func (o objectLiteral) estimateLen() int {
+	n := 2
+	for _, kv := range o.pairs {
+		n += len(kv.Key) + 6 + estimate(kv.Value)
+	}
+	return n
+}
+
+func (p propAccess) estimateLen() int  { return estimate(p.obj) + 3 + len(p.prop) }
+func (i indexAccess) estimateLen() int { return estimate(i.obj) + 4 + estimate(i.index) }
+func (f funcCall) estimateLen() int    { return estimate(f.fn) + 2 + estimateExprs(f.args) }
+func (n newExpr) estimateLen() int {
+	return 6 + estimate(n.constructor) + estimateExprs(n.args)
+}
+func (o optionalChain) estimateLen() int { return estimate(o.obj) + 4 + len(o.prop) }
+func (o optionalMethodCall) estimateLen() int {
+	return estimate(o.obj) + 4 + len(o.method) + estimateExprs(o.args)
+}
+
+func (b binaryOp) estimateLen() int {
+	return estimate(b.left) + len(b.op) + estimate(b.right) + 6
+}
+func (u unaryOp) estimateLen() int { return len(u.op) + estimate(u.expr) + 2 }
+func (t ternaryOp) estimateLen() int {
+	return estimate(t.cond) + estimate(t.ifTrue) + estimate(t.ifFalse) + 10
+}
+func (g groupExpr) estimateLen() int { return estimate(g.expr) + 2 }
+func (c commaExpr) estimateLen() int { return estimateExprs(c.exprs) }
+func (s spreadExpr) estimateLen() int {
+	return 5 + estimate(s.expr)
+}
+
+func (a arrowFuncExpr) estimateLen() int {
+	return estimateParams(a.params) + 6 + estimate(a.body)
+}
+func (a arrowFuncStmtsExpr) estimateLen() int {
+	return estimateParams(a.params) + 4 + estimateStmts(a.body)
+}
+func (a asyncArrowFuncExpr) estimateLen() int {
+	return 6 + estimateParams(a.params) + 6 + estimate(a.body)
+}
+func (a asyncArrowFuncStmtsExpr) estimateLen() int {
+	return 6 + estimateParams(a.params) + 4 + estimateStmts(a.body)
+}
+func (f funcExpr) estimateLen() int { return 9 + estimateParams(f.params) + estimateStmts(f.body) }
+func (i iifeExpr) estimateLen() int { return 16 + estimateStmts(i.body) }
+func (a awaitExpr) estimateLen() int {
+	return 8 + estimate(a.expr)
+}
+
+func (t templateLiteral) estimateLen() int {
+	n := 2
+	for _, part := range t.parts {
+		if s, ok := part.(string); ok {
+			n += quotedLen(s)
+		} else {
+			n += 3 + estimate(part)
+		}
+	}
+	return n
+}
+
+// Statements
+
+func (e exprStmt) estimateLen() int { return estimate(e.expr) }
+func (a assignStmt) estimateLen() int {
+	return estimate(a.target) + 3 + estimate(a.value)
+}
+func (c compoundAssign) estimateLen() int {
+	return estimate(c.target) + len(c.op) + 4 + estimate(c.value)
+}
+func (v varDecl) estimateLen() int {
+	return len(v.kind) + 1 + len(v.name) + 3 + estimate(v.value)
+}
+func (i incrDecr) estimateLen() int   { return estimate(i.target) + 2 }
+func (r returnStmt) estimateLen() int { return 7 + estimate(r.value) }
+func (t throwStmt) estimateLen() int  { return 6 + estimate(t.value) }
+func (b breakStmt) estimateLen() int  { return 6 + len(b.label) }
+func (c continueStmt) estimateLen() int {
+	return 9 + len(c.label)
+}
+func (d debuggerStmt) estimateLen() int { return 8 }
+func (b blockStmt) estimateLen() int    { return estimateStmts(b.body) }
+func (s stmtList) estimateLen() int     { return estimateStmts(s) }
+
+func (t tryStmt) estimateLen() int {
+	n := 4 + estimateStmts(t.body)
+	if t.hasCatch {
+		n += 9 + len(t.catchParam) + estimateStmts(t.catchBody)
+	}
+	if t.finallyBody != nil {
+		n += 9 + estimateStmts(t.finallyBody)
+	}
+	return n
+}
+
+func (i ifStmt) estimateLen() int {
+	n := 5 + estimate(i.cond) + estimateStmts(i.body)
+	if i.elseBody != nil {
+		n += 6 + estimateStmts(i.elseBody)
+	}
+	return n
+}
+
+func (f forStmt) estimateLen() int {
+	return 10 + estimate(f.init) + estimate(f.cond) + estimate(f.post) + estimateStmts(f.body)
+}
+
+func (f forEachStmt) estimateLen() int {
+	return 16 + len(f.name) + estimate(f.iterable) + estimateStmts(f.body)
+}
+
+func (w whileStmt) estimateLen() int {
+	return 12 + estimate(w.cond) + estimateStmts(w.body)
+}
+
+func (s switchStmt) estimateLen() int {
+	n := 14 + estimate(s.disc)
+	for _, c := range s.clauses {
+		n += 16 + estimate(c.test)
+		for _, stmt := range c.body {
+			n += estimate(stmt) + 2
+		}
+	}
+	return n
+}
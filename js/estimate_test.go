@@ -0,0 +1,35 @@
+package js
+
+import "testing"
+
+func TestEstimateLen(t *testing.T) {
+	x := Ident("x")
+	stmts := []Stmt{
+		ExprStmt(PreventDefault()),
+		Let("value", EventValue()),
+		If(Eq(Ident("value"), String("")), Return(Null())),
+		IfElse(Gt(x, Int(0)), []Stmt{Return(String("positive"))}, []Stmt{Return(String("non-positive"))}),
+		ExprStmt(ClassListAdd(This(), String("submitted"))),
+		Const("el", Method(GetElementById(String("out")), "querySelector", String(".row[data-id='1']"))),
+		Assign(Prop(x, "textContent"), Template("Hello, ", Ident("name"), "! You have ", Prop(Ident("items"), "length"), " items")),
+		ExprStmt(FetchJSON(String("/api/items"), ArrowFunc([]string{"data"}, Call(Ident("render"), Ident("data"))), ArrowFunc([]string{"err"}, ConsoleError(Ident("err"))))),
+		For(Let("i", Int(0)), Lt(Ident("i"), Int(10)), Incr(Ident("i")), ExprStmt(ConsoleLog(Ident("i")))),
+		ForOf("item", Ident("items"), If(Not(Prop(Ident("item"), "ok")), Continue())),
+		Switch(x, CaseClause(Int(1), ExprStmt(ConsoleLog(String("one"))), Break()), DefaultClause(Throw(New(Ident("Error"), String("bad"))))),
+		TryCatchFinally([]Stmt{ExprStmt(Call(Ident("risky")))}, "e", []Stmt{ExprStmt(ConsoleError(Ident("e")))}, []Stmt{ExprStmt(Call(Ident("done")))}),
+		ExprStmt(Object(Pair("a", Int(1)), Pair("list", Array(Int(1), Int(2), Spread(Ident("rest")))))),
+		ExprStmt(Await(AsyncArrowFuncStmts(nil, Return(Ternary(Or(x, Null()), Int(1), Int(2)))))),
+		ExprStmt(String(`quotes " and <tags> & \ backslashes`)),
+		ExprStmt(OptionalCall(OptionalProp(x, "y"), "z", Int(1))),
+	}
+	for _, s := range stmts {
+		got := ToJSStmt(s)
+		est := estimate(s)
+		if est < len(got) {
+			t.Errorf("estimate %d < rendered length %d for %s", est, len(got), got)
+		}
+		if est > 2*len(got)+32 {
+			t.Errorf("estimate %d far above rendered length %d for %s", est, len(got), got)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package js
+
+// EventOption sets a field of the options object passed to the
+// CustomEvent constructor.
+type EventOption KV
+
+// Bubbles lets the event propagate to ancestors, as needed for listeners
+// on a parent element or document: {"bubbles": true}
+func Bubbles() EventOption { return EventOption{"bubbles", Bool(true)} }
+
+// Cancelable lets listeners call preventDefault: {"cancelable": true}
+func Cancelable() EventOption { return EventOption{"cancelable", Bool(true)} }
+
+// Composed lets the event cross shadow DOM boundaries: {"composed": true}
+func Composed() EventOption { return EventOption{"composed", Bool(true)} }
+
+// NewCustomEvent creates new CustomEvent(name, {"detail": detail, ...}).
+// The detail is omitted when nil, and the options object when empty.
+func NewCustomEvent(name string, detail Expr, opts ...EventOption) Callable {
+	var pairs []KV
+	if detail != nil {
+		pairs = append(pairs, KV{"detail", detail})
+	}
+	for _, o := range opts {
+		pairs = append(pairs, KV(o))
+	}
+	if len(pairs) == 0 {
+		return New(Ident("CustomEvent"), String(name))
+	}
+	return New(Ident("CustomEvent"), String(name), Object(pairs...))
+}
+
+// DispatchCustomEvent creates target.dispatchEvent(new CustomEvent(...)),
+// for components that emit events handled elsewhere, such as by HTMX
+// (hx-trigger="item-saved from:body") or Datastar (data-on-item-saved):
+//
+//	js.DispatchCustomEvent(js.EventThis, "item-saved", js.Object(js.Pair("id", js.Int(42))), js.Bubbles())
+//	// this.dispatchEvent(new CustomEvent("item-saved", {"detail": {"id": 42}, "bubbles": true}))
+func DispatchCustomEvent(target Callable, name string, detail Expr, opts ...EventOption) Callable {
+	return Method(target, "dispatchEvent", NewCustomEvent(name, detail, opts...))
+}
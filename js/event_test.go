@@ -0,0 +1,28 @@
+package js
+
+import "testing"
+
+func TestCustomEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected string
+	}{
+		{"bare", NewCustomEvent("ready", nil), `new CustomEvent("ready")`},
+		{"detail", NewCustomEvent("saved", Ident("item")), `new CustomEvent("saved", {"detail": item})`},
+		{"options only", NewCustomEvent("closed", nil, Bubbles(), Composed()), `new CustomEvent("closed", {"bubbles": true, "composed": true})`},
+		{
+			"dispatch",
+			DispatchCustomEvent(EventThis, "item-saved", Object(Pair("id", Int(42))), Bubbles(), Cancelable()),
+			`this.dispatchEvent(new CustomEvent("item-saved", {"detail": {"id": 42}, "bubbles": true, "cancelable": true}))`,
+		},
+		{"escaped name", NewCustomEvent(`a"b`, nil), `new CustomEvent("a\"b")`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJS(tt.expr); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -44,7 +44,7 @@ func Example_conditionalLogic() {
 		),
 	)
 	fmt.Println(handler)
-	// Output: if ((event.target.value === "")) { alert("Please enter a value"); return }
+	// Output: if (event.target.value === "") { alert("Please enter a value"); return }
 }
 
 func Example_toggleClass() {
@@ -77,7 +77,7 @@ func Example_ternaryOperator() {
 		js.String("Login"),
 	)
 	fmt.Println(js.ExprHandler(expr))
-	// Output: (isLoggedIn ? "Logout" : "Login")
+	// Output: isLoggedIn ? "Logout" : "Login"
 }
 
 func Example_objectLiteral() {
@@ -140,7 +140,7 @@ func Example_keyboardShortcut() {
 		),
 	)
 	fmt.Println(handler)
-	// Output: if ((event.ctrlKey && (event.key === "s"))) { event.preventDefault(); console.log("Save triggered") }
+	// Output: if (event.ctrlKey && event.key === "s") { event.preventDefault(); console.log("Save triggered") }
 }
 
 func ExampleOnClick() {
@@ -225,7 +225,7 @@ func ExampleArrowFunc() {
 	// Single expression arrow function
 	fn := js.ArrowFunc([]string{"a", "b"}, js.Add(js.Ident("a"), js.Ident("b")))
 	fmt.Println(js.ExprHandler(fn))
-	// Output: (a, b) => (a + b)
+	// Output: (a, b) => a + b
 }
 
 func ExampleArrowFuncStmts() {
@@ -235,7 +235,7 @@ func ExampleArrowFuncStmts() {
 		js.Return(js.Ident("result")),
 	)
 	fmt.Println(js.ExprHandler(fn))
-	// Output: x => { let result = (x * 2); return result }
+	// Output: x => { let result = x * 2; return result }
 }
 
 func ExampleIf() {
@@ -243,7 +243,7 @@ func ExampleIf() {
 		js.ExprStmt(js.ConsoleLog(js.String("positive"))),
 	)
 	fmt.Println(js.ToJSStmt(stmt))
-	// Output: if ((x > 0)) { console.log("positive") }
+	// Output: if (x > 0) { console.log("positive") }
 }
 
 func ExampleIfElse() {
@@ -253,7 +253,7 @@ func ExampleIfElse() {
 		[]js.Stmt{js.Return(js.String("non-positive"))},
 	)
 	fmt.Println(js.ToJSStmt(stmt))
-	// Output: if ((x > 0)) { return "positive" } else { return "non-positive" }
+	// Output: if (x > 0) { return "positive" } else { return "non-positive" }
 }
 
 func ExampleTernary() {
@@ -263,7 +263,7 @@ func ExampleTernary() {
 		js.String("minor"),
 	)
 	fmt.Println(js.ExprHandler(expr))
-	// Output: ((age > 18) ? "adult" : "minor")
+	// Output: age > 18 ? "adult" : "minor"
 }
 
 func ExampleTemplate() {
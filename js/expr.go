@@ -1,12 +1,10 @@
 package js
 
-import "strings"
-
 // Expr represents a JavaScript expression that produces a value.
 // Expressions can be composed into larger expressions or used as statements.
 type Expr interface {
 	// js writes the JavaScript expression to the builder.
-	js(sb *strings.Builder)
+	js(sb *printer)
 }
 
 // Stmt represents a JavaScript statement.
@@ -14,7 +12,7 @@ type Expr interface {
 type Stmt interface {
 	// stmt writes the JavaScript statement to the builder.
 	// The statement should NOT include a trailing semicolon.
-	stmt(sb *strings.Builder)
+	stmt(sb *printer)
 }
 
 // Callable represents a JavaScript value that can have properties accessed
@@ -29,7 +27,7 @@ type exprStmt struct {
 	expr Expr
 }
 
-func (e exprStmt) stmt(sb *strings.Builder) {
+func (e exprStmt) stmt(sb *printer) {
 	e.expr.js(sb)
 }
 
@@ -42,8 +40,8 @@ func ExprStmt(expr Expr) Stmt {
 // This is the escape hatch for arbitrary JS.
 type rawExpr string
 
-func (r rawExpr) js(sb *strings.Builder) { sb.WriteString(string(r)) }
-func (r rawExpr) callable()              {}
+func (r rawExpr) js(sb *printer) { sb.WriteString(string(r)) }
+func (r rawExpr) callable()      {}
 
 // Raw injects raw JavaScript code. This is the ONLY way to inject arbitrary JS.
 // Use with caution as this bypasses type safety.
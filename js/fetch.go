@@ -0,0 +1,109 @@
+package js
+
+// FetchBuilder builds a fetch(url, options) call. Each method returns an
+// updated copy, so a partially configured request can be shared:
+//
+//	js.FetchRequest(js.String("/api/items")).
+//	    Method("POST").
+//	    Header("X-CSRF-Token", js.String(token)).
+//	    JSONBody(js.Object(js.Pair("name", js.Ident("name")))).
+//	    Build()
+//	// fetch("/api/items", {"method": "POST", "headers": {"X-CSRF-Token": "...", "Content-Type": "application/json"}, "body": JSON.stringify({"name": name})})
+type FetchBuilder struct {
+	url     Expr
+	method  string
+	headers []KV
+	body    Expr
+}
+
+// FetchRequest starts a fetch of url. With no options, Build creates
+// fetch(url).
+func FetchRequest(url Expr) FetchBuilder {
+	return FetchBuilder{url: url}
+}
+
+// Method sets the HTTP method, such as "POST".
+func (f FetchBuilder) Method(method string) FetchBuilder {
+	f.method = method
+	return f
+}
+
+// Header adds a request header. Later headers with the same name replace
+// earlier ones.
+func (f FetchBuilder) Header(name string, value Expr) FetchBuilder {
+	headers := make([]KV, 0, len(f.headers)+1)
+	for _, kv := range f.headers {
+		if kv.Key != name {
+			headers = append(headers, kv)
+		}
+	}
+	f.headers = append(headers, KV{name, value})
+	return f
+}
+
+// Body sets the request body to body as-is, such as a FormData object.
+func (f FetchBuilder) Body(body Expr) FetchBuilder {
+	f.body = body
+	return f
+}
+
+// JSONBody sets the body to JSON.stringify(value) and the Content-Type
+// header to application/json.
+func (f FetchBuilder) JSONBody(value Expr) FetchBuilder {
+	f = f.Header("Content-Type", String("application/json"))
+	f.body = JSONStringify(value)
+	return f
+}
+
+// Options creates the fetch options object.
+func (f FetchBuilder) Options() Callable {
+	var pairs []KV
+	if f.method != "" {
+		pairs = append(pairs, KV{"method", String(f.method)})
+	}
+	if len(f.headers) > 0 {
+		pairs = append(pairs, KV{"headers", Object(f.headers...)})
+	}
+	if f.body != nil {
+		pairs = append(pairs, KV{"body", f.body})
+	}
+	return Object(pairs...)
+}
+
+// Build creates fetch(url, options), omitting options when none are set.
+func (f FetchBuilder) Build() Callable {
+	if f.method == "" && len(f.headers) == 0 && f.body == nil {
+		return Fetch(f.url)
+	}
+	return Fetch(f.url, f.Options())
+}
+
+// JSON creates the request followed by a then/catch chain that parses the
+// response as JSON and passes it to onSuccess. Responses with a non-2xx
+// status are rejected with an Error. onError receives rejections and
+// network errors; the catch is omitted when onError is nil.
+func (f FetchBuilder) JSON(onSuccess, onError Expr) Callable {
+	r := Ident("r")
+	chain := PromiseThen(
+		PromiseThen(f.Build(), ArrowFuncStmts([]string{"r"},
+			If(Not(Prop(r, "ok")), Throw(New(Ident("Error"), Prop(r, "statusText")))),
+			Return(Method(r, "json")),
+		)),
+		onSuccess,
+	)
+	if onError == nil {
+		return chain
+	}
+	return PromiseCatch(chain, onError)
+}
+
+// FetchJSON creates a GET of url whose JSON response is passed to onSuccess,
+// with failures passed to onError. See FetchBuilder.JSON.
+//
+//	js.FetchJSON(js.String("/api/user"),
+//	    js.ArrowFunc([]string{"user"}, js.ConsoleLog(js.Ident("user"))),
+//	    js.ArrowFunc([]string{"err"}, js.ConsoleError(js.Ident("err"))),
+//	)
+func FetchJSON(url, onSuccess, onError Expr) Callable {
+	return FetchRequest(url).JSON(onSuccess, onError)
+}
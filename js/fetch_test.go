@@ -0,0 +1,57 @@
+package js
+
+import "testing"
+
+func TestFetchRequest(t *testing.T) {
+	api := String("/api/items")
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected string
+	}{
+		{"plain", FetchRequest(api).Build(), `fetch("/api/items")`},
+		{"method", FetchRequest(api).Method("DELETE").Build(), `fetch("/api/items", {"method": "DELETE"})`},
+		{
+			"json body",
+			FetchRequest(api).Method("POST").Header("X-CSRF-Token", Ident("token")).JSONBody(Object(Pair("name", Ident("name")))).Build(),
+			`fetch("/api/items", {"method": "POST", "headers": {"X-CSRF-Token": token, "Content-Type": "application/json"}, "body": JSON.stringify({"name": name})})`,
+		},
+		{
+			"header replaced",
+			FetchRequest(api).Header("Accept", String("text/html")).Header("Accept", String("application/json")).Build(),
+			`fetch("/api/items", {"headers": {"Accept": "application/json"}})`,
+		},
+		{
+			"escaped header",
+			FetchRequest(api).Header(`X-"Quoted"`, String("</script>")).Build(),
+			`fetch("/api/items", {"headers": {"X-\"Quoted\"": "\u003c/script\u003e"}})`,
+		},
+		{"body", FetchRequest(api).Method("POST").Body(New(Ident("FormData"), This())).Build(), `fetch("/api/items", {"method": "POST", "body": new FormData(this)})`},
+		{"options", FetchRequest(api).Method("PUT").Options(), `{"method": "PUT"}`},
+		{
+			"fetch json",
+			FetchJSON(api, Ident("render"), Ident("showError")),
+			`fetch("/api/items").then(r => { if (!r.ok) { throw new Error(r.statusText) }; return r.json() }).then(render).catch(showError)`,
+		},
+		{
+			"json without catch",
+			FetchRequest(api).Method("POST").JSON(Ident("render"), nil),
+			`fetch("/api/items", {"method": "POST"}).then(r => { if (!r.ok) { throw new Error(r.statusText) }; return r.json() }).then(render)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJS(tt.expr); got != tt.expected {
+				t.Errorf("got  %s\nwant %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFetchRequestIsImmutable(t *testing.T) {
+	base := FetchRequest(String("/x")).Header("A", String("1"))
+	_ = base.Header("B", String("2"))
+	if got, expected := ToJS(base.Build()), `fetch("/x", {"headers": {"A": "1"}})`; got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
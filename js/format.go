@@ -0,0 +1,42 @@
+package js
+
+// FormatOptions configures multi-line output from [FormatOptions.Format].
+type FormatOptions struct {
+	// Indent is repeated once per nesting level. Defaults to two spaces.
+	Indent string
+}
+
+// Format renders statements as readable multi-line JavaScript with two-space
+// indentation, for embedding in <script> blocks or debugging generated
+// handlers. Use [Handler] for compact single-line output.
+//
+//	js.Format(js.If(js.Ident("ok"), js.ExprStmt(js.Call(js.Ident("save")))))
+//	// if (ok) {
+//	//   save();
+//	// }
+func Format(stmts ...Stmt) string {
+	return FormatOptions{}.Format(stmts...)
+}
+
+// Format renders statements as multi-line JavaScript using o's settings.
+// Statements end with semicolons, and each block body is placed on its own
+// indented lines, including function bodies inside expressions. Only the
+// blocks and statements built by this package are laid out; Raw code and
+// literals are copied unchanged.
+func (o FormatOptions) Format(stmts ...Stmt) string {
+	if len(stmts) == 0 {
+		return ""
+	}
+	p := &printer{format: true, indent: o.Indent}
+	if p.indent == "" {
+		p.indent = "  "
+	}
+	n := 0
+	for _, s := range stmts {
+		n += estimate(s) + 2
+	}
+	p.Grow(n)
+	writeStmtList(p, stmts)
+	p.endStmt()
+	return p.String()
+}
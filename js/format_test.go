@@ -0,0 +1,115 @@
+package js
+
+import (
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		stmts    []Stmt
+		expected string
+	}{
+		{"empty", nil, ""},
+		{
+			"simple statements",
+			[]Stmt{Let("x", Int(1)), Incr(Ident("x"))},
+			"let x = 1;\nx++;",
+		},
+		{
+			"if else",
+			[]Stmt{IfElse(Ident("ok"),
+				[]Stmt{ExprStmt(Call(Ident("save"))), Return(Bool(true))},
+				[]Stmt{ReturnVoid()},
+			)},
+			"if (ok) {\n  save();\n  return true;\n} else {\n  return;\n}",
+		},
+		{
+			"nested loop",
+			[]Stmt{
+				For(Let("i", Int(0)), Lt(Ident("i"), Int(3)), Incr(Ident("i")),
+					If(Eq(Ident("i"), Int(1)), Continue()),
+					ExprStmt(ConsoleLog(Ident("i"))),
+				),
+				ExprStmt(ConsoleLog(String("done; { }"))),
+			},
+			"for (let i = 0; i < 3; i++) {\n  if (i === 1) {\n    continue;\n  }\n  console.log(i);\n}\nconsole.log(\"done; { }\");",
+		},
+		{
+			"arrow body in expression",
+			[]Stmt{ExprStmt(Method(QuerySelectorAll(String("li")), "forEach",
+				ArrowFuncStmts([]string{"el"}, ExprStmt(Remove(Ident("el")))),
+			))},
+			"document.querySelectorAll(\"li\").forEach(el => {\n  el.remove();\n});",
+		},
+		{
+			"try catch",
+			[]Stmt{Try([]Stmt{Const("o", Object(Pair("a", Int(1))))}, "e", nil)},
+			"try {\n  const o = {\"a\": 1};\n} catch (e) {}",
+		},
+		{
+			"switch",
+			[]Stmt{Switch(Ident("k"), CaseClause(Int(1), Break()), DefaultClause(ReturnVoid()))},
+			"switch (k) {\n  case 1: break;\n  default: return;\n}",
+		},
+		{
+			"raw code untouched",
+			[]Stmt{ExprStmt(Raw("a({ x: 1 })")), If(Ident("ok"), ExprStmt(Raw("b({ y: 2; })")))},
+			"a({ x: 1 });\nif (ok) {\n  b({ y: 2; });\n}",
+		},
+		{
+			"raw code with NUL bytes",
+			[]Stmt{If(Ident("ok"), ExprStmt(Raw("f(\"\x00{\x00;\x00}\")")))},
+			"if (ok) {\n  f(\"\x00{\x00;\x00}\");\n}",
+		},
+		{
+			"object literal in block",
+			[]Stmt{If(Ident("ok"), ExprStmt(Call(Ident("send"), Object(Pair("a", Object(Pair("b", Int(1))))))))},
+			"if (ok) {\n  send({\"a\": {\"b\": 1}});\n}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.stmts...); got != tt.expected {
+				t.Errorf("Format() =\n%s\nwant\n%s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatOptionsIndent(t *testing.T) {
+	got := FormatOptions{Indent: "\t"}.Format(While(Ident("busy"), If(Ident("done"), Break())))
+	expected := "while (busy) {\n\tif (done) {\n\t\tbreak;\n\t}\n}"
+	if got != expected {
+		t.Errorf("got\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestScriptTag(t *testing.T) {
+	got := h.RenderString(ScriptTag(
+		Const("el", GetElementById(String("app"))),
+		If(Ident("el"), Assign(Prop(Ident("el"), "textContent"), String("</script>"))),
+	))
+	expected := "<script>const el = document.getElementById(\"app\");\nif (el) {\n  el.textContent = \"\\u003c/script\\u003e\";\n}</script>"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestScriptTagEscapesRaw(t *testing.T) {
+	got := h.RenderString(ScriptTag(ExprStmt(Raw(`log("</script><script>alert(1)</SCRIPT><!--")`))))
+	expected := `<script>log("<\/script><script>alert(1)<\/SCRIPT><\!--");</script>`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestScriptModule(t *testing.T) {
+	got := h.RenderString(ScriptModule(ExprStmt(ConsoleLog(String("hi")))))
+	expected := `<script type="module">console.log("hi");</script>`
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -1,11 +1,11 @@
 package js
 
-import "strings"
+import "fmt"
 
 // writeArrowParams writes arrow function parameters in the format:
 // - Single param: x
 // - Zero or multiple params: (a, b)
-func writeArrowParams(sb *strings.Builder, params []string) {
+func writeArrowParams(sb *printer, params []string) {
 	if len(params) == 1 {
 		sb.WriteString(params[0])
 	} else {
@@ -21,7 +21,7 @@ func writeArrowParams(sb *strings.Builder, params []string) {
 }
 
 // writeParenParams writes parenthesized parameters: (a, b)
-func writeParenParams(sb *strings.Builder, params []string) {
+func writeParenParams(sb *printer, params []string) {
 	sb.WriteString("(")
 	for i, p := range params {
 		if i > 0 {
@@ -32,20 +32,10 @@ func writeParenParams(sb *strings.Builder, params []string) {
 	sb.WriteString(")")
 }
 
-// writeStmtList writes statements separated by "; "
-func writeStmtList(sb *strings.Builder, stmts []Stmt) {
-	for i, s := range stmts {
-		if i > 0 {
-			sb.WriteString("; ")
-		}
-		s.stmt(sb)
-	}
-}
-
 // ArrowFunc creates an arrow function expression with a single expression body.
 // Example: ArrowFunc([]string{"x", "y"}, Add(Ident("x"), Ident("y")))
 //
-//	=> (x, y) => (x + y)
+//	=> (x, y) => x + y
 func ArrowFunc(params []string, body Expr) Callable {
 	return arrowFuncExpr{params: params, body: body}
 }
@@ -55,12 +45,13 @@ type arrowFuncExpr struct {
 	body   Expr
 }
 
-func (a arrowFuncExpr) js(sb *strings.Builder) {
+func (a arrowFuncExpr) js(sb *printer) {
 	writeArrowParams(sb, a.params)
 	sb.WriteString(" => ")
-	a.body.js(sb)
+	writeOperand(sb, a.body, precAssign)
 }
-func (a arrowFuncExpr) callable() {}
+func (a arrowFuncExpr) callable()       {}
+func (a arrowFuncExpr) precedence() int { return precAssign }
 
 // ArrowFuncStmts creates an arrow function with a statement body.
 // Example: ArrowFuncStmts([]string{"e"}, ExprStmt(ConsoleLog(Ident("e"))))
@@ -75,18 +66,18 @@ type arrowFuncStmtsExpr struct {
 	body   []Stmt
 }
 
-func (a arrowFuncStmtsExpr) js(sb *strings.Builder) {
+func (a arrowFuncStmtsExpr) js(sb *printer) {
 	writeArrowParams(sb, a.params)
-	sb.WriteString(" => { ")
-	writeStmtList(sb, a.body)
-	sb.WriteString(" }")
+	sb.WriteString(" => ")
+	writeBlock(sb, a.body, false)
 }
-func (a arrowFuncStmtsExpr) callable() {}
+func (a arrowFuncStmtsExpr) callable()       {}
+func (a arrowFuncStmtsExpr) precedence() int { return precAssign }
 
 // Func creates an anonymous function expression.
 // Example: Func([]string{"x", "y"}, Return(Add(Ident("x"), Ident("y"))))
 //
-//	=> function(x, y) { return (x + y) }
+//	=> function(x, y) { return x + y }
 func Func(params []string, stmts ...Stmt) Callable {
 	return funcExpr{params: params, body: stmts}
 }
@@ -96,12 +87,11 @@ type funcExpr struct {
 	body   []Stmt
 }
 
-func (f funcExpr) js(sb *strings.Builder) {
+func (f funcExpr) js(sb *printer) {
 	sb.WriteString("function")
 	writeParenParams(sb, f.params)
-	sb.WriteString(" { ")
-	writeStmtList(sb, f.body)
-	sb.WriteString(" }")
+	sb.WriteString(" ")
+	writeBlock(sb, f.body, false)
 }
 func (f funcExpr) callable() {}
 
@@ -117,10 +107,10 @@ type iifeExpr struct {
 	body []Stmt
 }
 
-func (i iifeExpr) js(sb *strings.Builder) {
-	sb.WriteString("(function() { ")
-	writeStmtList(sb, i.body)
-	sb.WriteString(" })()")
+func (i iifeExpr) js(sb *printer) {
+	sb.WriteString("(function() ")
+	writeBlock(sb, i.body, false)
+	sb.WriteString(")()")
 }
 func (i iifeExpr) callable() {}
 
@@ -137,16 +127,27 @@ type templateLiteral struct {
 	parts []any // alternating strings and Expr
 }
 
-func (t templateLiteral) js(sb *strings.Builder) {
+func (t templateLiteral) js(sb *printer) {
 	sb.WriteString("`")
 	for _, part := range t.parts {
 		switch v := part.(type) {
 		case string:
-			// Escape backticks, backslashes, and ${
+			// Escape backticks, backslashes, and ${, and in attribute
+			// context the characters StringForAttr escapes
 			for _, r := range v {
 				switch r {
 				case '`':
-					sb.WriteString("\\`")
+					if sb.attr {
+						sb.WriteString(`\u0060`)
+					} else {
+						sb.WriteString("\\`")
+					}
+				case '\'', '"', '<', '>', '&', '\u2028', '\u2029':
+					if sb.attr {
+						fmt.Fprintf(sb, `\u%04x`, r)
+					} else {
+						sb.WriteRune(r)
+					}
 				case '\\':
 					sb.WriteString("\\\\")
 				case '$':
@@ -178,11 +179,12 @@ type awaitExpr struct {
 	expr Expr
 }
 
-func (a awaitExpr) js(sb *strings.Builder) {
+func (a awaitExpr) js(sb *printer) {
 	sb.WriteString("await ")
-	a.expr.js(sb)
+	writeOperand(sb, a.expr, precUnary)
 }
-func (a awaitExpr) callable() {}
+func (a awaitExpr) callable()       {}
+func (a awaitExpr) precedence() int { return precUnary }
 
 // AsyncArrowFunc creates an async arrow function with a single expression body.
 // Example: AsyncArrowFunc([]string{}, Await(Fetch(String("/api"))))
@@ -197,13 +199,14 @@ type asyncArrowFuncExpr struct {
 	body   Expr
 }
 
-func (a asyncArrowFuncExpr) js(sb *strings.Builder) {
+func (a asyncArrowFuncExpr) js(sb *printer) {
 	sb.WriteString("async ")
 	writeArrowParams(sb, a.params)
 	sb.WriteString(" => ")
-	a.body.js(sb)
+	writeOperand(sb, a.body, precAssign)
 }
-func (a asyncArrowFuncExpr) callable() {}
+func (a asyncArrowFuncExpr) callable()       {}
+func (a asyncArrowFuncExpr) precedence() int { return precAssign }
 
 // AsyncArrowFuncStmts creates an async arrow function with a statement body.
 // Example: AsyncArrowFuncStmts([]string{}, Let("data", Await(Fetch(String("/api")))))
@@ -218,14 +221,14 @@ type asyncArrowFuncStmtsExpr struct {
 	body   []Stmt
 }
 
-func (a asyncArrowFuncStmtsExpr) js(sb *strings.Builder) {
+func (a asyncArrowFuncStmtsExpr) js(sb *printer) {
 	sb.WriteString("async ")
 	writeArrowParams(sb, a.params)
-	sb.WriteString(" => { ")
-	writeStmtList(sb, a.body)
-	sb.WriteString(" }")
+	sb.WriteString(" => ")
+	writeBlock(sb, a.body, false)
 }
-func (a asyncArrowFuncStmtsExpr) callable() {}
+func (a asyncArrowFuncStmtsExpr) callable()       {}
+func (a asyncArrowFuncStmtsExpr) precedence() int { return precAssign }
 
 // PromiseThen creates expr.then(onFulfilled)
 func PromiseThen(promise Callable, onFulfilled Expr) Callable {
@@ -0,0 +1,52 @@
+package js
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// FuzzEscape is a fuzz target for the string escaping of this package. It
+// checks that String and StringForAttr decode back to their input as JSON,
+// and that StringForAttr, and the literals written by the On* helpers, hold
+// no character that could end an enclosing attribute or template literal.
+// Call it from a fuzz test in a _test.go file:
+//
+//	func FuzzJSEscape(f *testing.F) { js.FuzzEscape(f) }
+//
+// and run it with go test -fuzz=FuzzJSEscape.
+func FuzzEscape(f *testing.F) {
+	for _, s := range []string{"", "hello", `"'` + "`", "</script>", "a\u2028b", "\x00\x1f\x7f", "\xff\xfe", "caf\u00e9 \U0001F600"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		// Invalid UTF-8 bytes are written as U+FFFD, one per byte.
+		want := string([]rune(s))
+		for _, lit := range []Callable{String(s), StringForAttr(s)} {
+			out := ToJS(lit)
+			var got string
+			if err := json.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatalf("%s is not a valid string literal: %v", out, err)
+			}
+			if got != want {
+				t.Fatalf("%s decodes to %q, want %q", out, got, want)
+			}
+		}
+		out := ToJS(StringForAttr(s))
+		if i := strings.IndexAny(out[1:len(out)-1], "'`<>&\u2028\u2029"); i >= 0 {
+			t.Fatalf("StringForAttr(%q) = %s contains %q", s, out, out[1+i])
+		}
+
+		// The On* helpers escape string, template, and JSON literals alike.
+		// Template text cannot hold unescaped backticks or double quotes
+		// either, which also end the template or the attribute.
+		handler := OnClick(ExprStmt(Call(Ident("f"), String(s), Template(s), JSON(s)))).Value
+		body := strings.TrimSuffix(strings.TrimPrefix(handler, "f("), ")")
+		if i := strings.IndexAny(body, "'<>&\u2028\u2029"); i >= 0 {
+			t.Fatalf("OnClick handler %s contains %q", handler, body[i])
+		}
+		if n := strings.Count(body, "`"); n != 2 {
+			t.Fatalf("OnClick handler %s has %d backticks, want 2", handler, n)
+		}
+	})
+}
@@ -1,25 +1,39 @@
 package js
 
 import (
-	"strings"
 	"sync"
 
 	"github.com/jeffh/htmlgen/h"
 )
 
 var builderPool = sync.Pool{
-	New: func() any { return new(strings.Builder) },
+	New: func() any { return new(printer) },
 }
 
 // Handler builds an inline JavaScript handler string from statements.
 // Statements are joined with semicolons.
 func Handler(stmts ...Stmt) string {
+	return handler(false, stmts)
+}
+
+// attrHandler is Handler with string, template, and JSON literals escaped
+// as by StringForAttr, for the On* helpers.
+func attrHandler(stmts ...Stmt) string {
+	return handler(true, stmts)
+}
+
+func handler(attr bool, stmts []Stmt) string {
 	if len(stmts) == 0 {
 		return ""
 	}
-	sb := builderPool.Get().(*strings.Builder)
+	sb := builderPool.Get().(*printer)
 	sb.Reset()
-	sb.Grow(64) // Pre-allocate for typical handler size
+	sb.attr = attr
+	n := 2 * (len(stmts) - 1)
+	for _, stmt := range stmts {
+		n += estimate(stmt)
+	}
+	sb.Grow(n)
 	for i, stmt := range stmts {
 		if i > 0 {
 			sb.WriteString("; ")
@@ -27,15 +41,16 @@ func Handler(stmts ...Stmt) string {
 		stmt.stmt(sb)
 	}
 	result := sb.String()
+	sb.attr = false
 	builderPool.Put(sb)
 	return result
 }
 
 // ExprHandler builds an inline JavaScript handler from a single expression.
 func ExprHandler(expr Expr) string {
-	sb := builderPool.Get().(*strings.Builder)
+	sb := builderPool.Get().(*printer)
 	sb.Reset()
-	sb.Grow(64)
+	sb.Grow(estimate(expr))
 	expr.js(sb)
 	result := sb.String()
 	builderPool.Put(sb)
@@ -44,8 +59,9 @@ func ExprHandler(expr Expr) string {
 
 // ToJS converts an expression to its JavaScript string representation.
 func ToJS(expr Expr) string {
-	sb := builderPool.Get().(*strings.Builder)
+	sb := builderPool.Get().(*printer)
 	sb.Reset()
+	sb.Grow(estimate(expr))
 	expr.js(sb)
 	result := sb.String()
 	builderPool.Put(sb)
@@ -54,8 +70,9 @@ func ToJS(expr Expr) string {
 
 // ToJSStmt converts a statement to its JavaScript string representation.
 func ToJSStmt(stmt Stmt) string {
-	sb := builderPool.Get().(*strings.Builder)
+	sb := builderPool.Get().(*printer)
 	sb.Reset()
+	sb.Grow(estimate(stmt))
 	stmt.stmt(sb)
 	result := sb.String()
 	builderPool.Put(sb)
@@ -66,412 +83,412 @@ func ToJSStmt(stmt Stmt) string {
 
 // OnClick creates an onclick attribute with the given handler.
 func OnClick(stmts ...Stmt) h.Attribute {
-	return h.Attr("onclick", Handler(stmts...))
+	return h.Attr("onclick", attrHandler(stmts...))
 }
 
 // OnDblClick creates an ondblclick attribute with the given handler.
 func OnDblClick(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondblclick", Handler(stmts...))
+	return h.Attr("ondblclick", attrHandler(stmts...))
 }
 
 // OnMouseDown creates an onmousedown attribute with the given handler.
 func OnMouseDown(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmousedown", Handler(stmts...))
+	return h.Attr("onmousedown", attrHandler(stmts...))
 }
 
 // OnMouseUp creates an onmouseup attribute with the given handler.
 func OnMouseUp(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmouseup", Handler(stmts...))
+	return h.Attr("onmouseup", attrHandler(stmts...))
 }
 
 // OnMouseOver creates an onmouseover attribute with the given handler.
 func OnMouseOver(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmouseover", Handler(stmts...))
+	return h.Attr("onmouseover", attrHandler(stmts...))
 }
 
 // OnMouseOut creates an onmouseout attribute with the given handler.
 func OnMouseOut(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmouseout", Handler(stmts...))
+	return h.Attr("onmouseout", attrHandler(stmts...))
 }
 
 // OnMouseEnter creates an onmouseenter attribute with the given handler.
 func OnMouseEnter(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmouseenter", Handler(stmts...))
+	return h.Attr("onmouseenter", attrHandler(stmts...))
 }
 
 // OnMouseLeave creates an onmouseleave attribute with the given handler.
 func OnMouseLeave(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmouseleave", Handler(stmts...))
+	return h.Attr("onmouseleave", attrHandler(stmts...))
 }
 
 // OnMouseMove creates an onmousemove attribute with the given handler.
 func OnMouseMove(stmts ...Stmt) h.Attribute {
-	return h.Attr("onmousemove", Handler(stmts...))
+	return h.Attr("onmousemove", attrHandler(stmts...))
 }
 
 // OnContextMenu creates an oncontextmenu attribute with the given handler.
 func OnContextMenu(stmts ...Stmt) h.Attribute {
-	return h.Attr("oncontextmenu", Handler(stmts...))
+	return h.Attr("oncontextmenu", attrHandler(stmts...))
 }
 
 // OnWheel creates an onwheel attribute with the given handler.
 func OnWheel(stmts ...Stmt) h.Attribute {
-	return h.Attr("onwheel", Handler(stmts...))
+	return h.Attr("onwheel", attrHandler(stmts...))
 }
 
 // Keyboard Events
 
 // OnKeyDown creates an onkeydown attribute with the given handler.
 func OnKeyDown(stmts ...Stmt) h.Attribute {
-	return h.Attr("onkeydown", Handler(stmts...))
+	return h.Attr("onkeydown", attrHandler(stmts...))
 }
 
 // OnKeyUp creates an onkeyup attribute with the given handler.
 func OnKeyUp(stmts ...Stmt) h.Attribute {
-	return h.Attr("onkeyup", Handler(stmts...))
+	return h.Attr("onkeyup", attrHandler(stmts...))
 }
 
 // OnKeyPress creates an onkeypress attribute with the given handler.
 // Deprecated: Use OnKeyDown or OnKeyUp instead.
 func OnKeyPress(stmts ...Stmt) h.Attribute {
-	return h.Attr("onkeypress", Handler(stmts...))
+	return h.Attr("onkeypress", attrHandler(stmts...))
 }
 
 // Focus Events
 
 // OnFocus creates an onfocus attribute with the given handler.
 func OnFocus(stmts ...Stmt) h.Attribute {
-	return h.Attr("onfocus", Handler(stmts...))
+	return h.Attr("onfocus", attrHandler(stmts...))
 }
 
 // OnBlur creates an onblur attribute with the given handler.
 func OnBlur(stmts ...Stmt) h.Attribute {
-	return h.Attr("onblur", Handler(stmts...))
+	return h.Attr("onblur", attrHandler(stmts...))
 }
 
 // OnFocusIn creates an onfocusin attribute with the given handler.
 func OnFocusIn(stmts ...Stmt) h.Attribute {
-	return h.Attr("onfocusin", Handler(stmts...))
+	return h.Attr("onfocusin", attrHandler(stmts...))
 }
 
 // OnFocusOut creates an onfocusout attribute with the given handler.
 func OnFocusOut(stmts ...Stmt) h.Attribute {
-	return h.Attr("onfocusout", Handler(stmts...))
+	return h.Attr("onfocusout", attrHandler(stmts...))
 }
 
 // Form Events
 
 // OnChange creates an onchange attribute with the given handler.
 func OnChange(stmts ...Stmt) h.Attribute {
-	return h.Attr("onchange", Handler(stmts...))
+	return h.Attr("onchange", attrHandler(stmts...))
 }
 
 // OnInput creates an oninput attribute with the given handler.
 func OnInput(stmts ...Stmt) h.Attribute {
-	return h.Attr("oninput", Handler(stmts...))
+	return h.Attr("oninput", attrHandler(stmts...))
 }
 
 // OnSubmit creates an onsubmit attribute with the given handler.
 func OnSubmit(stmts ...Stmt) h.Attribute {
-	return h.Attr("onsubmit", Handler(stmts...))
+	return h.Attr("onsubmit", attrHandler(stmts...))
 }
 
 // OnReset creates an onreset attribute with the given handler.
 func OnReset(stmts ...Stmt) h.Attribute {
-	return h.Attr("onreset", Handler(stmts...))
+	return h.Attr("onreset", attrHandler(stmts...))
 }
 
 // OnSelect creates an onselect attribute with the given handler.
 func OnSelect(stmts ...Stmt) h.Attribute {
-	return h.Attr("onselect", Handler(stmts...))
+	return h.Attr("onselect", attrHandler(stmts...))
 }
 
 // OnInvalid creates an oninvalid attribute with the given handler.
 func OnInvalid(stmts ...Stmt) h.Attribute {
-	return h.Attr("oninvalid", Handler(stmts...))
+	return h.Attr("oninvalid", attrHandler(stmts...))
 }
 
 // Window/Document Events
 
 // OnLoad creates an onload attribute with the given handler.
 func OnLoad(stmts ...Stmt) h.Attribute {
-	return h.Attr("onload", Handler(stmts...))
+	return h.Attr("onload", attrHandler(stmts...))
 }
 
 // OnUnload creates an onunload attribute with the given handler.
 func OnUnload(stmts ...Stmt) h.Attribute {
-	return h.Attr("onunload", Handler(stmts...))
+	return h.Attr("onunload", attrHandler(stmts...))
 }
 
 // OnBeforeUnload creates an onbeforeunload attribute with the given handler.
 func OnBeforeUnload(stmts ...Stmt) h.Attribute {
-	return h.Attr("onbeforeunload", Handler(stmts...))
+	return h.Attr("onbeforeunload", attrHandler(stmts...))
 }
 
 // OnError creates an onerror attribute with the given handler.
 func OnError(stmts ...Stmt) h.Attribute {
-	return h.Attr("onerror", Handler(stmts...))
+	return h.Attr("onerror", attrHandler(stmts...))
 }
 
 // OnScroll creates an onscroll attribute with the given handler.
 func OnScroll(stmts ...Stmt) h.Attribute {
-	return h.Attr("onscroll", Handler(stmts...))
+	return h.Attr("onscroll", attrHandler(stmts...))
 }
 
 // OnResize creates an onresize attribute with the given handler.
 func OnResize(stmts ...Stmt) h.Attribute {
-	return h.Attr("onresize", Handler(stmts...))
+	return h.Attr("onresize", attrHandler(stmts...))
 }
 
 // OnHashChange creates an onhashchange attribute with the given handler.
 func OnHashChange(stmts ...Stmt) h.Attribute {
-	return h.Attr("onhashchange", Handler(stmts...))
+	return h.Attr("onhashchange", attrHandler(stmts...))
 }
 
 // OnPopState creates an onpopstate attribute with the given handler.
 func OnPopState(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpopstate", Handler(stmts...))
+	return h.Attr("onpopstate", attrHandler(stmts...))
 }
 
 // OnStorage creates an onstorage attribute with the given handler.
 func OnStorage(stmts ...Stmt) h.Attribute {
-	return h.Attr("onstorage", Handler(stmts...))
+	return h.Attr("onstorage", attrHandler(stmts...))
 }
 
 // OnOnline creates an ononline attribute with the given handler.
 func OnOnline(stmts ...Stmt) h.Attribute {
-	return h.Attr("ononline", Handler(stmts...))
+	return h.Attr("ononline", attrHandler(stmts...))
 }
 
 // OnOffline creates an onoffline attribute with the given handler.
 func OnOffline(stmts ...Stmt) h.Attribute {
-	return h.Attr("onoffline", Handler(stmts...))
+	return h.Attr("onoffline", attrHandler(stmts...))
 }
 
 // Clipboard Events
 
 // OnCopy creates an oncopy attribute with the given handler.
 func OnCopy(stmts ...Stmt) h.Attribute {
-	return h.Attr("oncopy", Handler(stmts...))
+	return h.Attr("oncopy", attrHandler(stmts...))
 }
 
 // OnCut creates an oncut attribute with the given handler.
 func OnCut(stmts ...Stmt) h.Attribute {
-	return h.Attr("oncut", Handler(stmts...))
+	return h.Attr("oncut", attrHandler(stmts...))
 }
 
 // OnPaste creates an onpaste attribute with the given handler.
 func OnPaste(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpaste", Handler(stmts...))
+	return h.Attr("onpaste", attrHandler(stmts...))
 }
 
 // Drag Events
 
 // OnDrag creates an ondrag attribute with the given handler.
 func OnDrag(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondrag", Handler(stmts...))
+	return h.Attr("ondrag", attrHandler(stmts...))
 }
 
 // OnDragStart creates an ondragstart attribute with the given handler.
 func OnDragStart(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondragstart", Handler(stmts...))
+	return h.Attr("ondragstart", attrHandler(stmts...))
 }
 
 // OnDragEnd creates an ondragend attribute with the given handler.
 func OnDragEnd(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondragend", Handler(stmts...))
+	return h.Attr("ondragend", attrHandler(stmts...))
 }
 
 // OnDragOver creates an ondragover attribute with the given handler.
 func OnDragOver(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondragover", Handler(stmts...))
+	return h.Attr("ondragover", attrHandler(stmts...))
 }
 
 // OnDragEnter creates an ondragenter attribute with the given handler.
 func OnDragEnter(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondragenter", Handler(stmts...))
+	return h.Attr("ondragenter", attrHandler(stmts...))
 }
 
 // OnDragLeave creates an ondragleave attribute with the given handler.
 func OnDragLeave(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondragleave", Handler(stmts...))
+	return h.Attr("ondragleave", attrHandler(stmts...))
 }
 
 // OnDrop creates an ondrop attribute with the given handler.
 func OnDrop(stmts ...Stmt) h.Attribute {
-	return h.Attr("ondrop", Handler(stmts...))
+	return h.Attr("ondrop", attrHandler(stmts...))
 }
 
 // Touch Events
 
 // OnTouchStart creates an ontouchstart attribute with the given handler.
 func OnTouchStart(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontouchstart", Handler(stmts...))
+	return h.Attr("ontouchstart", attrHandler(stmts...))
 }
 
 // OnTouchMove creates an ontouchmove attribute with the given handler.
 func OnTouchMove(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontouchmove", Handler(stmts...))
+	return h.Attr("ontouchmove", attrHandler(stmts...))
 }
 
 // OnTouchEnd creates an ontouchend attribute with the given handler.
 func OnTouchEnd(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontouchend", Handler(stmts...))
+	return h.Attr("ontouchend", attrHandler(stmts...))
 }
 
 // OnTouchCancel creates an ontouchcancel attribute with the given handler.
 func OnTouchCancel(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontouchcancel", Handler(stmts...))
+	return h.Attr("ontouchcancel", attrHandler(stmts...))
 }
 
 // Pointer Events
 
 // OnPointerDown creates an onpointerdown attribute with the given handler.
 func OnPointerDown(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointerdown", Handler(stmts...))
+	return h.Attr("onpointerdown", attrHandler(stmts...))
 }
 
 // OnPointerUp creates an onpointerup attribute with the given handler.
 func OnPointerUp(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointerup", Handler(stmts...))
+	return h.Attr("onpointerup", attrHandler(stmts...))
 }
 
 // OnPointerMove creates an onpointermove attribute with the given handler.
 func OnPointerMove(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointermove", Handler(stmts...))
+	return h.Attr("onpointermove", attrHandler(stmts...))
 }
 
 // OnPointerEnter creates an onpointerenter attribute with the given handler.
 func OnPointerEnter(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointerenter", Handler(stmts...))
+	return h.Attr("onpointerenter", attrHandler(stmts...))
 }
 
 // OnPointerLeave creates an onpointerleave attribute with the given handler.
 func OnPointerLeave(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointerleave", Handler(stmts...))
+	return h.Attr("onpointerleave", attrHandler(stmts...))
 }
 
 // OnPointerCancel creates an onpointercancel attribute with the given handler.
 func OnPointerCancel(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointercancel", Handler(stmts...))
+	return h.Attr("onpointercancel", attrHandler(stmts...))
 }
 
 // OnPointerOver creates an onpointerover attribute with the given handler.
 func OnPointerOver(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointerover", Handler(stmts...))
+	return h.Attr("onpointerover", attrHandler(stmts...))
 }
 
 // OnPointerOut creates an onpointerout attribute with the given handler.
 func OnPointerOut(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpointerout", Handler(stmts...))
+	return h.Attr("onpointerout", attrHandler(stmts...))
 }
 
 // Media Events
 
 // OnPlay creates an onplay attribute with the given handler.
 func OnPlay(stmts ...Stmt) h.Attribute {
-	return h.Attr("onplay", Handler(stmts...))
+	return h.Attr("onplay", attrHandler(stmts...))
 }
 
 // OnPause creates an onpause attribute with the given handler.
 func OnPause(stmts ...Stmt) h.Attribute {
-	return h.Attr("onpause", Handler(stmts...))
+	return h.Attr("onpause", attrHandler(stmts...))
 }
 
 // OnEnded creates an onended attribute with the given handler.
 func OnEnded(stmts ...Stmt) h.Attribute {
-	return h.Attr("onended", Handler(stmts...))
+	return h.Attr("onended", attrHandler(stmts...))
 }
 
 // OnTimeUpdate creates an ontimeupdate attribute with the given handler.
 func OnTimeUpdate(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontimeupdate", Handler(stmts...))
+	return h.Attr("ontimeupdate", attrHandler(stmts...))
 }
 
 // OnVolumeChange creates an onvolumechange attribute with the given handler.
 func OnVolumeChange(stmts ...Stmt) h.Attribute {
-	return h.Attr("onvolumechange", Handler(stmts...))
+	return h.Attr("onvolumechange", attrHandler(stmts...))
 }
 
 // OnSeeking creates an onseeking attribute with the given handler.
 func OnSeeking(stmts ...Stmt) h.Attribute {
-	return h.Attr("onseeking", Handler(stmts...))
+	return h.Attr("onseeking", attrHandler(stmts...))
 }
 
 // OnSeeked creates an onseeked attribute with the given handler.
 func OnSeeked(stmts ...Stmt) h.Attribute {
-	return h.Attr("onseeked", Handler(stmts...))
+	return h.Attr("onseeked", attrHandler(stmts...))
 }
 
 // OnLoadedData creates an onloadeddata attribute with the given handler.
 func OnLoadedData(stmts ...Stmt) h.Attribute {
-	return h.Attr("onloadeddata", Handler(stmts...))
+	return h.Attr("onloadeddata", attrHandler(stmts...))
 }
 
 // OnLoadedMetadata creates an onloadedmetadata attribute with the given handler.
 func OnLoadedMetadata(stmts ...Stmt) h.Attribute {
-	return h.Attr("onloadedmetadata", Handler(stmts...))
+	return h.Attr("onloadedmetadata", attrHandler(stmts...))
 }
 
 // OnCanPlay creates an oncanplay attribute with the given handler.
 func OnCanPlay(stmts ...Stmt) h.Attribute {
-	return h.Attr("oncanplay", Handler(stmts...))
+	return h.Attr("oncanplay", attrHandler(stmts...))
 }
 
 // OnCanPlayThrough creates an oncanplaythrough attribute with the given handler.
 func OnCanPlayThrough(stmts ...Stmt) h.Attribute {
-	return h.Attr("oncanplaythrough", Handler(stmts...))
+	return h.Attr("oncanplaythrough", attrHandler(stmts...))
 }
 
 // Animation Events
 
 // OnAnimationStart creates an onanimationstart attribute with the given handler.
 func OnAnimationStart(stmts ...Stmt) h.Attribute {
-	return h.Attr("onanimationstart", Handler(stmts...))
+	return h.Attr("onanimationstart", attrHandler(stmts...))
 }
 
 // OnAnimationEnd creates an onanimationend attribute with the given handler.
 func OnAnimationEnd(stmts ...Stmt) h.Attribute {
-	return h.Attr("onanimationend", Handler(stmts...))
+	return h.Attr("onanimationend", attrHandler(stmts...))
 }
 
 // OnAnimationIteration creates an onanimationiteration attribute with the given handler.
 func OnAnimationIteration(stmts ...Stmt) h.Attribute {
-	return h.Attr("onanimationiteration", Handler(stmts...))
+	return h.Attr("onanimationiteration", attrHandler(stmts...))
 }
 
 // Transition Events
 
 // OnTransitionEnd creates an ontransitionend attribute with the given handler.
 func OnTransitionEnd(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontransitionend", Handler(stmts...))
+	return h.Attr("ontransitionend", attrHandler(stmts...))
 }
 
 // OnTransitionStart creates an ontransitionstart attribute with the given handler.
 func OnTransitionStart(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontransitionstart", Handler(stmts...))
+	return h.Attr("ontransitionstart", attrHandler(stmts...))
 }
 
 // OnTransitionRun creates an ontransitionrun attribute with the given handler.
 func OnTransitionRun(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontransitionrun", Handler(stmts...))
+	return h.Attr("ontransitionrun", attrHandler(stmts...))
 }
 
 // OnTransitionCancel creates an ontransitioncancel attribute with the given handler.
 func OnTransitionCancel(stmts ...Stmt) h.Attribute {
-	return h.Attr("ontransitioncancel", Handler(stmts...))
+	return h.Attr("ontransitioncancel", attrHandler(stmts...))
 }
 
 // Print Events
 
 // OnBeforePrint creates an onbeforeprint attribute with the given handler.
 func OnBeforePrint(stmts ...Stmt) h.Attribute {
-	return h.Attr("onbeforeprint", Handler(stmts...))
+	return h.Attr("onbeforeprint", attrHandler(stmts...))
 }
 
 // OnAfterPrint creates an onafterprint attribute with the given handler.
 func OnAfterPrint(stmts ...Stmt) h.Attribute {
-	return h.Attr("onafterprint", Handler(stmts...))
+	return h.Attr("onafterprint", attrHandler(stmts...))
 }
 
 // Custom Event
@@ -479,5 +496,5 @@ func OnAfterPrint(stmts ...Stmt) h.Attribute {
 // On creates a custom event handler attribute.
 // Example: On("touchstart", stmts...) creates ontouchstart="..."
 func On(event string, stmts ...Stmt) h.Attribute {
-	return h.Attr("on"+event, Handler(stmts...))
+	return h.Attr("on"+event, attrHandler(stmts...))
 }
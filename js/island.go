@@ -0,0 +1,87 @@
+package js
+
+import "github.com/jeffh/htmlgen/h"
+
+// IslandRegistry is the window property holding island bootstrap functions,
+// keyed by the name given to h.Island. Each function receives the island's
+// element:
+//
+//	window.islands = window.islands || {};
+//	window.islands.clock = el => { ... };
+const IslandRegistry = "islands"
+
+// IslandScheduler renders a <script> that hydrates every h.Island on the page
+// according to its priority: IslandEager (or no priority) immediately,
+// IslandIdle via requestIdleCallback, and IslandVisible once an
+// IntersectionObserver reports it on screen. Islands are scheduled
+// breadth-first, so outer islands run before islands nested inside them,
+// and each island runs at most once.
+//
+// Render it after the scripts that register islands, typically at the end
+// of <body>.
+func IslandScheduler() h.Builder {
+	return ScriptTag(ExprStmt(IIFE(islandSchedulerStmts()...)))
+}
+
+func islandSchedulerStmts() []Stmt {
+	el := Ident("el")
+	dataset := Prop(el, "dataset")
+	registry := Prop(Window, IslandRegistry)
+	run := Ident("run")
+	depth := Ident("depth")
+	io := Ident("io")
+	idle := Ident("idle")
+	p := Ident("p")
+	d := Ident("d")
+
+	return []Stmt{
+		// run hydrates el once, if its bootstrap function is registered.
+		Const("run", ArrowFuncStmts([]string{"el"},
+			Const("fn", And(registry, Index(registry, Prop(dataset, "island")))),
+			If(Or(Not(Ident("fn")), Prop(dataset, "islandReady")), ReturnVoid()),
+			Assign(Prop(dataset, "islandReady"), String("true")),
+			ExprStmt(Call(Ident("fn"), el)),
+		)),
+		// depth counts the islands enclosing el.
+		Const("depth", ArrowFuncStmts([]string{"el"},
+			Let("d", Int(0)),
+			For(Let("p", Prop(el, "parentElement")), p, Assign(p, Prop(p, "parentElement")),
+				If(Method(p, "hasAttribute", String("data-island")), Incr(d)),
+			),
+			Return(d),
+		)),
+		Const("els", Method(Array_, "from", QuerySelectorAll(String("[data-island]")))),
+		ExprStmt(Method(Ident("els"), "sort", ArrowFunc([]string{"a", "b"},
+			Sub(Call(depth, Ident("a")), Call(depth, Ident("b"))),
+		))),
+		Const("io", Ternary(
+			In(String("IntersectionObserver"), Window),
+			New(Ident("IntersectionObserver"), ArrowFuncStmts([]string{"entries", "obs"},
+				ForOf("e", Ident("entries"),
+					If(Prop(Ident("e"), "isIntersecting"),
+						ExprStmt(Method(Ident("obs"), "unobserve", Prop(Ident("e"), "target"))),
+						ExprStmt(Call(run, Prop(Ident("e"), "target"))),
+					),
+				),
+			)),
+			Null(),
+		)),
+		Const("idle", Or(
+			Prop(Window, "requestIdleCallback"),
+			Group(ArrowFunc([]string{"fn"}, SetTimeout(Ident("fn"), Int(1)))),
+		)),
+		ForOf("el", Ident("els"),
+			Switch(Prop(dataset, "islandHydrate"),
+				CaseClause(String("idle"), ExprStmt(Call(idle, ArrowFunc(nil, Call(run, el)))), Break()),
+				CaseClause(String("visible"),
+					IfElse(io,
+						[]Stmt{ExprStmt(Method(io, "observe", el))},
+						[]Stmt{ExprStmt(Call(run, el))},
+					),
+					Break(),
+				),
+				DefaultClause(ExprStmt(Call(run, el))),
+			),
+		),
+	}
+}
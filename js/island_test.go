@@ -0,0 +1,29 @@
+package js
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestIslandScheduler(t *testing.T) {
+	got := h.RenderString(IslandScheduler())
+	if !strings.HasPrefix(got, "<script>(function() {\n") || !strings.HasSuffix(got, "})();</script>") {
+		t.Errorf("expected an IIFE in a script tag, got %s", got)
+	}
+	for _, want := range []string{
+		"window.islands[el.dataset.island]",
+		`el.dataset.islandReady = "true"`,
+		"els.sort((a, b) => depth(a) - depth(b))",
+		"new IntersectionObserver(",
+		"window.requestIdleCallback || (fn => setTimeout(fn, 1))",
+		`case "idle": idle(() => run(el));`,
+		`case "visible": if (io) {`,
+		"default: run(el);",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+}
@@ -6,13 +6,13 @@ import (
 )
 
 func exprString(e Expr) string {
-	var sb strings.Builder
+	var sb printer
 	e.js(&sb)
 	return sb.String()
 }
 
 func stmtString(s Stmt) string {
-	var sb strings.Builder
+	var sb printer
 	s.stmt(&sb)
 	return sb.String()
 }
@@ -38,6 +38,42 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestStringForAttr(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello", `"hello"`},
+		{"it's", `"it\u0027s"`},
+		{"`${x}`", `"\u0060${x}\u0060"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"a\u2028b\u2029c", `"a\u2028b\u2029c"`},
+		{"<a href='x'>&", `"\u003ca href=\u0027x\u0027\u003e\u0026"`},
+	}
+	for _, tt := range tests {
+		got := exprString(StringForAttr(tt.input))
+		if got != tt.expected {
+			t.Errorf("StringForAttr(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestOnEscapesForAttr(t *testing.T) {
+	got := OnClick(ExprStmt(Call(Ident("f"), String("it's"), Template("`a` <b>"), JSON(map[string]string{"k": "'"})))).Value
+	expected := `f("it\u0027s", ` + "`" + `\u0060a\u0060 \u003cb\u003e` + "`" + `, {"k":"\u0027"})`
+	if got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+	if got := Handler(ExprStmt(Call(Ident("f"), String("it's")))); got != `f("it's")` {
+		t.Errorf("Handler escaped for attributes: %s", got)
+	}
+}
+
+// FuzzEscapeTarget runs FuzzEscape: go test -fuzz=FuzzEscapeTarget ./js
+func FuzzEscapeTarget(f *testing.F) {
+	FuzzEscape(f)
+}
+
 func TestInt(t *testing.T) {
 	tests := []struct {
 		input    int
@@ -274,24 +310,24 @@ func TestBinaryOps(t *testing.T) {
 		expr     Expr
 		expected string
 	}{
-		{Add(Int(1), Int(2)), "(1 + 2)"},
-		{Sub(Int(5), Int(3)), "(5 - 3)"},
-		{Mul(Int(4), Int(2)), "(4 * 2)"},
-		{Div(Int(10), Int(2)), "(10 / 2)"},
-		{Mod(Int(10), Int(3)), "(10 % 3)"},
-		{Eq(Ident("x"), Int(5)), "(x === 5)"},
-		{NotEq(Ident("x"), Null()), "(x !== null)"},
-		{LooseEq(Ident("x"), Int(5)), "(x == 5)"},
-		{LooseNotEq(Ident("x"), Null()), "(x != null)"},
-		{Lt(Ident("x"), Int(10)), "(x < 10)"},
-		{LtEq(Ident("x"), Int(10)), "(x <= 10)"},
-		{Gt(Ident("x"), Int(0)), "(x > 0)"},
-		{GtEq(Ident("x"), Int(0)), "(x >= 0)"},
-		{And(Bool(true), Bool(false)), "(true && false)"},
-		{Or(Ident("a"), Ident("b")), "(a || b)"},
-		{NullishCoalesce(Ident("x"), String("default")), `(x ?? "default")`},
-		{Instanceof(Ident("obj"), Ident("Date")), "(obj instanceof Date)"},
-		{In(String("key"), Ident("obj")), `("key" in obj)`},
+		{Add(Int(1), Int(2)), "1 + 2"},
+		{Sub(Int(5), Int(3)), "5 - 3"},
+		{Mul(Int(4), Int(2)), "4 * 2"},
+		{Div(Int(10), Int(2)), "10 / 2"},
+		{Mod(Int(10), Int(3)), "10 % 3"},
+		{Eq(Ident("x"), Int(5)), "x === 5"},
+		{NotEq(Ident("x"), Null()), "x !== null"},
+		{LooseEq(Ident("x"), Int(5)), "x == 5"},
+		{LooseNotEq(Ident("x"), Null()), "x != null"},
+		{Lt(Ident("x"), Int(10)), "x < 10"},
+		{LtEq(Ident("x"), Int(10)), "x <= 10"},
+		{Gt(Ident("x"), Int(0)), "x > 0"},
+		{GtEq(Ident("x"), Int(0)), "x >= 0"},
+		{And(Bool(true), Bool(false)), "true && false"},
+		{Or(Ident("a"), Ident("b")), "a || b"},
+		{NullishCoalesce(Ident("x"), String("default")), `x ?? "default"`},
+		{Instanceof(Ident("obj"), Ident("Date")), "obj instanceof Date"},
+		{In(String("key"), Ident("obj")), `"key" in obj`},
 	}
 	for _, tt := range tests {
 		got := exprString(tt.expr)
@@ -324,7 +360,7 @@ func TestUnaryOps(t *testing.T) {
 
 func TestTernary(t *testing.T) {
 	got := exprString(Ternary(Ident("cond"), String("yes"), String("no")))
-	expected := `(cond ? "yes" : "no")`
+	expected := `cond ? "yes" : "no"`
 	if got != expected {
 		t.Errorf("Ternary() = %q, want %q", got, expected)
 	}
@@ -340,7 +376,7 @@ func TestSpread(t *testing.T) {
 
 func TestGroup(t *testing.T) {
 	got := exprString(Group(Add(Int(1), Int(2))))
-	expected := `((1 + 2))`
+	expected := `(1 + 2)`
 	if got != expected {
 		t.Errorf("Group() = %q, want %q", got, expected)
 	}
@@ -463,6 +499,78 @@ func TestThrow(t *testing.T) {
 	}
 }
 
+func TestTry(t *testing.T) {
+	got := stmtString(Try(
+		[]Stmt{Const("data", Method(Ident("JSON"), "parse", Ident("text")))},
+		"err",
+		[]Stmt{ExprStmt(ConsoleError(Ident("err")))},
+	))
+	expected := "try { const data = JSON.parse(text) } catch (err) { console.error(err) }"
+	if got != expected {
+		t.Errorf("Try() = %q, want %q", got, expected)
+	}
+}
+
+func TestTryOptionalCatchBinding(t *testing.T) {
+	got := stmtString(Try([]Stmt{ExprStmt(Call(Ident("run")))}, "", []Stmt{ReturnVoid()}))
+	expected := "try { run() } catch { return }"
+	if got != expected {
+		t.Errorf("Try() = %q, want %q", got, expected)
+	}
+}
+
+func TestTryFinally(t *testing.T) {
+	got := stmtString(TryFinally(
+		[]Stmt{ExprStmt(Call(Ident("run")))},
+		[]Stmt{Assign(Ident("busy"), Bool(false))},
+	))
+	expected := "try { run() } finally { busy = false }"
+	if got != expected {
+		t.Errorf("TryFinally() = %q, want %q", got, expected)
+	}
+}
+
+func TestTryCatchFinally(t *testing.T) {
+	got := stmtString(TryCatchFinally(
+		[]Stmt{ExprStmt(Call(Ident("run")))},
+		"e",
+		[]Stmt{ExprStmt(ConsoleError(Ident("e")))},
+		[]Stmt{Assign(Ident("busy"), Bool(false))},
+	))
+	expected := "try { run() } catch (e) { console.error(e) } finally { busy = false }"
+	if got != expected {
+		t.Errorf("TryCatchFinally() = %q, want %q", got, expected)
+	}
+}
+
+func TestDestructure(t *testing.T) {
+	tests := []struct {
+		name     string
+		stmt     Stmt
+		expected string
+	}{
+		{"let array", LetDestructure([]string{"a", "b"}, Ident("pair")), "let [a, b] = pair"},
+		{"array hole", ConstDestructure([]string{"", "second"}, Ident("list")), "const [, second] = list"},
+		{
+			"let object",
+			LetDestructureObj(map[string]string{"status": "", "ok": "ok", "detail": "info"}, Ident("res")),
+			"let {detail: info, ok, status} = res",
+		},
+		{
+			"const object",
+			ConstDestructureObj(map[string]string{"id": "id"}, Prop(Ident("event"), "detail")),
+			"const {id} = event.detail",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stmtString(tt.stmt); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestBreak(t *testing.T) {
 	got := stmtString(Break())
 	if got != "break" {
@@ -515,6 +623,82 @@ func TestIfElse(t *testing.T) {
 	}
 }
 
+func TestFor(t *testing.T) {
+	got := stmtString(For(
+		Let("i", Int(0)),
+		Lt(Ident("i"), Int(3)),
+		Incr(Ident("i")),
+		ExprStmt(ConsoleLog(Ident("i"))),
+	))
+	expected := "for (let i = 0; i < 3; i++) { console.log(i) }"
+	if got != expected {
+		t.Errorf("For() = %q, want %q", got, expected)
+	}
+}
+
+func TestForEmptyClauses(t *testing.T) {
+	got := stmtString(For(nil, nil, nil, Break()))
+	expected := "for (; ; ) { break }"
+	if got != expected {
+		t.Errorf("For() = %q, want %q", got, expected)
+	}
+}
+
+func TestForOf(t *testing.T) {
+	got := stmtString(ForOf("el", QuerySelectorAll(String(".item")),
+		ExprStmt(ClassListAdd(Ident("el"), String("active"))),
+	))
+	expected := `for (const el of document.querySelectorAll(".item")) { el.classList.add("active") }`
+	if got != expected {
+		t.Errorf("ForOf() = %q, want %q", got, expected)
+	}
+}
+
+func TestForIn(t *testing.T) {
+	got := stmtString(ForIn("key", Ident("obj"), ExprStmt(ConsoleLog(Ident("key")))))
+	expected := "for (const key in obj) { console.log(key) }"
+	if got != expected {
+		t.Errorf("ForIn() = %q, want %q", got, expected)
+	}
+}
+
+func TestWhile(t *testing.T) {
+	got := stmtString(While(Gt(Ident("n"), Int(0)), Decr(Ident("n"))))
+	expected := "while (n > 0) { n-- }"
+	if got != expected {
+		t.Errorf("While() = %q, want %q", got, expected)
+	}
+}
+
+func TestDoWhile(t *testing.T) {
+	got := stmtString(DoWhile(Gt(Ident("n"), Int(0)), Decr(Ident("n"))))
+	expected := "do { n-- } while (n > 0)"
+	if got != expected {
+		t.Errorf("DoWhile() = %q, want %q", got, expected)
+	}
+}
+
+func TestSwitch(t *testing.T) {
+	got := stmtString(Switch(EventKey(),
+		CaseClause(String("ArrowUp"), ExprStmt(Call(Ident("up"))), Break()),
+		CaseClause(String("j")),
+		CaseClause(String("ArrowDown"), ExprStmt(Call(Ident("down"))), Break()),
+		DefaultClause(ReturnVoid()),
+	))
+	expected := `switch (event.key) { case "ArrowUp": up(); break; case "j": case "ArrowDown": down(); break; default: return; }`
+	if got != expected {
+		t.Errorf("Switch() = %q, want %q", got, expected)
+	}
+}
+
+func TestSwitchNoClauses(t *testing.T) {
+	got := stmtString(Switch(Ident("x")))
+	expected := "switch (x) { }"
+	if got != expected {
+		t.Errorf("Switch() = %q, want %q", got, expected)
+	}
+}
+
 func TestStmts(t *testing.T) {
 	got := stmtString(Stmts(
 		Let("x", Int(1)),
@@ -773,7 +957,7 @@ func TestJSONParse(t *testing.T) {
 
 func TestArrowFunc(t *testing.T) {
 	got := exprString(ArrowFunc([]string{"x"}, Mul(Ident("x"), Int(2))))
-	expected := "x => (x * 2)"
+	expected := "x => x * 2"
 	if got != expected {
 		t.Errorf("ArrowFunc() = %q, want %q", got, expected)
 	}
@@ -781,7 +965,7 @@ func TestArrowFunc(t *testing.T) {
 
 func TestArrowFuncMultiParams(t *testing.T) {
 	got := exprString(ArrowFunc([]string{"a", "b"}, Add(Ident("a"), Ident("b"))))
-	expected := "(a, b) => (a + b)"
+	expected := "(a, b) => a + b"
 	if got != expected {
 		t.Errorf("ArrowFunc() = %q, want %q", got, expected)
 	}
@@ -800,7 +984,7 @@ func TestArrowFuncStmts(t *testing.T) {
 		Let("result", Mul(Ident("x"), Int(2))),
 		Return(Ident("result")),
 	))
-	expected := "x => { let result = (x * 2); return result }"
+	expected := "x => { let result = x * 2; return result }"
 	if got != expected {
 		t.Errorf("ArrowFuncStmts() = %q, want %q", got, expected)
 	}
@@ -808,7 +992,7 @@ func TestArrowFuncStmts(t *testing.T) {
 
 func TestFunc(t *testing.T) {
 	got := exprString(Func([]string{"x", "y"}, Return(Add(Ident("x"), Ident("y")))))
-	expected := "function(x, y) { return (x + y) }"
+	expected := "function(x, y) { return x + y }"
 	if got != expected {
 		t.Errorf("Func() = %q, want %q", got, expected)
 	}
@@ -877,7 +1061,7 @@ func TestPromiseThen(t *testing.T) {
 
 func TestToJS(t *testing.T) {
 	got := ToJS(Add(Int(1), Int(2)))
-	expected := "(1 + 2)"
+	expected := "1 + 2"
 	if got != expected {
 		t.Errorf("ToJS() = %q, want %q", got, expected)
 	}
@@ -0,0 +1,62 @@
+package js
+
+// ListenerOption sets a field of the options object passed to
+// addEventListener and removeEventListener.
+type ListenerOption KV
+
+// Capture runs the listener during the capture phase: {"capture": true}
+func Capture() ListenerOption { return ListenerOption{"capture", Bool(true)} }
+
+// Passive promises the listener never calls preventDefault, letting the
+// browser scroll without waiting for it: {"passive": true}
+func Passive() ListenerOption { return ListenerOption{"passive", Bool(true)} }
+
+// Once removes the listener after its first call: {"once": true}
+func Once() ListenerOption { return ListenerOption{"once", Bool(true)} }
+
+// Signal removes the listener when the AbortSignal is aborted: {"signal": signal}
+func Signal(signal Expr) ListenerOption { return ListenerOption{"signal", signal} }
+
+// AddEventListener creates target.addEventListener(event, handler, options).
+// The options object is omitted when no options are given.
+//
+//	js.AddEventListener(js.Window, "scroll", js.Ident("onScroll"), js.Passive())
+//	// window.addEventListener("scroll", onScroll, {"passive": true})
+func AddEventListener(target Callable, event string, handler Expr, opts ...ListenerOption) Callable {
+	return Method(target, "addEventListener", listenerArgs(event, handler, opts)...)
+}
+
+// RemoveEventListener creates target.removeEventListener(event, handler, options).
+// Pass the same Capture option used when the listener was added.
+func RemoveEventListener(target Callable, event string, handler Expr, opts ...ListenerOption) Callable {
+	return Method(target, "removeEventListener", listenerArgs(event, handler, opts)...)
+}
+
+func listenerArgs(event string, handler Expr, opts []ListenerOption) []Expr {
+	args := []Expr{String(event), handler}
+	if len(opts) > 0 {
+		pairs := make([]KV, len(opts))
+		for i, o := range opts {
+			pairs[i] = KV(o)
+		}
+		args = append(args, Object(pairs...))
+	}
+	return args
+}
+
+// DocumentReady runs stmts once the DOM is parsed: on DOMContentLoaded, or
+// immediately if that event has already fired.
+//
+//	js.DocumentReady(js.ExprStmt(js.Call(js.Ident("init"))))
+//	// (fn => document.readyState === "loading" ? document.addEventListener("DOMContentLoaded", fn) : fn())(() => { init() })
+func DocumentReady(stmts ...Stmt) Stmt {
+	fn := Ident("fn")
+	return ExprStmt(Call(
+		Group(ArrowFunc([]string{"fn"}, Ternary(
+			Eq(Prop(Document, "readyState"), String("loading")),
+			AddEventListener(Document, "DOMContentLoaded", fn),
+			Call(fn),
+		))),
+		ArrowFuncStmts(nil, stmts...),
+	))
+}
@@ -0,0 +1,47 @@
+package js
+
+import "testing"
+
+func TestEventListeners(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected string
+	}{
+		{
+			"add",
+			AddEventListener(GetElementById(String("btn")), "click", Ident("onClick")),
+			`document.getElementById("btn").addEventListener("click", onClick)`,
+		},
+		{
+			"add with options",
+			AddEventListener(Window, "scroll", Ident("onScroll"), Passive(), Once()),
+			`window.addEventListener("scroll", onScroll, {"passive": true, "once": true})`,
+		},
+		{
+			"signal",
+			AddEventListener(Document, "keydown", Ident("k"), Signal(Prop(Ident("ctrl"), "signal"))),
+			`document.addEventListener("keydown", k, {"signal": ctrl.signal})`,
+		},
+		{
+			"remove",
+			RemoveEventListener(Window, "resize", Ident("onResize"), Capture()),
+			`window.removeEventListener("resize", onResize, {"capture": true})`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJS(tt.expr); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDocumentReady(t *testing.T) {
+	got := ToJSStmt(DocumentReady(ExprStmt(Call(Ident("init")))))
+	expected := `(fn => document.readyState === "loading" ? document.addEventListener("DOMContentLoaded", fn) : fn())(() => { init() })`
+	if got != expected {
+		t.Errorf("got %q, want %q", got, expected)
+	}
+}
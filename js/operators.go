@@ -10,16 +10,40 @@ type binaryOp struct {
 	right Expr
 }
 
-func (b binaryOp) js(sb *strings.Builder) {
-	sb.WriteString("(")
-	b.left.js(sb)
+func (b binaryOp) js(sb *printer) {
+	if alwaysParenthesize() {
+		sb.WriteString("(")
+		b.left.js(sb)
+		sb.WriteString(" ")
+		sb.WriteString(b.op)
+		sb.WriteString(" ")
+		b.right.js(sb)
+		sb.WriteString(")")
+		return
+	}
+	// Operators are left-associative, so a right operand of equal
+	// precedence needs parentheses: a - (b - c).
+	p := binaryPrecedence(b.op)
+	if mixesNullish(b.op, b.left) {
+		p = precPrimary
+	}
+	writeOperand(sb, b.left, p)
 	sb.WriteString(" ")
 	sb.WriteString(b.op)
 	sb.WriteString(" ")
-	b.right.js(sb)
-	sb.WriteString(")")
+	p = binaryPrecedence(b.op) + 1
+	if mixesNullish(b.op, b.right) {
+		p = precPrimary
+	}
+	writeOperand(sb, b.right, p)
 }
 func (b binaryOp) callable() {}
+func (b binaryOp) precedence() int {
+	if alwaysParenthesize() {
+		return precPrimary
+	}
+	return binaryPrecedence(b.op)
+}
 
 // Add returns left + right
 func Add(left, right Expr) Callable { return binaryOp{left, "+", right} }
@@ -101,16 +125,49 @@ type unaryOp struct {
 	prefix bool
 }
 
-func (u unaryOp) js(sb *strings.Builder) {
+func (u unaryOp) js(sb *printer) {
 	if u.prefix {
 		sb.WriteString(u.op)
-		u.expr.js(sb)
+		if (u.op == "-" || u.op == "+") && leadingSign(u.expr) == u.op[0] {
+			sb.WriteString(" ") // - -x, not the decrement --x
+		}
+		writeOperand(sb, u.expr, precUnary)
 	} else {
-		u.expr.js(sb)
+		writeOperand(sb, u.expr, precMember)
 		sb.WriteString(u.op)
 	}
 }
 func (u unaryOp) callable() {}
+func (u unaryOp) precedence() int {
+	if u.prefix {
+		return precUnary
+	}
+	return precPostfix
+}
+
+// leadingSign returns the + or - that e is written with at its start
+// without parentheses, or 0 if it has none.
+func leadingSign(e Expr) byte {
+	switch e := e.(type) {
+	case unaryOp:
+		if e.prefix && (e.op == "-" || e.op == "+") {
+			return e.op[0]
+		}
+	case incrDecr:
+		if e.pre {
+			return e.op[0]
+		}
+	case literal:
+		if strings.HasPrefix(e.value, "-") {
+			return '-'
+		}
+	case jsonLiteral:
+		if strings.HasPrefix(e.value, "-") {
+			return '-'
+		}
+	}
+	return 0
+}
 
 // Not returns !expr
 func Not(expr Expr) Callable { return unaryOp{"!", expr, true} }
@@ -141,16 +198,30 @@ type ternaryOp struct {
 	ifFalse Expr
 }
 
-func (t ternaryOp) js(sb *strings.Builder) {
-	sb.WriteString("(")
-	t.cond.js(sb)
+func (t ternaryOp) js(sb *printer) {
+	if alwaysParenthesize() {
+		sb.WriteString("(")
+		t.cond.js(sb)
+		sb.WriteString(" ? ")
+		t.ifTrue.js(sb)
+		sb.WriteString(" : ")
+		t.ifFalse.js(sb)
+		sb.WriteString(")")
+		return
+	}
+	writeOperand(sb, t.cond, precOr)
 	sb.WriteString(" ? ")
-	t.ifTrue.js(sb)
+	writeOperand(sb, t.ifTrue, precAssign)
 	sb.WriteString(" : ")
-	t.ifFalse.js(sb)
-	sb.WriteString(")")
+	writeOperand(sb, t.ifFalse, precAssign)
 }
 func (t ternaryOp) callable() {}
+func (t ternaryOp) precedence() int {
+	if alwaysParenthesize() {
+		return precPrimary
+	}
+	return precAssign
+}
 
 // Ternary returns cond ? ifTrue : ifFalse
 func Ternary(cond, ifTrue, ifFalse Expr) Callable {
@@ -163,7 +234,7 @@ type groupExpr struct {
 	expr Expr
 }
 
-func (g groupExpr) js(sb *strings.Builder) {
+func (g groupExpr) js(sb *printer) {
 	sb.WriteString("(")
 	g.expr.js(sb)
 	sb.WriteString(")")
@@ -181,7 +252,7 @@ type commaExpr struct {
 	exprs []Expr
 }
 
-func (c commaExpr) js(sb *strings.Builder) {
+func (c commaExpr) js(sb *printer) {
 	sb.WriteString("(")
 	for i, e := range c.exprs {
 		if i > 0 {
@@ -205,9 +276,9 @@ type spreadExpr struct {
 	expr Expr
 }
 
-func (s spreadExpr) js(sb *strings.Builder) {
+func (s spreadExpr) js(sb *printer) {
 	sb.WriteString("...")
-	s.expr.js(sb)
+	writeOperand(sb, s.expr, precAssign)
 }
 func (s spreadExpr) callable() {}
 
@@ -0,0 +1,103 @@
+package js
+
+import (
+	"github.com/jeffh/htmlgen/config"
+)
+
+// AlwaysParenthesize restores the output of earlier versions, which wrapped
+// every binary and ternary expression in parentheses, e.g.
+// ((1 + 2) + (3 * 4)) instead of 1 + 2 + 3 * 4. Set it during
+// initialization, before rendering; it is not safe to change concurrently
+// with rendering. config.AlwaysParenthesize(true) has the same effect.
+var AlwaysParenthesize = false
+
+func alwaysParenthesize() bool {
+	return AlwaysParenthesize || config.Default().AlwaysParenthesize
+}
+
+// Operator precedence levels, following MDN's operator precedence table.
+// Expressions print parentheses around operands with lower precedence than
+// their position allows.
+const (
+	precComma          = 1
+	precAssign         = 2 // Assignment, arrow functions, ternary, spread
+	precOr             = 3 // || and ??
+	precAnd            = 4
+	precBitOr          = 5
+	precBitXor         = 6
+	precBitAnd         = 7
+	precEquality       = 8
+	precRelational     = 9
+	precShift          = 10
+	precAdditive       = 11
+	precMultiplicative = 12
+	precUnary          = 14
+	precPostfix        = 15
+	precMember         = 17 // Member access, calls, new with arguments
+	precPrimary        = 18
+)
+
+// precedenced is implemented by expressions that bind less tightly than
+// primary expressions such as identifiers, literals, and calls.
+type precedenced interface {
+	precedence() int
+}
+
+// precedence returns e's precedence level. Raw code is assumed to be a
+// primary expression; wrap it with Group if it is not.
+func precedence(e Expr) int {
+	if p, ok := e.(precedenced); ok {
+		return p.precedence()
+	}
+	return precPrimary
+}
+
+// writeOperand writes e, in parentheses if its precedence is below min.
+func writeOperand(sb *printer, e Expr, min int) {
+	if precedence(e) < min {
+		sb.WriteString("(")
+		e.js(sb)
+		sb.WriteString(")")
+		return
+	}
+	e.js(sb)
+}
+
+// binaryPrecedence returns the precedence of a binary operator.
+func binaryPrecedence(op string) int {
+	switch op {
+	case "||", "??":
+		return precOr
+	case "&&":
+		return precAnd
+	case "|":
+		return precBitOr
+	case "^":
+		return precBitXor
+	case "&":
+		return precBitAnd
+	case "===", "!==", "==", "!=":
+		return precEquality
+	case "<", "<=", ">", ">=", "instanceof", "in":
+		return precRelational
+	case "<<", ">>", ">>>":
+		return precShift
+	case "+", "-":
+		return precAdditive
+	default: // "*", "/", "%"
+		return precMultiplicative
+	}
+}
+
+// mixesNullish reports whether an operand of op must be parenthesized
+// because JavaScript forbids mixing ?? with && or || without parentheses.
+func mixesNullish(op string, operand Expr) bool {
+	b, ok := operand.(binaryOp)
+	if !ok || AlwaysParenthesize {
+		return false
+	}
+	if op == "??" {
+		return b.op == "&&" || b.op == "||"
+	}
+	return (op == "&&" || op == "||") && b.op == "??"
+}
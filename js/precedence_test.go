@@ -0,0 +1,90 @@
+package js
+
+import (
+	"testing"
+
+	"github.com/jeffh/htmlgen/config"
+)
+
+func TestPrecedence(t *testing.T) {
+	a, b, c := Ident("a"), Ident("b"), Ident("c")
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected string
+	}{
+		{"left assoc", Add(Add(Int(1), Int(2)), Mul(Int(3), Int(4))), "1 + 2 + 3 * 4"},
+		{"lower left", Mul(Add(a, b), c), "(a + b) * c"},
+		{"right equal", Sub(a, Sub(b, c)), "a - (b - c)"},
+		{"right higher", Sub(a, Mul(b, c)), "a - b * c"},
+		{"logical", Or(And(a, b), c), "a && b || c"},
+		{"logical right", And(a, Or(b, c)), "a && (b || c)"},
+		{"comparison in logical", And(Eq(a, Int(1)), Gt(b, Int(2))), "a === 1 && b > 2"},
+		{"nullish with or", NullishCoalesce(Or(a, b), c), "(a || b) ?? c"},
+		{"or with nullish", Or(a, NullishCoalesce(b, c)), "a || (b ?? c)"},
+		{"nullish chain", NullishCoalesce(NullishCoalesce(a, b), c), "a ?? b ?? c"},
+		{"not binary", Not(Eq(a, b)), "!(a === b)"},
+		{"not not", Not(Not(a)), "!!a"},
+		{"typeof in comparison", Eq(Typeof(a), String("string")), `typeof a === "string"`},
+		{"member of binary", Prop(Add(a, b), "length"), "(a + b).length"},
+		{"call of arrow", Call(ArrowFunc(nil, a)), "(() => a)()"},
+		{"method of ternary", Method(Ternary(a, b, c), "focus"), "(a ? b : c).focus()"},
+		{"index of or", Index(Or(a, b), Int(0)), "(a || b)[0]"},
+		{"ternary cond", Ternary(Or(a, b), Int(1), Int(2)), "a || b ? 1 : 2"},
+		{"ternary nested cond", Ternary(Ternary(a, b, c), Int(1), Int(2)), "(a ? b : c) ? 1 : 2"},
+		{"ternary nested branch", Ternary(a, Int(1), Ternary(b, Int(2), Int(3))), "a ? 1 : b ? 2 : 3"},
+		{"arrow in or", Or(a, ArrowFunc(nil, b)), "a || (() => b)"},
+		{"arrow body", ArrowFunc([]string{"x"}, Add(Ident("x"), Int(1))), "x => x + 1"},
+		{"arrow body comma", ArrowFunc(nil, Comma(a, b)), "() => (a, b)"},
+		{"await binary", Add(Await(a), Int(1)), "await a + 1"},
+		{"await of binary", Await(Or(a, b)), "await (a || b)"},
+		{"new of call", Prop(New(Ident("Date")), "getTime"), "new Date().getTime"},
+		{"group kept", Group(Add(a, b)), "(a + b)"},
+		{"arg", Call(Ident("f"), Add(a, b)), "f(a + b)"},
+		{"neg neg", Neg(Neg(a)), "- -a"},
+		{"neg negative", Neg(Int(-1)), "- -1"},
+		{"pos pos", Pos(Pos(a)), "+ +a"},
+		{"neg pos", Neg(Pos(a)), "-+a"},
+		{"neg predecrement", Neg(PreDecr(a)), "- --a"},
+		{"member of negative", Prop(Int(-1), "toFixed"), "(-1).toFixed"},
+		{"member of number", Method(Int(1), "toFixed", Int(2)), "(1).toFixed(2)"},
+		{"negative operand", Sub(a, Int(-1)), "a - -1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToJS(tt.expr); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAlwaysParenthesize(t *testing.T) {
+	AlwaysParenthesize = true
+	defer func() { AlwaysParenthesize = false }()
+
+	a, b, c := Ident("a"), Ident("b"), Ident("c")
+	tests := []struct {
+		expr     Expr
+		expected string
+	}{
+		{Add(Add(Int(1), Int(2)), Mul(Int(3), Int(4))), "((1 + 2) + (3 * 4))"},
+		{Ternary(Eq(a, b), c, Null()), "((a === b) ? c : null)"},
+		{Not(And(a, b)), "!(a && b)"},
+		{Prop(Or(a, b), "x"), "(a || b).x"},
+		{NullishCoalesce(Or(a, b), c), "((a || b) ?? c)"},
+	}
+	for _, tt := range tests {
+		if got := ToJS(tt.expr); got != tt.expected {
+			t.Errorf("got %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestAlwaysParenthesizeConfig(t *testing.T) {
+	config.Set(config.AlwaysParenthesize(true))
+	defer config.Reset()
+	if got := ToJS(Add(Int(1), Mul(Int(2), Int(3)))); got != "(1 + (2 * 3))" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package js
+
+import "strings"
+
+// printer accumulates generated JavaScript. By default statements are
+// written on one line; Format sets format to lay blocks out on indented
+// lines instead, and the On* helpers set attr to escape every string
+// literal for attribute context.
+type printer struct {
+	strings.Builder
+	format   bool   // Multi-line output (see FormatOptions.Format)
+	indent   string // Indentation per block level when formatting
+	depth    int    // Current block level when formatting
+	blockEnd int    // Length after the last statement block was closed
+	attr     bool   // Escape string literals as StringForAttr does (see On)
+}
+
+// lineBreak separates parts of a block: a space on one line, or a newline
+// and indentation when formatting.
+func (p *printer) lineBreak() {
+	if !p.format {
+		p.WriteByte(' ')
+		return
+	}
+	p.WriteByte('\n')
+	for range p.depth {
+		p.WriteString(p.indent)
+	}
+}
+
+// endStmt ends a statement in a list. When formatting, statements that end
+// with a block or already end with a semicolon get none.
+func (p *printer) endStmt() {
+	if p.format {
+		if p.Len() == p.blockEnd {
+			return
+		}
+		if s := p.String(); s != "" && s[len(s)-1] == ';' {
+			return
+		}
+	}
+	p.WriteByte(';')
+}
+
+// writeStmtList writes statements separated by semicolons, one per line
+// when formatting.
+func writeStmtList(p *printer, stmts []Stmt) {
+	for i, s := range stmts {
+		if i > 0 {
+			p.endStmt()
+			p.lineBreak()
+		}
+		s.stmt(p)
+	}
+}
+
+// writeBlock writes body in braces. stmt reports whether the block ends a
+// statement (if, for, ...) rather than being a function body inside an
+// expression, which is followed by a semicolon when formatting.
+func writeBlock(p *printer, body []Stmt, stmt bool) {
+	if p.format && len(body) == 0 {
+		p.WriteString("{}")
+	} else {
+		p.WriteByte('{')
+		p.depth++
+		p.lineBreak()
+		writeStmtList(p, body)
+		if p.format {
+			p.endStmt()
+		}
+		p.depth--
+		p.lineBreak()
+		p.WriteByte('}')
+	}
+	if stmt {
+		p.blockEnd = p.Len()
+	}
+}
@@ -0,0 +1,24 @@
+package js
+
+import "github.com/jeffh/htmlgen/h"
+
+// ScriptTag renders statements as a classic <script> element, formatted
+// with [Format]:
+//
+//	js.ScriptTag(
+//	    js.Const("el", js.GetElementById(js.String("app"))),
+//	    js.ExprStmt(js.ClassListAdd(js.Ident("el"), js.String("ready"))),
+//	)
+//
+// The code is written as script text by h, which escapes sequences that
+// would end the script element early ("</script" and "<!--"), including
+// any in [Raw] code; [String] values are already safe.
+func ScriptTag(stmts ...Stmt) h.Builder {
+	return h.Script(h.Text(Format(stmts...)))
+}
+
+// ScriptModule is like [ScriptTag] but renders a <script type="module">,
+// which is deferred and runs in strict mode with its own scope.
+func ScriptModule(stmts ...Stmt) h.Builder {
+	return h.Script(h.Attr("type", "module"), h.Text(Format(stmts...)))
+}
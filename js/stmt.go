@@ -1,6 +1,10 @@
 package js
 
-import "strings"
+import (
+	"maps"
+	"slices"
+	"strings"
+)
 
 // Assignment statements
 
@@ -9,7 +13,7 @@ type assignStmt struct {
 	value  Expr
 }
 
-func (a assignStmt) stmt(sb *strings.Builder) {
+func (a assignStmt) stmt(sb *printer) {
 	a.target.js(sb)
 	sb.WriteString(" = ")
 	a.value.js(sb)
@@ -28,7 +32,7 @@ type compoundAssign struct {
 	value  Expr
 }
 
-func (c compoundAssign) stmt(sb *strings.Builder) {
+func (c compoundAssign) stmt(sb *printer) {
 	c.target.js(sb)
 	sb.WriteString(" ")
 	sb.WriteString(c.op)
@@ -84,7 +88,7 @@ type varDecl struct {
 	value Expr // nil for declaration without initialization
 }
 
-func (v varDecl) stmt(sb *strings.Builder) {
+func (v varDecl) stmt(sb *printer) {
 	sb.WriteString(v.kind)
 	sb.WriteString(" ")
 	sb.WriteString(v.name)
@@ -119,6 +123,50 @@ func VarDecl(name string) Stmt {
 	return varDecl{"var", name, nil}
 }
 
+// LetDestructure creates an array destructuring declaration: let [a, b] = value
+// An empty name leaves a hole that skips that element.
+func LetDestructure(names []string, value Expr) Stmt {
+	return varDecl{"let", arrayPattern(names), value}
+}
+
+// LetDestructureObj creates an object destructuring declaration mapping
+// property names to variable names: let {a, b: c} = value
+// Properties are listed in sorted order.
+func LetDestructureObj(props map[string]string, value Expr) Stmt {
+	return varDecl{"let", objectPattern(props), value}
+}
+
+// ConstDestructure creates an array destructuring declaration: const [a, b] = value
+func ConstDestructure(names []string, value Expr) Stmt {
+	return varDecl{"const", arrayPattern(names), value}
+}
+
+// ConstDestructureObj creates an object destructuring declaration: const {a, b: c} = value
+func ConstDestructureObj(props map[string]string, value Expr) Stmt {
+	return varDecl{"const", objectPattern(props), value}
+}
+
+func arrayPattern(names []string) string {
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+func objectPattern(props map[string]string) string {
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, prop := range slices.Sorted(maps.Keys(props)) {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(prop)
+		if name := props[prop]; name != "" && name != prop {
+			sb.WriteString(": ")
+			sb.WriteString(name)
+		}
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
 // Increment/Decrement
 
 type incrDecr struct {
@@ -127,7 +175,7 @@ type incrDecr struct {
 	pre    bool
 }
 
-func (i incrDecr) stmt(sb *strings.Builder) {
+func (i incrDecr) stmt(sb *printer) {
 	if i.pre {
 		sb.WriteString(i.op)
 		i.target.js(sb)
@@ -138,8 +186,14 @@ func (i incrDecr) stmt(sb *strings.Builder) {
 }
 
 // Expressions versions for use in larger expressions
-func (i incrDecr) js(sb *strings.Builder) { i.stmt(sb) }
-func (i incrDecr) callable()              {}
+func (i incrDecr) js(sb *printer) { i.stmt(sb) }
+func (i incrDecr) callable()      {}
+func (i incrDecr) precedence() int {
+	if i.pre {
+		return precUnary
+	}
+	return precPostfix
+}
 
 // Incr creates: target++ (post-increment statement)
 func Incr(target Callable) Stmt { return incrDecr{target, "++", false} }
@@ -165,7 +219,7 @@ type returnStmt struct {
 	value Expr // nil for bare return
 }
 
-func (r returnStmt) stmt(sb *strings.Builder) {
+func (r returnStmt) stmt(sb *printer) {
 	sb.WriteString("return")
 	if r.value != nil {
 		sb.WriteString(" ")
@@ -189,7 +243,7 @@ type throwStmt struct {
 	value Expr
 }
 
-func (t throwStmt) stmt(sb *strings.Builder) {
+func (t throwStmt) stmt(sb *printer) {
 	sb.WriteString("throw ")
 	t.value.js(sb)
 }
@@ -199,13 +253,67 @@ func Throw(value Expr) Stmt {
 	return throwStmt{value}
 }
 
+// Try statement
+
+type tryStmt struct {
+	body        []Stmt
+	hasCatch    bool
+	catchParam  string // empty for an optional catch binding
+	catchBody   []Stmt
+	finallyBody []Stmt // nil for no finally clause
+}
+
+func (t tryStmt) stmt(sb *printer) {
+	sb.WriteString("try ")
+	blockStmt{t.body}.stmt(sb)
+	if t.hasCatch {
+		sb.WriteString(" catch ")
+		if t.catchParam != "" {
+			sb.WriteString("(")
+			sb.WriteString(t.catchParam)
+			sb.WriteString(") ")
+		}
+		blockStmt{t.catchBody}.stmt(sb)
+	}
+	if t.finallyBody != nil {
+		sb.WriteString(" finally ")
+		blockStmt{t.finallyBody}.stmt(sb)
+	}
+}
+
+// Try creates a try-catch statement: try { body... } catch (catchParam) { catchBody... }
+// An empty catchParam omits the binding: try { body... } catch { catchBody... }
+func Try(body []Stmt, catchParam string, catchBody []Stmt) Stmt {
+	return tryStmt{body: body, hasCatch: true, catchParam: catchParam, catchBody: catchBody}
+}
+
+// TryFinally creates a try-finally statement: try { body... } finally { finallyBody... }
+func TryFinally(body []Stmt, finallyBody []Stmt) Stmt {
+	return tryStmt{body: body, finallyBody: nonNil(finallyBody)}
+}
+
+// TryCatchFinally creates a try-catch-finally statement:
+// try { body... } catch (catchParam) { catchBody... } finally { finallyBody... }
+func TryCatchFinally(body []Stmt, catchParam string, catchBody []Stmt, finallyBody []Stmt) Stmt {
+	return tryStmt{body: body, hasCatch: true, catchParam: catchParam, catchBody: catchBody, finallyBody: nonNil(finallyBody)}
+}
+
+// nonNil returns body, or an empty slice if it is nil, so that an explicitly
+// requested but empty block is still rendered.
+func nonNil(body []Stmt) []Stmt {
+	if body == nil {
+		return []Stmt{}
+	}
+	return body
+}
+
 // Break statement
 
 type breakStmt struct {
 	label string
 }
 
-func (b breakStmt) stmt(sb *strings.Builder) {
+func (b breakStmt) stmt(sb *printer) {
 	sb.WriteString("break")
 	if b.label != "" {
 		sb.WriteString(" ")
@@ -229,7 +337,7 @@ type continueStmt struct {
 	label string
 }
 
-func (c continueStmt) stmt(sb *strings.Builder) {
+func (c continueStmt) stmt(sb *printer) {
 	sb.WriteString("continue")
 	if c.label != "" {
 		sb.WriteString(" ")
@@ -255,26 +363,14 @@ type ifStmt struct {
 	elseBody []Stmt
 }
 
-func (i ifStmt) stmt(sb *strings.Builder) {
+func (i ifStmt) stmt(sb *printer) {
 	sb.WriteString("if (")
 	i.cond.js(sb)
-	sb.WriteString(") { ")
-	for j, s := range i.body {
-		if j > 0 {
-			sb.WriteString("; ")
-		}
-		s.stmt(sb)
-	}
-	sb.WriteString(" }")
+	sb.WriteString(") ")
+	writeBlock(sb, i.body, true)
 	if len(i.elseBody) > 0 {
-		sb.WriteString(" else { ")
-		for j, s := range i.elseBody {
-			if j > 0 {
-				sb.WriteString("; ")
-			}
-			s.stmt(sb)
-		}
-		sb.WriteString(" }")
+		sb.WriteString(" else ")
+		writeBlock(sb, i.elseBody, true)
 	}
 }
 
@@ -288,17 +384,168 @@ func IfElse(cond Expr, thenBody []Stmt, elseBody []Stmt) Stmt {
 	return ifStmt{cond: cond, body: thenBody, elseBody: elseBody}
 }
 
-// Statement list
+// Loop statements
 
-type stmtList []Stmt
+type forStmt struct {
+	init Stmt // nil to omit
+	cond Expr // nil to omit
+	post Stmt // nil to omit
+	body []Stmt
+}
 
-func (s stmtList) stmt(sb *strings.Builder) {
-	for i, stmt := range s {
-		if i > 0 {
-			sb.WriteString("; ")
+func (f forStmt) stmt(sb *printer) {
+	sb.WriteString("for (")
+	if f.init != nil {
+		f.init.stmt(sb)
+	}
+	sb.WriteString("; ")
+	if f.cond != nil {
+		f.cond.js(sb)
+	}
+	sb.WriteString("; ")
+	if f.post != nil {
+		f.post.stmt(sb)
+	}
+	sb.WriteString(") ")
+	blockStmt{f.body}.stmt(sb)
+}
+
+// For creates a for loop: for (init; cond; post) { body... }
+// Any of init, cond, and post may be nil to leave that clause empty.
+//
+//	js.For(js.Let("i", js.Int(0)), js.Lt(js.Ident("i"), js.Int(3)), js.Incr(js.Ident("i")), ...)
+func For(init Stmt, cond Expr, post Stmt, body ...Stmt) Stmt {
+	return forStmt{init, cond, post, body}
+}
+
+type forEachStmt struct {
+	op       string // "of" or "in"
+	name     string
+	iterable Expr
+	body     []Stmt
+}
+
+func (f forEachStmt) stmt(sb *printer) {
+	sb.WriteString("for (const ")
+	sb.WriteString(f.name)
+	sb.WriteString(" ")
+	sb.WriteString(f.op)
+	sb.WriteString(" ")
+	f.iterable.js(sb)
+	sb.WriteString(") ")
+	blockStmt{f.body}.stmt(sb)
+}
+
+// ForOf creates a for...of loop: for (const name of iterable) { body... }
+//
+//	js.ForOf("el", js.QuerySelectorAll(js.String(".item")), ...)
+func ForOf(name string, iterable Expr, body ...Stmt) Stmt {
+	return forEachStmt{"of", name, iterable, body}
+}
+
+// ForIn creates a for...in loop: for (const name in object) { body... }
+func ForIn(name string, object Expr, body ...Stmt) Stmt {
+	return forEachStmt{"in", name, object, body}
+}
+
+type whileStmt struct {
+	cond Expr
+	body []Stmt
+	do   bool
+}
+
+func (w whileStmt) stmt(sb *printer) {
+	if w.do {
+		sb.WriteString("do ")
+		blockStmt{w.body}.stmt(sb)
+		sb.WriteString(" while (")
+		w.cond.js(sb)
+		sb.WriteString(")")
+		return
+	}
+	sb.WriteString("while (")
+	w.cond.js(sb)
+	sb.WriteString(") ")
+	blockStmt{w.body}.stmt(sb)
+}
+
+// While creates a while loop: while (cond) { body... }
+func While(cond Expr, body ...Stmt) Stmt {
+	return whileStmt{cond: cond, body: body}
+}
+
+// DoWhile creates a do...while loop: do { body... } while (cond)
+func DoWhile(cond Expr, body ...Stmt) Stmt {
+	return whileStmt{cond: cond, body: body, do: true}
+}
+
+// Switch statement
+
+// SwitchClause is a case or default clause of a [Switch] statement.
+type SwitchClause struct {
+	test Expr // nil for the default clause
+	body []Stmt
+}
+
+// CaseClause creates a switch case: case value: body...
+// Clauses fall through unless body ends with [Break] or [Return].
+func CaseClause(value Expr, body ...Stmt) SwitchClause {
+	return SwitchClause{test: value, body: body}
+}
+
+// DefaultClause creates a switch default clause: default: body...
+func DefaultClause(body ...Stmt) SwitchClause {
+	return SwitchClause{body: body}
+}
+
+type switchStmt struct {
+	disc    Expr
+	clauses []SwitchClause
+}
+
+func (s switchStmt) stmt(sb *printer) {
+	sb.WriteString("switch (")
+	s.disc.js(sb)
+	sb.WriteString(") {")
+	sb.depth++
+	for _, c := range s.clauses {
+		sb.lineBreak()
+		if c.test != nil {
+			sb.WriteString("case ")
+			c.test.js(sb)
+			sb.WriteString(":")
+		} else {
+			sb.WriteString("default:")
+		}
+		for _, stmt := range c.body {
+			sb.WriteString(" ")
+			stmt.stmt(sb)
+			sb.WriteString(";")
 		}
-		stmt.stmt(sb)
 	}
+	sb.depth--
+	sb.lineBreak()
+	sb.WriteString("}")
+	sb.blockEnd = sb.Len()
+}
+
+// Switch creates a switch statement: switch (disc) { case ...: ...; default: ... }
+//
+//	js.Switch(js.EventKey(),
+//	    js.CaseClause(js.String("ArrowUp"), js.ExprStmt(js.Call(js.Ident("up"))), js.Break()),
+//	    js.DefaultClause(js.ReturnVoid()),
+//	)
+//	// switch (event.key) { case "ArrowUp": up(); break; default: return; }
+func Switch(disc Expr, clauses ...SwitchClause) Stmt {
+	return switchStmt{disc, clauses}
+}
+
+// Statement list
+
+type stmtList []Stmt
+
+func (s stmtList) stmt(sb *printer) {
+	writeStmtList(sb, s)
 }
 
 // Stmts combines multiple statements (semicolon-separated).
@@ -312,15 +559,8 @@ type blockStmt struct {
 	body []Stmt
 }
 
-func (b blockStmt) stmt(sb *strings.Builder) {
-	sb.WriteString("{ ")
-	for i, s := range b.body {
-		if i > 0 {
-			sb.WriteString("; ")
-		}
-		s.stmt(sb)
-	}
-	sb.WriteString(" }")
+func (b blockStmt) stmt(sb *printer) {
+	writeBlock(sb, b.body, true)
 }
 
 // Block creates a block statement: { body... }
@@ -332,7 +572,7 @@ func Block(body ...Stmt) Stmt {
 
 type debuggerStmt struct{}
 
-func (d debuggerStmt) stmt(sb *strings.Builder) {
+func (d debuggerStmt) stmt(sb *printer) {
 	sb.WriteString("debugger")
 }
 
@@ -11,6 +11,16 @@ import (
 // writeJSONString writes a JSON-encoded string directly to the builder,
 // avoiding the allocation from json.Marshal.
 func writeJSONString(sb *strings.Builder, s string) {
+	writeQuotedString(sb, s, false)
+}
+
+// attrEscapes are the escapes writeQuotedString adds for attributes.
+var attrEscapes = map[rune]string{'\'': `\u0027`, '`': `\u0060`, '\u2028': `\u2028`, '\u2029': `\u2029`}
+
+// writeQuotedString writes s as a double-quoted string literal. With attr,
+// single quotes, backticks, U+2028, and U+2029 are also \u-escaped, as for
+// StringForAttr.
+func writeQuotedString(sb *strings.Builder, s string, attr bool) {
 	sb.WriteByte('"')
 	for i := 0; i < len(s); {
 		r, size := utf8.DecodeRuneInString(s[i:])
@@ -36,6 +46,12 @@ func writeJSONString(sb *strings.Builder, s string) {
 			sb.WriteString(`\u003e`)
 		case '&':
 			sb.WriteString(`\u0026`)
+		case '\'', '`', '\u2028', '\u2029':
+			if attr {
+				sb.WriteString(attrEscapes[r])
+			} else {
+				sb.WriteRune(r)
+			}
 		default:
 			if r < 0x20 {
 				// Control characters use \uXXXX format
@@ -59,20 +75,42 @@ type literal struct {
 	value string
 }
 
-func (l literal) js(sb *strings.Builder) { sb.WriteString(l.value) }
-func (l literal) callable()              {}
+func (l literal) js(sb *printer) { sb.WriteString(l.value) }
+func (l literal) callable()      {}
+
+func (l literal) precedence() int { return literalPrecedence(l.value) }
+
+// literalPrecedence returns the precedence of a number or other literal.
+// A negative number is a unary minus expression: (-1).toFixed().
+func literalPrecedence(v string) int {
+	if strings.HasPrefix(v, "-") {
+		return precUnary
+	}
+	return precPrimary
+}
 
 // stringLiteral represents a JavaScript string literal that escapes on output.
 type stringLiteral struct {
 	value string
+	attr  bool // Escape for any quoted attribute (see StringForAttr)
 }
 
-func (s stringLiteral) js(sb *strings.Builder) { writeJSONString(sb, s.value) }
-func (s stringLiteral) callable()              {}
+func (s stringLiteral) js(sb *printer) { writeQuotedString(&sb.Builder, s.value, s.attr || sb.attr) }
+func (s stringLiteral) callable()      {}
 
 // String creates a JavaScript string literal, properly escaped using JSON encoding.
 func String(s string) Callable {
-	return stringLiteral{s}
+	return stringLiteral{value: s}
+}
+
+// StringForAttr is like String but also escapes single quotes, backticks,
+// U+2028, and U+2029 as \uXXXX, so the literal holds no quote, backtick,
+// <, >, or & other than its own double quotes. Use it for handler code
+// that is placed, unescaped, inside a single-quoted attribute or a
+// template literal by another template system. The On* helpers escape
+// every string, template, and JSON literal this way; Handler does not.
+func StringForAttr(s string) Callable {
+	return stringLiteral{value: s, attr: true}
 }
 
 // Int creates a JavaScript number literal from an integer.
@@ -115,8 +153,31 @@ func JSON(value any) Callable {
 	if err != nil {
 		panic(fmt.Errorf("js.JSON: %w: value=%#v", err, value))
 	}
-	return literal{string(b)}
+	return jsonLiteral{string(b)}
+}
+
+// jsonLiteral is JSON text. json.Marshal already escapes <, >, &, U+2028,
+// and U+2029; in attribute context single quotes and backticks, which can
+// only occur inside its strings, are escaped too.
+type jsonLiteral struct {
+	value string
+}
+
+func (l jsonLiteral) js(sb *printer) {
+	if !sb.attr || !strings.ContainsAny(l.value, "'`") {
+		sb.WriteString(l.value)
+		return
+	}
+	for _, r := range l.value {
+		if e, ok := attrEscapes[r]; ok {
+			sb.WriteString(e)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
 }
+func (l jsonLiteral) callable()       {}
+func (l jsonLiteral) precedence() int { return literalPrecedence(l.value) }
 
 // Array creates a JavaScript array literal from expressions.
 func Array(elements ...Expr) Callable {
@@ -127,7 +188,7 @@ type arrayLiteral struct {
 	elements []Expr
 }
 
-func (a arrayLiteral) js(sb *strings.Builder) {
+func (a arrayLiteral) js(sb *printer) {
 	sb.WriteString("[")
 	for i, el := range a.elements {
 		if i > 0 {
@@ -159,14 +220,14 @@ type objectLiteral struct {
 	pairs []KV
 }
 
-func (o objectLiteral) js(sb *strings.Builder) {
+func (o objectLiteral) js(sb *printer) {
 	sb.WriteString("{")
 	for i, kv := range o.pairs {
 		if i > 0 {
 			sb.WriteString(", ")
 		}
 		// Quote the key using JSON encoding for safety
-		writeJSONString(sb, kv.Key)
+		writeQuotedString(&sb.Builder, kv.Key, sb.attr)
 		sb.WriteString(": ")
 		kv.Value.js(sb)
 	}
@@ -182,8 +243,8 @@ func Ident(name string) Callable {
 
 type identifier string
 
-func (i identifier) js(sb *strings.Builder) { sb.WriteString(string(i)) }
-func (i identifier) callable()              {}
+func (i identifier) js(sb *printer) { sb.WriteString(string(i)) }
+func (i identifier) callable()      {}
 
 // This creates the special "this" identifier.
 func This() Callable {
@@ -0,0 +1,122 @@
+package lqip
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+)
+
+// ErrInvalidBlurhash is returned by Blurhash for malformed hash strings.
+var ErrInvalidBlurhash = errors.New("lqip: invalid blurhash")
+
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Blurhash decodes a blurhash string (see https://blurha.sh) into a
+// width x height PNG placeholder. Small sizes (e.g., 32x32) are sufficient
+// since the browser scales the placeholder to cover the image.
+func Blurhash(hash string, width, height int) (Placeholder, error) {
+	img, err := decodeBlurhash(hash, width, height)
+	if err != nil {
+		return Placeholder{}, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return Placeholder{}, err
+	}
+	return PreviewBytes("image/png", buf.Bytes()), nil
+}
+
+func decodeBlurhash(hash string, width, height int) (*image.NRGBA, error) {
+	if len(hash) < 6 || width <= 0 || height <= 0 {
+		return nil, ErrInvalidBlurhash
+	}
+	sizeFlag, ok := decode83(hash[0:1])
+	if !ok {
+		return nil, ErrInvalidBlurhash
+	}
+	numX, numY := sizeFlag%9+1, sizeFlag/9+1
+	if len(hash) != 4+2*numX*numY {
+		return nil, ErrInvalidBlurhash
+	}
+	quantMax, ok := decode83(hash[1:2])
+	if !ok {
+		return nil, ErrInvalidBlurhash
+	}
+	maxValue := float64(quantMax+1) / 166
+
+	colors := make([][3]float64, numX*numY)
+	for i := range colors {
+		if i == 0 {
+			v, ok := decode83(hash[2:6])
+			if !ok {
+				return nil, ErrInvalidBlurhash
+			}
+			colors[i] = [3]float64{srgbToLinear(v >> 16), srgbToLinear(v >> 8 & 255), srgbToLinear(v & 255)}
+			continue
+		}
+		v, ok := decode83(hash[4+i*2 : 6+i*2])
+		if !ok {
+			return nil, ErrInvalidBlurhash
+		}
+		colors[i] = [3]float64{
+			signPow((float64(v/(19*19))-9)/9, 2) * maxValue,
+			signPow((float64(v/19%19)-9)/9, 2) * maxValue,
+			signPow((float64(v%19)-9)/9, 2) * maxValue,
+		}
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			var r, g, b float64
+			for j := range numY {
+				for i := range numX {
+					basis := math.Cos(math.Pi*float64(x*i)/float64(width)) *
+						math.Cos(math.Pi*float64(y*j)/float64(height))
+					c := colors[i+j*numX]
+					r += c[0] * basis
+					g += c[1] * basis
+					b += c[2] * basis
+				}
+			}
+			img.SetNRGBA(x, y, color.NRGBA{linearToSRGB(r), linearToSRGB(g), linearToSRGB(b), 255})
+		}
+	}
+	return img, nil
+}
+
+func decode83(s string) (int, bool) {
+	v := 0
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base83Chars, s[i])
+		if d < 0 {
+			return 0, false
+		}
+		v = v*83 + d
+	}
+	return v, true
+}
+
+func srgbToLinear(v int) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(v float64) uint8 {
+	v = max(0, min(1, v))
+	if v <= 0.0031308 {
+		return uint8(v*12.92*255 + 0.5)
+	}
+	return uint8((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func signPow(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
@@ -0,0 +1,123 @@
+// Package lqip renders images with a low-quality image placeholder (LQIP)
+// that is shown while the full image loads, improving perceived performance
+// of image-heavy pages.
+//
+// A placeholder is either a tiny precomputed preview (typically a base64 data
+// URI of a ~20px image) or a blurhash string decoded on the server:
+//
+//	ph, err := lqip.Blurhash("LEHV6nWB2yk8pyo0adR*.7kCMdnj", 32, 32)
+//	if err != nil {
+//	    // handle error
+//	}
+//	lqip.Image("/photos/1.jpg", "A mountain lake", ph,
+//	    h.Attrs("width", "800", "height", "600"),
+//	)
+//
+// The placeholder is painted as the wrapper's background. The image starts
+// transparent and fades in from an onload handler, after which the background
+// is cleared. A <noscript> copy of the image keeps pages usable without JavaScript.
+package lqip
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/js"
+)
+
+// Placeholder is an image shown while the full image loads.
+// The zero value renders no placeholder.
+type Placeholder struct {
+	uri string
+}
+
+// Preview returns a Placeholder from a precomputed data URI
+// (e.g., "data:image/webp;base64,UklGR..."). Only base64 image data URIs
+// are painted; any other string renders no placeholder.
+func Preview(dataURI string) Placeholder {
+	return Placeholder{uri: dataURI}
+}
+
+// PreviewBytes returns a Placeholder from raw image bytes of the given
+// MIME type, encoding them as a base64 data URI.
+func PreviewBytes(mimeType string, data []byte) Placeholder {
+	return Placeholder{uri: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)}
+}
+
+// URI returns the placeholder's data URI.
+func (p Placeholder) URI() string { return p.uri }
+
+// Style returns CSS declarations painting the placeholder as a covering
+// background, or an empty string for the zero Placeholder or one whose URI
+// is not a base64 image data URI.
+func (p Placeholder) Style() string {
+	if !imageDataURI(p.uri) {
+		return ""
+	}
+	return "background-image:url('" + p.uri + "');background-size:cover;background-position:center"
+}
+
+// imageDataURI reports whether uri has the form
+// "data:image/<subtype>;base64,<data>". Such a URI contains no quotes,
+// parentheses, or backslashes, so it can sit unescaped inside url('...').
+func imageDataURI(uri string) bool {
+	rest, ok := strings.CutPrefix(uri, "data:image/")
+	if !ok {
+		return false
+	}
+	subtype, data, ok := strings.Cut(rest, ";base64,")
+	if !ok || subtype == "" || data == "" {
+		return false
+	}
+	for _, c := range subtype {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '+' || c == '-') {
+			return false
+		}
+	}
+	for _, c := range data {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '+' || c == '/' || c == '=') {
+			return false
+		}
+	}
+	return true
+}
+
+// FadeDuration is the CSS duration of the fade-in once the image loads.
+var FadeDuration = "0.3s"
+
+// swapOnLoad reveals the image and clears the wrapper's placeholder.
+var swapOnLoad = js.OnLoad(
+	js.Assign(js.Prop(js.Prop(js.This(), "style"), "opacity"), js.Int(1)),
+	js.Assign(js.Prop(js.Prop(js.Prop(js.This(), "parentElement"), "style"), "backgroundImage"), js.String("none")),
+)
+
+// Image renders an <img> wrapped in a <div class="lqip"> that shows p until
+// the image has loaded. Additional args are applied to the <img> element;
+// set width and height to reserve layout space and avoid shifts.
+// Images default to loading="lazy" and decoding="async".
+func Image(src, alt string, p Placeholder, args ...h.TagArg) h.Builder {
+	imgArgs := func(extra ...h.TagArg) []h.TagArg {
+		base := []h.TagArg{h.Attrs(
+			"src", src,
+			"alt", alt,
+			"loading", "lazy",
+			"decoding", "async",
+		)}
+		base = append(base, extra...)
+		return append(base, args...)
+	}
+
+	wrapper := h.Attrs("class", "lqip")
+	if style := p.Style(); style != "" {
+		wrapper.Set("style", style)
+	}
+	return h.Div(
+		wrapper,
+		h.Img(imgArgs(
+			h.Attr("style", "opacity:0;transition:opacity "+FadeDuration),
+			swapOnLoad,
+		)...),
+		h.Noscript(h.Img(imgArgs()...)),
+	)
+}
@@ -0,0 +1,89 @@
+package lqip
+
+import (
+	"errors"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestDecodeBlurhashSolidColor(t *testing.T) {
+	// A 1x1-component hash encoding only the average color #336699.
+	img, err := decodeBlurhash("005?}k", 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := color.NRGBA{0x33, 0x66, 0x99, 0xff}
+	for y := range 3 {
+		for x := range 4 {
+			if got := img.NRGBAAt(x, y); got != want {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestBlurhash(t *testing.T) {
+	p, err := Blurhash("LEHV6nWB2yk8pyo0adR*.7kCMdnj", 32, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(p.URI(), "data:image/png;base64,") {
+		t.Errorf("URI() = %q", p.URI())
+	}
+}
+
+func TestBlurhashInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		hash string
+		w, h int
+	}{
+		{"empty", "", 32, 32},
+		{"wrong length", "LEHV6nWB2yk8pyo0adR*.7kCMdn", 32, 32},
+		{"bad character", "00\"?}k", 32, 32},
+		{"zero size", "005?}k", 0, 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Blurhash(tt.hash, tt.w, tt.h); !errors.Is(err, ErrInvalidBlurhash) {
+				t.Errorf("err = %v, want ErrInvalidBlurhash", err)
+			}
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	got := h.RenderString(Image("/a.jpg", "A", Preview("data:image/gif;base64,R0lG"), h.Attrs("width", "80")))
+	expected := `<div class="lqip" style="background-image:url(&#39;data:image/gif;base64,R0lG&#39;);background-size:cover;background-position:center">` +
+		`<img src="/a.jpg" alt="A" loading="lazy" decoding="async" style="opacity:0;transition:opacity 0.3s" onload="this.style.opacity = 1; this.parentElement.style.backgroundImage = &#34;none&#34;" width="80"/>` +
+		`<noscript><img src="/a.jpg" alt="A" loading="lazy" decoding="async" width="80"/></noscript></div>`
+	if got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestImageWithoutPlaceholder(t *testing.T) {
+	got := h.RenderString(Image("/a.jpg", "", Placeholder{}))
+	if !strings.HasPrefix(got, `<div class="lqip"><img`) {
+		t.Errorf("unexpected output: %s", got)
+	}
+}
+
+func TestPlaceholderStyleRejectsNonImageURI(t *testing.T) {
+	for _, uri := range []string{
+		"data:image/gif;base64,R0lG');background:url('https://evil.example/",
+		"data:text/html;base64,PGI+",
+		"https://example.com/a.jpg",
+		"data:image/svg+xml,<svg/>",
+	} {
+		if style := Preview(uri).Style(); style != "" {
+			t.Errorf("Preview(%q).Style() = %q, want empty", uri, style)
+		}
+	}
+	if style := PreviewBytes("image/svg+xml", []byte("<svg/>")).Style(); style == "" {
+		t.Error("PreviewBytes(image/svg+xml) has no style")
+	}
+}
@@ -0,0 +1,351 @@
+package mathml
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+type amKind int
+
+const (
+	amConst   amKind = iota // mi, mn, mo, or mtext leaf
+	amLeft                  // Opening bracket
+	amRight                 // Closing bracket
+	amUnary                 // Takes one argument: sqrt, hat, ...
+	amBinary                // Takes two arguments: frac, root, ...
+	amText                  // text(...) with a raw argument
+	amSpecial               // /, ^, or _
+)
+
+type amSymbol struct {
+	kind   amKind
+	tag    string // Leaf element for amConst
+	out    string // Rendered text, or the fence for brackets
+	limits bool   // Big operator with limits in display mode
+}
+
+// amSymbols is the AsciiMath symbol table. Names are matched longest
+// first; single letters not listed here are identifiers.
+var amSymbols = map[string]amSymbol{
+	// Operators and relations.
+	"+": {tag: "mo", out: "+"}, "-": {tag: "mo", out: "−"},
+	"*": {tag: "mo", out: "⋅"}, "**": {tag: "mo", out: "∗"},
+	"***": {tag: "mo", out: "⋆"}, "//": {tag: "mo", out: "/"},
+	"xx": {tag: "mo", out: "×"}, "-:": {tag: "mo", out: "÷"},
+	"@": {tag: "mo", out: "∘"}, "o+": {tag: "mo", out: "⊕"},
+	"ox": {tag: "mo", out: "⊗"}, "+-": {tag: "mo", out: "±"},
+	"=": {tag: "mo", out: "="}, "!=": {tag: "mo", out: "≠"},
+	"<": {tag: "mo", out: "<"}, ">": {tag: "mo", out: ">"},
+	"<=": {tag: "mo", out: "≤"}, ">=": {tag: "mo", out: "≥"},
+	"-=": {tag: "mo", out: "≡"}, "~=": {tag: "mo", out: "≅"},
+	"~~": {tag: "mo", out: "≈"}, "prop": {tag: "mo", out: "∝"},
+	"in": {tag: "mo", out: "∈"}, "!in": {tag: "mo", out: "∉"},
+	"sub": {tag: "mo", out: "⊂"}, "sup": {tag: "mo", out: "⊃"},
+	"sube": {tag: "mo", out: "⊆"}, "supe": {tag: "mo", out: "⊇"},
+	"nn": {tag: "mo", out: "∩"}, "uu": {tag: "mo", out: "∪"},
+	"^^": {tag: "mo", out: "∧"}, "vv": {tag: "mo", out: "∨"},
+	"not": {tag: "mo", out: "¬"}, "AA": {tag: "mo", out: "∀"},
+	"EE": {tag: "mo", out: "∃"}, "->": {tag: "mo", out: "→"},
+	"<-": {tag: "mo", out: "←"}, "=>": {tag: "mo", out: "⇒"},
+	"<=>": {tag: "mo", out: "⇔"}, "|->": {tag: "mo", out: "↦"},
+	",": {tag: "mo", out: ","}, ";": {tag: "mo", out: ";"},
+	":": {tag: "mo", out: ":"}, "!": {tag: "mo", out: "!"},
+	"'": {tag: "mo", out: "′"}, "|": {tag: "mo", out: "|"},
+	"...": {tag: "mo", out: "…"}, "cdots": {tag: "mo", out: "⋯"},
+	"int": {tag: "mo", out: "∫"}, "oint": {tag: "mo", out: "∮"},
+	"sum":  {tag: "mo", out: "∑", limits: true},
+	"prod": {tag: "mo", out: "∏", limits: true},
+
+	// Identifiers.
+	"oo": {tag: "mi", out: "∞"}, "del": {tag: "mi", out: "∂"},
+	"grad": {tag: "mi", out: "∇"}, "O/": {tag: "mi", out: "∅"},
+	"RR": {tag: "mi", out: "ℝ"}, "NN": {tag: "mi", out: "ℕ"},
+	"ZZ": {tag: "mi", out: "ℤ"}, "QQ": {tag: "mi", out: "ℚ"},
+	"CC": {tag: "mi", out: "ℂ"}, "dx": {tag: "mi", out: "dx"},
+	"dy": {tag: "mi", out: "dy"}, "dt": {tag: "mi", out: "dt"},
+	"sin": {tag: "mi", out: "sin"}, "cos": {tag: "mi", out: "cos"},
+	"tan": {tag: "mi", out: "tan"}, "log": {tag: "mi", out: "log"},
+	"ln": {tag: "mi", out: "ln"}, "exp": {tag: "mi", out: "exp"},
+	"det": {tag: "mi", out: "det"},
+	"lim": {tag: "mi", out: "lim", limits: true},
+	"min": {tag: "mi", out: "min", limits: true},
+	"max": {tag: "mi", out: "max", limits: true},
+
+	// Brackets. (: :) and {: :} are angle and invisible brackets.
+	"(": {kind: amLeft, out: "("}, ")": {kind: amRight, out: ")"},
+	"[": {kind: amLeft, out: "["}, "]": {kind: amRight, out: "]"},
+	"{": {kind: amLeft, out: "{"}, "}": {kind: amRight, out: "}"},
+	"(:": {kind: amLeft, out: "⟨"}, ":)": {kind: amRight, out: "⟩"},
+	"{:": {kind: amLeft}, ":}": {kind: amRight},
+
+	// Commands.
+	"sqrt": {kind: amUnary}, "hat": {kind: amUnary, out: "^"},
+	"bar": {kind: amUnary, out: "¯"}, "vec": {kind: amUnary, out: "→"},
+	"dot": {kind: amUnary, out: "˙"}, "ddot": {kind: amUnary, out: "¨"},
+	"tilde": {kind: amUnary, out: "~"}, "ul": {kind: amUnary, out: "_"},
+	"abs": {kind: amUnary, out: "|"}, "norm": {kind: amUnary, out: "‖"},
+	"floor": {kind: amUnary, out: "⌊"}, "ceil": {kind: amUnary, out: "⌈"},
+	"bb": {kind: amUnary}, "bbb": {kind: amUnary}, "cc": {kind: amUnary},
+	"tt": {kind: amUnary}, "sf": {kind: amUnary}, "fr": {kind: amUnary},
+	"frac": {kind: amBinary}, "root": {kind: amBinary},
+	"stackrel": {kind: amBinary}, "overset": {kind: amBinary},
+	"underset": {kind: amBinary},
+	"text":     {kind: amText}, "mbox": {kind: amText},
+
+	"/": {kind: amSpecial}, "^": {kind: amSpecial}, "_": {kind: amSpecial},
+}
+
+// amFonts maps font commands to alphabets understood by restyle.
+var amFonts = map[string]string{
+	"bb": "bold", "bbb": "double-struck", "cc": "script",
+	"tt": "monospace", "sf": "sans-serif", "fr": "fraktur",
+}
+
+// amClosers are the closing marks of abs, norm, floor, and ceil.
+var amClosers = map[string]string{"abs": "|", "norm": "‖", "floor": "⌋", "ceil": "⌉"}
+
+// amNames lists amSymbols keys longest first for greedy matching.
+var amNames = func() []string {
+	names := make([]string, 0, len(amSymbols)+len(greek))
+	for name := range amSymbols {
+		names = append(names, name)
+	}
+	for name := range greek {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), strings.Compare(a, b))
+	})
+	return names
+}()
+
+func convertAsciiMath(src string, display bool) (h.Builder, error) {
+	p := &amParser{src: src, display: display}
+	kids, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unmatched " + p.next().name)
+	}
+	return wrap(row(kids), src, EncodingAsciiMath, display), nil
+}
+
+// amParser parses AsciiMath's grammar:
+//
+//	E ::= I E | I / I E
+//	I ::= S | S _ S | S ^ S | S _ S ^ S
+//	S ::= constant | left E right | unary S | binary S S | "text"
+type amParser struct {
+	src     string
+	pos     int
+	display bool
+}
+
+type amToken struct {
+	name string
+	amSymbol
+}
+
+func (p *amParser) errorf(msg string) error {
+	return &SyntaxError{Src: p.src, Pos: p.pos, Msg: msg}
+}
+
+// next returns the next token without consuming it. The zero token marks
+// the end of input.
+func (p *amParser) next() amToken {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return amToken{}
+	}
+	rest := p.src[p.pos:]
+	if rest[0] == '"' {
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			end = len(rest)
+		} else {
+			end += 2
+		}
+		return amToken{rest[:end], amSymbol{tag: "mtext", out: strings.Trim(rest[:end], `"`)}}
+	}
+	if isDigit(rest[0]) || (rest[0] == '.' && len(rest) > 1 && isDigit(rest[1])) {
+		end := 1
+		for end < len(rest) && (isDigit(rest[end]) || (rest[end] == '.' && end+1 < len(rest) && isDigit(rest[end+1]))) {
+			end++
+		}
+		return amToken{rest[:end], amSymbol{tag: "mn", out: rest[:end]}}
+	}
+	for _, name := range amNames {
+		if !strings.HasPrefix(rest, name) {
+			continue
+		}
+		if sym, ok := amSymbols[name]; ok {
+			return amToken{name, sym}
+		}
+		n, _ := greekIdent(name)
+		return amToken{name, amSymbol{tag: "mi", out: n.text}}
+	}
+	r, size := utf8.DecodeRuneInString(rest)
+	tag := "mo"
+	if unicode.IsLetter(r) {
+		tag = "mi"
+	}
+	return amToken{rest[:size], amSymbol{tag: tag, out: rest[:size]}}
+}
+
+func (p *amParser) consume(t amToken) { p.pos += len(t.name) }
+
+// parseExpr parses a sequence of intermediate expressions up to a closing
+// bracket or the end of input.
+func (p *amParser) parseExpr() ([]*node, error) {
+	var kids []*node
+	for {
+		t := p.next()
+		if t.name == "" || t.kind == amRight {
+			return kids, nil
+		}
+		n, err := p.parseIntermediate()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind == amSpecial && t.name == "/" {
+			p.consume(t)
+			den, err := p.parseIntermediate()
+			if err != nil {
+				return nil, err
+			}
+			n = elem("mfrac", bare(n), bare(den))
+		}
+		kids = append(kids, n)
+	}
+}
+
+func (p *amParser) parseIntermediate() (*node, error) {
+	base, err := p.parseSimple()
+	if err != nil {
+		return nil, err
+	}
+	var sub, sup *node
+	for {
+		t := p.next()
+		if t.kind != amSpecial || t.name == "/" {
+			break
+		}
+		p.consume(t)
+		arg, err := p.parseSimple()
+		if err != nil {
+			return nil, err
+		}
+		if t.name == "_" && sub == nil {
+			sub = bare(arg)
+		} else if t.name == "^" && sup == nil {
+			sup = bare(arg)
+		} else {
+			return nil, p.errorf("double " + t.name)
+		}
+	}
+	return script(base, sub, sup, base.limits && p.display), nil
+}
+
+func (p *amParser) parseSimple() (*node, error) {
+	t := p.next()
+	if t.name == "" {
+		return nil, p.errorf("missing argument")
+	}
+	p.consume(t)
+	switch t.kind {
+	case amLeft:
+		kids, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		closing := p.next()
+		if closing.kind != amRight {
+			return nil, p.errorf("unclosed " + t.name)
+		}
+		p.consume(closing)
+		n := fenced(t.out, closing.out, kids)
+		n.bare = row(kids)
+		if len(kids) == 0 {
+			n.bare = elem("mrow")
+		}
+		return n, nil
+	case amRight:
+		return nil, p.errorf("unmatched " + t.name)
+	case amUnary:
+		arg, err := p.parseSimple()
+		if err != nil {
+			return nil, err
+		}
+		arg = bare(arg)
+		if style, ok := amFonts[t.name]; ok {
+			restyle(arg, style)
+			return arg, nil
+		}
+		switch t.name {
+		case "sqrt":
+			return elem("msqrt", arg), nil
+		case "ul":
+			return &node{tag: "munder", attrs: []string{"accentunder", "true"}, kids: []*node{arg, leaf("mo", t.out)}}, nil
+		}
+		if closing, ok := amClosers[t.name]; ok {
+			return fenced(t.out, closing, []*node{arg}), nil
+		}
+		return &node{tag: "mover", attrs: []string{"accent", "true"}, kids: []*node{arg, leaf("mo", t.out)}}, nil
+	case amBinary:
+		a, err := p.parseSimple()
+		if err != nil {
+			return nil, err
+		}
+		b, err := p.parseSimple()
+		if err != nil {
+			return nil, err
+		}
+		a, b = bare(a), bare(b)
+		switch t.name {
+		case "frac":
+			return elem("mfrac", a, b), nil
+		case "root":
+			return elem("mroot", b, a), nil
+		case "underset":
+			return elem("munder", b, a), nil
+		}
+		return elem("mover", b, a), nil
+	case amText:
+		open := p.next()
+		if open.kind != amLeft {
+			return nil, p.errorf("expected ( after " + t.name)
+		}
+		end := strings.IndexAny(p.src[p.pos+len(open.name):], ")]}")
+		if end < 0 {
+			return nil, p.errorf("unclosed " + open.name)
+		}
+		start := p.pos + len(open.name)
+		p.pos = start + end + 1
+		return leaf("mtext", p.src[start:start+end]), nil
+	case amSpecial:
+		return leaf("mo", t.name), nil
+	}
+	n := leaf(t.tag, t.out)
+	if _, ok := greek[t.name]; ok {
+		n, _ = greekIdent(t.name)
+	}
+	n.limits = t.limits
+	return n, nil
+}
+
+// bare returns n without its brackets, as AsciiMath drops them around
+// fractions, scripts, and command arguments: (a+b)/2.
+func bare(n *node) *node {
+	if n.bare != nil {
+		return n.bare
+	}
+	return n
+}
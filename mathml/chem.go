@@ -0,0 +1,215 @@
+package mathml
+
+import (
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// chemArrows are reaction arrows, longest first so "<=>" wins over "<-".
+var chemArrows = []struct{ src, arrow string }{
+	{"<=>", "⇌"},
+	{"<->", "↔"},
+	{"->", "→"},
+	{"<-", "←"},
+}
+
+func convertChem(src string, display bool) (h.Builder, error) {
+	n, err := parseChem(src)
+	if err != nil {
+		return nil, err
+	}
+	return wrap(n, `\ce{`+src+`}`, EncodingTeX, display), nil
+}
+
+// parseChem converts the common subset of mhchem notation: formulas with
+// subscript counts (H2SO4), charges (Fe^{3+}, SO4^2-, Na+), coefficients
+// (2H2O), states ((aq)), hydrate dots (CuSO4*5H2O), and reaction arrows with
+// optional conditions (->[heat]).
+func parseChem(src string) (*node, error) {
+	var (
+		kids   []*node
+		groups []int // Indexes in kids of unclosed ( and [
+		pos    int
+		// word is true at the start of a term, where digits are
+		// coefficients and + and - are operators rather than charges.
+		word = true
+	)
+	errorf := func(msg string) error {
+		return &SyntaxError{Src: src, Pos: pos, Msg: msg}
+	}
+	// attach adds a sub- or superscript to the last node, merging a
+	// superscript into an existing subscript (SO4^2-).
+	attach := func(sub, sup *node) error {
+		if len(kids) == 0 {
+			return errorf("script without a formula")
+		}
+		last := kids[len(kids)-1]
+		switch {
+		case sup != nil && last.tag == "msub":
+			last = elem("msubsup", last.kids[0], last.kids[1], sup)
+		case sub != nil:
+			last = elem("msub", last, sub)
+		default:
+			last = elem("msup", last, sup)
+		}
+		kids[len(kids)-1] = last
+		return nil
+	}
+	digits := func() string {
+		start := pos
+		for pos < len(src) && isDigit(src[pos]) {
+			pos++
+		}
+		return src[start:pos]
+	}
+	// charge reads a superscript after ^: a {group}, or digits with an
+	// optional sign.
+	charge := func() (string, error) {
+		if pos < len(src) && src[pos] == '{' {
+			end := strings.IndexByte(src[pos:], '}')
+			if end < 0 {
+				return "", errorf("unclosed {")
+			}
+			s := src[pos+1 : pos+end]
+			pos += end + 1
+			return s, nil
+		}
+		s := digits()
+		if pos < len(src) && (src[pos] == '+' || src[pos] == '-') {
+			pos++
+			s = src[pos-len(s)-1 : pos]
+		}
+		if s == "" {
+			return "", errorf("missing charge")
+		}
+		return s, nil
+	}
+
+Loop:
+	for pos < len(src) {
+		c := src[pos]
+		if c == ' ' || c == '\t' || c == '\n' {
+			pos++
+			word = true
+			continue
+		}
+		for _, a := range chemArrows {
+			if !strings.HasPrefix(src[pos:], a.src) {
+				continue
+			}
+			pos += len(a.src)
+			arrow := leaf("mo", a.arrow, "stretchy", "true")
+			if pos < len(src) && src[pos] == '[' {
+				end := strings.IndexByte(src[pos:], ']')
+				if end < 0 {
+					return nil, errorf("unclosed [")
+				}
+				arrow = elem("mover", arrow, leaf("mtext", src[pos+1:pos+end]))
+				pos += end + 1
+			}
+			kids = append(kids, arrow)
+			word = true
+			continue Loop
+		}
+		switch {
+		case isDigit(c) && word:
+			kids = append(kids, leaf("mn", digits()))
+			word = false
+		case isDigit(c):
+			if err := attach(leaf("mn", digits()), nil); err != nil {
+				return nil, err
+			}
+		case c >= 'A' && c <= 'Z':
+			end := pos + 1
+			if end < len(src) && src[end] >= 'a' && src[end] <= 'z' {
+				end++
+			}
+			kids = append(kids, upright(src[pos:end]))
+			pos = end
+			word = false
+		case c >= 'a' && c <= 'z':
+			end := pos + 1
+			for end < len(src) && src[end] >= 'a' && src[end] <= 'z' {
+				end++
+			}
+			kids = append(kids, upright(src[pos:end]))
+			pos = end
+			word = false
+		case c == '(' || c == '[':
+			groups = append(groups, len(kids))
+			kids = append(kids, leaf("mo", string(c)))
+			pos++
+			word = true
+		case c == ')' || c == ']':
+			if len(groups) == 0 {
+				return nil, errorf("unmatched " + string(c))
+			}
+			start := groups[len(groups)-1]
+			groups = groups[:len(groups)-1]
+			group := elem("mrow", append(kids[start:len(kids):len(kids)], leaf("mo", string(c)))...)
+			kids = append(kids[:start], group)
+			pos++
+		case c == '^' || c == '_':
+			pos++
+			s, err := charge()
+			if err != nil {
+				return nil, err
+			}
+			n := chemScript(s)
+			if c == '_' {
+				err = attach(n, nil)
+			} else {
+				err = attach(nil, n)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case (c == '+' || c == '-') && !word:
+			pos++
+			if err := attach(nil, chemScript(string(c))); err != nil {
+				return nil, err
+			}
+		case c == '+' || c == '-' || c == '=':
+			kids = append(kids, chemScript(string(c)))
+			pos++
+		case c == '*' || c == '.':
+			kids = append(kids, leaf("mo", "·"))
+			pos++
+			word = true
+		default:
+			return nil, errorf("unexpected " + string(c))
+		}
+	}
+	if len(groups) > 0 {
+		return nil, errorf("unclosed " + kids[groups[len(groups)-1]].text)
+	}
+	return row(kids), nil
+}
+
+// chemScript converts script or operator text such as "2-" or "3+" into
+// numbers, signs (with a true minus), and identifiers.
+func chemScript(s string) *node {
+	var kids []*node
+	for i := 0; i < len(s); {
+		j := i + 1
+		switch c := s[i]; {
+		case isDigit(c):
+			for j < len(s) && isDigit(s[j]) {
+				j++
+			}
+			kids = append(kids, leaf("mn", s[i:j]))
+		case c == '-':
+			kids = append(kids, leaf("mo", "−"))
+		case isLetter(c):
+			for j < len(s) && isLetter(s[j]) {
+				j++
+			}
+			kids = append(kids, upright(s[i:j]))
+		default:
+			kids = append(kids, leaf("mo", s[i:j]))
+		}
+		i = j
+	}
+	return row(kids)
+}
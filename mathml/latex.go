@@ -0,0 +1,611 @@
+package mathml
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// texOps are commands rendered as <mo>.
+var texOps = map[string]string{
+	"times": "×", "cdot": "⋅", "pm": "±", "mp": "∓", "div": "÷", "ast": "∗",
+	"star": "⋆", "circ": "∘", "bullet": "∙", "oplus": "⊕", "otimes": "⊗",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠",
+	"approx": "≈", "equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅",
+	"propto": "∝", "ll": "≪", "gg": "≫", "perp": "⊥", "parallel": "∥", "mid": "∣",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "gets": "←",
+	"leftrightarrow": "↔", "Rightarrow": "⇒", "Leftarrow": "⇐",
+	"Leftrightarrow": "⇔", "implies": "⟹", "iff": "⟺", "mapsto": "↦",
+	"rightleftharpoons": "⇌", "uparrow": "↑", "downarrow": "↓",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "supset": "⊃",
+	"subseteq": "⊆", "supseteq": "⊇", "cup": "∪", "cap": "∩", "setminus": "∖",
+	"forall": "∀", "exists": "∃", "neg": "¬", "lnot": "¬", "land": "∧",
+	"wedge": "∧", "lor": "∨", "vee": "∨", "therefore": "∴", "because": "∵",
+	"cdots": "⋯", "ldots": "…", "dots": "…", "vdots": "⋮", "ddots": "⋱",
+	"langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋",
+	"lceil": "⌈", "rceil": "⌉", "lvert": "|", "rvert": "|", "vert": "|",
+	"lVert": "‖", "rVert": "‖", "Vert": "‖", "prime": "′",
+	"int": "∫", "iint": "∬", "iiint": "∭", "oint": "∮",
+}
+
+// texLimitOps are big operators whose scripts become limits in display mode.
+var texLimitOps = map[string]string{
+	"sum": "∑", "prod": "∏", "coprod": "∐", "bigcup": "⋃", "bigcap": "⋂",
+	"bigoplus": "⨁", "bigotimes": "⨂", "bigvee": "⋁", "bigwedge": "⋀",
+}
+
+// texIdents are commands rendered as <mi>.
+var texIdents = map[string]string{
+	"infty": "∞", "partial": "∂", "nabla": "∇", "emptyset": "∅",
+	"varnothing": "∅", "hbar": "ℏ", "ell": "ℓ", "aleph": "ℵ", "angle": "∠",
+	"triangle": "△", "Re": "ℜ", "Im": "ℑ",
+}
+
+// texFuncs are upright function names. The bool reports whether the
+// function takes limits in display mode.
+var texFuncs = map[string]bool{
+	"sin": false, "cos": false, "tan": false, "cot": false, "sec": false,
+	"csc": false, "arcsin": false, "arccos": false, "arctan": false,
+	"sinh": false, "cosh": false, "tanh": false, "log": false, "ln": false,
+	"lg": false, "exp": false, "deg": false, "dim": false, "ker": false,
+	"arg": false, "hom": false, "det": true, "gcd": true, "Pr": true,
+	"lim": true, "liminf": true, "limsup": true, "max": true, "min": true,
+	"sup": true, "inf": true,
+}
+
+// texAccents are commands that place a mark over (or under) their argument.
+var texAccents = map[string]struct {
+	mark  string
+	under bool
+}{
+	"hat": {"^", false}, "widehat": {"^", false}, "bar": {"¯", false},
+	"overline": {"‾", false}, "vec": {"→", false}, "dot": {"˙", false},
+	"ddot": {"¨", false}, "tilde": {"~", false}, "widetilde": {"~", false},
+	"overrightarrow": {"→", false}, "overbrace": {"⏞", false},
+	"underline": {"_", true}, "underbrace": {"⏟", true},
+}
+
+// texFonts maps font commands to alphabets understood by restyle.
+var texFonts = map[string]string{
+	"mathbf": "bold", "boldsymbol": "bold", "mathbb": "double-struck",
+	"mathcal": "script", "mathscr": "script", "mathfrak": "fraktur",
+	"mathsf": "sans-serif", "mathtt": "monospace", "mathrm": "normal",
+}
+
+// texSpaces are explicit spacing commands and their widths.
+var texSpaces = map[string]string{
+	",": "0.1667em", ":": "0.2222em", ">": "0.2222em", ";": "0.2778em",
+	"quad": "1em", "qquad": "2em",
+}
+
+// texBig are sizing prefixes for delimiters such as \big( and \Bigr].
+var texBig = map[string]string{
+	"big": "1.2em", "bigl": "1.2em", "bigr": "1.2em",
+	"Big": "1.8em", "Bigl": "1.8em", "Bigr": "1.8em",
+	"bigg": "2.4em", "biggl": "2.4em", "biggr": "2.4em",
+	"Bigg": "3em", "Biggl": "3em", "Biggr": "3em",
+}
+
+// texEnvs are the supported \begin environments and their fences.
+var texEnvs = map[string]struct{ open, close, align string }{
+	"matrix":   {"", "", ""},
+	"pmatrix":  {"(", ")", ""},
+	"bmatrix":  {"[", "]", ""},
+	"Bmatrix":  {"{", "}", ""},
+	"vmatrix":  {"|", "|", ""},
+	"Vmatrix":  {"‖", "‖", ""},
+	"cases":    {"{", "", "left left"},
+	"aligned":  {"", "", "right left"},
+	"align*":   {"", "", "right left"},
+	"gathered": {"", "", ""},
+}
+
+func convertLaTeX(src string, display bool) (h.Builder, error) {
+	p := &texParser{src: src, display: display}
+	kids, err := p.parseRow()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected " + p.peekToken())
+	}
+	return wrap(row(kids), src, EncodingTeX, display), nil
+}
+
+// texParser is a recursive descent parser for the math-mode subset of LaTeX.
+type texParser struct {
+	src     string
+	pos     int
+	display bool
+	// optional counts enclosing [...] arguments, in which ']' ends a row.
+	optional int
+}
+
+func (p *texParser) errorf(msg string) error {
+	return &SyntaxError{Src: p.src, Pos: p.pos, Msg: msg}
+}
+
+func (p *texParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case c == '%':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// peekToken returns the next token for error messages and row ends.
+func (p *texParser) peekToken() string {
+	if p.pos >= len(p.src) {
+		return "end of input"
+	}
+	if p.src[p.pos] != '\\' {
+		_, n := utf8.DecodeRuneInString(p.src[p.pos:])
+		return p.src[p.pos : p.pos+n]
+	}
+	end := p.pos + 1
+	for end < len(p.src) && isLetter(p.src[end]) {
+		end++
+	}
+	if end == p.pos+1 && end < len(p.src) {
+		end++
+	}
+	return p.src[p.pos:end]
+}
+
+// atRowEnd reports whether the next token ends the current row: a closing
+// brace, a table separator, \right, \end, or ']' inside an optional argument.
+func (p *texParser) atRowEnd() bool {
+	if p.pos >= len(p.src) {
+		return true
+	}
+	switch p.src[p.pos] {
+	case '}', '&':
+		return true
+	case ']':
+		return p.optional > 0
+	case '\\':
+		switch p.peekToken() {
+		case `\\`, `\right`, `\end`:
+			return true
+		}
+	}
+	return false
+}
+
+func (p *texParser) parseRow() ([]*node, error) {
+	var kids []*node
+	for {
+		p.skipSpace()
+		if p.atRowEnd() {
+			return kids, nil
+		}
+		if c := p.src[p.pos]; c == '^' || c == '_' {
+			var err error
+			if kids, err = p.parseScripts(kids); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		n, err := p.parseAtom(false)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			kids = append(kids, n)
+		}
+	}
+}
+
+// parseScripts attaches a ^ and/or _ to the last node of kids.
+func (p *texParser) parseScripts(kids []*node) ([]*node, error) {
+	base := elem("mrow")
+	if len(kids) > 0 {
+		base = kids[len(kids)-1]
+		kids = kids[:len(kids)-1]
+	}
+	var sub, sup *node
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) || (p.src[p.pos] != '^' && p.src[p.pos] != '_') {
+			break
+		}
+		c := p.src[p.pos]
+		p.pos++
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		if c == '^' {
+			if sup != nil {
+				return nil, p.errorf("double superscript")
+			}
+			sup = arg
+		} else {
+			if sub != nil {
+				return nil, p.errorf("double subscript")
+			}
+			sub = arg
+		}
+	}
+	return append(kids, script(base, sub, sup, base.limits && p.display)), nil
+}
+
+// parseArg parses a command or script argument: a group or a single token.
+func (p *texParser) parseArg() (*node, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("missing argument")
+	}
+	n, err := p.parseAtom(true)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return elem("mrow"), nil
+	}
+	return n, nil
+}
+
+// parseGroup parses a {...} group.
+func (p *texParser) parseGroup() (*node, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	kids, err := p.parseRow()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("}"); err != nil {
+		return nil, err
+	}
+	if len(kids) == 0 {
+		return elem("mrow"), nil
+	}
+	return row(kids), nil
+}
+
+func (p *texParser) expect(tok string) error {
+	p.skipSpace()
+	if !strings.HasPrefix(p.src[p.pos:], tok) {
+		return p.errorf("expected " + tok + ", found " + p.peekToken())
+	}
+	p.pos += len(tok)
+	return nil
+}
+
+// rawGroup returns the text of a {...} group without parsing it.
+func (p *texParser) rawGroup() (string, error) {
+	if err := p.expect("{"); err != nil {
+		return "", err
+	}
+	start, depth := p.pos, 1
+	for ; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				p.pos++
+				return p.src[start : p.pos-1], nil
+			}
+		}
+	}
+	return "", p.errorf("unclosed {")
+}
+
+// parseAtom parses one token. single limits numbers to one digit, as TeX
+// does for script arguments like x^23.
+func (p *texParser) parseAtom(single bool) (*node, error) {
+	c := p.src[p.pos]
+	switch {
+	case c == '{':
+		return p.parseGroup()
+	case c == '\\':
+		return p.parseCommand()
+	case isDigit(c) || (c == '.' && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1])):
+		start := p.pos
+		p.pos++
+		for !single && p.pos < len(p.src) && (isDigit(p.src[p.pos]) || (p.src[p.pos] == '.' && p.pos+1 < len(p.src) && isDigit(p.src[p.pos+1]))) {
+			p.pos++
+		}
+		return leaf("mn", p.src[start:p.pos]), nil
+	case isLetter(c):
+		p.pos++
+		return leaf("mi", string(c)), nil
+	case c == '~':
+		p.pos++
+		return leaf("mtext", " "), nil
+	case c == '\'':
+		p.pos++
+		return leaf("mo", "′"), nil
+	case c == '-':
+		p.pos++
+		return leaf("mo", "−"), nil
+	case c == '*':
+		p.pos++
+		return leaf("mo", "∗"), nil
+	case c == '}' || c == ']' || c == '&' || c == '$' || c == '#' || c == '^' || c == '_':
+		return nil, p.errorf("unexpected " + string(c))
+	case c < utf8.RuneSelf:
+		p.pos++
+		return leaf("mo", string(c)), nil
+	}
+	r, n := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += n
+	if unicode.IsLetter(r) {
+		return leaf("mi", string(r)), nil
+	}
+	return leaf("mo", string(r)), nil
+}
+
+func (p *texParser) parseCommand() (*node, error) {
+	start := p.pos
+	name := p.peekToken()[1:]
+	p.pos += 1 + len(name)
+	if s, ok := texOps[name]; ok {
+		return leaf("mo", s), nil
+	}
+	if s, ok := texLimitOps[name]; ok {
+		n := leaf("mo", s)
+		n.limits = true
+		return n, nil
+	}
+	if s, ok := texIdents[name]; ok {
+		return leaf("mi", s), nil
+	}
+	if n, ok := greekIdent(name); ok {
+		return n, nil
+	}
+	if limits, ok := texFuncs[name]; ok {
+		switch name {
+		case "liminf":
+			name = "lim inf"
+		case "limsup":
+			name = "lim sup"
+		}
+		n := leaf("mi", name)
+		n.limits = limits
+		return n, nil
+	}
+	if w, ok := texSpaces[name]; ok {
+		return leaf("mspace", "", "width", w), nil
+	}
+	if a, ok := texAccents[name]; ok {
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		mark := leaf("mo", a.mark)
+		if a.under {
+			return &node{tag: "munder", attrs: []string{"accentunder", "true"}, kids: []*node{arg, mark}}, nil
+		}
+		return &node{tag: "mover", attrs: []string{"accent", "true"}, kids: []*node{arg, mark}}, nil
+	}
+	if style, ok := texFonts[name]; ok {
+		arg, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		restyle(arg, style)
+		return arg, nil
+	}
+	if size, ok := texBig[name]; ok {
+		d, err := p.parseDelimiter()
+		if err != nil {
+			return nil, err
+		}
+		return leaf("mo", d, "minsize", size, "maxsize", size), nil
+	}
+	switch name {
+	case "{", "}", "|", "%", "$", "&", "#", "_":
+		if name == "|" {
+			name = "‖"
+		}
+		return leaf("mo", name), nil
+	case " ":
+		return leaf("mtext", " "), nil
+	case "!":
+		return nil, nil
+	case "frac", "dfrac", "tfrac", "cfrac":
+		num, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		den, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		return elem("mfrac", num, den), nil
+	case "binom":
+		n, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		k, err := p.parseArg()
+		if err != nil {
+			return nil, err
+		}
+		frac := &node{tag: "mfrac", attrs: []string{"linethickness", "0"}, kids: []*node{n, k}}
+		return elem("mrow", leaf("mo", "("), frac, leaf("mo", ")")), nil
+	case "sqrt":
+		return p.parseSqrt()
+	case "text", "textrm", "textit", "textbf", "mbox":
+		s, err := p.rawGroup()
+		if err != nil {
+			return nil, err
+		}
+		return leaf("mtext", s), nil
+	case "operatorname":
+		s, err := p.rawGroup()
+		if err != nil {
+			return nil, err
+		}
+		return upright(s), nil
+	case "left":
+		return p.parseLeftRight()
+	case "begin":
+		return p.parseEnv()
+	case "ce":
+		s, err := p.rawGroup()
+		if err != nil {
+			return nil, err
+		}
+		return parseChem(s)
+	}
+	p.pos = start
+	return nil, p.errorf(`unsupported command \` + name)
+}
+
+func (p *texParser) parseSqrt() (*node, error) {
+	p.skipSpace()
+	var index []*node
+	if p.pos < len(p.src) && p.src[p.pos] == '[' {
+		p.pos++
+		p.optional++
+		kids, err := p.parseRow()
+		p.optional--
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+		index = kids
+	}
+	arg, err := p.parseArg()
+	if err != nil {
+		return nil, err
+	}
+	if index != nil {
+		return elem("mroot", arg, row(index)), nil
+	}
+	return elem("msqrt", arg), nil
+}
+
+// parseDelimiter reads the delimiter after \left, \right, or \big. "." is
+// the empty delimiter.
+func (p *texParser) parseDelimiter() (string, error) {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return "", p.errorf("missing delimiter")
+	}
+	tok := p.peekToken()
+	p.pos += len(tok)
+	switch {
+	case tok == ".":
+		return "", nil
+	case tok == `\{` || tok == `\}`:
+		return tok[1:], nil
+	case tok == `\|`:
+		return "‖", nil
+	case strings.HasPrefix(tok, `\`):
+		if s, ok := texOps[tok[1:]]; ok {
+			return s, nil
+		}
+	case strings.ContainsAny(tok, "()[]|/") || tok == "⟨" || tok == "⟩":
+		return tok, nil
+	}
+	p.pos -= len(tok)
+	return "", p.errorf("invalid delimiter " + tok)
+}
+
+func (p *texParser) parseLeftRight() (*node, error) {
+	open, err := p.parseDelimiter()
+	if err != nil {
+		return nil, err
+	}
+	kids, err := p.parseRow()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(`\right`); err != nil {
+		return nil, err
+	}
+	closing, err := p.parseDelimiter()
+	if err != nil {
+		return nil, err
+	}
+	return fenced(open, closing, kids), nil
+}
+
+// fenced surrounds kids with stretchy delimiters; empty delimiters are
+// omitted.
+func fenced(open, closing string, kids []*node) *node {
+	out := make([]*node, 0, len(kids)+2)
+	if open != "" {
+		out = append(out, leaf("mo", open, "fence", "true", "stretchy", "true"))
+	}
+	out = append(out, kids...)
+	if closing != "" {
+		out = append(out, leaf("mo", closing, "fence", "true", "stretchy", "true"))
+	}
+	return elem("mrow", out...)
+}
+
+func (p *texParser) parseEnv() (*node, error) {
+	name, err := p.rawGroup()
+	if err != nil {
+		return nil, err
+	}
+	env, ok := texEnvs[name]
+	if !ok {
+		return nil, p.errorf("unsupported environment " + name)
+	}
+	table := elem("mtable")
+	if env.align != "" {
+		table.attrs = []string{"columnalign", env.align}
+	}
+	tr := elem("mtr")
+	for {
+		kids, err := p.parseRow()
+		if err != nil {
+			return nil, err
+		}
+		tr.kids = append(tr.kids, elem("mtd", kids...))
+		p.skipSpace()
+		switch tok := p.peekToken(); tok {
+		case "&":
+			p.pos++
+			continue
+		case `\\`:
+			p.pos += 2
+			table.kids = append(table.kids, tr)
+			tr = elem("mtr")
+			continue
+		case `\end`:
+			p.pos += len(tok)
+		default:
+			return nil, p.errorf("unexpected " + tok + " in " + name)
+		}
+		break
+	}
+	// A trailing \\ leaves an empty final row, which TeX drops.
+	if len(tr.kids) > 1 || len(tr.kids[0].kids) > 0 {
+		table.kids = append(table.kids, tr)
+	}
+	end, err := p.rawGroup()
+	if err != nil {
+		return nil, err
+	}
+	if end != name {
+		return nil, p.errorf(`\begin{` + name + `} ended by \end{` + end + `}`)
+	}
+	if env.open == "" && env.close == "" {
+		return table, nil
+	}
+	return fenced(env.open, env.close, []*node{table}), nil
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
@@ -0,0 +1,203 @@
+// Package mathml renders LaTeX, AsciiMath, and chemical equations as MathML
+// on the server, so scientific content displays in modern browsers without
+// loading MathJax or KaTeX:
+//
+//	h.P(
+//	    h.Text("The roots are "),
+//	    mathml.Inline(`x = \frac{-b \pm \sqrt{b^2 - 4ac}}{2a}`),
+//	)
+//	mathml.Block(`\ce{2H2 + O2 -> 2H2O}`)
+//
+// The built-in converters cover the commonly used subset of each notation.
+// Input they cannot convert is handed to the Renderer's Fallback, which by
+// default emits the source between MathJax delimiters so a client-side
+// renderer can still pick it up. Plug in a more complete converter by
+// implementing Converter.
+package mathml
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Annotation encodings attached to converted <math> elements.
+const (
+	EncodingTeX       = "application/x-tex"
+	EncodingAsciiMath = "text/x-asciimath"
+)
+
+// ErrSyntax is returned (wrapped in a *SyntaxError) when a converter cannot
+// parse its input.
+var ErrSyntax = errors.New("mathml: syntax error")
+
+// SyntaxError reports where a converter failed to parse its input.
+type SyntaxError struct {
+	Src string // Input being converted
+	Pos int    // Byte offset of the problem
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return "mathml: " + e.Msg + " at offset " + strconv.Itoa(e.Pos) + " in " + strconv.Quote(e.Src)
+}
+
+func (e *SyntaxError) Unwrap() error { return ErrSyntax }
+
+// Converter converts source notation into a complete <math> element.
+// display selects block layout (limits above and below big operators,
+// display="block") instead of inline.
+type Converter interface {
+	Convert(src string, display bool) (h.Builder, error)
+}
+
+// ConverterFunc adapts a function to the Converter interface.
+type ConverterFunc func(src string, display bool) (h.Builder, error)
+
+func (f ConverterFunc) Convert(src string, display bool) (h.Builder, error) { return f(src, display) }
+
+// Built-in converters. LaTeX also understands the mhchem \ce{...} command;
+// Chem takes the contents of \ce directly.
+var (
+	LaTeX     Converter = ConverterFunc(convertLaTeX)
+	AsciiMath Converter = ConverterFunc(convertAsciiMath)
+	Chem      Converter = ConverterFunc(convertChem)
+)
+
+// Renderer converts math with a chosen Converter and falls back to
+// client-side rendering for input it cannot handle. The zero value uses
+// LaTeX and ClientSide.
+type Renderer struct {
+	Converter Converter
+	Fallback  func(src string, display bool, err error) h.Builder
+}
+
+// Inline renders src as inline math.
+func (r Renderer) Inline(src string) h.Builder { return r.render(src, false) }
+
+// Block renders src as display math.
+func (r Renderer) Block(src string) h.Builder { return r.render(src, true) }
+
+func (r Renderer) render(src string, display bool) h.Builder {
+	conv := r.Converter
+	if conv == nil {
+		conv = LaTeX
+	}
+	b, err := conv.Convert(src, display)
+	if err == nil {
+		return b
+	}
+	fallback := r.Fallback
+	if fallback == nil {
+		fallback = ClientSide
+	}
+	return fallback(src, display, err)
+}
+
+// Inline renders LaTeX src as inline math, falling back to ClientSide.
+func Inline(src string) h.Builder { return Renderer{}.Inline(src) }
+
+// Block renders LaTeX src as display math, falling back to ClientSide.
+func Block(src string) h.Builder { return Renderer{}.Block(src) }
+
+// ClientSide is the default fallback. It renders src between \( \) or \[ \]
+// delimiters in an element with class "math-fallback", which MathJax and
+// KaTeX's auto-render typeset on the client.
+func ClientSide(src string, display bool, err error) h.Builder {
+	if display {
+		return h.Div(h.Class("math-fallback"), h.Text(`\[`+src+`\]`))
+	}
+	return h.Span(h.Class("math-fallback"), h.Text(`\(`+src+`\)`))
+}
+
+// wrap builds the <math> element around converted content, keeping the
+// source as an annotation for copy/paste and assistive technology.
+func wrap(content *node, src, encoding string, display bool) h.Builder {
+	math := &node{tag: "math", kids: []*node{{
+		tag: "semantics",
+		kids: []*node{
+			content.asRow(),
+			{tag: "annotation", attrs: []string{"encoding", encoding}, text: src},
+		},
+	}}}
+	if display {
+		math.attrs = []string{"display", "block"}
+	}
+	return math.builder()
+}
+
+// node is a MathML element under construction. Leaf tokens (mi, mn, mo,
+// mtext) carry text; everything else carries kids.
+type node struct {
+	tag   string
+	text  string
+	attrs []string
+	kids  []*node
+
+	// limits marks big operators (sum, lim, ...) whose scripts go above and
+	// below in display mode.
+	limits bool
+	// bare is the content of a bracketed AsciiMath group without its
+	// brackets, used when the group is an argument.
+	bare *node
+}
+
+func leaf(tag, text string, attrs ...string) *node {
+	return &node{tag: tag, text: text, attrs: attrs}
+}
+
+func elem(tag string, kids ...*node) *node { return &node{tag: tag, kids: kids} }
+
+// row groups nodes in an <mrow>, unwrapping a single node.
+func row(kids []*node) *node {
+	if len(kids) == 1 {
+		return kids[0]
+	}
+	return &node{tag: "mrow", kids: kids}
+}
+
+// asRow makes n suitable as the single child of <semantics>.
+func (n *node) asRow() *node {
+	if n.tag == "mrow" {
+		return n
+	}
+	return elem("mrow", n)
+}
+
+func (n *node) builder() h.Builder {
+	args := make([]h.TagArg, 0, len(n.kids)+2)
+	if len(n.attrs) > 0 {
+		args = append(args, h.Attrs(n.attrs...))
+	}
+	if n.text != "" {
+		args = append(args, h.Text(n.text))
+	}
+	for _, k := range n.kids {
+		args = append(args, k.builder())
+	}
+	return h.CustomElement(n.tag, args...)
+}
+
+// script attaches sub- and superscripts to base, using munder/mover when
+// limits is set so big operators stack their bounds in display mode.
+func script(base, sub, sup *node, limits bool) *node {
+	switch {
+	case sub != nil && sup != nil:
+		if limits {
+			return elem("munderover", base, sub, sup)
+		}
+		return elem("msubsup", base, sub, sup)
+	case sub != nil:
+		if limits {
+			return elem("munder", base, sub)
+		}
+		return elem("msub", base, sub)
+	case sup != nil:
+		if limits {
+			return elem("mover", base, sup)
+		}
+		return elem("msup", base, sup)
+	}
+	return base
+}
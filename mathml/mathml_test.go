@@ -0,0 +1,215 @@
+package mathml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func render(t *testing.T, b h.Builder) string {
+	t.Helper()
+	var sb strings.Builder
+	if err := h.Render(&sb, b); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+// content returns the converted MathML between <semantics> and the
+// source annotation.
+func content(t *testing.T, conv Converter, src string, display bool) string {
+	t.Helper()
+	b, err := conv.Convert(src, display)
+	if err != nil {
+		t.Fatalf("Convert(%q): %v", src, err)
+	}
+	out := render(t, b)
+	start := strings.Index(out, "<semantics>") + len("<semantics>")
+	end := strings.Index(out, "<annotation")
+	return out[start:end]
+}
+
+func TestLaTeX(t *testing.T) {
+	tests := []struct {
+		src      string
+		display  bool
+		expected string
+	}{
+		{`x`, false, `<mrow><mi>x</mi></mrow>`},
+		{`x+1.5`, false, `<mrow><mi>x</mi><mo>+</mo><mn>1.5</mn></mrow>`},
+		{`a-b`, false, `<mrow><mi>a</mi><mo>−</mo><mi>b</mi></mrow>`},
+		{`x^2`, false, `<mrow><msup><mi>x</mi><mn>2</mn></msup></mrow>`},
+		{`x^23`, false, `<mrow><msup><mi>x</mi><mn>2</mn></msup><mn>3</mn></mrow>`},
+		{`x_i^{n+1}`, false, `<mrow><msubsup><mi>x</mi><mi>i</mi><mrow><mi>n</mi><mo>+</mo><mn>1</mn></mrow></msubsup></mrow>`},
+		{`\frac{1}{2}`, false, `<mrow><mfrac><mn>1</mn><mn>2</mn></mfrac></mrow>`},
+		{`\sqrt{x}`, false, `<mrow><msqrt><mi>x</mi></msqrt></mrow>`},
+		{`\sqrt[3]{x}`, false, `<mrow><mroot><mi>x</mi><mn>3</mn></mroot></mrow>`},
+		{`\alpha\Omega`, false, `<mrow><mi>α</mi><mi mathvariant="normal">Ω</mi></mrow>`},
+		{`a \leq b`, false, `<mrow><mi>a</mi><mo>≤</mo><mi>b</mi></mrow>`},
+		{`\sin x`, false, `<mrow><mi>sin</mi><mi>x</mi></mrow>`},
+		{`\sum_{i=1}^n i`, false, `<mrow><msubsup><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></msubsup><mi>i</mi></mrow>`},
+		{`\sum_{i=1}^n i`, true, `<mrow><munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover><mi>i</mi></mrow>`},
+		{`\lim_{x \to 0}`, true, `<mrow><munder><mi>lim</mi><mrow><mi>x</mi><mo>→</mo><mn>0</mn></mrow></munder></mrow>`},
+		{`\int_0^1`, true, `<mrow><msubsup><mo>∫</mo><mn>0</mn><mn>1</mn></msubsup></mrow>`},
+		{`\left( x \right.`, false, `<mrow><mo fence="true" stretchy="true">(</mo><mi>x</mi></mrow>`},
+		{`\left\langle x \right\rangle`, false, `<mrow><mo fence="true" stretchy="true">⟨</mo><mi>x</mi><mo fence="true" stretchy="true">⟩</mo></mrow>`},
+		{`\big(`, false, `<mrow><mo minsize="1.2em" maxsize="1.2em">(</mo></mrow>`},
+		{`\hat{x}`, false, `<mrow><mover accent="true"><mi>x</mi><mo>^</mo></mover></mrow>`},
+		{`\underline{x}`, false, `<mrow><munder accentunder="true"><mi>x</mi><mo>_</mo></munder></mrow>`},
+		{`\mathbb{R}\mathbf{v}`, false, `<mrow><mi>ℝ</mi><mi>𝐯</mi></mrow>`},
+		{`\mathrm{d}x`, false, `<mrow><mi mathvariant="normal">d</mi><mi>x</mi></mrow>`},
+		{`\text{if } x`, false, `<mrow><mtext>if </mtext><mi>x</mi></mrow>`},
+		{`\operatorname{sgn}`, false, `<mrow><mi>sgn</mi></mrow>`},
+		{`\binom{n}{k}`, false, `<mrow><mo>(</mo><mfrac linethickness="0"><mi>n</mi><mi>k</mi></mfrac><mo>)</mo></mrow>`},
+		{`a\,b\quad c`, false, `<mrow><mi>a</mi><mspace width="0.1667em"></mspace><mi>b</mi><mspace width="1em"></mspace><mi>c</mi></mrow>`},
+		{`f'(x) % derivative`, false, `<mrow><mi>f</mi><mo>′</mo><mo>(</mo><mi>x</mi><mo>)</mo></mrow>`},
+		{`\begin{matrix} a & b \\ c & d \\ \end{matrix}`, false, `<mrow><mtable><mtr><mtd><mi>a</mi></mtd><mtd><mi>b</mi></mtd></mtr><mtr><mtd><mi>c</mi></mtd><mtd><mi>d</mi></mtd></mtr></mtable></mrow>`},
+		{`\begin{cases} 1 & x > 0 \end{cases}`, false, `<mrow><mo fence="true" stretchy="true">{</mo><mtable columnalign="left left"><mtr><mtd><mn>1</mn></mtd><mtd><mi>x</mi><mo>&gt;</mo><mn>0</mn></mtd></mtr></mtable></mrow>`},
+		{`\ce{H2O}`, false, `<mrow><msub><mi mathvariant="normal">H</mi><mn>2</mn></msub><mi mathvariant="normal">O</mi></mrow>`},
+	}
+	for _, tt := range tests {
+		if got := content(t, LaTeX, tt.src, tt.display); got != tt.expected {
+			t.Errorf("LaTeX %q (display=%v):\n got: %s\nwant: %s", tt.src, tt.display, got, tt.expected)
+		}
+	}
+}
+
+func TestLaTeXErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+	}{
+		{`\unknown`, 0},
+		{`\frac{1}`, 8},
+		{`x^`, 2},
+		{`x^1^2`, 5},
+		{`{x`, 2},
+		{`x}`, 1},
+		{`\left( x`, 8},
+		{`\begin{matrix} a \end{pmatrix}`, 30},
+		{`\begin{tabular}`, 15},
+	}
+	for _, tt := range tests {
+		_, err := LaTeX.Convert(tt.src, false)
+		var se *SyntaxError
+		if !errors.As(err, &se) || !errors.Is(err, ErrSyntax) {
+			t.Errorf("LaTeX %q: expected a *SyntaxError, got %v", tt.src, err)
+			continue
+		}
+		if se.Pos != tt.pos {
+			t.Errorf("LaTeX %q: error %q at %d, want %d", tt.src, se.Msg, se.Pos, tt.pos)
+		}
+	}
+}
+
+func TestAsciiMath(t *testing.T) {
+	tests := []struct {
+		src      string
+		display  bool
+		expected string
+	}{
+		{`x^2`, false, `<mrow><msup><mi>x</mi><mn>2</mn></msup></mrow>`},
+		{`(a+b)/2`, false, `<mrow><mfrac><mrow><mi>a</mi><mo>+</mo><mi>b</mi></mrow><mn>2</mn></mfrac></mrow>`},
+		{`x_(i+1)`, false, `<mrow><msub><mi>x</mi><mrow><mi>i</mi><mo>+</mo><mn>1</mn></mrow></msub></mrow>`},
+		{`(x)`, false, `<mrow><mo fence="true" stretchy="true">(</mo><mi>x</mi><mo fence="true" stretchy="true">)</mo></mrow>`},
+		{`{: x :}`, false, `<mrow><mi>x</mi></mrow>`},
+		{`sqrt x`, false, `<mrow><msqrt><mi>x</mi></msqrt></mrow>`},
+		{`root(3)(x)`, false, `<mrow><mroot><mi>x</mi><mn>3</mn></mroot></mrow>`},
+		{`frac(1)(2)`, false, `<mrow><mfrac><mn>1</mn><mn>2</mn></mfrac></mrow>`},
+		{`alpha != beta`, false, `<mrow><mi>α</mi><mo>≠</mo><mi>β</mi></mrow>`},
+		{`sin x`, false, `<mrow><mi>sin</mi><mi>x</mi></mrow>`},
+		{`a xx b -> oo`, false, `<mrow><mi>a</mi><mo>×</mo><mi>b</mi><mo>→</mo><mi>∞</mi></mrow>`},
+		{`sum_(i=1)^n`, true, `<mrow><munderover><mo>∑</mo><mrow><mi>i</mi><mo>=</mo><mn>1</mn></mrow><mi>n</mi></munderover></mrow>`},
+		{`abs(x)`, false, `<mrow><mo fence="true" stretchy="true">|</mo><mi>x</mi><mo fence="true" stretchy="true">|</mo></mrow>`},
+		{`hat x`, false, `<mrow><mover accent="true"><mi>x</mi><mo>^</mo></mover></mrow>`},
+		{`bbb R`, false, `<mrow><mi>ℝ</mi></mrow>`},
+		{`text(if) x`, false, `<mrow><mtext>if</mtext><mi>x</mi></mrow>`},
+		{`"for all" x`, false, `<mrow><mtext>for all</mtext><mi>x</mi></mrow>`},
+	}
+	for _, tt := range tests {
+		if got := content(t, AsciiMath, tt.src, tt.display); got != tt.expected {
+			t.Errorf("AsciiMath %q (display=%v):\n got: %s\nwant: %s", tt.src, tt.display, got, tt.expected)
+		}
+	}
+
+	for _, src := range []string{`(x`, `x)`, `x^`, `x^2^3`} {
+		if _, err := AsciiMath.Convert(src, false); !errors.Is(err, ErrSyntax) {
+			t.Errorf("AsciiMath %q: expected ErrSyntax, got %v", src, err)
+		}
+	}
+}
+
+func TestChem(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected string
+	}{
+		{`H2O`, `<mrow><msub><mi mathvariant="normal">H</mi><mn>2</mn></msub><mi mathvariant="normal">O</mi></mrow>`},
+		{`2H2`, `<mrow><mn>2</mn><msub><mi mathvariant="normal">H</mi><mn>2</mn></msub></mrow>`},
+		{`SO4^2-`, `<mrow><mi mathvariant="normal">S</mi><msubsup><mi mathvariant="normal">O</mi><mn>4</mn><mrow><mn>2</mn><mo>−</mo></mrow></msubsup></mrow>`},
+		{`Fe^{3+}`, `<mrow><msup><mi>Fe</mi><mrow><mn>3</mn><mo>+</mo></mrow></msup></mrow>`},
+		{`Na+ + Cl-`, `<mrow><msup><mi>Na</mi><mo>+</mo></msup><mo>+</mo><msup><mi>Cl</mi><mo>−</mo></msup></mrow>`},
+		{`Ca(OH)2`, `<mrow><mi>Ca</mi><msub><mrow><mo>(</mo><mi mathvariant="normal">O</mi><mi mathvariant="normal">H</mi><mo>)</mo></mrow><mn>2</mn></msub></mrow>`},
+		{`NaCl(aq)`, `<mrow><mi>Na</mi><mi>Cl</mi><mrow><mo>(</mo><mi>aq</mi><mo>)</mo></mrow></mrow>`},
+		{`A <=> B`, `<mrow><mi mathvariant="normal">A</mi><mo stretchy="true">⇌</mo><mi mathvariant="normal">B</mi></mrow>`},
+		{`A ->[heat] B`, `<mrow><mi mathvariant="normal">A</mi><mover><mo stretchy="true">→</mo><mtext>heat</mtext></mover><mi mathvariant="normal">B</mi></mrow>`},
+		{`CuSO4*5H2O`, `<mrow><mi>Cu</mi><mi mathvariant="normal">S</mi><msub><mi mathvariant="normal">O</mi><mn>4</mn></msub><mo>·</mo><mn>5</mn><msub><mi mathvariant="normal">H</mi><mn>2</mn></msub><mi mathvariant="normal">O</mi></mrow>`},
+	}
+	for _, tt := range tests {
+		if got := content(t, Chem, tt.src, false); got != tt.expected {
+			t.Errorf("Chem %q:\n got: %s\nwant: %s", tt.src, got, tt.expected)
+		}
+	}
+
+	for _, src := range []string{`Ca(OH`, `OH)`, `^2`, `H2O#`} {
+		if _, err := Chem.Convert(src, false); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Chem %q: expected ErrSyntax, got %v", src, err)
+		}
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	got := render(t, Inline(`x^2`))
+	expected := `<math><semantics><mrow><msup><mi>x</mi><mn>2</mn></msup></mrow><annotation encoding="application/x-tex">x^2</annotation></semantics></math>`
+	if got != expected {
+		t.Errorf("Inline:\n got: %s\nwant: %s", got, expected)
+	}
+
+	got = render(t, Block(`\ce{H2}`))
+	if !strings.HasPrefix(got, `<math display="block">`) || !strings.Contains(got, `<annotation encoding="application/x-tex">\ce{H2}</annotation>`) {
+		t.Errorf("Block: got %s", got)
+	}
+
+	got = render(t, Inline(`\unsupported{x}`))
+	expected = `<span class="math-fallback">\(\unsupported{x}\)</span>`
+	if got != expected {
+		t.Errorf("Inline fallback:\n got: %s\nwant: %s", got, expected)
+	}
+	got = render(t, Block(`<\unsupported>`))
+	expected = `<div class="math-fallback">\[&lt;\unsupported&gt;\]</div>`
+	if got != expected {
+		t.Errorf("Block fallback:\n got: %s\nwant: %s", got, expected)
+	}
+
+	var fallbackErr error
+	r := Renderer{
+		Converter: ConverterFunc(func(src string, display bool) (h.Builder, error) {
+			if src == "" {
+				return nil, errors.New("empty")
+			}
+			return h.Math(h.Text(src)), nil
+		}),
+		Fallback: func(src string, display bool, err error) h.Builder {
+			fallbackErr = err
+			return h.Text("?")
+		},
+	}
+	if got := render(t, r.Inline("pi")); got != `<math>pi</math>` {
+		t.Errorf("custom converter: got %s", got)
+	}
+	if got := render(t, r.Block("")); got != "?" || fallbackErr == nil || fallbackErr.Error() != "empty" {
+		t.Errorf("custom fallback: got %s, err %v", got, fallbackErr)
+	}
+}
@@ -0,0 +1,105 @@
+package mathml
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// greek maps letter names, shared by LaTeX (\alpha) and AsciiMath (alpha),
+// to their code points. Capitals are rendered upright as in TeX.
+var greek = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ϵ",
+	"varepsilon": "ε", "zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ",
+	"iota": "ι", "kappa": "κ", "lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ",
+	"pi": "π", "varpi": "ϖ", "rho": "ρ", "varrho": "ϱ", "sigma": "σ",
+	"varsigma": "ς", "tau": "τ", "upsilon": "υ", "phi": "ϕ", "varphi": "φ",
+	"chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ",
+	"Pi": "Π", "Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+}
+
+// greekIdent returns the <mi> for a Greek letter name.
+func greekIdent(name string) (*node, bool) {
+	s, ok := greek[name]
+	if !ok {
+		return nil, false
+	}
+	if name[0] >= 'A' && name[0] <= 'Z' {
+		return leaf("mi", s, "mathvariant", "normal"), true
+	}
+	return leaf("mi", s), true
+}
+
+// upright returns an <mi> for a multi-letter name such as a function.
+// Single characters get mathvariant="normal" so they are not italicized.
+func upright(s string) *node {
+	if utf8.RuneCountInString(s) == 1 {
+		return leaf("mi", s, "mathvariant", "normal")
+	}
+	return leaf("mi", s)
+}
+
+// Math alphabet styles applied by \mathbb, \mathbf, and friends.
+type alphabet struct {
+	upper, lower, digit rune // First code point of each range, 0 if none
+	holes               map[rune]rune
+}
+
+var alphabets = map[string]alphabet{
+	"bold": {upper: 0x1D400, lower: 0x1D41A, digit: 0x1D7CE},
+	"double-struck": {upper: 0x1D538, lower: 0x1D552, digit: 0x1D7D8, holes: map[rune]rune{
+		'C': 'ℂ', 'H': 'ℍ', 'N': 'ℕ', 'P': 'ℙ', 'Q': 'ℚ', 'R': 'ℝ', 'Z': 'ℤ',
+	}},
+	"script": {upper: 0x1D49C, lower: 0x1D4B6, holes: map[rune]rune{
+		'B': 'ℬ', 'E': 'ℰ', 'F': 'ℱ', 'H': 'ℋ', 'I': 'ℐ', 'L': 'ℒ', 'M': 'ℳ', 'R': 'ℛ',
+		'e': 'ℯ', 'g': 'ℊ', 'o': 'ℴ',
+	}},
+	"fraktur": {upper: 0x1D504, lower: 0x1D51E, holes: map[rune]rune{
+		'C': 'ℭ', 'H': 'ℌ', 'I': 'ℑ', 'R': 'ℜ', 'Z': 'ℨ',
+	}},
+	"sans-serif": {upper: 0x1D5A0, lower: 0x1D5BA, digit: 0x1D7E2},
+	"monospace":  {upper: 0x1D670, lower: 0x1D68A, digit: 0x1D7F6},
+}
+
+// restyle rewrites the ASCII letters and digits of every token under n into
+// the named math alphabet. MathML Core only honors mathvariant="normal", so
+// styled alphabets are expressed with the Unicode code points instead.
+func restyle(n *node, style string) {
+	if style == "normal" {
+		n.walk(func(t *node) {
+			if t.tag == "mi" && utf8.RuneCountInString(t.text) == 1 {
+				t.attrs = []string{"mathvariant", "normal"}
+			}
+		})
+		return
+	}
+	a := alphabets[style]
+	n.walk(func(t *node) {
+		if t.text == "" || t.tag == "mo" {
+			return
+		}
+		t.text = strings.Map(func(r rune) rune {
+			if s, ok := a.holes[r]; ok {
+				return s
+			}
+			switch {
+			case r >= 'A' && r <= 'Z' && a.upper != 0:
+				return a.upper + r - 'A'
+			case r >= 'a' && r <= 'z' && a.lower != 0:
+				return a.lower + r - 'a'
+			case r >= '0' && r <= '9' && a.digit != 0:
+				return a.digit + r - '0'
+			}
+			return r
+		}, t.text)
+		t.attrs = nil
+	})
+}
+
+// walk calls fn for n and each of its descendants.
+func (n *node) walk(fn func(*node)) {
+	fn(n)
+	for _, k := range n.kids {
+		k.walk(fn)
+	}
+}
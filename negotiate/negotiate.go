@@ -0,0 +1,160 @@
+// Package negotiate lets one handler serve both rendered HTML and JSON,
+// choosing the representation from the request's Accept and HTMX/Datastar headers.
+//
+// Return a value implementing both h.Builder (typically by embedding one) and
+// json.Marshaler, or a Pair:
+//
+//	http.Handle("/users/{id}", negotiate.Handler(func(r *http.Request) (any, error) {
+//	    u, err := loadUser(r.PathValue("id"))
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return negotiate.Pair{HTML: userPage(u), JSON: u}, nil
+//	}))
+//
+// Browsers and HTMX/Datastar requests receive HTML; clients that prefer
+// application/json receive JSON.
+package negotiate
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Format is a response representation.
+type Format int
+
+const (
+	// HTML renders an h.Builder as text/html.
+	HTML Format = iota
+	// JSON encodes a value as application/json.
+	JSON
+)
+
+func (f Format) String() string {
+	if f == JSON {
+		return "json"
+	}
+	return "html"
+}
+
+// Pair supplies separate HTML and JSON representations of a response.
+// Either field may be nil, in which case the other is used regardless of
+// the negotiated format.
+type Pair struct {
+	HTML h.Builder
+	JSON any
+}
+
+// Negotiate picks the representation for r. HTMX (HX-Request) and Datastar
+// (Datastar-Request) requests always receive HTML. Otherwise the Accept
+// header's quality values decide, with HTML winning ties and missing headers.
+func Negotiate(r *http.Request) Format {
+	if r.Header.Get("HX-Request") == "true" || r.Header.Get("Datastar-Request") == "true" {
+		return HTML
+	}
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return HTML
+	}
+	htmlQ, jsonQ := quality(accept, "text", "html"), quality(accept, "application", "json")
+	if jsonQ > htmlQ {
+		return JSON
+	}
+	return HTML
+}
+
+// quality returns the q-value the Accept header assigns to typ/subtype,
+// preferring the most specific matching media range.
+func quality(accept, typ, subtype string) float64 {
+	best, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mt := strings.ToLower(strings.TrimSpace(params[0]))
+		t, st, ok := strings.Cut(mt, "/")
+		if !ok {
+			continue
+		}
+		var spec int
+		switch {
+		case t == typ && st == subtype:
+			spec = 2
+		case t == typ && st == "*":
+			spec = 1
+		case t == "*" && st == "*":
+			spec = 0
+		default:
+			continue
+		}
+		if spec < specificity {
+			continue
+		}
+		q := 1.0
+		for _, p := range params[1:] {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if f, err := strconv.ParseFloat(v, 64); err == nil {
+					q = f
+				}
+			}
+		}
+		best, specificity = q, spec
+	}
+	return best
+}
+
+// Write writes v to w in the representation negotiated for r.
+//
+// v may be a Pair, a value implementing h.Builder and/or json.Marshaler,
+// or any other value (which is always encoded as JSON). When v only
+// supports one representation, that representation is used.
+func Write(w http.ResponseWriter, r *http.Request, v any) error {
+	var (
+		builder h.Builder
+		data    any
+	)
+	switch v := v.(type) {
+	case Pair:
+		builder, data = v.HTML, v.JSON
+	case h.Builder:
+		builder = v
+		if _, ok := v.(json.Marshaler); ok {
+			data = v
+		}
+	default:
+		data = v
+	}
+
+	hdr := w.Header()
+	hdr.Add("Vary", "Accept")
+	hdr.Add("Vary", "HX-Request")
+	hdr.Add("Vary", "Datastar-Request")
+	if builder != nil && (data == nil || Negotiate(r) == HTML) {
+		hdr.Set("Content-Type", "text/html; charset=utf-8")
+		return h.Render(w, builder)
+	}
+	hdr.Set("Content-Type", "application/json; charset=utf-8")
+	return json.NewEncoder(w).Encode(data)
+}
+
+// Handler adapts fn to an http.Handler that writes its result with Write.
+// If fn returns an error, a 500 Internal Server Error is sent instead.
+// Errors from Write, which may come after part of the body was sent, are
+// logged with the log package.
+func Handler(fn func(r *http.Request) (any, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, err := fn(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if err := Write(w, r, v); err != nil {
+			log.Printf("negotiate: writing response for %s %s: %v", r.Method, r.URL.Path, err)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package negotiate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected Format
+	}{
+		{"no accept", nil, HTML},
+		{"browser", map[string]string{"Accept": "text/html,application/xhtml+xml,*/*;q=0.8"}, HTML},
+		{"json", map[string]string{"Accept": "application/json"}, JSON},
+		{"wildcard", map[string]string{"Accept": "*/*"}, HTML},
+		{"json preferred", map[string]string{"Accept": "text/html;q=0.5, application/json"}, JSON},
+		{"application wildcard", map[string]string{"Accept": "application/*"}, JSON},
+		{"specific beats wildcard", map[string]string{"Accept": "application/json;q=0.2, */*"}, HTML},
+		{"htmx", map[string]string{"Accept": "application/json", "HX-Request": "true"}, HTML},
+		{"datastar", map[string]string{"Accept": "application/json", "Datastar-Request": "true"}, HTML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := Negotiate(r); got != tt.expected {
+				t.Errorf("Negotiate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// userView implements h.Builder by embedding the rendered markup.
+type userView struct {
+	h.Builder
+	Name string
+}
+
+func newUserView(name string) userView {
+	return userView{Builder: h.P(h.Text(name)), Name: name}
+}
+
+func (u userView) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{"name": u.Name})
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		value       any
+		contentType string
+		body        string
+	}{
+		{"dual html", "text/html", newUserView("Ann"), "text/html; charset=utf-8", "<p>Ann</p>"},
+		{"dual json", "application/json", newUserView("Ann"), "application/json; charset=utf-8", `{"name":"Ann"}` + "\n"},
+		{"pair html", "", Pair{HTML: h.Text("hi"), JSON: 1}, "text/html; charset=utf-8", "hi"},
+		{"pair json", "application/json", Pair{HTML: h.Text("hi"), JSON: 1}, "application/json; charset=utf-8", "1\n"},
+		{"builder only", "application/json", h.Text("hi"), "text/html; charset=utf-8", "hi"},
+		{"data only", "text/html", []int{1}, "application/json; charset=utf-8", "[1]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			if err := Write(rec, r, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if got := rec.Body.String(); got != tt.body {
+				t.Errorf("body = %q, want %q", got, tt.body)
+			}
+			if got := rec.Header().Values("Vary"); !slices.Equal(got, []string{"Accept", "HX-Request", "Datastar-Request"}) {
+				t.Errorf("Vary = %v", got)
+			}
+		})
+	}
+}
+
+func TestHandlerError(t *testing.T) {
+	handler := Handler(func(r *http.Request) (any, error) { return nil, errors.New("boom") })
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d", rec.Code)
+	}
+}
+
+func TestHandlerWriteError(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	handler := Handler(func(r *http.Request) (any, error) { return func() {}, nil })
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users", nil))
+	if !strings.Contains(logs.String(), "negotiate: writing response for GET /users: json: unsupported type") {
+		t.Errorf("log = %q", logs.String())
+	}
+}
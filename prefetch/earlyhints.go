@@ -0,0 +1,71 @@
+package prefetch
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Preload is a critical asset announced in a Link header, so the browser
+// can fetch it before the page that uses it arrives.
+type Preload struct {
+	URL         string
+	As          string // Destination: "style", "script", "font", "image", ...
+	Type        string // MIME type, optional
+	CrossOrigin string // "anonymous" or "use-credentials"; fonts require it
+	Rel         string // Empty means "preload"; also "modulepreload", "preconnect"
+}
+
+// Link returns p as a Link header value:
+//
+//	</assets/app.1a2b3c4d.css>; rel=preload; as=style
+func (p Preload) Link() string {
+	var sb strings.Builder
+	sb.WriteString("<" + p.URL + ">; rel=")
+	if p.Rel == "" {
+		sb.WriteString("preload")
+	} else {
+		sb.WriteString(p.Rel)
+	}
+	if p.As != "" {
+		sb.WriteString("; as=" + p.As)
+	}
+	if p.Type != "" {
+		sb.WriteString(`; type="` + p.Type + `"`)
+	}
+	if p.CrossOrigin != "" {
+		sb.WriteString("; crossorigin=" + p.CrossOrigin)
+	}
+	return sb.String()
+}
+
+// EarlyHints wraps next to announce assets in Link headers and send them
+// in a 103 Early Hints response before next runs, so the browser fetches
+// critical styles, scripts, and fonts while the page is still rendering.
+// The Link headers are also kept on the final response for browsers and
+// proxies that ignore 103.
+//
+//	critical := []prefetch.Preload{
+//	    {URL: "/assets/app.css", As: "style"},
+//	    {URL: "/assets/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"},
+//	}
+//	mux.Handle("/", prefetch.EarlyHints(h.Handler(page), critical...))
+//
+// Hints are sent only for GET and HEAD requests from HTTP/1.1 or later
+// clients, since 1xx responses are not allowed in HTTP/1.0. bundle.Bundle
+// assets can be announced with their fingerprinted URLs via Bundle.Preload.
+func EarlyHints(next http.Handler, assets ...Preload) http.Handler {
+	links := make([]string, len(assets))
+	for i, a := range assets {
+		links[i] = a.Link()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(links) > 0 && (r.Method == http.MethodGet || r.Method == http.MethodHead) && r.ProtoAtLeast(1, 1) {
+			hdr := w.Header()
+			for _, link := range links {
+				hdr.Add("Link", link)
+			}
+			w.WriteHeader(http.StatusEarlyHints)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
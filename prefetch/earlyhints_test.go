@@ -0,0 +1,70 @@
+package prefetch
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"slices"
+	"testing"
+)
+
+func TestPreloadLink(t *testing.T) {
+	tests := []struct {
+		preload  Preload
+		expected string
+	}{
+		{Preload{URL: "/app.css", As: "style"}, "</app.css>; rel=preload; as=style"},
+		{Preload{URL: "/app.js", Rel: "modulepreload"}, "</app.js>; rel=modulepreload"},
+		{
+			Preload{URL: "/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: "anonymous"},
+			`</inter.woff2>; rel=preload; as=font; type="font/woff2"; crossorigin=anonymous`,
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.preload.Link(); got != tt.expected {
+			t.Errorf("Link() = %s, want %s", got, tt.expected)
+		}
+	}
+}
+
+func TestEarlyHints(t *testing.T) {
+	page := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<p>page</p>")
+	})
+	handler := EarlyHints(page, Preload{URL: "/app.css", As: "style"}, Preload{URL: "/app.js", As: "script"})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	for _, method := range []string{"GET", "POST"} {
+		var hints []string
+		trace := &httptrace.ClientTrace{Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = append(hints, header.Values("Link")...)
+			}
+			return nil
+		}}
+		req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(t.Context(), trace), method, srv.URL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "<p>page</p>" {
+			t.Errorf("%s: body %q", method, body)
+		}
+
+		expected := []string{"</app.css>; rel=preload; as=style", "</app.js>; rel=preload; as=script"}
+		if method == "POST" {
+			expected = nil
+		}
+		if !slices.Equal(hints, expected) {
+			t.Errorf("%s: 103 Link headers %q, want %q", method, hints, expected)
+		}
+		if got := resp.Header.Values("Link"); !slices.Equal(got, expected) {
+			t.Errorf("%s: final Link headers %q, want %q", method, got, expected)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+// Package prefetch adds navigation hints for the likely next pages of a
+// rendered document, reducing navigation latency for HTMX-driven sites.
+//
+// Candidate URLs are links inside hx-boost regions and hx-get targets.
+// Authors can tune candidates with a data-prefetch annotation giving the
+// probability that the link is followed ("0.9"), or opting out ("false"):
+//
+//	h.Nav(hx.Boost(true),
+//	    h.A(h.Attrs("href", "/docs", "data-prefetch", "0.9"), h.Text("Docs")),
+//	    h.A(h.Attrs("href", "/logout", "data-prefetch", "false"), h.Text("Log out")),
+//	)
+//
+// Hints are injected before </head> as <link rel="prefetch"> elements or as a
+// <script type="speculationrules"> block:
+//
+//	prefetch.Render(w, page, prefetch.Options{Mode: prefetch.SpeculationRules})
+package prefetch
+
+import (
+	"bytes"
+	"cmp"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeffh/htmlgen/h"
+	"github.com/jeffh/htmlgen/internal/htmlscan"
+)
+
+// AnnotationAttr is the attribute holding a link's prefetch probability.
+const AnnotationAttr = "data-prefetch"
+
+// Mode selects the kind of hints emitted.
+type Mode int
+
+const (
+	// LinkPrefetch emits <link rel="prefetch" href="..."> elements.
+	LinkPrefetch Mode = iota
+	// SpeculationRules emits a speculation rules JSON block, which supports
+	// prerendering in browsers that implement it.
+	SpeculationRules
+)
+
+// Source identifies how a candidate link was discovered.
+type Source string
+
+const (
+	// SourceBoost is an <a href> inside an hx-boost region.
+	SourceBoost Source = "boost"
+	// SourceHxGet is an hx-get attribute.
+	SourceHxGet Source = "hx-get"
+)
+
+// Link is a candidate URL for prefetching.
+type Link struct {
+	URL         string
+	Probability float64
+	Source      Source
+	// Element is the name of the element the URL was found on.
+	Element string
+}
+
+// Options configures link discovery and hint output. The zero value emits
+// up to DefaultLimit <link rel="prefetch"> hints for every unannotated candidate.
+type Options struct {
+	Mode Mode
+	// Prerender requests prerendering instead of prefetching (SpeculationRules only).
+	Prerender bool
+	// Eagerness is the speculation rules eagerness ("immediate", "eager",
+	// "moderate", "conservative"). Empty uses the browser default.
+	Eagerness string
+
+	// DefaultProbability is assigned to links without an annotation.
+	// Zero means 0.5.
+	DefaultProbability float64
+	// Threshold is the minimum probability for a link to be hinted.
+	// Zero means 0.5.
+	Threshold float64
+	// Limit caps the number of hinted URLs. Zero means DefaultLimit;
+	// negative means unlimited.
+	Limit int
+	// Match, if set, further filters candidates.
+	Match func(Link) bool
+	// Sources restricts discovery to the given sources. Empty means all.
+	Sources []Source
+}
+
+// DefaultLimit is the number of URLs hinted when Options.Limit is zero.
+const DefaultLimit = 10
+
+func (o Options) defaultProbability() float64 {
+	if o.DefaultProbability == 0 {
+		return 0.5
+	}
+	return o.DefaultProbability
+}
+
+func (o Options) threshold() float64 {
+	if o.Threshold == 0 {
+		return 0.5
+	}
+	return o.Threshold
+}
+
+// Find scans a rendered document for candidate links, returning those that
+// pass the options' threshold and filters, most likely first. Each URL
+// appears once, with its highest probability.
+func Find(doc []byte, opts Options) []Link {
+	type frame struct {
+		name  string
+		boost bool
+	}
+	var (
+		stack []frame
+		links []Link
+		seen  = map[string]int{}
+	)
+	add := func(l Link) {
+		if len(opts.Sources) > 0 && !slices.Contains(opts.Sources, l.Source) {
+			return
+		}
+		if l.Probability < opts.threshold() || !prefetchable(l.URL) {
+			return
+		}
+		if opts.Match != nil && !opts.Match(l) {
+			return
+		}
+		if i, ok := seen[l.URL]; ok {
+			links[i].Probability = max(links[i].Probability, l.Probability)
+			return
+		}
+		seen[l.URL] = len(links)
+		links = append(links, l)
+	}
+
+	htmlscan.Scan(doc, func(t htmlscan.Tag) {
+		if t.End {
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == t.Name {
+					stack = stack[:i]
+					break
+				}
+			}
+			return
+		}
+
+		boosted := len(stack) > 0 && stack[len(stack)-1].boost
+		if v, ok := t.Attr("hx-boost"); ok {
+			boosted = v == "true"
+		}
+		if !t.IsVoid() {
+			stack = append(stack, frame{t.Name, boosted})
+		}
+
+		p, ok := probability(&t, opts.defaultProbability())
+		if !ok {
+			return
+		}
+		if t.Name == "a" && boosted {
+			if href := t.Value("href"); href != "" {
+				add(Link{URL: href, Probability: p, Source: SourceBoost, Element: t.Name})
+			}
+		}
+		if url := t.Value("hx-get"); url != "" {
+			add(Link{URL: url, Probability: p, Source: SourceHxGet, Element: t.Name})
+		}
+	})
+
+	slices.SortStableFunc(links, func(a, b Link) int { return cmp.Compare(b.Probability, a.Probability) })
+	limit := opts.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit > 0 && len(links) > limit {
+		links = links[:limit]
+	}
+	return links
+}
+
+// probability returns the annotated probability of t, or def when t is not
+// annotated. ok is false when the element opts out of prefetching.
+func probability(t *htmlscan.Tag, def float64) (p float64, ok bool) {
+	v, present := t.Attr(AnnotationAttr)
+	if !present {
+		return def, true
+	}
+	switch v = strings.TrimSpace(v); v {
+	case "", "true":
+		return 1, true
+	case "false":
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def, true
+	}
+	return p, p > 0
+}
+
+// prefetchable reports whether ref is a same-origin navigation target.
+// Absolute and protocol-relative URLs, fragments, and non-HTTP schemes are
+// skipped; relative URLs may contain colons after the path starts, as in
+// "/search?q=a:b".
+func prefetchable(ref string) bool {
+	if ref == "" || ref[0] == '#' || strings.HasPrefix(ref, "//") || strings.HasPrefix(ref, "/\\") {
+		return false
+	}
+	u, err := url.Parse(ref)
+	return err == nil && u.Scheme == "" && u.Host == ""
+}
+
+// Hints returns the hint markup for links in the given mode.
+// Returns nil if links is empty.
+func Hints(links []Link, opts Options) h.Builder {
+	if len(links) == 0 {
+		return nil
+	}
+	if opts.Mode == SpeculationRules {
+		urls := make([]string, len(links))
+		for i, l := range links {
+			urls[i] = l.URL
+		}
+		rule := []Rule{{URLs: urls, Eagerness: Eagerness(opts.Eagerness)}}
+		if opts.Prerender {
+			return Rules{Prerender: rule}.Script()
+		}
+		return Rules{Prefetch: rule}.Script()
+	}
+	return h.ForEach(slices.Values(links), func(l Link) h.Builder {
+		return h.Link(h.Attrs("rel", "prefetch", "href", l.URL))
+	})
+}
+
+// Inject inserts hints for the links found in doc before its </head> tag.
+// If doc has no </head>, the hints are prepended. Returns doc unchanged
+// when there is nothing to hint.
+func Inject(doc []byte, opts Options) []byte {
+	hints := h.RenderBytes(Hints(Find(doc, opts), opts))
+	if len(hints) == 0 {
+		return doc
+	}
+	at := htmlscan.IndexFold(doc, []byte("</head"))
+	if at < 0 {
+		at = 0
+	}
+	out := make([]byte, 0, len(doc)+len(hints))
+	out = append(out, doc[:at]...)
+	out = append(out, hints...)
+	return append(out, doc[at:]...)
+}
+
+// Render renders page, injects prefetch hints, and writes the result to w.
+// The page is buffered because hints are placed in the head but derived
+// from links anywhere in the document.
+func Render(w io.Writer, page h.Builder, opts Options) error {
+	var buf bytes.Buffer
+	if err := h.Render(&buf, page); err != nil {
+		return err
+	}
+	_, err := w.Write(Inject(buf.Bytes(), opts))
+	return err
+}
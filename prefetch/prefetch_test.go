@@ -0,0 +1,129 @@
+package prefetch
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func page() h.Builder {
+	return h.Html(
+		h.Head(h.Title(h.Text("t"))),
+		h.Body(
+			h.Nav(h.Attrs("hx-boost", "true"),
+				h.A(h.Attrs("href", "/docs", "data-prefetch", "0.9"), h.Text("Docs")),
+				h.A(h.Attrs("href", "/about"), h.Text("About")),
+				h.A(h.Attrs("href", "/logout", "data-prefetch", "false"), h.Text("Log out")),
+				h.A(h.Attrs("href", "/rare", "data-prefetch", "0.1"), h.Text("Rare")),
+				h.A(h.Attrs("href", "https://other.example.com/"), h.Text("Elsewhere")),
+				h.Div(h.Attrs("hx-boost", "false"),
+					h.A(h.Attrs("href", "/unboosted"), h.Text("Plain")),
+				),
+			),
+			h.A(h.Attrs("href", "/outside"), h.Text("Outside")),
+			h.Button(h.Attrs("hx-get", "/fragment"), h.Text("Load")),
+			h.Img(h.Attrs("src", "/x.png")),
+			h.A(h.Attrs("href", "/about", "hx-boost", "true"), h.Text("About again")),
+		),
+	)
+}
+
+func urls(links []Link) string {
+	var parts []string
+	for _, l := range links {
+		parts = append(parts, l.URL)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestFind(t *testing.T) {
+	doc := h.RenderBytes(page())
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"defaults", Options{}, "/docs /about /fragment"},
+		{"threshold", Options{Threshold: 0.8}, "/docs"},
+		{"low threshold", Options{Threshold: 0.05}, "/docs /about /fragment /rare"},
+		{"limit", Options{Limit: 1}, "/docs"},
+		{"sources", Options{Sources: []Source{SourceHxGet}}, "/fragment"},
+		{"match", Options{Match: func(l Link) bool { return l.Element == "a" }}, "/docs /about"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := urls(Find(doc, tt.opts)); got != tt.expected {
+				t.Errorf("Find() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrefetchable(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"/docs", true},
+		{"/search?q=a:b", true},
+		{"docs/a:b", true},
+		{"", false},
+		{"#top", false},
+		{"//other.example.com/", false},
+		{"/\\other.example.com/", false},
+		{"https://other.example.com/", false},
+		{"mailto:a@example.com", false},
+		{"javascript:alert(1)", false},
+		{"java\tscript:alert(1)", false},
+	}
+	for _, tt := range tests {
+		if got := prefetchable(tt.url); got != tt.expected {
+			t.Errorf("prefetchable(%q) = %v, want %v", tt.url, got, tt.expected)
+		}
+	}
+}
+
+func TestHints(t *testing.T) {
+	links := []Link{{URL: "/a"}, {URL: "/b"}}
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"links", Options{}, `<link rel="prefetch" href="/a"/><link rel="prefetch" href="/b"/>`},
+		{
+			"speculation rules",
+			Options{Mode: SpeculationRules, Prerender: true, Eagerness: "moderate"},
+			`<script type="speculationrules">{"prerender":[{"source":"list","urls":["/a","/b"],"eagerness":"moderate"}]}</script>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.RenderString(Hints(links, tt.opts)); got != tt.expected {
+				t.Errorf("Hints() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+	if Hints(nil, Options{}) != nil {
+		t.Error("expected nil Hints for no links")
+	}
+}
+
+func TestRender(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Render(&buf, page(), Options{Threshold: 0.8}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `<title>t</title><link rel="prefetch" href="/docs"/></head>`) {
+		t.Errorf("hints not injected before </head>:\n%s", buf.String())
+	}
+}
+
+func TestInjectWithoutLinks(t *testing.T) {
+	doc := []byte("<p>nothing</p>")
+	if got := Inject(doc, Options{}); !bytes.Equal(got, doc) {
+		t.Errorf("Inject() = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package prefetch
+
+import (
+	"encoding/json"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+// Rules is a speculation rules document, rendered by Script into a
+// <script type="speculationrules"> block. Find and Hints build a list rule
+// from the links on a page; Rules is for writing rules by hand:
+//
+//	prefetch.Rules{
+//	    Prerender: []prefetch.Rule{{URLs: []string{"/checkout"}, Eagerness: prefetch.Moderate}},
+//	    Prefetch: []prefetch.Rule{{
+//	        Where:     &prefetch.Condition{HrefMatches: "/products/*"},
+//	        Eagerness: prefetch.Conservative,
+//	    }},
+//	}.Script()
+type Rules struct {
+	Prefetch  []Rule `json:"prefetch,omitempty"`
+	Prerender []Rule `json:"prerender,omitempty"`
+}
+
+// Rule is a single speculation rule. Source may be left empty: it is "list"
+// when URLs is set and "document" otherwise.
+type Rule struct {
+	Source         string     `json:"source,omitempty"`
+	URLs           []string   `json:"urls,omitempty"`
+	Where          *Condition `json:"where,omitempty"` // Document rules only
+	Eagerness      Eagerness  `json:"eagerness,omitempty"`
+	ReferrerPolicy string     `json:"referrer_policy,omitempty"`
+	Tag            string     `json:"tag,omitempty"` // Sent in the Sec-Speculation-Tags header
+}
+
+// Condition selects the links a document rule applies to. Set one field;
+// And, Or, and Not combine conditions.
+type Condition struct {
+	HrefMatches     string      `json:"href_matches,omitempty"` // URL pattern: "/products/*"
+	SelectorMatches string      `json:"selector_matches,omitempty"`
+	And             []Condition `json:"and,omitempty"`
+	Or              []Condition `json:"or,omitempty"`
+	Not             *Condition  `json:"not,omitempty"`
+}
+
+// Eagerness is when the browser acts on a rule.
+type Eagerness string
+
+// Eagerness values, from most to least eager.
+const (
+	Immediate    Eagerness = "immediate"    // As soon as the rule is seen
+	Eager        Eagerness = "eager"        // On the slightest sign of intent
+	Moderate     Eagerness = "moderate"     // On hover
+	Conservative Eagerness = "conservative" // On pointer or touch down
+)
+
+// Script renders the rules as a <script type="speculationrules"> block, or
+// nothing if there are no rules.
+func (r Rules) Script() h.Builder {
+	if len(r.Prefetch) == 0 && len(r.Prerender) == 0 {
+		return nil
+	}
+	r.Prefetch, r.Prerender = withSources(r.Prefetch), withSources(r.Prerender)
+	// json.Marshal escapes "<", so URLs cannot close the script element early.
+	data, _ := json.Marshal(r)
+	return h.Script(h.Attrs("type", "speculationrules"), h.Raw(string(data)))
+}
+
+// withSources returns a copy of rules with empty sources filled in.
+func withSources(rules []Rule) []Rule {
+	if rules == nil {
+		return nil
+	}
+	result := make([]Rule, len(rules))
+	for i, rule := range rules {
+		if rule.Source == "" {
+			rule.Source = "document"
+			if len(rule.URLs) > 0 {
+				rule.Source = "list"
+			}
+		}
+		result[i] = rule
+	}
+	return result
+}
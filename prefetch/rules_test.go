@@ -0,0 +1,47 @@
+package prefetch
+
+import (
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestRulesScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    Rules
+		expected string
+	}{
+		{"empty", Rules{}, ""},
+		{
+			"list",
+			Rules{Prerender: []Rule{{URLs: []string{"/checkout"}, Eagerness: Moderate}}},
+			`<script type="speculationrules">{"prerender":[{"source":"list","urls":["/checkout"],"eagerness":"moderate"}]}</script>`,
+		},
+		{
+			"document",
+			Rules{Prefetch: []Rule{{
+				Where: &Condition{And: []Condition{
+					{HrefMatches: "/products/*"},
+					{Not: &Condition{SelectorMatches: ".no-prefetch"}},
+				}},
+				Eagerness: Conservative,
+				Tag:       "products",
+			}}},
+			`<script type="speculationrules">{"prefetch":[{"source":"document","where":{"and":[{"href_matches":"/products/*"},` +
+				`{"not":{"selector_matches":".no-prefetch"}}]},"eagerness":"conservative","tag":"products"}]}</script>`,
+		},
+		{
+			"escaped",
+			Rules{Prefetch: []Rule{{URLs: []string{"/</script>"}}}},
+			`<script type="speculationrules">{"prefetch":[{"source":"list","urls":["/\u003c/script\u003e"]}]}</script>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.RenderString(tt.rules.Script()); got != tt.expected {
+				t.Errorf("Script() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
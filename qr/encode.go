@@ -0,0 +1,452 @@
+package qr
+
+import "errors"
+
+// ErrTooLong is returned when the data does not fit in a version 40 QR code
+// at the requested error correction level.
+var ErrTooLong = errors.New("qr: data too long to encode")
+
+// Level is the error correction level of a QR code. Higher levels survive
+// more damage (or a logo overlay) at the cost of a larger code.
+type Level int
+
+const (
+	L Level = iota // Recovers ~7% of codewords
+	M              // Recovers ~15% of codewords
+	Q              // Recovers ~25% of codewords
+	H              // Recovers ~30% of codewords
+)
+
+// formatBits are the two error correction bits of the format information.
+var formatBits = [...]int{L: 1, M: 0, Q: 3, H: 2}
+
+// eccPerBlock and eccBlocks are indexed by level and version (index 0 unused).
+var eccPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var eccBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is an encoded QR code: a square grid of dark and light modules,
+// without the surrounding quiet zone.
+type Code struct {
+	Size    int // Modules per side
+	Version int // 1 through 40
+	Level   Level
+
+	modules    []bool
+	isFunction []bool
+}
+
+// Dark reports whether the module at column x, row y is dark.
+// Coordinates outside the code are light.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.modules[y*c.Size+x]
+}
+
+// Encode encodes data in byte mode using the smallest version that fits at
+// level, choosing the mask pattern with the lowest penalty.
+func Encode(data string, level Level) (*Code, error) {
+	return encode(data, level, -1)
+}
+
+// encode is Encode with a fixed mask pattern (0-7), or the best one if mask < 0.
+func encode(data string, level Level, mask int) (*Code, error) {
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if 4+countBits(v)+8*len(data) <= dataCodewords(v, level)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	var bb bitBuffer
+	bb.append(0b0100, 4) // Byte mode
+	bb.append(len(data), countBits(version))
+	for i := 0; i < len(data); i++ {
+		bb.append(int(data[i]), 8)
+	}
+	capacity := dataCodewords(version, level) * 8
+	bb.append(0, min(4, capacity-len(bb)))
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+
+	size := version*4 + 17
+	c := &Code{
+		Size:       size,
+		Version:    version,
+		Level:      level,
+		modules:    make([]bool, size*size),
+		isFunction: make([]bool, size*size),
+	}
+	c.drawFunctionPatterns()
+	c.drawCodewords(addECCAndInterleave(codewords, version, level))
+
+	if mask < 0 {
+		best := 0
+		for m := range 8 {
+			c.applyMask(m)
+			c.drawFormatBits(m)
+			if p := c.penalty(); m == 0 || p < best {
+				best, mask = p, m
+			}
+			c.applyMask(m) // XOR undoes the mask
+		}
+	}
+	c.applyMask(mask)
+	c.drawFormatBits(mask)
+	c.isFunction = nil
+	return c, nil
+}
+
+// countBits is the length of the byte mode character count field.
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// rawDataModules is the number of modules available for data and error
+// correction after the function patterns are placed.
+func rawDataModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccPerBlock[level][version]*eccBlocks[level][version]
+}
+
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, version*4+10; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Size+x] = dark
+	c.isFunction[y*c.Size+x] = true
+}
+
+func (c *Code) drawFunctionPatterns() {
+	for i := range c.Size {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	for _, p := range [][2]int{{3, 3}, {c.Size - 4, 3}, {3, c.Size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := p[0]+dx, p[1]+dy
+				if x >= 0 && x < c.Size && y >= 0 && y < c.Size {
+					d := max(abs(dx), abs(dy))
+					c.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	pos := alignmentPositions(c.Version)
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue // Overlaps a finder pattern
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	c.drawFormatBits(0) // Reserves the area; redrawn once the mask is chosen
+	if c.Version >= 7 {
+		rem := c.Version
+		for range 12 {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := c.Version<<12 | rem
+		for i := range 18 {
+			dark := bits>>i&1 != 0
+			a, b := c.Size-11+i%3, i/3
+			c.set(a, b, dark)
+			c.set(b, a, dark)
+		}
+	}
+}
+
+func (c *Code) drawFormatBits(mask int) {
+	data := formatBits[c.Level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	for i := range 6 {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true) // Always dark
+}
+
+// drawCodewords places data in the zigzag pattern of two-module columns,
+// from the bottom right corner, skipping function modules.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := range c.Size {
+			for j := range 2 {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert // Upward column
+				}
+				if !c.isFunction[y*c.Size+x] && i < len(data)*8 {
+					c.modules[y*c.Size+x] = data[i>>3]>>(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := range c.Size {
+		for x := range c.Size {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunction[y*c.Size+x] {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// penalty scores the current module layout using the four rules of
+// ISO/IEC 18004 section 7.8.3; lower is better.
+func (c *Code) penalty() int {
+	p := 0
+	finder := []bool{true, false, true, true, true, false, true}
+	for pass := range 2 {
+		for a := range c.Size {
+			run := 0
+			for b := range c.Size {
+				x, y := b, a
+				if pass == 1 {
+					x, y = a, b
+				}
+				if b > 0 && c.Dark(x, y) == c.Dark(x-(1-pass), y-pass) {
+					run++
+				} else {
+					run = 1
+				}
+				if run == 5 {
+					p += 3
+				} else if run > 5 {
+					p++
+				}
+
+				// A 1:1:3:1:1 finder-like pattern with four light modules
+				// on either side (outside the code counts as light).
+				match := true
+				for k, dark := range finder {
+					if c.Dark(x+k*(1-pass), y+k*pass) != dark {
+						match = false
+						break
+					}
+				}
+				if match {
+					before, after := true, true
+					for k := 1; k <= 4; k++ {
+						before = before && !c.Dark(x-k*(1-pass), y-k*pass)
+						after = after && !c.Dark(x+(6+k)*(1-pass), y+(6+k)*pass)
+					}
+					if before {
+						p += 40
+					}
+					if after {
+						p += 40
+					}
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := range c.Size {
+		for x := range c.Size {
+			d := c.Dark(x, y)
+			if d {
+				dark++
+			}
+			if x > 0 && y > 0 && d == c.Dark(x-1, y) && d == c.Dark(x, y-1) && d == c.Dark(x-1, y-1) {
+				p += 3
+			}
+		}
+	}
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return p + k*10
+}
+
+// addECCAndInterleave splits data into blocks, appends Reed-Solomon error
+// correction to each, and interleaves the blocks' codewords.
+func addECCAndInterleave(data []byte, version int, level Level) []byte {
+	numBlocks := eccBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	raw := rawDataModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := rsDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range numBlocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := make([]byte, 0, shortLen+1)
+		block = append(block, data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, divisor)
+		if i < numShort {
+			block = append(block, 0) // Placeholder so all blocks align
+		}
+		blocks[i] = append(block, ecc...)
+	}
+
+	result := make([]byte, 0, raw)
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= gfMul(d, factor)
+		}
+	}
+	return result
+}
+
+// gfMul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, val>>i&1 != 0)
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
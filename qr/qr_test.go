@@ -0,0 +1,116 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeffh/htmlgen/h"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "01234567" at 1-M, from ISO/IEC 18004 Annex I.
+	data := []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	expected := []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, expected) {
+		t.Errorf("got % X, want % X", got, expected)
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		n       int
+		level   Level
+		version int
+	}{
+		{0, L, 1},
+		{17, L, 1},
+		{18, L, 2},
+		{14, M, 1},
+		{15, M, 2},
+		{7, H, 1},
+		{2953, L, 40},
+	}
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.n), tt.level)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", tt.n, err)
+		}
+		if c.Version != tt.version || c.Size != tt.version*4+17 {
+			t.Errorf("Encode(%d bytes, %d) = version %d size %d, want version %d", tt.n, tt.level, c.Version, c.Size, tt.version)
+		}
+	}
+}
+
+func TestEncodeTooLong(t *testing.T) {
+	if _, err := Encode(strings.Repeat("a", 2954), L); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+	if _, err := SVG(strings.Repeat("a", 1274), Options{Level: H}); !errors.Is(err, ErrTooLong) {
+		t.Errorf("expected ErrTooLong, got %v", err)
+	}
+}
+
+func TestEncodeFunctionPatterns(t *testing.T) {
+	c, err := Encode("https://example.com/tickets/12345", M)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Finder patterns: dark outer ring, light ring, dark 3x3 center.
+	for _, origin := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for i := range 7 {
+			for _, p := range [][2]int{{i, 0}, {i, 6}, {0, i}, {6, i}} {
+				if !c.Dark(origin[0]+p[0], origin[1]+p[1]) {
+					t.Errorf("finder at %v: expected dark border at %v", origin, p)
+				}
+			}
+		}
+		if c.Dark(origin[0]+1, origin[1]+1) || !c.Dark(origin[0]+3, origin[1]+3) {
+			t.Errorf("finder at %v: unexpected inner rings", origin)
+		}
+	}
+	// Timing patterns alternate between the finders.
+	for i := 8; i < c.Size-8; i++ {
+		if c.Dark(i, 6) != (i%2 == 0) || c.Dark(6, i) != (i%2 == 0) {
+			t.Errorf("timing pattern broken at %d", i)
+		}
+	}
+	if !c.Dark(8, c.Size-8) {
+		t.Error("expected the dark module")
+	}
+}
+
+func TestEncodeChoosesLowestPenaltyMask(t *testing.T) {
+	best, err := Encode("hello, world", Q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for mask := range 8 {
+		c, err := encode("hello, world", Q, mask)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.penalty() < best.penalty() {
+			t.Errorf("mask %d has a lower penalty than the chosen mask", mask)
+		}
+	}
+}
+
+func TestSVG(t *testing.T) {
+	b, err := SVG("hi", Options{Scale: 2, Label: "Scan to pay"}, h.Class("qr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := h.RenderString(b)
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 29 29" width="58" height="58" shape-rendering="crispEdges" role="img" aria-label="Scan to pay" class="qr">`,
+		`<rect width="100%" height="100%" fill="#fff">`,
+		`<path d="M4 4h7v1h-7z`,
+		`fill="#000"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+}